@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/tinovyatkin/tally/internal/baseline"
+	"github.com/tinovyatkin/tally/internal/processor"
+	"github.com/tinovyatkin/tally/internal/rules"
+)
+
+// loadBaselineFilter returns the baseline filter for --baseline, or nil when
+// no baseline is used. A baseline is not applied while writing a new one, so
+// that the new file records every current violation.
+func loadBaselineFilter(cmd *cli.Command) (*processor.BaselineFilter, error) {
+	path := cmd.String("baseline")
+	if path == "" || cmd.String("write-baseline") != "" {
+		return nil, nil //nolint:nilnil // no baseline configured
+	}
+	b, err := baseline.Load(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load baseline: %w", err)
+	}
+	return processor.NewBaselineFilter(b), nil
+}
+
+// writeBaseline records violations in a new baseline file at path.
+func writeBaseline(path string, violations []rules.Violation, procCtx *processor.Context) error {
+	b, err := baseline.New(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to write baseline: %v\n", err)
+		return cli.Exit("", ExitConfigError)
+	}
+	b.Add(violations, procCtx.GetSourceMap)
+	if err := b.Write(path); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to write baseline: %v\n", err)
+		return cli.Exit("", ExitConfigError)
+	}
+	fmt.Fprintf(os.Stderr, "Wrote %d violation(s) to baseline %s\n", len(violations), path)
+	return nil
+}
+
+// reportFixedBaseline prints a note about baseline entries that no longer
+// match any violation, listing them when showFixed is set.
+func reportFixedBaseline(fixed []baseline.Entry, showFixed bool) {
+	if len(fixed) == 0 {
+		return
+	}
+	n := 0
+	for _, e := range fixed {
+		n += e.Count
+	}
+	if !showFixed {
+		fmt.Fprintf(os.Stderr,
+			"note: %d baselined violation(s) are fixed (use --baseline-show-fixed to list, --write-baseline to update)\n", n)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "note: %d baselined violation(s) are fixed:\n", n)
+	for _, e := range fixed {
+		suffix := ""
+		if e.Count > 1 {
+			suffix = fmt.Sprintf(" (x%d)", e.Count)
+		}
+		fmt.Fprintf(os.Stderr, "  %s: %s: %s%s\n", e.File, e.Rule, e.Message, suffix)
+	}
+}
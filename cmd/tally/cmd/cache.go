@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/tinovyatkin/tally/internal/config"
+	"github.com/tinovyatkin/tally/internal/lintcache"
+)
+
+func cacheCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "cache",
+		Usage: "Manage the lint result cache",
+		Commands: []*cli.Command{
+			{
+				Name:  "clean",
+				Usage: "Remove the lint result cache directory",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "config",
+						Aliases: []string{"c"},
+						Usage:   "Path to config file (default: auto-discover from the current directory)",
+					},
+				},
+				Action: func(_ context.Context, cmd *cli.Command) error {
+					cfg, err := loadWorkingDirConfig(cmd.String("config"))
+					if err != nil {
+						fmt.Fprintf(os.Stderr, "Error: failed to load config: %v\n", err)
+						return cli.Exit("", ExitConfigError)
+					}
+					dir := cfg.CacheDir("")
+					if err := lintcache.Clean(dir); err != nil {
+						fmt.Fprintf(os.Stderr, "Error: failed to clean cache: %v\n", err)
+						return cli.Exit("", ExitConfigError)
+					}
+					fmt.Printf("Removed cache directory %s\n", dir)
+					return nil
+				},
+			},
+		},
+	}
+}
+
+// loadWorkingDirConfig loads the config that the lint command would use for files
+// in the current directory.
+func loadWorkingDirConfig(configPath string) (*config.Config, error) {
+	if configPath != "" {
+		return config.LoadFromFile(configPath)
+	}
+	// Discovery starts at the directory containing the target path.
+	return config.Load(filepath.Join(".", "Dockerfile"))
+}
@@ -0,0 +1,373 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/tinovyatkin/tally/internal/config"
+	"github.com/tinovyatkin/tally/internal/configcheck"
+)
+
+// configSchema is the config JSON schema (schema.json), set by SetConfigSchema.
+var configSchema []byte
+
+// SetConfigSchema provides the config JSON schema used by tally config validate.
+// The schema lives at the repository root, so the main package embeds it.
+func SetConfigSchema(schema []byte) {
+	configSchema = schema
+}
+
+// configShowFlagNames are the lint flags that change the resolved configuration.
+// Output format and destination flags are left out: they don't change how
+// files are linted, and --format selects the output of config show itself.
+var configShowFlagNames = []string{
+	"config",
+	"max-lines", "skip-blank-lines", "skip-comments",
+	"fail-level",
+	"no-inline-directives", "warn-unused-directives", "require-reason",
+	"select", "ignore",
+	"jobs", "no-cache",
+	"build-arg", "build-arg-file", "target",
+	"slow-checks", "slow-checks-timeout", "offline",
+	"ai", "acp-command", "ai-timeout", "ai-max-input-bytes", "ai-redact-secrets",
+}
+
+func configCommand() *cli.Command {
+	showFlags := []cli.Flag{
+		&cli.StringFlag{
+			Name:    "format",
+			Aliases: []string{"f"},
+			Usage:   "Output format: toml, json",
+			Value:   "toml",
+		},
+	}
+	for _, f := range lintFlags() {
+		if slices.Contains(configShowFlagNames, f.Names()[0]) {
+			showFlags = append(showFlags, f)
+		}
+	}
+
+	return &cli.Command{
+		Name:  "config",
+		Usage: "Inspect and validate configuration",
+		Commands: []*cli.Command{
+			{
+				Name:      "show",
+				Usage:     "Print the configuration that applies to a Dockerfile and where each value comes from",
+				ArgsUsage: "[DOCKERFILE]",
+				Description: `Accepts the lint flags that change configuration, so
+  tally config show --select 'hadolint/*' services/api/Dockerfile
+shows what tally lint would use with the same flags.`,
+				Flags:  showFlags,
+				Action: configShowAction,
+			},
+			{
+				Name:      "validate",
+				Usage:     "Check config files for unknown keys, invalid values and unknown rules",
+				ArgsUsage: "[CONFIG...]",
+				Action:    configValidateAction,
+			},
+		},
+	}
+}
+
+func configShowAction(_ context.Context, cmd *cli.Command) error {
+	format := cmd.String("format")
+	if format != "toml" && format != "json" {
+		fmt.Fprintf(os.Stderr, "Error: unsupported format %q (expected toml or json)\n", format)
+		return cli.Exit("", ExitConfigError)
+	}
+	if cmd.Args().Len() > 1 {
+		fmt.Fprintln(os.Stderr, "Error: expected at most one Dockerfile")
+		return cli.Exit("", ExitConfigError)
+	}
+	target := cmd.Args().First()
+	if target == "" {
+		target = "Dockerfile"
+	}
+
+	e, err := config.Explain(target, cmd.String("config"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to load config: %v\n", err)
+		return cli.Exit("", ExitConfigError)
+	}
+	if err := applyConfigFlags(cmd, e.Config); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return cli.Exit("", ExitConfigError)
+	}
+	if cmd.IsSet("fail-level") {
+		e.Config.Output.FailLevel = cmd.String("fail-level")
+	}
+	if cmd.IsSet("jobs") {
+		e.Config.Jobs = cmd.Int("jobs")
+	}
+	if cmd.Bool("no-cache") {
+		e.Config.Cache.Enabled = false
+	}
+	e.Update(config.Origin{Source: config.SourceCLI})
+
+	if format == "json" {
+		return writeConfigJSON(os.Stdout, target, e)
+	}
+	return writeConfigTOML(os.Stdout, target, e)
+}
+
+// configValue is one resolved setting in tally config show --format json.
+type configValue struct {
+	Key    string `json:"key"`
+	Value  any    `json:"value"`
+	Source string `json:"source"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// configReport is the JSON output of tally config show.
+type configReport struct {
+	Target      string        `json:"target"`
+	ConfigFile  string        `json:"configFile,omitempty"`
+	ConfigChain []string      `json:"configChain,omitempty"`
+	Overrides   []int         `json:"overrides,omitempty"`
+	Select      []string      `json:"select,omitempty"`
+	Ignore      []string      `json:"ignore,omitempty"`
+	Values      []configValue `json:"values"`
+}
+
+func writeConfigJSON(w io.Writer, target string, e *config.Explanation) error {
+	report := configReport{
+		Target:      target,
+		ConfigFile:  e.Config.ConfigFile,
+		ConfigChain: e.Config.ConfigChain,
+		Overrides:   e.Overrides,
+		Select:      e.Config.Rules.Selected.Include,
+		Ignore:      e.Config.Rules.Selected.Exclude,
+		Values:      []configValue{},
+	}
+	for _, key := range slices.Sorted(maps.Keys(e.Values)) {
+		origin := e.Sources[key]
+		report.Values = append(report.Values, configValue{
+			Key:    key,
+			Value:  e.Values[key],
+			Source: origin.Source,
+			Detail: origin.Detail,
+		})
+	}
+	return writeRulesJSON(w, report)
+}
+
+func writeConfigTOML(w io.Writer, target string, e *config.Explanation) error {
+	fmt.Fprintf(w, "# Configuration for %s\n", target)
+	if e.Config.ConfigFile == "" {
+		fmt.Fprintln(w, "# Config file: none (defaults)")
+	} else {
+		fmt.Fprintf(w, "# Config file: %s\n", displayPath(e.Config.ConfigFile))
+	}
+	if len(e.Config.ConfigChain) > 1 {
+		chain := make([]string, len(e.Config.ConfigChain))
+		for i, src := range e.Config.ConfigChain {
+			chain[i] = displayPath(src)
+		}
+		fmt.Fprintf(w, "# Merge order: %s\n", strings.Join(chain, ", "))
+	}
+	for _, i := range e.Overrides {
+		o := e.Config.Overrides[i]
+		fmt.Fprintf(w, "# Applied overrides[%d]: files = %s\n", i, tomlValue(o.Files))
+		if len(o.Rules.Include) > 0 {
+			fmt.Fprintf(w, "#   rules.include = %s\n", tomlValue(o.Rules.Include))
+		}
+		if len(o.Rules.Exclude) > 0 {
+			fmt.Fprintf(w, "#   rules.exclude = %s\n", tomlValue(o.Rules.Exclude))
+		}
+	}
+	if sel := e.Config.Rules.Selected; len(sel.Include) > 0 || len(sel.Exclude) > 0 {
+		fmt.Fprintln(w, "# Command line selection (takes precedence over overrides):")
+		if len(sel.Include) > 0 {
+			fmt.Fprintf(w, "#   --select %s\n", tomlValue(sel.Include))
+		}
+		if len(sel.Exclude) > 0 {
+			fmt.Fprintf(w, "#   --ignore %s\n", tomlValue(sel.Exclude))
+		}
+	}
+
+	// Top-level keys must precede the first table header.
+	sections := make(map[string][]string)
+	for key := range e.Values {
+		section, _, _ := strings.Cut(key, ".")
+		if section == key {
+			section = ""
+		}
+		sections[section] = append(sections[section], key)
+	}
+	for _, section := range slices.Sorted(maps.Keys(sections)) {
+		fmt.Fprintln(w)
+		if section != "" {
+			fmt.Fprintf(w, "[%s]\n", section)
+		}
+		keys := sections[section]
+		slices.Sort(keys)
+		for _, key := range keys {
+			parts := configKeyParts(key)
+			if section != "" {
+				parts = parts[1:]
+			}
+			origin := e.Sources[key]
+			origin.Detail = displayPath(origin.Detail)
+			fmt.Fprintf(w, "%s = %s  # %s\n", tomlKey(parts), tomlValue(e.Values[key]), origin)
+		}
+	}
+	return nil
+}
+
+// displayPath shortens absolute paths below the working directory.
+func displayPath(p string) string {
+	if !filepath.IsAbs(p) {
+		return p
+	}
+	if wd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(wd, p); err == nil && !strings.HasPrefix(rel, "..") {
+			return rel
+		}
+	}
+	return p
+}
+
+var bareKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// mapConfigKeys are the settings whose values are maps keyed by names that
+// may contain dots, such as label names (org.opencontainers.image.created).
+var mapConfigKeys = []string{"label-schema", "build.args"}
+
+// configKeyParts splits a flattened config key into its TOML key segments.
+// A key below one of mapConfigKeys stays a single segment.
+func configKeyParts(key string) []string {
+	for _, prefix := range mapConfigKeys {
+		if name, ok := strings.CutPrefix(key, prefix+"."); ok {
+			return append(strings.Split(prefix, "."), name)
+		}
+	}
+	return strings.Split(key, ".")
+}
+
+// tomlKey formats key segments as a dotted key, quoting segments that
+// aren't bare keys.
+func tomlKey(parts []string) string {
+	parts = slices.Clone(parts)
+	for i, part := range parts {
+		if !bareKeyPattern.MatchString(part) {
+			parts[i] = tomlString(part)
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// tomlValue formats a config value as a TOML value.
+func tomlValue(v any) string {
+	rv := reflect.ValueOf(v)
+	//exhaustive:ignore
+	switch rv.Kind() {
+	case reflect.Invalid:
+		return `""`
+	case reflect.String:
+		return tomlString(rv.String())
+	case reflect.Slice, reflect.Array:
+		items := make([]string, rv.Len())
+		for i := range items {
+			items[i] = tomlValue(rv.Index(i).Interface())
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case reflect.Map:
+		keys := make([]string, 0, rv.Len())
+		for _, k := range rv.MapKeys() {
+			keys = append(keys, fmt.Sprint(k.Interface()))
+		}
+		slices.Sort(keys)
+		items := make([]string, len(keys))
+		for i, k := range keys {
+			items[i] = tomlKey([]string{k}) + " = " + tomlValue(rv.MapIndex(reflect.ValueOf(k).Convert(rv.Type().Key())).Interface())
+		}
+		return "{ " + strings.Join(items, ", ") + " }"
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// tomlString quotes s as a TOML basic string.
+func tomlString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\r':
+			b.WriteString(`\r`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				b.WriteString(`\u` + fmt.Sprintf("%04X", r))
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+func configValidateAction(_ context.Context, cmd *cli.Command) error {
+	if len(configSchema) == 0 {
+		fmt.Fprintln(os.Stderr, "Error: config schema is not available in this build")
+		return cli.Exit("", ExitConfigError)
+	}
+
+	paths := cmd.Args().Slice()
+	if len(paths) == 0 {
+		// Discovery starts at the directory containing the target path.
+		path := config.Discover(filepath.Join(".", "Dockerfile"))
+		if path == "" {
+			fmt.Fprintf(os.Stderr, "Error: no config file found (looked for %s)\n",
+				strings.Join(config.ConfigFileNames, ", "))
+			return cli.Exit("", ExitConfigError)
+		}
+		paths = []string{path}
+	}
+
+	invalid := false
+	for _, path := range paths {
+		problems, err := configcheck.Check(path, configSchema)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return cli.Exit("", ExitConfigError)
+		}
+		name := displayPath(path)
+		if len(problems) == 0 {
+			fmt.Printf("%s: ok\n", name)
+			continue
+		}
+		invalid = true
+		for _, p := range problems {
+			if p.Line == 0 {
+				fmt.Printf("%s: %s\n", name, p.Message)
+			} else {
+				fmt.Printf("%s:%s\n", name, p)
+			}
+		}
+	}
+	if invalid {
+		return cli.Exit("", ExitConfigError)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pelletier/go-toml/v2"
+
+	"github.com/tinovyatkin/tally/internal/config"
+)
+
+func TestTOMLValue(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{name: "string", value: "a \"b\"\n", want: `"a \"b\"\n"`},
+		{name: "control character", value: "\x01", want: `"\u0001"`},
+		{name: "bool", value: true, want: "true"},
+		{name: "int", value: int64(42), want: "42"},
+		{name: "nil list", value: []string(nil), want: "[]"},
+		{name: "list", value: []any{"a", int64(1)}, want: `["a", 1]`},
+		{name: "table", value: map[string]any{"b": 2, "a": "x"}, want: `{ a = "x", b = 2 }`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tomlValue(tt.value); got != tt.want {
+				t.Errorf("tomlValue(%#v) = %s, want %s", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTOMLKey(t *testing.T) {
+	t.Parallel()
+	tests := map[string]string{
+		"rules.tally.max-lines.max":                     "rules.tally.max-lines.max",
+		"build.args.MY_ARG":                             "build.args.MY_ARG",
+		"build.args.with space":                         `build.args."with space"`,
+		"build.args.my.arg":                             `build.args."my.arg"`,
+		"label-schema.org.opencontainers.image.created": `label-schema."org.opencontainers.image.created"`,
+	}
+	for key, want := range tests {
+		if got := tomlKey(configKeyParts(key)); got != want {
+			t.Errorf("tomlKey(%q) = %s, want %s", key, got, want)
+		}
+	}
+}
+
+func TestWriteConfigTOML_DottedLabelKeys(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, ".tally.toml")
+	content := "[label-schema]\n\"org.opencontainers.image.created\" = \"rfc3339\"\n"
+	if err := os.WriteFile(configPath, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	e, err := config.Explain(filepath.Join(dir, "Dockerfile"), configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := writeConfigTOML(&buf, "Dockerfile", e); err != nil {
+		t.Fatal(err)
+	}
+	var shown struct {
+		LabelSchema map[string]string `toml:"label-schema"`
+	}
+	if err := toml.Unmarshal(buf.Bytes(), &shown); err != nil {
+		t.Fatalf("config show output is not valid TOML: %v\n%s", err, buf.String())
+	}
+	if got := shown.LabelSchema["org.opencontainers.image.created"]; got != "rfc3339" {
+		t.Errorf("label-schema[org.opencontainers.image.created] = %q, want rfc3339\n%s", got, buf.String())
+	}
+}
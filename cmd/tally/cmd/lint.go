@@ -5,11 +5,14 @@ import (
 	stdcontext "context"
 	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"runtime"
 	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/urfave/cli/v3"
@@ -22,6 +25,8 @@ import (
 	"github.com/tinovyatkin/tally/internal/discovery"
 	"github.com/tinovyatkin/tally/internal/dockerfile"
 	"github.com/tinovyatkin/tally/internal/fix"
+	"github.com/tinovyatkin/tally/internal/gitdiff"
+	"github.com/tinovyatkin/tally/internal/lintcache"
 	"github.com/tinovyatkin/tally/internal/linter"
 	"github.com/tinovyatkin/tally/internal/processor"
 	"github.com/tinovyatkin/tally/internal/registry"
@@ -42,148 +47,211 @@ func lintCommand() *cli.Command {
 		Name:      "lint",
 		Usage:     "Lint Dockerfile(s) for issues",
 		ArgsUsage: "[DOCKERFILE...]",
-		Flags: []cli.Flag{
-			&cli.StringFlag{
-				Name:    "config",
-				Aliases: []string{"c"},
-				Usage:   "Path to config file (default: auto-discover)",
-			},
-			&cli.IntFlag{
-				Name:    "max-lines",
-				Aliases: []string{"l"},
-				Usage:   "Maximum number of lines allowed (0 = unlimited)",
-				Sources: cli.EnvVars("TALLY_RULES_MAX_LINES_MAX"),
-			},
-			&cli.BoolFlag{
-				Name:    "skip-blank-lines",
-				Usage:   "Exclude blank lines from the line count",
-				Sources: cli.EnvVars("TALLY_RULES_MAX_LINES_SKIP_BLANK_LINES"),
-			},
-			&cli.BoolFlag{
-				Name:    "skip-comments",
-				Usage:   "Exclude comment lines from the line count",
-				Sources: cli.EnvVars("TALLY_RULES_MAX_LINES_SKIP_COMMENTS"),
-			},
-			&cli.StringFlag{
-				Name:    "format",
-				Aliases: []string{"f"},
-				Usage:   "Output format: text, json, sarif, github-actions",
-				Sources: cli.EnvVars("TALLY_FORMAT", "TALLY_OUTPUT_FORMAT"),
-			},
-			&cli.StringFlag{
-				Name:    "output",
-				Aliases: []string{"o"},
-				Usage:   "Output path: stdout, stderr, or file path",
-				Sources: cli.EnvVars("TALLY_OUTPUT_PATH"),
-			},
-			&cli.BoolFlag{
-				Name:    "no-color",
-				Usage:   "Disable colored output",
-				Sources: cli.EnvVars("NO_COLOR"),
-			},
-			&cli.BoolFlag{
-				Name:    "show-source",
-				Usage:   "Show source code snippets (default: true)",
-				Value:   true,
-				Sources: cli.EnvVars("TALLY_OUTPUT_SHOW_SOURCE"),
-			},
-			&cli.BoolFlag{
-				Name:  "hide-source",
-				Usage: "Hide source code snippets",
-			},
-			&cli.StringFlag{
-				Name:    "fail-level",
-				Usage:   "Minimum severity to cause non-zero exit: error, warning, info, style, none",
-				Sources: cli.EnvVars("TALLY_OUTPUT_FAIL_LEVEL"),
-			},
-			&cli.BoolFlag{
-				Name:    "no-inline-directives",
-				Usage:   "Disable processing of inline ignore directives",
-				Sources: cli.EnvVars("TALLY_NO_INLINE_DIRECTIVES"),
-			},
-			&cli.BoolFlag{
-				Name:    "warn-unused-directives",
-				Usage:   "Warn about unused ignore directives",
-				Sources: cli.EnvVars("TALLY_INLINE_DIRECTIVES_WARN_UNUSED"),
-			},
-			&cli.BoolFlag{
-				Name:    "require-reason",
-				Usage:   "Warn about ignore directives without reason= explanation",
-				Sources: cli.EnvVars("TALLY_INLINE_DIRECTIVES_REQUIRE_REASON"),
-			},
-			&cli.StringSliceFlag{
-				Name:    "exclude",
-				Usage:   "Glob pattern to exclude files (can be repeated)",
-				Sources: cli.EnvVars("TALLY_EXCLUDE"),
-			},
-			&cli.StringSliceFlag{
-				Name:    "select",
-				Usage:   "Enable specific rules (pattern: rule-code, namespace/*, *)",
-				Sources: cli.EnvVars("TALLY_RULES_SELECT"),
-			},
-			&cli.StringSliceFlag{
-				Name:    "ignore",
-				Usage:   "Disable specific rules (pattern: rule-code, namespace/*, *)",
-				Sources: cli.EnvVars("TALLY_RULES_IGNORE"),
-			},
-			&cli.StringFlag{
-				Name:    "context",
-				Usage:   "Build context directory for context-aware rules",
-				Sources: cli.EnvVars("TALLY_CONTEXT"),
-			},
-			&cli.StringFlag{
-				Name:    "slow-checks",
-				Usage:   "Slow checks mode: auto, on, off",
-				Sources: cli.EnvVars("TALLY_SLOW_CHECKS"),
-			},
-			&cli.StringFlag{
-				Name:    "slow-checks-timeout",
-				Usage:   "Timeout for slow checks (e.g., 20s)",
-				Sources: cli.EnvVars("TALLY_SLOW_CHECKS_TIMEOUT"),
-			},
-			&cli.BoolFlag{
-				Name:    "fix",
-				Usage:   "Apply all safe fixes automatically",
-				Sources: cli.EnvVars("TALLY_FIX"),
-			},
-			&cli.StringSliceFlag{
-				Name:    "fix-rule",
-				Usage:   "Only fix specific rules (can be repeated)",
-				Sources: cli.EnvVars("TALLY_FIX_RULE"),
-			},
-			&cli.BoolFlag{
-				Name:    "fix-unsafe",
-				Usage:   "Also apply suggestion/unsafe fixes (requires --fix)",
-				Sources: cli.EnvVars("TALLY_FIX_UNSAFE"),
-			},
-			&cli.BoolFlag{
-				Name:    "ai",
-				Usage:   "Enable AI AutoFix (requires an ACP agent command)",
-				Sources: cli.EnvVars("TALLY_AI_ENABLED"),
-			},
-			&cli.StringFlag{
-				Name:    "acp-command",
-				Usage:   "ACP agent command line (e.g. \"gemini --experimental-acp\")",
-				Sources: cli.EnvVars("TALLY_ACP_COMMAND"),
-			},
-			&cli.StringFlag{
-				Name:    "ai-timeout",
-				Usage:   "Per-fix AI timeout (e.g., 90s)",
-				Sources: cli.EnvVars("TALLY_AI_TIMEOUT"),
-			},
-			&cli.IntFlag{
-				Name:    "ai-max-input-bytes",
-				Usage:   "Maximum prompt size in bytes",
-				Sources: cli.EnvVars("TALLY_AI_MAX_INPUT_BYTES"),
-			},
-			&cli.BoolFlag{
-				Name:    "ai-redact-secrets",
-				Usage:   "Redact obvious secrets before sending content to the agent",
-				Value:   true,
-				Sources: cli.EnvVars("TALLY_AI_REDACT_SECRETS"),
-			},
+		Flags:     lintFlags(),
+		Action:    runLint,
+	}
+}
+
+// lintFlags returns the flags of the lint command.
+func lintFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    "config",
+			Aliases: []string{"c"},
+			Usage:   "Path to config file (default: auto-discover)",
+		},
+		&cli.IntFlag{
+			Name:    "max-lines",
+			Aliases: []string{"l"},
+			Usage:   "Maximum number of lines allowed (0 = unlimited)",
+			Sources: cli.EnvVars("TALLY_RULES_MAX_LINES_MAX"),
+		},
+		&cli.BoolFlag{
+			Name:    "skip-blank-lines",
+			Usage:   "Exclude blank lines from the line count",
+			Sources: cli.EnvVars("TALLY_RULES_MAX_LINES_SKIP_BLANK_LINES"),
+		},
+		&cli.BoolFlag{
+			Name:    "skip-comments",
+			Usage:   "Exclude comment lines from the line count",
+			Sources: cli.EnvVars("TALLY_RULES_MAX_LINES_SKIP_COMMENTS"),
+		},
+		&cli.StringFlag{
+			Name:    "format",
+			Aliases: []string{"f"},
+			Usage:   "Output format: text, json, sarif, github-actions",
+			Sources: cli.EnvVars("TALLY_FORMAT", "TALLY_OUTPUT_FORMAT"),
+		},
+		&cli.StringFlag{
+			Name:    "output",
+			Aliases: []string{"o"},
+			Usage:   "Output path: stdout, stderr, or file path",
+			Sources: cli.EnvVars("TALLY_OUTPUT_PATH"),
+		},
+		&cli.BoolFlag{
+			Name:    "no-color",
+			Usage:   "Disable colored output",
+			Sources: cli.EnvVars("NO_COLOR"),
+		},
+		&cli.BoolFlag{
+			Name:    "show-source",
+			Usage:   "Show source code snippets (default: true)",
+			Value:   true,
+			Sources: cli.EnvVars("TALLY_OUTPUT_SHOW_SOURCE"),
+		},
+		&cli.BoolFlag{
+			Name:  "hide-source",
+			Usage: "Hide source code snippets",
+		},
+		&cli.StringFlag{
+			Name:    "fail-level",
+			Usage:   "Minimum severity to cause non-zero exit: error, warning, info, style, none",
+			Sources: cli.EnvVars("TALLY_OUTPUT_FAIL_LEVEL"),
+		},
+		&cli.BoolFlag{
+			Name:    "no-inline-directives",
+			Usage:   "Disable processing of inline ignore directives",
+			Sources: cli.EnvVars("TALLY_NO_INLINE_DIRECTIVES"),
+		},
+		&cli.BoolFlag{
+			Name:    "warn-unused-directives",
+			Usage:   "Warn about unused ignore directives",
+			Sources: cli.EnvVars("TALLY_INLINE_DIRECTIVES_WARN_UNUSED"),
+		},
+		&cli.BoolFlag{
+			Name:    "require-reason",
+			Usage:   "Warn about ignore directives without reason= explanation",
+			Sources: cli.EnvVars("TALLY_INLINE_DIRECTIVES_REQUIRE_REASON"),
+		},
+		&cli.StringSliceFlag{
+			Name:    "exclude",
+			Usage:   "Glob pattern to exclude files (can be repeated)",
+			Sources: cli.EnvVars("TALLY_EXCLUDE"),
+		},
+		&cli.StringSliceFlag{
+			Name:    "select",
+			Usage:   "Enable specific rules (pattern: rule-code, namespace/*, *)",
+			Sources: cli.EnvVars("TALLY_RULES_SELECT"),
+		},
+		&cli.StringSliceFlag{
+			Name:    "ignore",
+			Usage:   "Disable specific rules (pattern: rule-code, namespace/*, *)",
+			Sources: cli.EnvVars("TALLY_RULES_IGNORE"),
+		},
+		&cli.StringFlag{
+			Name:    "context",
+			Usage:   "Build context directory for context-aware rules",
+			Sources: cli.EnvVars("TALLY_CONTEXT"),
+		},
+		&cli.IntFlag{
+			Name:    "jobs",
+			Aliases: []string{"j"},
+			Usage:   "Number of files to lint in parallel (default: number of CPUs)",
+			Sources: cli.EnvVars("TALLY_JOBS"),
+		},
+		&cli.BoolFlag{
+			Name:    "no-cache",
+			Usage:   "Disable the lint result and registry metadata caches for this run",
+			Sources: cli.EnvVars("TALLY_NO_CACHE"),
+		},
+		&cli.GenericFlag{
+			Name:  "build-arg",
+			Usage: "Set a build-time variable as with docker build (KEY=VALUE, can be repeated)",
+			Value: &repeatedStringValue{},
+		},
+		&cli.StringSliceFlag{
+			Name:  "build-arg-file",
+			Usage: "Read build-time variables from a file of KEY=VALUE lines (can be repeated)",
+		},
+		&cli.StringFlag{
+			Name:    "target",
+			Usage:   "Target build stage to lint against (default: last stage)",
+			Sources: cli.EnvVars("TALLY_BUILD_TARGET"),
+		},
+		&cli.StringFlag{
+			Name:    "slow-checks",
+			Usage:   "Slow checks mode: auto, on, off",
+			Sources: cli.EnvVars("TALLY_SLOW_CHECKS"),
+		},
+		&cli.StringFlag{
+			Name:    "slow-checks-timeout",
+			Usage:   "Timeout for slow checks (e.g., 20s)",
+			Sources: cli.EnvVars("TALLY_SLOW_CHECKS_TIMEOUT"),
+		},
+		&cli.BoolFlag{
+			Name:    "offline",
+			Usage:   "Run slow checks from cached registry metadata only, without network access",
+			Sources: cli.EnvVars("TALLY_OFFLINE"),
+		},
+		&cli.StringFlag{
+			Name:    "changed-since",
+			Usage:   "Only lint Dockerfiles changed since this git revision",
+			Sources: cli.EnvVars("TALLY_CHANGED_SINCE"),
+		},
+		&cli.BoolFlag{
+			Name:    "diff-only",
+			Usage:   "Only report violations on lines changed since --changed-since (default: HEAD)",
+			Sources: cli.EnvVars("TALLY_DIFF_ONLY"),
+		},
+		&cli.BoolFlag{
+			Name:  "diff-file-level",
+			Usage: "Keep file-level violations of changed files with --diff-only",
+			Value: true,
+		},
+		&cli.StringFlag{
+			Name:    "baseline",
+			Usage:   "Only report violations not recorded in this baseline file",
+			Sources: cli.EnvVars("TALLY_BASELINE"),
+		},
+		&cli.StringFlag{
+			Name:  "write-baseline",
+			Usage: "Record all current violations in a baseline file and exit",
+		},
+		&cli.BoolFlag{
+			Name:  "baseline-show-fixed",
+			Usage: "List baseline entries that no longer match any violation",
+		},
+		&cli.BoolFlag{
+			Name:    "fix",
+			Usage:   "Apply all safe fixes automatically",
+			Sources: cli.EnvVars("TALLY_FIX"),
+		},
+		&cli.StringSliceFlag{
+			Name:    "fix-rule",
+			Usage:   "Only fix specific rules (can be repeated)",
+			Sources: cli.EnvVars("TALLY_FIX_RULE"),
+		},
+		&cli.BoolFlag{
+			Name:    "fix-unsafe",
+			Usage:   "Also apply suggestion/unsafe fixes (requires --fix)",
+			Sources: cli.EnvVars("TALLY_FIX_UNSAFE"),
+		},
+		&cli.BoolFlag{
+			Name:    "ai",
+			Usage:   "Enable AI AutoFix (requires an ACP agent command)",
+			Sources: cli.EnvVars("TALLY_AI_ENABLED"),
+		},
+		&cli.StringFlag{
+			Name:    "acp-command",
+			Usage:   "ACP agent command line (e.g. \"gemini --experimental-acp\")",
+			Sources: cli.EnvVars("TALLY_ACP_COMMAND"),
+		},
+		&cli.StringFlag{
+			Name:    "ai-timeout",
+			Usage:   "Per-fix AI timeout (e.g., 90s)",
+			Sources: cli.EnvVars("TALLY_AI_TIMEOUT"),
+		},
+		&cli.IntFlag{
+			Name:    "ai-max-input-bytes",
+			Usage:   "Maximum prompt size in bytes",
+			Sources: cli.EnvVars("TALLY_AI_MAX_INPUT_BYTES"),
+		},
+		&cli.BoolFlag{
+			Name:    "ai-redact-secrets",
+			Usage:   "Redact obvious secrets before sending content to the agent",
+			Value:   true,
+			Sources: cli.EnvVars("TALLY_AI_REDACT_SECRETS"),
 		},
-		Action: runLint,
 	}
 }
 
@@ -296,12 +364,32 @@ func runLint(ctx stdcontext.Context, cmd *cli.Command) error {
 		inputs = []string{"."}
 	}
 
+	if cmd.String("write-baseline") != "" && cmd.Bool("fix") {
+		fmt.Fprintf(os.Stderr, "Error: --write-baseline cannot be combined with --fix\n")
+		return cli.Exit("", ExitConfigError)
+	}
+
+	baselineFilter, err := loadBaselineFilter(cmd)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return cli.Exit("", ExitConfigError)
+	}
+
+	changes, changedSince, err := loadGitChanges(ctx, cmd, inputs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return cli.Exit("", ExitConfigError)
+	}
+
 	// Discover files using the discovery package
 	discoveryOpts := discovery.Options{
 		Patterns:        discovery.DefaultPatterns(),
 		ExcludePatterns: cmd.StringSlice("exclude"),
 		ContextDir:      cmd.String("context"),
 	}
+	if changes != nil {
+		discoveryOpts.Include = changes.FileChanged
+	}
 
 	discovered, err := discovery.Discover(inputs, discoveryOpts)
 	if err != nil {
@@ -309,6 +397,10 @@ func runLint(ctx stdcontext.Context, cmd *cli.Command) error {
 		return cli.Exit("", ExitConfigError)
 	}
 
+	if len(discovered) == 0 && changes != nil {
+		fmt.Fprintf(os.Stderr, "note: no Dockerfiles changed since %s\n", changedSince)
+		return nil
+	}
 	if len(discovered) == 0 {
 		fmt.Fprintf(os.Stderr, "Error: no Dockerfiles found\n")
 		return cli.Exit("", ExitConfigError)
@@ -327,21 +419,40 @@ func runLint(ctx stdcontext.Context, cmd *cli.Command) error {
 		asyncPlans  []async.CheckRequest
 	)
 	if len(res.asyncPlans) > 0 {
-		asyncResult, asyncPlans = runAsyncChecks(ctx, res)
+		asyncResult, asyncPlans = runAsyncChecks(ctx, cmd, res)
 		if asyncResult != nil {
-			res.violations = mergeAsyncViolations(res.violations, asyncResult)
+			res.violations = linter.MergeAsyncViolations(res.violations, asyncResult)
 		}
 	}
 
 	// Build processor chain for violation processing.
 	// Each file gets its own config for rule enable/disable, severity, etc.
-	chain, inlineFilter := linter.CLIProcessors()
+	var diffFilter *processor.DiffFilter
+	if changes != nil && cmd.Bool("diff-only") {
+		diffFilter = processor.NewDiffFilter(changes, cmd.Bool("diff-file-level"))
+	}
+	chain, inlineFilter := linter.CLIProcessors(
+		linter.WithDiffFilter(diffFilter),
+		linter.WithBaselineFilter(baselineFilter),
+	)
 	procCtx := processor.NewContext(res.fileConfigs, res.firstCfg, res.fileSources)
 	allViolations := chain.Process(res.violations, procCtx)
 
+	// Directive diagnostics below are about the comments themselves and are
+	// never recorded in a baseline.
+	if path := cmd.String("write-baseline"); path != "" {
+		return writeBaseline(path, allViolations, procCtx)
+	}
+	if baselineFilter != nil {
+		reportFixedBaseline(baselineFilter.Fixed(), cmd.Bool("baseline-show-fixed"))
+	}
+
 	// Add any additional violations from the inline directive filter
 	// (parse errors, unused directives, missing reasons)
 	additionalViolations := inlineFilter.AdditionalViolations()
+	if diffFilter != nil {
+		additionalViolations = diffFilter.Process(additionalViolations, procCtx)
+	}
 	if len(additionalViolations) > 0 {
 		additionalViolations = processor.NewPathNormalization().Process(additionalViolations, procCtx)
 		additionalViolations = processor.NewSnippetAttachment().Process(additionalViolations, procCtx)
@@ -376,13 +487,19 @@ func runLint(ctx stdcontext.Context, cmd *cli.Command) error {
 }
 
 // lintFiles runs the lint pipeline on each discovered file and aggregates results.
+//
+// Configs are loaded and validated sequentially so that config warnings are
+// printed in discovery order. Parsing and rule execution then run on a bounded
+// worker pool (--jobs); results are merged in discovery order so output stays
+// deterministic regardless of scheduling.
 func lintFiles(ctx stdcontext.Context, discovered []discovery.DiscoveredFile, cmd *cli.Command) (*lintResults, error) {
 	res := &lintResults{
 		fileSources: make(map[string][]byte),
 		fileConfigs: make(map[string]*config.Config),
 	}
 
-	for _, df := range discovered {
+	configs := make([]*config.Config, len(discovered))
+	for i, df := range discovered {
 		file := df.Path
 
 		cfg, err := loadConfigForFile(cmd, file)
@@ -393,41 +510,177 @@ func lintFiles(ctx stdcontext.Context, discovered []discovery.DiscoveredFile, cm
 		validateRuleConfigs(cfg, file)
 		validateAIConfig(cfg, file)
 		validateDurationConfigs(cfg, file)
+		configs[i] = cfg
 		res.fileConfigs[file] = cfg
 
 		if res.firstCfg == nil {
 			res.firstCfg = cfg
 		}
+	}
 
-		// Build context for context-aware rules (e.g. .dockerignore checks).
-		// This requires parsing the Dockerfile first to extract heredoc files.
-		var buildCtx rules.BuildContext
-		if df.ContextDir != "" {
-			parseResult, parseErr := dockerfile.ParseFile(ctx, file, cfg)
-			if parseErr == nil {
-				buildCtx, err = context.New(df.ContextDir, file,
-					context.WithHeredocFiles(extractHeredocFiles(parseResult)))
-				if err != nil {
-					fmt.Fprintf(os.Stderr, "Warning: failed to create build context: %v\n", err)
-				}
-			}
+	// A target from config only warns (tally/unknown-build-target), as it
+	// need not apply to every Dockerfile; an explicit --target must exist.
+	requireTarget := cmd.IsSet("target")
+	outcomes := make([]fileLintOutcome, len(discovered))
+	jobs := min(lintJobs(cmd, res.firstCfg), len(discovered))
+
+	sem := make(chan struct{}, jobs)
+	var wg sync.WaitGroup
+	for i, df := range discovered {
+		wg.Add(1)
+		go func(i int, df discovery.DiscoveredFile) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			outcomes[i] = lintFile(ctx, df, configs[i], openLintCache(cmd, configs[i]), requireTarget)
+		}(i, df)
+	}
+	wg.Wait()
+
+	for i, out := range outcomes {
+		for _, w := range out.warnings {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+		}
+		if out.err != nil {
+			return nil, fmt.Errorf("failed to lint %s: %w", discovered[i].Path, out.err)
+		}
+		file := discovered[i].Path
+		res.fileSources[file] = out.source
+		res.violations = append(res.violations, out.violations...)
+		res.asyncPlans = append(res.asyncPlans, out.asyncPlan...)
+	}
+
+	return res, nil
+}
+
+// fileLintOutcome is the result of linting a single file on the worker pool.
+// Warnings are buffered so they can be printed in discovery order.
+type fileLintOutcome struct {
+	source     []byte
+	violations []rules.Violation
+	asyncPlan  []async.CheckRequest
+	warnings   []string
+	err        error
+}
+
+// lintFile builds the context for one discovered file and runs the lint pipeline,
+// consulting lintCache (if non-nil) first. With requireTarget, a build target
+// that is not a stage of the file is an error.
+// It is safe to call concurrently for different files.
+func lintFile(
+	ctx stdcontext.Context, df discovery.DiscoveredFile, cfg *config.Config, lintCache *lintcache.Cache,
+	requireTarget bool,
+) fileLintOutcome {
+	var out fileLintOutcome
+	file := df.Path
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		out.err = err
+		return out
+	}
+	out.source = content
+
+	var analysis *linter.Analysis
+	if requireTarget {
+		analysis, err = linter.Analyze(file, content, cfg)
+		if err != nil {
+			out.err = err
+			return out
+		}
+		if analysis.UnknownTarget != "" {
+			out.err = fmt.Errorf("target stage %q could not be found", analysis.UnknownTarget)
+			return out
 		}
+	}
 
-		result, err := linter.LintFile(linter.Input{
-			FilePath:     file,
+	// Results of context-aware rules depend on files outside the Dockerfile,
+	// so only context-free runs are cached.
+	var cacheKey string
+	if lintCache != nil && df.ContextDir == "" {
+		cacheKey, err = lintcache.Key(lintcache.KeyInput{
+			Path:         file,
+			Content:      content,
 			Config:       cfg,
-			BuildContext: buildCtx,
+			EnabledRules: linter.EnabledRuleCodes(cfg),
 		})
 		if err != nil {
-			return nil, fmt.Errorf("failed to lint %s: %w", file, err)
+			out.warnings = append(out.warnings, fmt.Sprintf("lint cache disabled for %s: %v", file, err))
+		} else if entry, ok := lintCache.Get(cacheKey); ok {
+			// Async check handlers are not persisted: when the checks will
+			// run, re-plan them instead of linting the file again.
+			asyncPlan := entry.AsyncPlan
+			if len(asyncPlan) > 0 && cfg.SlowChecks.Enabled() {
+				asyncPlan, err = linter.PlanAsync(file, content, cfg)
+			}
+			if err == nil {
+				out.violations = entry.Violations
+				out.asyncPlan = asyncPlan
+				return out
+			}
 		}
+	}
 
-		res.fileSources[file] = result.ParseResult.Source
-		res.violations = append(res.violations, result.Violations...)
-		res.asyncPlans = append(res.asyncPlans, result.AsyncPlan...)
+	// Build context for context-aware rules (e.g. .dockerignore checks).
+	// This requires parsing the Dockerfile first to extract heredoc files.
+	var buildCtx rules.BuildContext
+	if df.ContextDir != "" {
+		parseResult, parseErr := dockerfile.ParseFile(ctx, file, cfg)
+		if parseErr == nil {
+			buildCtx, err = context.New(df.ContextDir, file,
+				context.WithHeredocFiles(extractHeredocFiles(parseResult)))
+			if err != nil {
+				out.warnings = append(out.warnings, fmt.Sprintf("failed to create build context: %v", err))
+			}
+		}
 	}
 
-	return res, nil
+	result, err := linter.LintFile(linter.Input{
+		FilePath:     file,
+		Content:      content,
+		Config:       cfg,
+		BuildContext: buildCtx,
+		Analysis:     analysis,
+	})
+	if err != nil {
+		out.err = err
+		return out
+	}
+	out.violations = result.Violations
+	out.asyncPlan = result.AsyncPlan
+
+	if cacheKey != "" {
+		entry := &lintcache.Entry{Violations: result.Violations, AsyncPlan: result.AsyncPlan}
+		if err := lintCache.Put(cacheKey, entry); err != nil {
+			out.warnings = append(out.warnings, fmt.Sprintf("failed to write lint cache: %v", err))
+		}
+	}
+	return out
+}
+
+// openLintCache returns the lint result cache configured by cfg,
+// or nil when caching is disabled by --no-cache or the config.
+func openLintCache(cmd *cli.Command, cfg *config.Config) *lintcache.Cache {
+	if cmd.Bool("no-cache") || cfg == nil || !cfg.Cache.Enabled {
+		return nil
+	}
+	return lintcache.New(cfg.CacheDir(""))
+}
+
+// lintJobs returns the number of files to lint concurrently.
+// --jobs takes precedence over the config file; zero or less means one job per CPU.
+func lintJobs(cmd *cli.Command, cfg *config.Config) int {
+	jobs := 0
+	if cfg != nil {
+		jobs = cfg.Jobs
+	}
+	if cmd.IsSet("jobs") {
+		jobs = cmd.Int("jobs")
+	}
+	if jobs <= 0 {
+		jobs = runtime.NumCPU()
+	}
+	return jobs
 }
 
 // writeReport formats and writes the violation report.
@@ -505,6 +758,7 @@ func loadConfigForFile(cmd *cli.Command, targetPath string) (*config.Config, err
 		if err != nil {
 			return nil, err
 		}
+		cfg.ApplyOverrides(targetPath)
 	} else {
 		// Auto-discover config file based on target path
 		cfg, err = config.Load(targetPath)
@@ -513,6 +767,15 @@ func loadConfigForFile(cmd *cli.Command, targetPath string) (*config.Config, err
 		}
 	}
 
+	if err := applyConfigFlags(cmd, cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// applyConfigFlags applies the CLI flags that override config values to cfg.
+// Output flags are handled separately by getOutputConfig.
+func applyConfigFlags(cmd *cli.Command, cfg *config.Config) error {
 	// Apply CLI flag overrides for max-lines rule
 	// Only override if the flag was explicitly set
 	if cmd.IsSet("max-lines") || cmd.IsSet("skip-blank-lines") || cmd.IsSet("skip-comments") {
@@ -542,12 +805,13 @@ func loadConfigForFile(cmd *cli.Command, targetPath string) (*config.Config, err
 		}
 	}
 
-	// Apply rule selection overrides from CLI flags
+	// Apply rule selection overrides from CLI flags. They form the last
+	// selection layer, so they also win over matching [[overrides]].
 	if cmd.IsSet("select") {
-		cfg.Rules.Include = append(cfg.Rules.Include, cmd.StringSlice("select")...)
+		cfg.Rules.Selected.Include = append(cfg.Rules.Selected.Include, cmd.StringSlice("select")...)
 	}
 	if cmd.IsSet("ignore") {
-		cfg.Rules.Exclude = append(cfg.Rules.Exclude, cmd.StringSlice("ignore")...)
+		cfg.Rules.Selected.Exclude = append(cfg.Rules.Selected.Exclude, cmd.StringSlice("ignore")...)
 	}
 
 	// Output settings are handled in getOutputConfig to avoid duplication
@@ -565,6 +829,11 @@ func loadConfigForFile(cmd *cli.Command, targetPath string) (*config.Config, err
 		cfg.InlineDirectives.RequireReason = cmd.Bool("require-reason")
 	}
 
+	// Apply build invocation overrides (--build-arg-file, then --build-arg)
+	if err := applyBuildOverrides(cmd, cfg); err != nil {
+		return err
+	}
+
 	// Apply slow-checks CLI overrides
 	if cmd.IsSet("slow-checks") {
 		cfg.SlowChecks.Mode = cmd.String("slow-checks")
@@ -572,6 +841,9 @@ func loadConfigForFile(cmd *cli.Command, targetPath string) (*config.Config, err
 	if cmd.IsSet("slow-checks-timeout") {
 		cfg.SlowChecks.Timeout = cmd.String("slow-checks-timeout")
 	}
+	if cmd.IsSet("offline") {
+		cfg.SlowChecks.Offline = cmd.Bool("offline")
+	}
 
 	// Apply AI CLI overrides
 	if cmd.IsSet("ai") {
@@ -580,7 +852,7 @@ func loadConfigForFile(cmd *cli.Command, targetPath string) (*config.Config, err
 	if cmd.IsSet("acp-command") {
 		argv, err := parseACPCmd(cmd.String("acp-command"))
 		if err != nil {
-			return nil, err
+			return err
 		}
 		cfg.AI.Command = argv
 		cfg.AI.Enabled = true
@@ -595,7 +867,64 @@ func loadConfigForFile(cmd *cli.Command, targetPath string) (*config.Config, err
 		cfg.AI.RedactSecrets = cmd.Bool("ai-redact-secrets")
 	}
 
-	return cfg, nil
+	return nil
+}
+
+// applyBuildOverrides merges CLI build args and target into cfg.Build.
+// Precedence (lowest to highest): config [build.args], --build-arg-file, --build-arg.
+func applyBuildOverrides(cmd *cli.Command, cfg *config.Config) error {
+	var argFiles, args []string
+	if cmd.IsSet("build-arg-file") {
+		argFiles = cmd.StringSlice("build-arg-file")
+	}
+	if cmd.IsSet("build-arg") {
+		args, _ = cmd.Value("build-arg").([]string)
+	}
+
+	if len(argFiles) > 0 || len(args) > 0 {
+		merged := maps.Clone(cfg.Build.Args)
+		if merged == nil {
+			merged = make(map[string]string)
+		}
+		for _, path := range argFiles {
+			fileArgs, err := config.LoadBuildArgFile(path)
+			if err != nil {
+				return fmt.Errorf("failed to read build arg file: %w", err)
+			}
+			maps.Copy(merged, fileArgs)
+		}
+		cliArgs, err := config.ParseBuildArgs(args)
+		if err != nil {
+			return err
+		}
+		maps.Copy(merged, cliArgs)
+		cfg.Build.Args = merged
+	}
+
+	if cmd.IsSet("target") {
+		cfg.Build.Target = cmd.String("target")
+	}
+	return nil
+}
+
+// repeatedStringValue collects every occurrence of a repeatable flag verbatim.
+// Unlike cli.StringSliceFlag it does not split on commas, which matters for
+// values such as --build-arg PLATFORMS=linux/amd64,linux/arm64.
+type repeatedStringValue struct {
+	values []string
+}
+
+func (v *repeatedStringValue) Set(s string) error {
+	v.values = append(v.values, s)
+	return nil
+}
+
+func (v *repeatedStringValue) String() string {
+	return strings.Join(v.values, ", ")
+}
+
+func (v *repeatedStringValue) Get() any {
+	return v.values
 }
 
 func parseACPCmd(commandLine string) ([]string, error) {
@@ -890,6 +1219,11 @@ func validateDurationConfigs(cfg *config.Config, file string) {
 			fmt.Fprintf(os.Stderr, "Warning: invalid slow-checks.timeout %q (%s): %v\n", t, source, err)
 		}
 	}
+	if t := cfg.SlowChecks.CacheTTL; t != "" {
+		if _, err := time.ParseDuration(t); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: invalid slow-checks.cache-ttl %q (%s): %v\n", t, source, err)
+		}
+	}
 	if t := cfg.AI.Timeout; t != "" {
 		if _, err := time.ParseDuration(t); err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: invalid ai.timeout %q (%s): %v\n", t, source, err)
@@ -1026,7 +1360,7 @@ func buildPerFileFixModes(fileConfigs map[string]*config.Config) map[string]map[
 // runAsyncChecks executes async check plans if slow checks are enabled.
 // Returns nil if slow checks are disabled or no plans exist.
 // Respects per-file slow-checks configuration from res.fileConfigs.
-func runAsyncChecks(ctx stdcontext.Context, res *lintResults) (*async.RunResult, []async.CheckRequest) {
+func runAsyncChecks(ctx stdcontext.Context, cmd *cli.Command, res *lintResults) (*async.RunResult, []async.CheckRequest) {
 	if len(res.asyncPlans) == 0 {
 		return nil, nil
 	}
@@ -1037,11 +1371,11 @@ func runAsyncChecks(ctx stdcontext.Context, res *lintResults) (*async.RunResult,
 	}
 
 	// Register the registry resolver (once per invocation).
-	if registry.NewDefaultResolver == nil {
+	imgResolver := newImageResolver(cmd, res.firstCfg)
+	if imgResolver == nil {
 		fmt.Fprintf(os.Stderr, "note: slow checks not available (missing build tags)\n")
 		return nil, nil
 	}
-	imgResolver := registry.NewDefaultResolver()
 	asyncImgResolver := registry.NewAsyncImageResolver(imgResolver)
 
 	rt := &async.Runtime{
@@ -1057,6 +1391,42 @@ func runAsyncChecks(ctx stdcontext.Context, res *lintResults) (*async.RunResult,
 	return result, plans
 }
 
+// newImageResolver returns the registry resolver for slow checks. Results are
+// cached on disk under the [cache] directory unless caching is disabled; in
+// offline mode only that cache is consulted. Returns nil when no resolver is
+// available (binary built without registry support and not offline).
+func newImageResolver(cmd *cli.Command, cfg *config.Config) registry.ImageResolver {
+	slowCfg := cfg.SlowChecks
+
+	var inner registry.ImageResolver
+	if !slowCfg.Offline && registry.NewDefaultResolver != nil {
+		inner = registry.NewDefaultResolver()
+	}
+	if inner == nil && !slowCfg.Offline {
+		return nil
+	}
+
+	var cacheDir string
+	if lintCache := openLintCache(cmd, cfg); lintCache != nil {
+		dir, err := lintCache.SubDir("registry")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: registry metadata cache disabled: %v\n", err)
+		}
+		cacheDir = dir
+	}
+	if cacheDir == "" && !slowCfg.Offline {
+		return inner
+	}
+
+	ttl := registry.DefaultCacheTTL
+	if d, err := time.ParseDuration(slowCfg.CacheTTL); err == nil {
+		ttl = d
+	}
+	return registry.NewCachingResolver(inner, cacheDir,
+		registry.WithCacheTTL(ttl),
+		registry.WithOffline(slowCfg.Offline))
+}
+
 // filterAsyncPlans applies per-file slow-checks policy to async plans.
 // Returns the filtered plans and the maximum timeout across all enabled files.
 func filterAsyncPlans(res *lintResults) ([]async.CheckRequest, time.Duration) {
@@ -1081,7 +1451,7 @@ func filterAsyncPlans(res *lintResults) ([]async.CheckRequest, time.Duration) {
 		}
 
 		slowCfg := cfg.SlowChecks
-		if !config.SlowChecksEnabled(slowCfg.Mode) {
+		if !slowCfg.Enabled() {
 			if slowCfg.Mode == "auto" {
 				skippedAuto++
 			}
@@ -1122,9 +1492,17 @@ func reportSkipped(result *async.RunResult) {
 		return
 	}
 	counts := make(map[async.SkipReason]int)
+	var offline int
 	for _, s := range result.Skipped {
+		if errors.Is(s.Err, registry.ErrOffline) {
+			offline++
+			continue
+		}
 		counts[s.Reason]++
 	}
+	if offline > 0 {
+		fmt.Fprintf(os.Stderr, "note: %d slow check(s) skipped (offline, image metadata not cached)\n", offline)
+	}
 	if n := counts[async.SkipTimeout]; n > 0 {
 		fmt.Fprintf(os.Stderr, "note: %d slow check(s) timed out (increase --slow-checks-timeout)\n", n)
 	}
@@ -1226,57 +1604,6 @@ func filesWithErrors(violations []rules.Violation) map[string]bool {
 	return m
 }
 
-// mergeAsyncViolations merges async results into the fast violations.
-// For rules with async resolution (e.g. UndefinedVar), fast violations for
-// a (rule, file, stage) triple are replaced by async results when the async
-// check completes — even when it produces zero violations (eliminating false
-// positives from the fast path). Stage-level granularity ensures that fast
-// violations from non-async stages in the same file are preserved.
-func mergeAsyncViolations(fast []rules.Violation, asyncResult *async.RunResult) []rules.Violation {
-	if asyncResult == nil {
-		return fast
-	}
-
-	// Convert []any to []rules.Violation.
-	var asyncViolations []rules.Violation
-	for _, v := range asyncResult.Violations {
-		if viol, ok := v.(rules.Violation); ok {
-			asyncViolations = append(asyncViolations, viol)
-		}
-	}
-
-	if len(asyncResult.Completed) == 0 && len(asyncViolations) == 0 {
-		return fast
-	}
-
-	// Build set of (rule, file, stage) triples that completed async resolution.
-	// Fast violations for these triples are replaced by async results.
-	// Stage-level granularity ensures that fast violations from non-async stages
-	// in the same file are preserved.
-	type ruleFileStage struct {
-		ruleCode   string
-		file       string
-		stageIndex int
-	}
-	completedSet := make(map[ruleFileStage]bool)
-	for _, c := range asyncResult.Completed {
-		completedSet[ruleFileStage{ruleCode: c.RuleCode, file: c.File, stageIndex: c.StageIndex}] = true
-	}
-
-	// Filter out fast violations that were superseded by async results.
-	var merged []rules.Violation
-	for _, v := range fast {
-		if completedSet[ruleFileStage{ruleCode: v.RuleCode, file: v.File(), stageIndex: v.StageIndex}] {
-			continue // replaced by async result
-		}
-		merged = append(merged, v)
-	}
-
-	// Append all async violations.
-	merged = append(merged, asyncViolations...)
-	return merged
-}
-
 // filterFixedViolations removes violations that were fixed from the list.
 func filterFixedViolations(violations []rules.Violation, fixResult *fix.Result) []rules.Violation {
 	// Build set of fixed locations (include column to handle multiple violations on same line)
@@ -1315,3 +1642,45 @@ func filterFixedViolations(violations []rules.Violation, fixResult *fix.Result)
 	}
 	return remaining
 }
+
+// loadGitChanges reads changes from the git repositories of the inputs for
+// --changed-since and --diff-only. It returns nil changes when neither is set.
+// --diff-only without --changed-since compares against HEAD.
+func loadGitChanges(ctx stdcontext.Context, cmd *cli.Command, inputs []string) (*gitdiff.Changes, string, error) {
+	rev := cmd.String("changed-since")
+	if rev == "" {
+		if !cmd.Bool("diff-only") {
+			return nil, "", nil
+		}
+		rev = "HEAD"
+	}
+	dirs := make([]string, 0, len(inputs))
+	for _, input := range inputs {
+		dirs = append(dirs, inputDir(input))
+	}
+	changes, err := gitdiff.LoadAll(ctx, dirs, rev)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read git changes: %w", err)
+	}
+	return changes, rev, nil
+}
+
+// inputDir returns the directory git should run in for a lint input: the
+// input itself for directories, the containing directory for files, and the
+// nearest existing parent for glob patterns.
+func inputDir(input string) string {
+	path := filepath.Clean(input)
+	for {
+		if fi, err := os.Stat(path); err == nil {
+			if fi.IsDir() {
+				return path
+			}
+			return filepath.Dir(path)
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return path
+		}
+		path = parent
+	}
+}
@@ -1,8 +1,18 @@
 package cmd
 
 import (
+	"context"
+	"os"
+	"path/filepath"
 	"slices"
+	"strings"
 	"testing"
+
+	"github.com/tinovyatkin/tally/internal/config"
+	"github.com/tinovyatkin/tally/internal/discovery"
+	"github.com/tinovyatkin/tally/internal/lintcache"
+	"github.com/tinovyatkin/tally/internal/linter"
+	"github.com/tinovyatkin/tally/internal/rules"
 )
 
 func TestParseACPCmd(t *testing.T) {
@@ -98,3 +108,115 @@ func TestParseACPCmd(t *testing.T) {
 		})
 	}
 }
+
+func TestLintFile_CacheHitWithAsyncPlan(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "Dockerfile")
+	if err := os.WriteFile(file, []byte("FROM alpine:3.20\nRUN echo hi\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.Default()
+	cfg.SlowChecks.Mode = "auto"
+	// Offline keeps auto mode enabled when the test itself runs in CI.
+	cfg.SlowChecks.Offline = true
+	lintCache := lintcache.New(filepath.Join(dir, ".tally_cache"))
+	df := discovery.DiscoveredFile{Path: file}
+
+	first := lintFile(context.Background(), df, cfg, lintCache, false)
+	if first.err != nil {
+		t.Fatalf("first lint: %v", first.err)
+	}
+	if len(first.asyncPlan) == 0 {
+		t.Fatal("expected async checks for a registry base image")
+	}
+
+	// Replace the cached violations so that a hit is observable.
+	key, err := lintcache.Key(lintcache.KeyInput{
+		Path: file, Content: first.source, Config: cfg, EnabledRules: linter.EnabledRuleCodes(cfg),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, ok := lintCache.Get(key)
+	if !ok {
+		t.Fatal("first lint did not write a cache entry")
+	}
+	entry.Violations = []rules.Violation{
+		rules.NewViolation(rules.NewFileLocation(file), "cached", "from cache", rules.SeverityInfo),
+	}
+	if err := lintCache.Put(key, entry); err != nil {
+		t.Fatal(err)
+	}
+
+	second := lintFile(context.Background(), df, cfg, lintCache, false)
+	if second.err != nil {
+		t.Fatalf("second lint: %v", second.err)
+	}
+	if len(second.violations) != 1 || second.violations[0].RuleCode != "cached" {
+		t.Fatalf("second lint was not served from the cache: %v", second.violations)
+	}
+	if len(second.asyncPlan) != len(first.asyncPlan) {
+		t.Fatalf("async plan has %d checks, want %d", len(second.asyncPlan), len(first.asyncPlan))
+	}
+	for _, req := range second.asyncPlan {
+		if req.Handler == nil {
+			t.Errorf("re-planned check %s has no result handler", req.Key)
+		}
+	}
+}
+
+func TestInputDir(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "Dockerfile")
+	if err := os.WriteFile(file, []byte("FROM alpine\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]string{
+		dir:                                    dir,
+		file:                                   dir,
+		filepath.Join(dir, "**", "Dockerfile"): dir,
+	}
+	for input, want := range tests {
+		if got := inputDir(input); got != want {
+			t.Errorf("inputDir(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestLintFile_RequireTarget(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "Dockerfile")
+	if err := os.WriteFile(file, []byte("FROM alpine:3.20 AS build\nFROM alpine:3.20\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.Default()
+	cfg.Build.Target = "release"
+	df := discovery.DiscoveredFile{Path: file}
+
+	// A target from config is reported by tally/unknown-build-target.
+	out := lintFile(context.Background(), df, cfg, nil, false)
+	if out.err != nil {
+		t.Fatalf("lint with a config target: %v", out.err)
+	}
+	if !slices.ContainsFunc(out.violations, func(v rules.Violation) bool {
+		return v.RuleCode == "tally/unknown-build-target"
+	}) {
+		t.Errorf("no tally/unknown-build-target violation in %v", out.violations)
+	}
+
+	// An explicit --target must exist.
+	out = lintFile(context.Background(), df, cfg, nil, true)
+	if out.err == nil || !strings.Contains(out.err.Error(), `target stage "release" could not be found`) {
+		t.Errorf("lint with an explicit target: err = %v, want target not found", out.err)
+	}
+
+	cfg.Build.Target = "build"
+	if out = lintFile(context.Background(), df, cfg, nil, true); out.err != nil {
+		t.Errorf("lint with an existing explicit target: %v", out.err)
+	}
+}
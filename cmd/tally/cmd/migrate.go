@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/tinovyatkin/tally/internal/config"
+	"github.com/tinovyatkin/tally/internal/migrate"
+)
+
+func migrateCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "migrate",
+		Usage: "Convert other linters' configuration to tally configuration",
+		Commands: []*cli.Command{
+			{
+				Name:      "hadolint",
+				Usage:     "Convert a .hadolint.yaml file to .tally.toml",
+				ArgsUsage: "[path]",
+				Description: `Reads a hadolint configuration file (or looks for .hadolint.yaml /
+.hadolint.yml in the given directory, default ".") and writes an equivalent
+.tally.toml next to it. Settings and rules tally does not support are
+reported and listed as comments in the generated file.`,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "output",
+						Aliases: []string{"o"},
+						Usage:   "Output path (default: .tally.toml next to the hadolint config; - for stdout)",
+					},
+					&cli.BoolFlag{
+						Name:  "force",
+						Usage: "Overwrite an existing output file",
+					},
+				},
+				Action: migrateHadolintAction,
+			},
+		},
+	}
+}
+
+func migrateHadolintAction(_ context.Context, cmd *cli.Command) error {
+	path := "."
+	if cmd.Args().Len() > 0 {
+		path = cmd.Args().First()
+	}
+	source, err := migrate.FindHadolintConfig(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return cli.Exit("", ExitConfigError)
+	}
+	data, err := os.ReadFile(source)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return cli.Exit("", ExitConfigError)
+	}
+	res, err := migrate.FromHadolint(data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s: %v\n", source, err)
+		return cli.Exit("", ExitConfigError)
+	}
+
+	out := res.TOML(filepath.Base(source))
+	target := cmd.String("output")
+	if target == "-" {
+		printUnsupported(res.Unsupported)
+		_, err := os.Stdout.Write(out)
+		return err
+	}
+	if target == "" {
+		target = filepath.Join(filepath.Dir(source), config.ConfigFileNames[0])
+	}
+	if !cmd.Bool("force") {
+		if _, err := os.Stat(target); err == nil {
+			fmt.Fprintf(os.Stderr, "Error: %s already exists (use --force to overwrite)\n", target)
+			return cli.Exit("", ExitConfigError)
+		} else if !errors.Is(err, os.ErrNotExist) {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return cli.Exit("", ExitConfigError)
+		}
+	}
+	printUnsupported(res.Unsupported)
+	if err := os.WriteFile(target, out, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to write %s: %v\n", target, err)
+		return cli.Exit("", ExitConfigError)
+	}
+	fmt.Printf("Wrote %s (migrated from %s)\n", target, source)
+	return nil
+}
+
+func printUnsupported(messages []string) {
+	for _, msg := range messages {
+		fmt.Fprintf(os.Stderr, "Warning: not migrated: %s\n", msg)
+	}
+}
@@ -26,6 +26,10 @@ Examples:
   tally lint .`,
 		Commands: []*cli.Command{
 			lintCommand(),
+			cacheCommand(),
+			configCommand(),
+			rulesCommand(),
+			migrateCommand(),
 			lspCommand(),
 			versionCommand(),
 		},
@@ -0,0 +1,298 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/tinovyatkin/tally/internal/config"
+	"github.com/tinovyatkin/tally/internal/linter"
+	"github.com/tinovyatkin/tally/internal/rules"
+)
+
+func rulesCommand() *cli.Command {
+	// Flags keep parsed state, so each subcommand gets its own instances.
+	configFlag := func() cli.Flag {
+		return &cli.StringFlag{
+			Name:    "config",
+			Aliases: []string{"c"},
+			Usage:   "Path to config file (default: auto-discover from the current directory)",
+		}
+	}
+	formatFlag := func() cli.Flag {
+		return &cli.StringFlag{
+			Name:    "format",
+			Aliases: []string{"f"},
+			Usage:   "Output format: text, json",
+			Value:   "text",
+		}
+	}
+
+	return &cli.Command{
+		Name:  "rules",
+		Usage: "List and explain lint rules",
+		Commands: []*cli.Command{
+			{
+				Name:  "list",
+				Usage: "List available rules",
+				Flags: []cli.Flag{
+					&cli.StringSliceFlag{
+						Name:  "namespace",
+						Usage: "Only list rules in these namespaces (tally, buildkit, hadolint)",
+					},
+					&cli.StringSliceFlag{
+						Name:  "category",
+						Usage: "Only list rules in these categories",
+					},
+					&cli.BoolFlag{
+						Name:  "fixable",
+						Usage: "Only list rules with auto-fixes (--fixable=false for rules without)",
+					},
+					&cli.BoolFlag{
+						Name:  "experimental",
+						Usage: "Only list experimental rules (--experimental=false for stable rules)",
+					},
+					&cli.BoolFlag{
+						Name:  "enabled",
+						Usage: "Only list rules enabled by the effective config (--enabled=false for disabled rules)",
+					},
+					formatFlag(),
+					configFlag(),
+				},
+				Action: rulesListAction,
+			},
+			{
+				Name:      "explain",
+				Usage:     "Show details, default configuration and schema for a rule",
+				ArgsUsage: "<code>",
+				Flags:     []cli.Flag{formatFlag(), configFlag()},
+				Action:    rulesExplainAction,
+			},
+		},
+	}
+}
+
+// ruleSummary is the JSON representation of a rule in tally rules output.
+type ruleSummary struct {
+	Code            string         `json:"code"`
+	Name            string         `json:"name"`
+	Description     string         `json:"description"`
+	DocURL          string         `json:"docUrl,omitempty"`
+	Category        string         `json:"category"`
+	DefaultSeverity rules.Severity `json:"defaultSeverity"`
+	Severity        rules.Severity `json:"severity"`
+	Enabled         bool           `json:"enabled"`
+	Experimental    bool           `json:"experimental"`
+	Fixable         bool           `json:"fixable"`
+	FixSafety       string         `json:"fixSafety,omitempty"`
+}
+
+// ruleDetails extends ruleSummary with configuration information for explain.
+type ruleDetails struct {
+	ruleSummary
+
+	DefaultConfig any            `json:"defaultConfig,omitempty"`
+	Schema        map[string]any `json:"schema,omitempty"`
+}
+
+func newRuleSummary(ri linter.RuleInfo, cfg *config.Config) ruleSummary {
+	sev, enabled := linter.EffectiveSeverity(ri.RuleMetadata, cfg)
+	s := ruleSummary{
+		Code:            ri.Code,
+		Name:            ri.Name,
+		Description:     ri.Description,
+		DocURL:          ri.DocURL,
+		Category:        ri.Category,
+		DefaultSeverity: ri.DefaultSeverity,
+		Severity:        sev,
+		Enabled:         enabled,
+		Experimental:    ri.IsExperimental,
+		Fixable:         ri.Fixable,
+	}
+	if ri.Fixable {
+		s.FixSafety = ri.FixSafety.String()
+	}
+	return s
+}
+
+func rulesListAction(_ context.Context, cmd *cli.Command) error {
+	format, err := rulesFormat(cmd)
+	if err != nil {
+		return err
+	}
+	cfg, err := loadWorkingDirConfig(cmd.String("config"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to load config: %v\n", err)
+		return cli.Exit("", ExitConfigError)
+	}
+
+	namespaces := cmd.StringSlice("namespace")
+	categories := cmd.StringSlice("category")
+	var out []ruleSummary
+	for _, ri := range linter.Catalog() {
+		if len(namespaces) > 0 && !containsFold(namespaces, ri.Namespace()) {
+			continue
+		}
+		if len(categories) > 0 && !containsFold(categories, ri.Category) {
+			continue
+		}
+		s := newRuleSummary(ri, cfg)
+		if cmd.IsSet("fixable") && s.Fixable != cmd.Bool("fixable") {
+			continue
+		}
+		if cmd.IsSet("experimental") && s.Experimental != cmd.Bool("experimental") {
+			continue
+		}
+		if cmd.IsSet("enabled") && s.Enabled != cmd.Bool("enabled") {
+			continue
+		}
+		out = append(out, s)
+	}
+
+	if format == "json" {
+		if out == nil {
+			out = []ruleSummary{}
+		}
+		return writeRulesJSON(os.Stdout, out)
+	}
+	return writeRulesTable(os.Stdout, out)
+}
+
+func rulesExplainAction(_ context.Context, cmd *cli.Command) error {
+	format, err := rulesFormat(cmd)
+	if err != nil {
+		return err
+	}
+	if cmd.Args().Len() != 1 {
+		fmt.Fprintln(os.Stderr, "Error: expected exactly one rule code, e.g. tally rules explain hadolint/DL3006")
+		return cli.Exit("", ExitConfigError)
+	}
+	code := cmd.Args().First()
+	ri, ok := linter.LookupRule(code)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: unknown rule %q (run 'tally rules list' to see available rules)\n", code)
+		return cli.Exit("", ExitConfigError)
+	}
+	cfg, err := loadWorkingDirConfig(cmd.String("config"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to load config: %v\n", err)
+		return cli.Exit("", ExitConfigError)
+	}
+
+	d := ruleDetails{ruleSummary: newRuleSummary(ri, cfg)}
+	if cr, ok := ri.Rule.(rules.ConfigurableRule); ok {
+		d.DefaultConfig = cr.DefaultConfig()
+		d.Schema = cr.Schema()
+	}
+
+	if format == "json" {
+		return writeRulesJSON(os.Stdout, d)
+	}
+	return writeRuleDetails(os.Stdout, d)
+}
+
+func rulesFormat(cmd *cli.Command) (string, error) {
+	switch format := cmd.String("format"); format {
+	case "text", "json":
+		return format, nil
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unsupported format %q (expected text or json)\n", format)
+		return "", cli.Exit("", ExitConfigError)
+	}
+}
+
+func containsFold(values []string, s string) bool {
+	return slices.ContainsFunc(values, func(v string) bool { return strings.EqualFold(v, s) })
+}
+
+func writeRulesJSON(w io.Writer, v any) error {
+	return json.MarshalWrite(w, v,
+		json.Deterministic(true),
+		jsontext.WithIndentPrefix(""),
+		jsontext.WithIndent("  "),
+	)
+}
+
+func writeRulesTable(w io.Writer, list []ruleSummary) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CODE\tSEVERITY\tCATEGORY\tFIX\tDESCRIPTION")
+	for _, s := range list {
+		fix := "-"
+		if s.Fixable {
+			fix = s.FixSafety
+		}
+		code := s.Code
+		if s.Experimental {
+			code += " (experimental)"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", code, s.Severity, s.Category, fix, s.Description)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "\n%d rules\n", len(list))
+	return err
+}
+
+func writeRuleDetails(w io.Writer, d ruleDetails) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: %s\n\n", d.Code, d.Name)
+	fmt.Fprintf(&b, "%s\n\n", d.Description)
+
+	tw := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Category:\t%s\n", d.Category)
+	fmt.Fprintf(tw, "Default severity:\t%s\n", d.DefaultSeverity)
+	if d.Enabled {
+		fmt.Fprintf(tw, "Effective severity:\t%s\n", d.Severity)
+	} else {
+		fmt.Fprintf(tw, "Effective severity:\toff (rule is disabled)\n")
+	}
+	if d.Fixable {
+		fmt.Fprintf(tw, "Auto-fix:\t%s\n", d.FixSafety)
+	} else {
+		fmt.Fprintf(tw, "Auto-fix:\tnone\n")
+	}
+	if d.Experimental {
+		fmt.Fprintf(tw, "Experimental:\tyes\n")
+	}
+	if d.DocURL != "" {
+		fmt.Fprintf(tw, "Documentation:\t%s\n", d.DocURL)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	type section struct {
+		title string
+		value any
+	}
+	var sections []section
+	if d.DefaultConfig != nil {
+		sections = append(sections, section{"Default configuration", d.DefaultConfig})
+	}
+	if d.Schema != nil {
+		sections = append(sections, section{"Configuration schema", d.Schema})
+	}
+	for _, section := range sections {
+		data, err := json.Marshal(section.value,
+			json.Deterministic(true),
+			jsontext.WithIndentPrefix("  "),
+			jsontext.WithIndent("  "),
+		)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&b, "\n%s:\n  %s\n", section.title, data)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -40,6 +40,13 @@ export class TallyLanguageClient {
       },
       middleware: {
         executeCommand: async (command, args, next) => {
+          if (command === "tally.suppressRule") {
+            const resolvedArgs = await promptSuppressReason(args);
+            if (resolvedArgs.length === 0) {
+              return;
+            }
+            return next(command, resolvedArgs);
+          }
           if (command !== "tally.applyAllFixes") {
             return next(command, args);
           }
@@ -95,6 +102,27 @@ export class TallyLanguageClient {
   }
 }
 
+// promptSuppressReason asks for the reason of an ignore directive, which the
+// server requires when `require-reason` is enabled. Returns no arguments when
+// the prompt is dismissed.
+async function promptSuppressReason(args: unknown[]): Promise<unknown[]> {
+  const [first] = args;
+  if (!first || typeof first !== "object") {
+    return args;
+  }
+
+  const suppress = first as { rule?: unknown };
+  const reason = await vscode.window.showInputBox({
+    title: `Tally: ignore ${String(suppress.rule ?? "rule")}`,
+    prompt: "Why is this rule suppressed?",
+    validateInput: (value) => (value.trim() === "" ? "A reason is required." : undefined),
+  });
+  if (reason === undefined) {
+    return [];
+  }
+  return [{ ...suppress, reason: reason.trim() }];
+}
+
 type WorkspaceEditWire = {
   changes?: Record<string, Array<{ range: unknown; newText: unknown }>>;
 };
@@ -41,6 +41,9 @@ func main() {
 	// Enhance AIConfig with descriptions (kept short in struct tags for lll).
 	enhanceAIConfigSchema(schema)
 
+	// Restrict label-schema values to the known label types.
+	enhanceLabelSchema(schema)
+
 	// Fix required fields - all config fields should be optional
 	fixRequiredFields(schema)
 
@@ -190,3 +193,16 @@ func enhanceAIConfigSchema(schema *jsonschema.Schema) {
 		redactSecrets.Description = "Redact secrets before sending content to agent"
 	}
 }
+
+// enhanceLabelSchema restricts the label-schema values to rules.LabelTypes.
+func enhanceLabelSchema(schema *jsonschema.Schema) {
+	labelSchema, ok := schema.Properties.Get("label-schema")
+	if !ok || labelSchema.AdditionalProperties == nil {
+		return
+	}
+	types := make([]any, len(rules.LabelTypes))
+	for i, typ := range rules.LabelTypes {
+		types[i] = typ
+	}
+	labelSchema.AdditionalProperties.Enum = types
+}
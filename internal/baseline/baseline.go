@@ -0,0 +1,245 @@
+// Package baseline records existing violations so that later runs report
+// only new ones.
+//
+// Entries are keyed by file, rule code and a fingerprint of the source text
+// the violation points at, so they survive unrelated edits that shift lines.
+// Identical violations (same file, rule and text) are tracked with a count.
+// File paths are stored relative to the baseline file's directory.
+package baseline
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/tinovyatkin/tally/internal/rules"
+	"github.com/tinovyatkin/tally/internal/sourcemap"
+)
+
+// formatVersion is the current baseline file format version.
+const formatVersion = 1
+
+// Entry is a group of identical baselined violations.
+type Entry struct {
+	// File is the slash-separated path relative to the baseline file.
+	File string `json:"file"`
+
+	// Rule is the rule code.
+	Rule string `json:"rule"`
+
+	// Fingerprint identifies the source text the violations point at.
+	Fingerprint string `json:"fingerprint"`
+
+	// Count is the number of identical violations.
+	Count int `json:"count"`
+
+	// Message is the first violation's message, for human readers.
+	Message string `json:"message,omitempty"`
+}
+
+// Baseline is a set of grandfathered violations.
+type Baseline struct {
+	Version int     `json:"version"`
+	Entries []Entry `json:"entries"`
+
+	// root is the directory entry paths are relative to.
+	root string
+}
+
+// Key identifies a group of identical violations.
+type Key struct {
+	File        string
+	Rule        string
+	Fingerprint string
+}
+
+// Key returns the entry's lookup key.
+func (e Entry) Key() Key {
+	return Key{File: e.File, Rule: e.Rule, Fingerprint: e.Fingerprint}
+}
+
+// Load reads a baseline file.
+func Load(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var b Baseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("parse baseline %s: %w", path, err)
+	}
+	if b.Version != formatVersion {
+		return nil, fmt.Errorf("baseline %s: unsupported version %d (expected %d)", path, b.Version, formatVersion)
+	}
+	b.root, err = rootDir(path)
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// New creates an empty baseline that will be written to path.
+func New(path string) (*Baseline, error) {
+	root, err := rootDir(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Baseline{Version: formatVersion, root: root}, nil
+}
+
+func rootDir(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Dir(abs), nil
+}
+
+// Add records violations. sourceFor returns the source map of a violation's
+// file (or nil if unavailable) and is used to fingerprint violations.
+func (b *Baseline) Add(violations []rules.Violation, sourceFor func(file string) *sourcemap.SourceMap) {
+	index := make(map[Key]int, len(b.Entries))
+	for i, e := range b.Entries {
+		index[e.Key()] = i
+	}
+	for _, v := range violations {
+		key := b.KeyFor(v, sourceFor(v.Location.File))
+		if i, ok := index[key]; ok {
+			b.Entries[i].Count++
+			continue
+		}
+		index[key] = len(b.Entries)
+		b.Entries = append(b.Entries, Entry{
+			File:        key.File,
+			Rule:        key.Rule,
+			Fingerprint: key.Fingerprint,
+			Count:       1,
+			Message:     v.Message,
+		})
+	}
+}
+
+// Write saves the baseline as indented JSON with entries in a stable order.
+func (b *Baseline) Write(path string) error {
+	slices.SortFunc(b.Entries, func(x, y Entry) int {
+		return cmp.Or(
+			cmp.Compare(x.File, y.File),
+			cmp.Compare(x.Rule, y.Rule),
+			cmp.Compare(x.Fingerprint, y.Fingerprint),
+		)
+	})
+	if b.Entries == nil {
+		b.Entries = []Entry{}
+	}
+	data, err := json.Marshal(b, jsontext.WithIndentPrefix(""), jsontext.WithIndent("  "))
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// KeyFor computes the lookup key of a violation. sm is the source map of the
+// violation's file and may be nil.
+func (b *Baseline) KeyFor(v rules.Violation, sm *sourcemap.SourceMap) Key {
+	return Key{
+		File:        b.relPath(v.Location.File),
+		Rule:        v.RuleCode,
+		Fingerprint: Fingerprint(v, sm),
+	}
+}
+
+// relPath converts a violation path to a slash path relative to the baseline root.
+func (b *Baseline) relPath(file string) string {
+	if b.root != "" {
+		if abs, err := filepath.Abs(file); err == nil {
+			if rel, err := filepath.Rel(b.root, abs); err == nil {
+				file = rel
+			}
+		}
+	}
+	return filepath.ToSlash(file)
+}
+
+// Fingerprint hashes the rule code and the source lines the violation covers,
+// ignoring indentation and trailing whitespace, so it does not depend on line
+// numbers. File-level violations (and violations without a source map) are
+// fingerprinted by their message instead.
+func Fingerprint(v rules.Violation, sm *sourcemap.SourceMap) string {
+	h := sha256.New()
+	h.Write([]byte(v.RuleCode))
+	h.Write([]byte{0})
+
+	loc := v.Location
+	if sm == nil || loc.IsFileLevel() {
+		h.Write([]byte(v.Message))
+	} else {
+		end := loc.End.Line
+		if end < loc.Start.Line {
+			end = loc.Start.Line
+		}
+		// Location lines are 1-based; SourceMap lines are 0-based.
+		for line := loc.Start.Line; line <= end && line <= sm.LineCount(); line++ {
+			h.Write([]byte(strings.TrimSpace(sm.Line(line - 1))))
+			h.Write([]byte{'\n'})
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// Matcher consumes baseline entries as matching violations are seen.
+// It is not safe for concurrent use.
+type Matcher struct {
+	baseline  *Baseline
+	remaining map[Key]int
+	seenFiles map[string]bool
+}
+
+// NewMatcher returns a matcher for one lint run.
+func (b *Baseline) NewMatcher() *Matcher {
+	remaining := make(map[Key]int, len(b.Entries))
+	for _, e := range b.Entries {
+		remaining[e.Key()] += e.Count
+	}
+	return &Matcher{baseline: b, remaining: remaining, seenFiles: make(map[string]bool)}
+}
+
+// MarkLinted records that file was linted in this run, so baseline entries
+// for it that match no violation are reported as fixed.
+func (m *Matcher) MarkLinted(file string) {
+	m.seenFiles[m.baseline.relPath(file)] = true
+}
+
+// Match reports whether v is baselined, consuming one occurrence.
+func (m *Matcher) Match(v rules.Violation, sm *sourcemap.SourceMap) bool {
+	key := m.baseline.KeyFor(v, sm)
+	if m.remaining[key] <= 0 {
+		return false
+	}
+	m.remaining[key]--
+	return true
+}
+
+// Fixed returns the baseline entries of linted files that matched fewer
+// violations than recorded. Count is the number of occurrences that are gone.
+func (m *Matcher) Fixed() []Entry {
+	var fixed []Entry
+	for _, e := range m.baseline.Entries {
+		if !m.seenFiles[e.File] {
+			continue
+		}
+		key := e.Key()
+		if n := m.remaining[key]; n > 0 {
+			e.Count = min(n, e.Count)
+			m.remaining[key] -= e.Count
+			fixed = append(fixed, e)
+		}
+	}
+	return fixed
+}
@@ -0,0 +1,110 @@
+package baseline
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/tinovyatkin/tally/internal/rules"
+	"github.com/tinovyatkin/tally/internal/sourcemap"
+)
+
+func lineViolation(file string, line int, code string) rules.Violation {
+	return rules.NewViolation(rules.NewLineLocation(file, line), code, "msg", rules.SeverityWarning)
+}
+
+func TestFingerprintSurvivesLineShift(t *testing.T) {
+	t.Parallel()
+	before := sourcemap.New([]byte("FROM alpine\nRUN apk add curl\n"))
+	after := sourcemap.New([]byte("# syntax=docker/dockerfile:1\n\nFROM alpine\n    RUN apk add curl\n"))
+
+	a := Fingerprint(lineViolation("Dockerfile", 2, "hadolint/DL3018"), before)
+	b := Fingerprint(lineViolation("Dockerfile", 4, "hadolint/DL3018"), after)
+	if a != b {
+		t.Errorf("fingerprint changed after line shift: %s != %s", a, b)
+	}
+
+	if c := Fingerprint(lineViolation("Dockerfile", 2, "hadolint/DL3019"), before); c == a {
+		t.Error("fingerprint should depend on the rule code")
+	}
+	if d := Fingerprint(lineViolation("Dockerfile", 1, "hadolint/DL3018"), before); d == a {
+		t.Error("fingerprint should depend on the source text")
+	}
+}
+
+func TestRoundTripAndMatch(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "tally-baseline.json")
+	file := filepath.Join(dir, "Dockerfile")
+
+	old := sourcemap.New([]byte("FROM alpine\nRUN apk add curl\nRUN apk add curl\nRUN apk add git\n"))
+	b, err := New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.Add([]rules.Violation{
+		lineViolation(file, 2, "hadolint/DL3018"),
+		lineViolation(file, 3, "hadolint/DL3018"),
+		lineViolation(file, 4, "hadolint/DL3018"),
+		rules.NewViolation(rules.NewFileLocation(file), "hadolint/DL3057", "no healthcheck", rules.SeverityInfo),
+	}, func(string) *sourcemap.SourceMap { return old })
+	if err := b.Write(path); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Entries) != 3 {
+		t.Fatalf("expected 3 entries (identical lines grouped), got %+v", loaded.Entries)
+	}
+	for _, e := range loaded.Entries {
+		if e.File != "Dockerfile" {
+			t.Errorf("expected path relative to the baseline, got %q", e.File)
+		}
+	}
+
+	// One curl install and the git install remain, shifted down by a line;
+	// a new violation appears on the new line.
+	cur := sourcemap.New([]byte("FROM alpine\nRUN apk add wget\nRUN apk add curl\nRUN apk add git\n"))
+	m := loaded.NewMatcher()
+	m.MarkLinted(file)
+	matched := 0
+	for _, v := range []rules.Violation{
+		lineViolation(file, 2, "hadolint/DL3018"),
+		lineViolation(file, 3, "hadolint/DL3018"),
+		lineViolation(file, 4, "hadolint/DL3018"),
+		rules.NewViolation(rules.NewFileLocation(file), "hadolint/DL3057", "no healthcheck", rules.SeverityInfo),
+	} {
+		if m.Match(v, cur) {
+			matched++
+		} else if v.Line() != 2 {
+			t.Errorf("unexpected new violation at line %d", v.Line())
+		}
+	}
+	if matched != 3 {
+		t.Errorf("expected 3 baselined violations, got %d", matched)
+	}
+
+	fixed := m.Fixed()
+	if len(fixed) != 1 || fixed[0].Count != 1 || fixed[0].Rule != "hadolint/DL3018" {
+		t.Errorf("expected one fixed curl violation, got %+v", fixed)
+	}
+}
+
+func TestFixedIgnoresFilesNotLinted(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "tally-baseline.json")
+	b, err := New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.Add([]rules.Violation{lineViolation("other/Dockerfile", 1, "hadolint/DL3006")},
+		func(string) *sourcemap.SourceMap { return nil })
+
+	m := b.NewMatcher()
+	if fixed := m.Fixed(); len(fixed) != 0 {
+		t.Errorf("entries of files not linted must not be reported as fixed, got %+v", fixed)
+	}
+}
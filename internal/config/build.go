@@ -0,0 +1,74 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// parseBuildArg parses a single --build-arg value.
+//
+// Accepted forms follow docker build semantics:
+//   - "KEY=VALUE" sets KEY to VALUE (VALUE may be empty)
+//   - "KEY" takes the value from the environment; ok is false when unset
+func parseBuildArg(arg string) (string, string, bool, error) {
+	key, value, hasValue := strings.Cut(arg, "=")
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return "", "", false, fmt.Errorf("invalid build arg %q: missing name", arg)
+	}
+	if hasValue {
+		return key, value, true, nil
+	}
+	value, ok := os.LookupEnv(key)
+	return key, value, ok, nil
+}
+
+// ParseBuildArgs parses repeated --build-arg values into a map.
+// Later values override earlier ones.
+func ParseBuildArgs(args []string) (map[string]string, error) {
+	out := make(map[string]string, len(args))
+	for _, arg := range args {
+		key, value, ok, err := parseBuildArg(arg)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			out[key] = value
+		}
+	}
+	return out, nil
+}
+
+// LoadBuildArgFile reads build args from a file with one KEY=VALUE per line.
+// Blank lines and lines starting with # are ignored, matching docker --env-file.
+func LoadBuildArgFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok, err := parseBuildArg(line)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, lineNum, err)
+		}
+		if ok {
+			out[key] = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
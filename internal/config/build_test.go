@@ -0,0 +1,95 @@
+package config
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoad_BuildConfig(t *testing.T) {
+	t.Parallel()
+	tmpDir, dockerfilePath := setupTempProject(t)
+
+	configPath := filepath.Join(tmpDir, ".tally.toml")
+	configContent := `
+[build]
+target = "production"
+
+[build.args]
+BASE_IMAGE = "python:3.12-slim"
+APP_VERSION = "1.2.3"
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(dockerfilePath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if cfg.Build.Target != "production" {
+		t.Errorf("Build.Target = %q, want %q", cfg.Build.Target, "production")
+	}
+	want := map[string]string{"BASE_IMAGE": "python:3.12-slim", "APP_VERSION": "1.2.3"}
+	if !maps.Equal(cfg.Build.Args, want) {
+		t.Errorf("Build.Args = %v, want %v", cfg.Build.Args, want)
+	}
+}
+
+func TestParseBuildArgs(t *testing.T) {
+	t.Setenv("TALLY_TEST_FROM_ENV", "from-env")
+
+	got, err := ParseBuildArgs([]string{
+		"BASE=alpine:3.20",
+		"EMPTY=",
+		"PLATFORMS=linux/amd64,linux/arm64",
+		"TALLY_TEST_FROM_ENV",
+		"TALLY_TEST_UNSET_VAR",
+		"BASE=alpine:3.21",
+	})
+	if err != nil {
+		t.Fatalf("ParseBuildArgs() error = %v", err)
+	}
+
+	want := map[string]string{
+		"BASE":                "alpine:3.21",
+		"EMPTY":               "",
+		"PLATFORMS":           "linux/amd64,linux/arm64",
+		"TALLY_TEST_FROM_ENV": "from-env",
+	}
+	if !maps.Equal(got, want) {
+		t.Errorf("ParseBuildArgs() = %v, want %v", got, want)
+	}
+
+	if _, err := ParseBuildArgs([]string{"=value"}); err == nil {
+		t.Error("ParseBuildArgs() should reject an arg without a name")
+	}
+}
+
+func TestLoadBuildArgFile(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "build.args")
+	content := `# Shared build args
+BASE_IMAGE=node:22-alpine
+
+  NODE_ENV=production
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := LoadBuildArgFile(path)
+	if err != nil {
+		t.Fatalf("LoadBuildArgFile() error = %v", err)
+	}
+	want := map[string]string{"BASE_IMAGE": "node:22-alpine", "NODE_ENV": "production"}
+	if !maps.Equal(got, want) {
+		t.Errorf("LoadBuildArgFile() = %v, want %v", got, want)
+	}
+
+	if _, err := LoadBuildArgFile(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("LoadBuildArgFile() should fail for a missing file")
+	}
+}
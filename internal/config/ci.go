@@ -15,6 +15,16 @@ func SlowChecksEnabled(mode string) bool {
 	}
 }
 
+// Enabled reports whether slow checks should run for this config.
+// Offline mode never touches the network, so in "auto" mode it runs
+// slow checks even on CI.
+func (c SlowChecksConfig) Enabled() bool {
+	if c.Offline && c.Mode == "auto" {
+		return true
+	}
+	return SlowChecksEnabled(c.Mode)
+}
+
 // CIName returns the detected CI provider name, or empty string if not in CI.
 func CIName() string {
 	if !ciinfo.IsCI {
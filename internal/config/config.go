@@ -9,7 +9,9 @@
 //
 // Config file discovery follows a cascading pattern similar to Ruff:
 // starting from the target file's directory, walk up the filesystem
-// until a config file is found. The closest config wins (no merging).
+// until a config file is found. The closest config wins; it can build on
+// other config files and built-in presets with extends = [...], which are
+// merged before it in order.
 package config
 
 import (
@@ -17,9 +19,7 @@ import (
 	"path/filepath"
 	"strings"
 
-	"github.com/knadh/koanf/parsers/toml/v2"
 	"github.com/knadh/koanf/providers/env/v2"
-	"github.com/knadh/koanf/providers/file"
 	"github.com/knadh/koanf/providers/structs"
 	"github.com/knadh/koanf/v2"
 )
@@ -30,8 +30,17 @@ var ConfigFileNames = []string{".tally.toml", "tally.toml"}
 // EnvPrefix is the prefix for environment variables.
 const EnvPrefix = "TALLY_"
 
+// DefaultCacheDir is the lint result cache directory used, relative to the
+// working directory, when the user cache directory is unknown.
+const DefaultCacheDir = ".tally_cache"
+
 // Config represents the complete tally configuration.
 type Config struct {
+	// Extends lists config files (relative to this file) and built-in presets
+	// ("preset:<name>") that this config builds on, merged in order before it.
+	// It is consumed while loading and is always empty on a loaded Config.
+	Extends []string `json:"extends,omitempty" jsonschema:"description=Config files and built-in presets (preset:NAME) to build on" koanf:"extends"`
+
 	// Rules contains configuration for individual linting rules.
 	Rules RulesConfig `json:"rules" jsonschema:"description=Rule configuration" koanf:"rules"`
 
@@ -47,9 +56,38 @@ type Config struct {
 	// SlowChecks configures async checks that require network or other slow I/O.
 	SlowChecks SlowChecksConfig `json:"slow-checks" jsonschema:"description=Slow checks configuration" koanf:"slow-checks"`
 
+	// Build describes the docker build invocation being linted (build args, target).
+	Build BuildConfig `json:"build" jsonschema:"description=Build invocation settings" koanf:"build"`
+
+	// Jobs is the number of files linted in parallel. Zero means one per CPU.
+	// Only the config that applies to the first linted file is consulted.
+	Jobs int `json:"jobs,omitempty" jsonschema:"minimum=0,description=Files to lint in parallel (0 = number of CPUs)" koanf:"jobs"`
+
+	// Cache configures the on-disk lint result cache.
+	Cache CacheConfig `json:"cache" jsonschema:"description=Lint result cache settings" koanf:"cache"`
+
+	// LabelSchema maps the labels every image must carry to the type of
+	// their values (rules.LabelTypes), like hadolint's label-schema. The
+	// label schema rules (hadolint/DL3049-DL3058) check LABEL instructions
+	// against it. Keys containing dots must be quoted in TOML:
+	//
+	//	[label-schema]
+	//	"org.opencontainers.image.source" = "url"
+	//	"org.opencontainers.image.created" = "rfc3339"
+	LabelSchema map[string]string `json:"label-schema,omitempty" jsonschema:"description=Required labels and their value types" koanf:"label-schema"`
+
+	// Overrides are path-scoped rule settings, applied in order to matching
+	// files by ApplyOverrides.
+	Overrides []PathOverride `json:"overrides,omitempty" jsonschema:"description=Path-scoped rule settings" koanf:"overrides"`
+
 	// ConfigFile is the path to the config file that was loaded (if any).
 	// This is metadata, not loaded from config.
 	ConfigFile string `json:"-" koanf:"-"`
+
+	// ConfigChain lists every config source merged into this config, in merge
+	// order: presets as "preset:<name>" and config files as absolute paths,
+	// ending with ConfigFile. This is metadata, not loaded from config.
+	ConfigChain []string `json:"-" koanf:"-"`
 }
 
 // SlowChecksConfig configures async checks that require potentially slow I/O
@@ -61,6 +99,8 @@ type Config struct {
 //	mode = "auto"
 //	fail-fast = true
 //	timeout = "20s"
+//	cache-ttl = "24h"
+//	offline = false
 type SlowChecksConfig struct {
 	// Mode controls when slow checks run: auto (CI detection), on, off.
 	Mode string `json:"mode,omitempty" jsonschema:"default=auto,enum=auto,enum=on,enum=off,description=When to run slow checks" koanf:"mode"`
@@ -70,6 +110,51 @@ type SlowChecksConfig struct {
 
 	// Timeout is the wall-clock budget for all async checks per invocation.
 	Timeout string `json:"timeout,omitempty" jsonschema:"default=20s,description=Timeout for slow checks (e.g. 20s)" koanf:"timeout"`
+
+	// CacheTTL is how long resolved registry metadata is reused from the
+	// on-disk cache ([cache] dir) before querying the registry again.
+	CacheTTL string `json:"cache-ttl,omitempty" jsonschema:"default=24h,description=How long cached registry metadata stays fresh (e.g. 24h)" koanf:"cache-ttl"`
+
+	// Offline resolves images only from the registry metadata cache and never
+	// contacts a registry. Uncached images are skipped.
+	Offline bool `json:"offline,omitempty" jsonschema:"default=false,description=Use only cached registry metadata" koanf:"offline"`
+}
+
+// BuildConfig describes the build invocation that semantic analysis should model.
+// It mirrors the docker build flags that change how a Dockerfile is evaluated.
+//
+// Example TOML configuration:
+//
+//	[build]
+//	target = "production"
+//
+//	[build.args]
+//	BASE_IMAGE = "python:3.12-slim"
+type BuildConfig struct {
+	// Args are build argument values, equivalent to docker build --build-arg.
+	Args map[string]string `json:"args,omitempty" jsonschema:"description=Build argument values (like --build-arg)" koanf:"args"`
+
+	// Target is the stage to build, equivalent to docker build --target.
+	// Empty means the last stage in the Dockerfile.
+	Target string `json:"target,omitempty" jsonschema:"description=Target build stage (like --target)" koanf:"target"`
+}
+
+// CacheConfig configures the persistent lint result cache.
+// Each file is cached according to its own config; the registry metadata
+// cache of slow checks uses the config of the first linted file.
+//
+// Example TOML configuration:
+//
+//	[cache]
+//	enabled = true
+//	dir = ".tally_cache"
+type CacheConfig struct {
+	// Enabled turns the cache on or off. The --no-cache flag disables it for one run.
+	Enabled bool `json:"enabled,omitempty" jsonschema:"default=true,description=Cache lint results between runs" koanf:"enabled"`
+
+	// Dir is the cache directory (see Config.CacheDir). Empty selects the
+	// tally directory under the user cache directory.
+	Dir string `json:"dir,omitempty" jsonschema:"description=Cache directory (default: tally in the user cache directory)" koanf:"dir"`
 }
 
 // OutputConfig configures output formatting and behavior.
@@ -159,25 +244,62 @@ func Default() *Config {
 			Mode:     "auto",
 			FailFast: true,
 			Timeout:  "20s",
+			CacheTTL: "24h",
+		},
+		Cache: CacheConfig{
+			Enabled: true,
 		},
 	}
 }
 
+// CacheDir returns the absolute lint result cache directory. An empty
+// [cache] dir selects the tally directory under the user cache directory
+// (os.UserCacheDir). A relative dir is resolved against the directory of the
+// config file, or base when no config file was found; an empty base is the
+// working directory.
+func (c *Config) CacheDir(base string) string {
+	dir := c.Cache.Dir
+	if dir == "" {
+		if userDir, err := os.UserCacheDir(); err == nil {
+			return filepath.Join(userDir, "tally")
+		}
+		dir = DefaultCacheDir
+	}
+	if !filepath.IsAbs(dir) {
+		if c.ConfigFile != "" {
+			base = filepath.Dir(c.ConfigFile)
+		}
+		dir = filepath.Join(base, dir)
+	}
+	if abs, err := filepath.Abs(dir); err == nil {
+		return abs
+	}
+	return dir
+}
+
 // Load loads configuration for a target file path.
-// It discovers the closest config file, loads it, and applies
-// environment variable overrides.
+// It discovers the closest config file, loads it, applies
+// environment variable overrides and the [[overrides]] matching the target.
 func Load(targetPath string) (*Config, error) {
-	return loadWithConfigPath(Discover(targetPath))
+	cfg, err := loadWithConfigPath(Discover(targetPath), nil)
+	if err != nil {
+		return nil, err
+	}
+	cfg.ApplyOverrides(targetPath)
+	return cfg, nil
 }
 
 // LoadFromFile loads configuration from a specific config file path.
-// Unlike Load, it does not perform config discovery.
+// Unlike Load, it does not perform config discovery, and since it has no
+// target file it does not apply [[overrides]] (see Config.ApplyOverrides).
 func LoadFromFile(configPath string) (*Config, error) {
-	return loadWithConfigPath(configPath)
+	return loadWithConfigPath(configPath, nil)
 }
 
 // loadWithConfigPath is an internal helper that loads config with an optional config file path.
-func loadWithConfigPath(configPath string) (*Config, error) {
+// When record is non-nil it receives the keys set by each config source and
+// environment variable, in load order.
+func loadWithConfigPath(configPath string, record recordFunc) (*Config, error) {
 	k := koanf.New(".")
 
 	// 1. Load defaults
@@ -185,11 +307,10 @@ func loadWithConfigPath(configPath string) (*Config, error) {
 		return nil, err
 	}
 
-	// 2. Load config file if provided
-	if configPath != "" {
-		if err := k.Load(file.Provider(configPath), toml.Parser()); err != nil {
-			return nil, err
-		}
+	// 2. Load config file (and everything it extends) if provided
+	chain, err := loadConfigChainRecorded(k, configPath, record)
+	if err != nil {
+		return nil, err
 	}
 
 	// 3. Load environment variables (TALLY_* prefix)
@@ -200,6 +321,9 @@ func loadWithConfigPath(configPath string) (*Config, error) {
 	}), nil); err != nil {
 		return nil, err
 	}
+	if record != nil {
+		recordEnv(record)
+	}
 
 	// 4. Unmarshal into config struct
 	cfg := &Config{}
@@ -208,6 +332,7 @@ func loadWithConfigPath(configPath string) (*Config, error) {
 	}
 
 	cfg.ConfigFile = configPath
+	cfg.ConfigChain = chain
 	return cfg, nil
 }
 
@@ -227,6 +352,7 @@ var knownHyphenatedKeys = map[string]string{
 	"redact.secrets":    "redact-secrets",
 	"slow.checks":       "slow-checks",
 	"fail.fast":         "fail-fast",
+	"cache.ttl":         "cache-ttl",
 }
 
 // envKeyTransform converts environment variable names to config keys.
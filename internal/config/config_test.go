@@ -565,3 +565,68 @@ format = "json"
 		t.Fatalf("ConfigFile = %q, want empty (editorOnly)", cfg.ConfigFile)
 	}
 }
+
+func TestLoad_Jobs(t *testing.T) {
+	t.Parallel()
+	tmpDir, dockerfilePath := setupTempProject(t)
+
+	if err := os.WriteFile(filepath.Join(tmpDir, ".tally.toml"), []byte("jobs = 3\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(dockerfilePath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Jobs != 3 {
+		t.Errorf("Jobs = %d, want 3", cfg.Jobs)
+	}
+}
+
+func TestCacheDir(t *testing.T) {
+	t.Parallel()
+	base := t.TempDir()
+	configDir := filepath.Join(base, "project")
+
+	userDir, err := os.UserCacheDir()
+	if err != nil {
+		t.Skipf("no user cache directory: %v", err)
+	}
+	if got := Default().CacheDir(base); got != filepath.Join(userDir, "tally") {
+		t.Errorf("default CacheDir = %q, want the tally directory in %s", got, userDir)
+	}
+
+	cfg := Default()
+	cfg.Cache.Dir = ".cache"
+	if got, want := cfg.CacheDir(base), filepath.Join(base, ".cache"); got != want {
+		t.Errorf("CacheDir without config file = %q, want %q", got, want)
+	}
+	cfg.ConfigFile = filepath.Join(configDir, ".tally.toml")
+	if got, want := cfg.CacheDir(base), filepath.Join(configDir, ".cache"); got != want {
+		t.Errorf("CacheDir = %q, want %q (relative to the config file)", got, want)
+	}
+	cfg.Cache.Dir = filepath.Join(base, "abs")
+	if got := cfg.CacheDir(base); got != cfg.Cache.Dir {
+		t.Errorf("CacheDir = %q, want absolute dir %q", got, cfg.Cache.Dir)
+	}
+}
+
+func TestRulesConfigIsEnabled_Selected(t *testing.T) {
+	t.Parallel()
+	rc := &RulesConfig{
+		Exclude:  []string{"hadolint/DL3008"},
+		Scoped:   []RuleSelection{{Include: []string{"hadolint/DL3006"}, Exclude: []string{"hadolint/DL3008"}}},
+		Selected: RuleSelection{Include: []string{"hadolint/DL3008"}, Exclude: []string{"hadolint/*"}},
+	}
+
+	// The CLI selection wins over [[overrides]] and the base patterns.
+	if enabled := rc.IsEnabled("hadolint/DL3006"); enabled == nil || *enabled {
+		t.Error("hadolint/DL3006 should be disabled by the CLI selection")
+	}
+	if enabled := rc.IsEnabled("hadolint/DL3008"); enabled == nil || !*enabled {
+		t.Error("hadolint/DL3008 should be enabled by the CLI selection")
+	}
+	if enabled := rc.IsEnabled("tally/max-lines"); enabled != nil {
+		t.Errorf("unconfigured rule should return nil, got %v", *enabled)
+	}
+}
@@ -0,0 +1,158 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/knadh/koanf/providers/structs"
+	"github.com/knadh/koanf/v2"
+)
+
+// Sources a configuration value can come from, in precedence order.
+const (
+	SourceDefault  = "default"
+	SourcePreset   = "preset"
+	SourceFile     = "file"
+	SourceEnv      = "env"
+	SourceOverride = "override"
+	SourceCLI      = "cli"
+)
+
+// Origin describes where a configuration value came from.
+type Origin struct {
+	// Source is one of the Source* constants.
+	Source string `json:"source"`
+
+	// Detail identifies the source: the preset reference, the absolute config
+	// file path, the environment variable or the [[overrides]] block.
+	// It is empty for defaults and CLI flags.
+	Detail string `json:"detail,omitempty"`
+}
+
+// String formats the origin as "source (detail)".
+func (o Origin) String() string {
+	if o.Detail == "" {
+		return o.Source
+	}
+	return o.Source + " (" + o.Detail + ")"
+}
+
+// Explanation is a resolved configuration together with the origin of each value.
+type Explanation struct {
+	// Config is the resolved configuration.
+	Config *Config
+
+	// Values is Config flattened to dotted keys (see Flatten).
+	Values map[string]any
+
+	// Sources maps each key in Values to the source that last set it.
+	Sources map[string]Origin
+
+	// Overrides are the indexes of the [[overrides]] blocks applied to the target.
+	Overrides []int
+}
+
+// Explain loads the configuration for targetPath the way Load does (or
+// LoadFromFile followed by ApplyOverrides when configPath is set) and records
+// which source set each value.
+func Explain(targetPath, configPath string) (*Explanation, error) {
+	if configPath == "" {
+		configPath = Discover(targetPath)
+	}
+
+	e := &Explanation{Sources: make(map[string]Origin)}
+	cfg, err := loadWithConfigPath(configPath, func(origin Origin, keys []string) {
+		for _, key := range keys {
+			e.Sources[key] = origin
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	e.Config = cfg
+	e.Values = Flatten(cfg)
+	for key := range e.Values {
+		if _, ok := e.Sources[key]; !ok {
+			e.Sources[key] = Origin{Source: SourceDefault}
+		}
+	}
+
+	for _, i := range cfg.matchingOverrides(targetPath) {
+		cfg.Rules.applyOverride(cfg.Overrides[i].Rules)
+		e.Update(Origin{Source: SourceOverride, Detail: fmt.Sprintf("overrides[%d]", i)})
+		e.Overrides = append(e.Overrides, i)
+	}
+	return e, nil
+}
+
+// Update re-flattens e.Config after the caller changed it, attributing every
+// value that changed to origin.
+func (e *Explanation) Update(origin Origin) {
+	values := Flatten(e.Config)
+	for key, v := range values {
+		if old, ok := e.Values[key]; !ok || !reflect.DeepEqual(old, v) {
+			e.Sources[key] = origin
+		}
+	}
+	for key := range e.Sources {
+		if _, ok := values[key]; !ok {
+			delete(e.Sources, key)
+		}
+	}
+	e.Values = values
+}
+
+// optionsField is the name the structs provider gives RuleConfig.Options,
+// whose keys live directly in the rule's table in config files.
+const optionsField = "Options"
+
+// Flatten returns cfg as dotted keys mapped to values, using the same keys as
+// config files (e.g. "rules.tally.max-lines.max"). Lists are single values.
+// Extends and [[overrides]] are omitted: they are consumed while loading.
+func Flatten(cfg *Config) map[string]any {
+	k := koanf.New(".")
+	if err := k.Load(structs.Provider(cfg, "koanf"), nil); err != nil {
+		return nil
+	}
+	out := make(map[string]any)
+	for key, v := range k.Raw() {
+		if key == extendsKey || key == "overrides" {
+			continue
+		}
+		flattenValue(out, key, v)
+	}
+	return out
+}
+
+func flattenValue(out map[string]any, prefix string, v any) {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, child := range v {
+			if key == optionsField {
+				flattenValue(out, prefix, child)
+				continue
+			}
+			flattenValue(out, prefix+"."+key, child)
+		}
+	case map[string]string:
+		for key, child := range v {
+			out[prefix+"."+key] = child
+		}
+	default:
+		out[prefix] = v
+	}
+}
+
+// recordEnv reports the keys set by TALLY_* environment variables.
+func recordEnv(record recordFunc) {
+	for _, kv := range os.Environ() {
+		name, value, _ := strings.Cut(kv, "=")
+		if !strings.HasPrefix(name, EnvPrefix) {
+			continue
+		}
+		key, _ := envKeyTransform(name, value)
+		record(Origin{Source: SourceEnv, Detail: name}, []string{key})
+	}
+}
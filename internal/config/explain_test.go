@@ -0,0 +1,69 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	t.Setenv("TALLY_OUTPUT_PATH", "stderr")
+
+	tmpDir := t.TempDir()
+	base := filepath.Join(tmpDir, "base.toml")
+	writeConfig(t, base, `
+[output]
+fail-level = "warning"
+`)
+	configPath := filepath.Join(tmpDir, ".tally.toml")
+	writeConfig(t, configPath, `
+extends = ["preset:strict", "base.toml"]
+
+[rules.tally.max-lines]
+max = 100
+
+[build.args]
+BASE = "alpine"
+
+[[overrides]]
+files = ["legacy/**"]
+
+[overrides.rules.tally.max-lines]
+max = 300
+`)
+
+	e, err := Explain(filepath.Join(tmpDir, "legacy", "Dockerfile"), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.Config.Output.Format = "json"
+	e.Update(Origin{Source: SourceCLI})
+
+	want := map[string]struct {
+		value  any
+		origin Origin
+	}{
+		"output.show-source":            {true, Origin{Source: SourceDefault}},
+		"inline-directives.warn-unused": {true, Origin{Source: SourcePreset, Detail: "preset:strict"}},
+		"output.fail-level":             {"warning", Origin{Source: SourceFile, Detail: base}},
+		"build.args.BASE":               {"alpine", Origin{Source: SourceFile, Detail: configPath}},
+		"output.path":                   {"stderr", Origin{Source: SourceEnv, Detail: "TALLY_OUTPUT_PATH"}},
+		"rules.tally.max-lines.max":     {int64(300), Origin{Source: SourceOverride, Detail: "overrides[0]"}},
+		"output.format":                 {"json", Origin{Source: SourceCLI}},
+	}
+	for key, w := range want {
+		if got := e.Values[key]; got != w.value {
+			t.Errorf("Values[%q] = %#v, want %#v", key, got, w.value)
+		}
+		if got := e.Sources[key]; got != w.origin {
+			t.Errorf("Sources[%q] = %v, want %v", key, got, w.origin)
+		}
+	}
+	for _, key := range []string{"extends", "overrides"} {
+		if _, ok := e.Values[key]; ok {
+			t.Errorf("Values contains %q", key)
+		}
+	}
+	if len(e.Overrides) != 1 || e.Overrides[0] != 0 {
+		t.Errorf("Overrides = %v, want [0]", e.Overrides)
+	}
+}
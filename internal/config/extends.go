@@ -0,0 +1,141 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/knadh/koanf/parsers/toml/v2"
+	"github.com/knadh/koanf/providers/confmap"
+	"github.com/knadh/koanf/providers/file"
+	"github.com/knadh/koanf/v2"
+
+	"github.com/tinovyatkin/tally/internal/rules"
+)
+
+// extendsKey is the config key listing the configs a file builds on.
+const extendsKey = "extends"
+
+// loadConfigChain loads configPath into k, preceded by everything it extends
+// (recursively, in order). It returns the merged sources in merge order:
+// presets as "preset:<name>" and config files as absolute paths.
+func loadConfigChain(k *koanf.Koanf, configPath string) ([]string, error) {
+	return loadConfigChainRecorded(k, configPath, nil)
+}
+
+// loadConfigChainRecorded is loadConfigChain that also reports the keys each
+// source sets to record (when non-nil), in merge order.
+func loadConfigChainRecorded(k *koanf.Koanf, configPath string, record recordFunc) ([]string, error) {
+	if configPath == "" {
+		return nil, nil
+	}
+	var chain []string
+	if err := loadExtending(k, configPath, nil, &chain, record); err != nil {
+		return nil, err
+	}
+	// extends only makes sense per file; don't let it leak into the result.
+	k.Delete(extendsKey)
+	return chain, nil
+}
+
+// recordFunc receives the flattened keys set by one config source.
+type recordFunc func(origin Origin, keys []string)
+
+// loadExtending merges the configs extended by path, then path itself.
+// stack holds the files currently being loaded, for cycle detection.
+func loadExtending(k *koanf.Koanf, path string, stack []string, chain *[]string, record recordFunc) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(stack, abs) {
+		return fmt.Errorf("config extends cycle: %s", strings.Join(append(stack, abs), " -> "))
+	}
+	stack = append(stack, abs)
+
+	fk := koanf.New(".")
+	if err := fk.Load(file.Provider(abs), toml.Parser()); err != nil {
+		if len(stack) > 1 {
+			return fmt.Errorf("%s (extended by %s): %w", abs, stack[len(stack)-2], err)
+		}
+		return err
+	}
+
+	extends, err := extendsList(fk.Get(extendsKey))
+	if err != nil {
+		return fmt.Errorf("%s: %w", abs, err)
+	}
+	for _, ref := range extends {
+		if name, ok := strings.CutPrefix(ref, rules.PresetPrefix); ok {
+			preset, found := rules.LookupPreset(name)
+			if !found {
+				return fmt.Errorf("%s: unknown preset %q (available: %s)",
+					abs, name, strings.Join(rules.PresetNames(), ", "))
+			}
+			pk := koanf.New(".")
+			if err := pk.Load(confmap.Provider(preset.Config(), ""), nil); err != nil {
+				return err
+			}
+			if err := k.Merge(pk); err != nil {
+				return err
+			}
+			if record != nil {
+				record(Origin{Source: SourcePreset, Detail: ref}, pk.Keys())
+			}
+			*chain = append(*chain, ref)
+			continue
+		}
+		if err := loadExtending(k, resolveExtends(abs, ref), stack, chain, record); err != nil {
+			return err
+		}
+	}
+
+	if err := k.Merge(fk); err != nil {
+		return err
+	}
+	if record != nil {
+		record(Origin{Source: SourceFile, Detail: abs}, fk.Keys())
+	}
+	*chain = append(*chain, abs)
+	return nil
+}
+
+// extendsList normalizes the extends value, which may be a string or a list of strings.
+func extendsList(v any) ([]string, error) {
+	switch v := v.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []string{v}, nil
+	case []any:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("extends entries must be strings, got %T", item)
+			}
+			out = append(out, s)
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("extends must be a string or a list of strings, got %T", v)
+	}
+}
+
+// resolveExtends resolves an extends path relative to the extending file.
+// A directory resolves to the config file it contains.
+func resolveExtends(from, ref string) string {
+	if !filepath.IsAbs(ref) {
+		ref = filepath.Join(filepath.Dir(from), filepath.FromSlash(ref))
+	}
+	if info, err := os.Stat(ref); err == nil && info.IsDir() {
+		for _, name := range ConfigFileNames {
+			if candidate := filepath.Join(ref, name); fileExists(candidate) {
+				return candidate
+			}
+		}
+	}
+	return ref
+}
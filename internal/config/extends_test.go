@@ -0,0 +1,200 @@
+package config
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func writeConfig(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoad_Extends(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+	org := filepath.Join(tmpDir, ".tally.toml")
+	writeConfig(t, org, `
+extends = ["preset:recommended"]
+
+[output]
+fail-level = "warning"
+
+[rules]
+exclude = ["buildkit/MaintainerDeprecated"]
+
+[rules.tally.max-lines]
+max = 100
+`)
+	service := filepath.Join(tmpDir, "services", "api", ".tally.toml")
+	writeConfig(t, service, `
+extends = ["../../.tally.toml"]
+
+[rules.tally.max-lines]
+max = 200
+`)
+
+	cfg, err := Load(filepath.Join(filepath.Dir(service), "Dockerfile"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if cfg.Output.FailLevel != "warning" {
+		t.Errorf("FailLevel = %q, want inherited %q", cfg.Output.FailLevel, "warning")
+	}
+	if !cfg.InlineDirectives.WarnUnused {
+		t.Error("WarnUnused should be inherited from preset:recommended")
+	}
+	if got := cfg.Rules.GetOptions("tally/max-lines")["max"]; got != int64(200) {
+		t.Errorf("max-lines max = %v, want 200 (closest config wins)", got)
+	}
+	if !slices.Equal(cfg.Rules.Exclude, []string{"buildkit/MaintainerDeprecated"}) {
+		t.Errorf("Exclude = %v, want inherited", cfg.Rules.Exclude)
+	}
+	if len(cfg.Extends) != 0 {
+		t.Errorf("Extends should be consumed while loading, got %v", cfg.Extends)
+	}
+
+	wantChain := []string{"preset:recommended", org, service}
+	if !slices.Equal(cfg.ConfigChain, wantChain) {
+		t.Errorf("ConfigChain = %v, want %v", cfg.ConfigChain, wantChain)
+	}
+}
+
+func TestLoad_ExtendsDirectory(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+	writeConfig(t, filepath.Join(tmpDir, "tally.toml"), "jobs = 4\n")
+	child := filepath.Join(tmpDir, "child", ".tally.toml")
+	writeConfig(t, child, `extends = ".."`+"\n")
+
+	cfg, err := LoadFromFile(child)
+	if err != nil {
+		t.Fatalf("LoadFromFile() error = %v", err)
+	}
+	if cfg.Jobs != 4 {
+		t.Errorf("Jobs = %d, want 4 from the parent directory's config", cfg.Jobs)
+	}
+}
+
+func TestLoad_ExtendsErrors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		files   map[string]string
+		wantErr string
+	}{
+		{
+			name:    "unknown preset",
+			files:   map[string]string{".tally.toml": `extends = ["preset:nope"]`},
+			wantErr: `unknown preset "nope"`,
+		},
+		{
+			name: "cycle",
+			files: map[string]string{
+				".tally.toml": `extends = ["a.toml"]`,
+				"a.toml":      `extends = [".tally.toml"]`,
+			},
+			wantErr: "config extends cycle",
+		},
+		{
+			name:    "missing file",
+			files:   map[string]string{".tally.toml": `extends = ["missing.toml"]`},
+			wantErr: "extended by",
+		},
+		{
+			name:    "bad type",
+			files:   map[string]string{".tally.toml": `extends = 1`},
+			wantErr: "extends must be a string or a list of strings",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			tmpDir := t.TempDir()
+			for name, content := range tt.files {
+				writeConfig(t, filepath.Join(tmpDir, name), content)
+			}
+			_, err := LoadFromFile(filepath.Join(tmpDir, ".tally.toml"))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoad_ExtendsPresetsLoad(t *testing.T) {
+	t.Parallel()
+	for _, name := range []string{"recommended", "strict", "security", "hadolint-compat", "oci-labels"} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			path := filepath.Join(t.TempDir(), ".tally.toml")
+			writeConfig(t, path, `extends = ["preset:`+name+`"]`)
+			if _, err := LoadFromFile(path); err != nil {
+				t.Errorf("LoadFromFile() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestLoad_ExtendsOCILabelsPreset(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), ".tally.toml")
+	writeConfig(t, path, `
+extends = ["preset:oci-labels"]
+
+[label-schema]
+"org.opencontainers.image.version" = "text"
+maintainer = "email"
+`)
+	cfg, err := LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadFromFile() error = %v", err)
+	}
+	want := map[string]string{
+		"org.opencontainers.image.created":     "rfc3339",
+		"org.opencontainers.image.description": "text",
+		"org.opencontainers.image.licenses":    "spdx",
+		"org.opencontainers.image.revision":    "hash",
+		"org.opencontainers.image.source":      "url",
+		"org.opencontainers.image.title":       "text",
+		"org.opencontainers.image.version":     "text",
+		"maintainer":                           "email",
+	}
+	if !maps.Equal(cfg.LabelSchema, want) {
+		t.Errorf("LabelSchema = %v, want %v", cfg.LabelSchema, want)
+	}
+}
+
+func TestLoad_ExtendsSecurityPreset(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), ".tally.toml")
+	writeConfig(t, path, `
+extends = ["preset:security"]
+
+[rules.hadolint.DL3004]
+severity = "warning"
+`)
+	cfg, err := LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadFromFile() error = %v", err)
+	}
+	if got := cfg.Rules.GetSeverity("hadolint/DL3002"); got != "error" {
+		t.Errorf("DL3002 severity = %q, want error from preset", got)
+	}
+	if got := cfg.Rules.GetSeverity("hadolint/DL3004"); got != "warning" {
+		t.Errorf("DL3004 severity = %q, want the file's override", got)
+	}
+	if enabled := cfg.Rules.IsEnabled("tally/secrets-in-code"); enabled == nil || !*enabled {
+		t.Error("tally/secrets-in-code should be included by the preset")
+	}
+}
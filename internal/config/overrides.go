@@ -1,10 +1,8 @@
 package config
 
 import (
-	"github.com/knadh/koanf/parsers/toml/v2"
 	"github.com/knadh/koanf/providers/confmap"
 	"github.com/knadh/koanf/providers/env/v2"
-	"github.com/knadh/koanf/providers/file"
 	"github.com/knadh/koanf/providers/structs"
 	"github.com/knadh/koanf/v2"
 )
@@ -54,7 +52,12 @@ func LoadWithOverrides(targetPath string, overrides map[string]any, preference C
 	if preference != ConfigurationPreferenceEditorOnly {
 		configPath = Discover(targetPath)
 	}
-	return loadWithConfigPathAndOverrides(configPath, overrides, preference)
+	cfg, err := loadWithConfigPathAndOverrides(configPath, overrides, preference)
+	if err != nil {
+		return nil, err
+	}
+	cfg.ApplyOverrides(targetPath)
+	return cfg, nil
 }
 
 func loadWithConfigPathAndOverrides(
@@ -65,6 +68,7 @@ func loadWithConfigPathAndOverrides(
 	preference = normalizeConfigurationPreference(preference)
 
 	k := koanf.New(".")
+	var chain []string
 
 	// 1) Defaults
 	if err := k.Load(structs.Provider(Default(), "koanf"), nil); err != nil {
@@ -84,14 +88,16 @@ func loadWithConfigPathAndOverrides(
 		if err := loadOverrides(k, overrides); err != nil {
 			return nil, err
 		}
-		if err := loadConfigFile(k, configPath); err != nil {
+		var err error
+		if chain, err = loadConfigChain(k, configPath); err != nil {
 			return nil, err
 		}
 		if err := loadEnv(k); err != nil {
 			return nil, err
 		}
 	case ConfigurationPreferenceEditorFirst:
-		if err := loadConfigFile(k, configPath); err != nil {
+		var err error
+		if chain, err = loadConfigChain(k, configPath); err != nil {
 			return nil, err
 		}
 		if err := loadEnv(k); err != nil {
@@ -109,16 +115,10 @@ func loadWithConfigPathAndOverrides(
 	}
 
 	cfg.ConfigFile = configPath
+	cfg.ConfigChain = chain
 	return cfg, nil
 }
 
-func loadConfigFile(k *koanf.Koanf, configPath string) error {
-	if configPath == "" {
-		return nil
-	}
-	return k.Load(file.Provider(configPath), toml.Parser())
-}
-
 func loadEnv(k *koanf.Koanf) error {
 	return k.Load(env.Provider(".", env.Opt{
 		Prefix:        EnvPrefix,
@@ -0,0 +1,139 @@
+package config
+
+import (
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+// PathOverride applies rule settings to the files matching Files.
+//
+// Example TOML configuration:
+//
+//	[[overrides]]
+//	files = ["legacy/**"]
+//
+//	[overrides.rules]
+//	exclude = ["hadolint/DL3006"]
+//
+//	[overrides.rules.tally.max-lines]
+//	severity = "warning"
+//	max = 300
+type PathOverride struct {
+	// Files are glob patterns relative to the config file's directory.
+	// Patterns without a slash match the file name in any directory.
+	Files []string `json:"files" jsonschema:"description=Glob patterns (relative to the config file) this block applies to" koanf:"files"`
+
+	// Rules are merged over the top-level rule configuration for matching files.
+	Rules RulesConfig `json:"rules" jsonschema:"description=Rule configuration for matching files" koanf:"rules"`
+}
+
+// Matches reports whether the override applies to rel, a slash-separated
+// path relative to the config file's directory.
+func (o PathOverride) Matches(rel string) bool {
+	for _, pattern := range o.Files {
+		pattern = strings.TrimPrefix(filepath.ToSlash(pattern), "./")
+		target := rel
+		if !strings.Contains(pattern, "/") {
+			target = path.Base(rel)
+		}
+		if matched, err := doublestar.Match(pattern, target); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyOverrides merges the [[overrides]] blocks matching targetPath into
+// c.Rules, in the order they appear. Patterns are resolved against the
+// directory of c.ConfigFile (or the working directory without a config file).
+//
+// Loaders that know the target file call this; callers using LoadFromFile
+// must call it themselves.
+func (c *Config) ApplyOverrides(targetPath string) {
+	for _, i := range c.matchingOverrides(targetPath) {
+		c.Rules.applyOverride(c.Overrides[i].Rules)
+	}
+}
+
+// matchingOverrides returns the indexes of the [[overrides]] blocks that
+// apply to targetPath, in order.
+func (c *Config) matchingOverrides(targetPath string) []int {
+	if len(c.Overrides) == 0 {
+		return nil
+	}
+	rel := c.overridePath(targetPath)
+	var matched []int
+	for i, o := range c.Overrides {
+		if o.Matches(rel) {
+			matched = append(matched, i)
+		}
+	}
+	return matched
+}
+
+// overridePath returns targetPath relative to the override base directory.
+func (c *Config) overridePath(targetPath string) string {
+	base := ""
+	if c.ConfigFile != "" {
+		base = filepath.Dir(c.ConfigFile)
+	} else if wd, err := os.Getwd(); err == nil {
+		base = wd
+	}
+	absBase, errBase := filepath.Abs(base)
+	absTarget, errTarget := filepath.Abs(targetPath)
+	if errBase == nil && errTarget == nil {
+		if rel, err := filepath.Rel(absBase, absTarget); err == nil {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return filepath.ToSlash(targetPath)
+}
+
+// applyOverride merges an override's rule configuration into rc.
+// Include/Exclude become a new selection layer that takes precedence over
+// earlier ones; per-rule settings are merged field by field.
+func (rc *RulesConfig) applyOverride(o RulesConfig) {
+	if len(o.Include) > 0 || len(o.Exclude) > 0 {
+		rc.Scoped = append(rc.Scoped, RuleSelection{Include: o.Include, Exclude: o.Exclude})
+	}
+	for ns, m := range map[string]map[string]RuleConfig{
+		"tally":      o.Tally,
+		"buildkit":   o.Buildkit,
+		"hadolint":   o.Hadolint,
+		"shellcheck": o.Shellcheck,
+	} {
+		for name, oc := range m {
+			code := ns + "/" + name
+			var base RuleConfig
+			if existing := rc.Get(code); existing != nil {
+				base = *existing
+			}
+			rc.Set(code, base.merge(oc))
+		}
+	}
+}
+
+// merge returns r with the settings configured in o applied on top.
+func (r RuleConfig) merge(o RuleConfig) RuleConfig {
+	if o.Severity != "" {
+		r.Severity = o.Severity
+	}
+	if o.Fix != "" {
+		r.Fix = o.Fix
+	}
+	if o.Exclude.Paths != nil {
+		r.Exclude.Paths = o.Exclude.Paths
+	}
+	if len(o.Options) > 0 {
+		opts := make(map[string]any, len(r.Options)+len(o.Options))
+		maps.Copy(opts, r.Options)
+		maps.Copy(opts, o.Options)
+		r.Options = opts
+	}
+	return r
+}
@@ -0,0 +1,124 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestLoad_PathOverrides(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+	writeConfig(t, filepath.Join(tmpDir, ".tally.toml"), `
+[rules]
+include = ["hadolint/*"]
+
+[rules.tally.max-lines]
+severity = "error"
+max = 100
+skip-comments = true
+
+[[overrides]]
+files = ["legacy/**"]
+
+[overrides.rules]
+exclude = ["hadolint/DL3006"]
+
+[overrides.rules.tally.max-lines]
+severity = "warning"
+max = 300
+
+[overrides.rules.hadolint.DL3008]
+fix = "never"
+
+[[overrides]]
+files = ["*.dev.Dockerfile"]
+
+[overrides.rules.tally.max-lines]
+severity = "off"
+`)
+
+	tests := []struct {
+		name         string
+		target       string
+		wantDL3006   bool
+		wantSeverity string
+		wantMax      any
+		wantFixMode  FixMode
+	}{
+		{
+			name:         "unmatched file uses top-level settings",
+			target:       filepath.Join(tmpDir, "app", "Dockerfile"),
+			wantDL3006:   true,
+			wantSeverity: "error",
+			wantMax:      int64(100),
+			wantFixMode:  FixModeAlways,
+		},
+		{
+			name:         "legacy file gets override settings",
+			target:       filepath.Join(tmpDir, "legacy", "api", "Dockerfile"),
+			wantDL3006:   false,
+			wantSeverity: "warning",
+			wantMax:      int64(300),
+			wantFixMode:  FixModeNever,
+		},
+		{
+			name:         "later blocks win and basename patterns match anywhere",
+			target:       filepath.Join(tmpDir, "legacy", "api.dev.Dockerfile"),
+			wantDL3006:   false,
+			wantSeverity: "off",
+			wantMax:      int64(300),
+			wantFixMode:  FixModeNever,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cfg, err := Load(tt.target)
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if enabled := cfg.Rules.IsEnabled("hadolint/DL3006"); enabled == nil || *enabled != tt.wantDL3006 {
+				t.Errorf("DL3006 enabled = %v, want %v", enabled, tt.wantDL3006)
+			}
+			if got := cfg.Rules.GetSeverity("tally/max-lines"); got != tt.wantSeverity {
+				t.Errorf("max-lines severity = %q, want %q", got, tt.wantSeverity)
+			}
+			opts := cfg.Rules.GetOptions("tally/max-lines")
+			if opts["max"] != tt.wantMax {
+				t.Errorf("max-lines max = %v, want %v", opts["max"], tt.wantMax)
+			}
+			if opts["skip-comments"] != true {
+				t.Errorf("max-lines skip-comments = %v, want inherited true", opts["skip-comments"])
+			}
+			if got := cfg.Rules.GetFixMode("hadolint/DL3008"); got != tt.wantFixMode {
+				t.Errorf("DL3008 fix mode = %q, want %q", got, tt.wantFixMode)
+			}
+		})
+	}
+}
+
+func TestApplyOverrides_LoadFromFile(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "tally.toml")
+	writeConfig(t, configPath, `
+[[overrides]]
+files = ["vendor/**"]
+
+[overrides.rules]
+exclude = ["*"]
+`)
+
+	cfg, err := LoadFromFile(configPath)
+	if err != nil {
+		t.Fatalf("LoadFromFile() error = %v", err)
+	}
+	if cfg.Rules.IsEnabled("tally/max-lines") != nil {
+		t.Fatal("LoadFromFile should not apply overrides by itself")
+	}
+
+	cfg.ApplyOverrides(filepath.Join(tmpDir, "vendor", "x", "Dockerfile"))
+	if enabled := cfg.Rules.IsEnabled("tally/max-lines"); enabled == nil || *enabled {
+		t.Errorf("expected vendor override to disable all rules, got %v", enabled)
+	}
+}
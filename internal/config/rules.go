@@ -86,6 +86,26 @@ type RulesConfig struct {
 
 	// Hadolint contains configuration for hadolint/* rules.
 	Hadolint map[string]RuleConfig `json:"hadolint,omitempty" jsonschema:"description=Configuration for hadolint/* rules" koanf:"hadolint"`
+
+	// Shellcheck contains configuration for shellcheck/* rules.
+	Shellcheck map[string]RuleConfig `json:"shellcheck,omitempty" jsonschema:"description=Configuration for shellcheck/* rules" koanf:"shellcheck"`
+
+	// Scoped holds the rule selections of [[overrides]] blocks that apply to
+	// this file, in application order. Later layers take precedence over
+	// earlier ones and over Include/Exclude.
+	// This is populated by Config.ApplyOverrides, not loaded from config.
+	Scoped []RuleSelection `json:"-" koanf:"-"`
+
+	// Selected holds the --select/--ignore patterns from the command line,
+	// which take precedence over Scoped and Include/Exclude.
+	// This is populated by the CLI, not loaded from config.
+	Selected RuleSelection `json:"-" koanf:"-"`
+}
+
+// RuleSelection is one layer of include/exclude rule patterns.
+type RuleSelection struct {
+	Include []string `json:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty"`
 }
 
 // Get returns the configuration for a specific rule.
@@ -116,22 +136,31 @@ func parseRuleCode(ruleCode string) (string, string) {
 	return "", ruleCode
 }
 
-// IsEnabled checks if a rule is enabled based on Include/Exclude patterns.
-// Returns nil if no configuration specifies enabled/disabled (use rule default).
-// Include takes precedence over Exclude (Ruff-style semantics).
-func (rc *RulesConfig) IsEnabled(ruleCode string) *bool {
+// Selections returns the rule selection layers in application order:
+// Include/Exclude, the Scoped layers, then Selected.
+func (rc *RulesConfig) Selections() []RuleSelection {
 	if rc == nil {
 		return nil
 	}
+	layers := make([]RuleSelection, 0, len(rc.Scoped)+2)
+	layers = append(layers, RuleSelection{Include: rc.Include, Exclude: rc.Exclude})
+	layers = append(layers, rc.Scoped...)
+	return append(layers, rc.Selected)
+}
 
-	// Check Include first (takes precedence)
-	if matchesAnyPattern(ruleCode, rc.Include) {
-		return new(true)
-	}
-
-	// Check Exclude
-	if matchesAnyPattern(ruleCode, rc.Exclude) {
-		return new(false)
+// IsEnabled checks if a rule is enabled based on Include/Exclude patterns.
+// Returns nil if no configuration specifies enabled/disabled (use rule default).
+// Within a layer, Include takes precedence over Exclude (Ruff-style
+// semantics). Layers are consulted last one first: the CLI selection, the
+// Scoped selections from [[overrides]], then Include/Exclude.
+func (rc *RulesConfig) IsEnabled(ruleCode string) *bool {
+	for _, sel := range slices.Backward(rc.Selections()) {
+		if matchesAnyPattern(ruleCode, sel.Include) {
+			return new(true)
+		}
+		if matchesAnyPattern(ruleCode, sel.Exclude) {
+			return new(false)
+		}
 	}
 
 	// No explicit config - use rule default
@@ -148,6 +177,12 @@ func matchesAnyPattern(ruleCode string, patterns []string) bool {
 	})
 }
 
+// MatchesRulePattern reports whether ruleCode matches an include/exclude
+// pattern: "*", a namespace wildcard ("buildkit/*") or an exact code.
+func MatchesRulePattern(ruleCode, pattern string) bool {
+	return matchesPattern(ruleCode, pattern)
+}
+
 // matchesPattern checks if ruleCode matches a single pattern.
 func matchesPattern(ruleCode, pattern string) bool {
 	// Universal wildcard matches everything
@@ -260,6 +295,12 @@ func (rc *RulesConfig) Set(ruleCode string, cfg RuleConfig) bool {
 		}
 		rc.Hadolint[name] = cfg
 		return true
+	case "shellcheck":
+		if rc.Shellcheck == nil {
+			rc.Shellcheck = make(map[string]RuleConfig)
+		}
+		rc.Shellcheck[name] = cfg
+		return true
 	default:
 		return false
 	}
@@ -274,6 +315,8 @@ func (rc *RulesConfig) namespaceMap(ns string) map[string]RuleConfig {
 		return rc.Buildkit
 	case "hadolint":
 		return rc.Hadolint
+	case "shellcheck":
+		return rc.Shellcheck
 	default:
 		return nil
 	}
@@ -0,0 +1,350 @@
+// Package configcheck validates tally config files.
+//
+// Loading a config is lenient: unknown keys are ignored and invalid values
+// fall back to defaults at the point of use. Check reports those mistakes
+// up front, with the line they appear on:
+//   - the file must be valid TOML
+//   - keys and values must match the config JSON schema (schema.json)
+//   - rule tables must name known rules, and their options must pass the
+//     rule's ConfigurableRule.ValidateConfig
+//   - include/exclude patterns must match at least one rule
+//   - durations must parse with time.ParseDuration
+//   - extends must resolve
+package configcheck
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json/v2"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/kind"
+
+	"github.com/tinovyatkin/tally/internal/config"
+	"github.com/tinovyatkin/tally/internal/linter"
+	"github.com/tinovyatkin/tally/internal/rules"
+)
+
+// Problem is one issue found in a config file.
+type Problem struct {
+	// Key is the dotted key the problem is about (e.g. "output.format"),
+	// empty for problems with the whole file.
+	Key string
+
+	// Line and Column locate the key (1-based), or 0 when unknown.
+	Line   int
+	Column int
+
+	// Message describes the problem.
+	Message string
+}
+
+// String formats the problem as "line:column: message" (or just the message
+// when the position is unknown).
+func (p Problem) String() string {
+	if p.Line == 0 {
+		return p.Message
+	}
+	return fmt.Sprintf("%d:%d: %s", p.Line, p.Column, p.Message)
+}
+
+// ruleTableKeys are the settings every rule table accepts; any other key is a
+// rule-specific option.
+var ruleTableKeys = []string{"severity", "fix", "exclude"}
+
+// durationKeys are the config keys holding time.ParseDuration strings.
+var durationKeys = []string{"slow-checks.timeout", "slow-checks.cache-ttl", "ai.timeout"}
+
+// checker accumulates the problems found in one file.
+type checker struct {
+	keys     keyPositions
+	catalog  []linter.RuleInfo
+	problems []Problem
+}
+
+// Check validates the config file at path. schema is the config JSON schema
+// (the contents of schema.json). It returns the problems found, sorted by
+// position; the error is reserved for failures to run the check at all.
+func Check(path string, schema []byte) ([]Problem, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	sch, err := compileSchema(schema)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config schema: %w", err)
+	}
+
+	c := &checker{catalog: linter.Catalog()}
+
+	var raw map[string]any
+	if err := toml.Unmarshal(data, &raw); err != nil {
+		var derr *toml.DecodeError
+		if errors.As(err, &derr) {
+			line, col := derr.Position()
+			return []Problem{{Line: line, Column: col, Message: err.Error()}}, nil
+		}
+		return []Problem{{Message: err.Error()}}, nil
+	}
+	c.keys = indexKeys(data)
+
+	stripped, options := splitRuleOptions(raw)
+	c.checkSchema(sch, stripped)
+	c.checkRules(raw, options)
+	c.checkDurations(raw)
+	if _, ok := raw["extends"]; ok {
+		if _, err := config.LoadFromFile(path); err != nil {
+			c.add("extends", "%v", err)
+		}
+	}
+
+	slices.SortStableFunc(c.problems, func(a, b Problem) int {
+		if a.Line != b.Line {
+			return cmp.Compare(a.Line, b.Line)
+		}
+		return cmp.Compare(a.Column, b.Column)
+	})
+	return c.problems, nil
+}
+
+func (c *checker) add(key, format string, args ...any) {
+	pos := c.keys.lookup(key)
+	c.problems = append(c.problems, Problem{
+		Key:     key,
+		Line:    pos.Line,
+		Column:  pos.Column,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// compileSchema compiles the config JSON schema.
+func compileSchema(schema []byte) (*jsonschema.Schema, error) {
+	// Use an absolute URI so the library doesn't resolve against cwd.
+	const schemaURI = "urn:tally:config"
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(schema))
+	if err != nil {
+		return nil, err
+	}
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource(schemaURI, doc); err != nil {
+		return nil, err
+	}
+	return compiler.Compile(schemaURI)
+}
+
+// ruleOptions are the rule-specific options of one rule table.
+type ruleOptions struct {
+	key     string // dotted key of the rule table
+	code    string // rule code, e.g. "tally/max-lines"
+	options map[string]any
+}
+
+// splitRuleOptions returns a copy of raw without rule-specific options (which
+// the schema does not describe), and the options it removed.
+func splitRuleOptions(raw map[string]any) (map[string]any, []ruleOptions) {
+	var options []ruleOptions
+	stripRules := func(prefix string, rulesTable map[string]any) map[string]any {
+		out := maps.Clone(rulesTable)
+		for ns, v := range rulesTable {
+			nsTable, ok := v.(map[string]any)
+			if !ok || ns == "include" || ns == "exclude" {
+				continue
+			}
+			nsOut := make(map[string]any, len(nsTable))
+			for name, v := range nsTable {
+				ruleTable, ok := v.(map[string]any)
+				if !ok {
+					nsOut[name] = v
+					continue
+				}
+				settings := make(map[string]any)
+				opts := make(map[string]any)
+				for key, v := range ruleTable {
+					if slices.Contains(ruleTableKeys, key) {
+						settings[key] = v
+					} else {
+						opts[key] = v
+					}
+				}
+				nsOut[name] = settings
+				options = append(options, ruleOptions{
+					key:     prefix + "." + ns + "." + name,
+					code:    ns + "/" + name,
+					options: opts,
+				})
+			}
+			out[ns] = nsOut
+		}
+		return out
+	}
+
+	stripped := maps.Clone(raw)
+	if rulesTable, ok := raw["rules"].(map[string]any); ok {
+		stripped["rules"] = stripRules("rules", rulesTable)
+	}
+	if overrides, ok := raw["overrides"].([]any); ok {
+		outOverrides := make([]any, len(overrides))
+		for i, o := range overrides {
+			outOverrides[i] = o
+			block, ok := o.(map[string]any)
+			if !ok {
+				continue
+			}
+			rulesTable, ok := block["rules"].(map[string]any)
+			if !ok {
+				continue
+			}
+			block = maps.Clone(block)
+			block["rules"] = stripRules(fmt.Sprintf("overrides.%d.rules", i), rulesTable)
+			outOverrides[i] = block
+		}
+		stripped["overrides"] = outOverrides
+	}
+	return stripped, options
+}
+
+// checkSchema validates doc against the config schema.
+func (c *checker) checkSchema(sch *jsonschema.Schema, doc map[string]any) {
+	// The validator expects JSON values (float64 numbers, no TOML dates).
+	data, err := json.Marshal(doc)
+	if err != nil {
+		c.add("", "%v", err)
+		return
+	}
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		c.add("", "%v", err)
+		return
+	}
+
+	err = sch.Validate(value)
+	var verr *jsonschema.ValidationError
+	if !errors.As(err, &verr) {
+		return
+	}
+	for _, leaf := range leafErrors(verr) {
+		key := strings.Join(leaf.InstanceLocation, ".")
+		if ap, ok := leaf.ErrorKind.(*kind.AdditionalProperties); ok {
+			for _, prop := range ap.Properties {
+				c.add(joinKey(key, prop), "unknown key %q", joinKey(key, prop))
+			}
+			continue
+		}
+		msg := leaf.BasicOutput().Error.String()
+		if key == "" {
+			c.add(key, "%s", msg)
+		} else {
+			c.add(key, "%s: %s", key, msg)
+		}
+	}
+}
+
+// leafErrors returns the innermost validation errors.
+func leafErrors(verr *jsonschema.ValidationError) []*jsonschema.ValidationError {
+	if len(verr.Causes) == 0 {
+		return []*jsonschema.ValidationError{verr}
+	}
+	var out []*jsonschema.ValidationError
+	for _, cause := range verr.Causes {
+		out = append(out, leafErrors(cause)...)
+	}
+	return out
+}
+
+// checkRules reports unknown rule codes, patterns that match no rule and
+// invalid rule options.
+func (c *checker) checkRules(raw map[string]any, options []ruleOptions) {
+	if rulesTable, ok := raw["rules"].(map[string]any); ok {
+		c.checkPatterns("rules", rulesTable)
+	}
+	if overrides, ok := raw["overrides"].([]any); ok {
+		for i, o := range overrides {
+			block, _ := o.(map[string]any)
+			if rulesTable, ok := block["rules"].(map[string]any); ok {
+				c.checkPatterns(fmt.Sprintf("overrides.%d.rules", i), rulesTable)
+			}
+		}
+	}
+
+	for _, ro := range options {
+		info, ok := c.lookup(ro.code)
+		if !ok {
+			if similar, found := linter.LookupRule(ro.code); found {
+				c.add(ro.key, "unknown rule %q (did you mean %q?)", ro.code, similar.Code)
+			} else {
+				c.add(ro.key, "unknown rule %q", ro.code)
+			}
+			continue
+		}
+		if len(ro.options) == 0 {
+			continue
+		}
+		cr, ok := info.Rule.(rules.ConfigurableRule)
+		if !ok {
+			for _, key := range slices.Sorted(maps.Keys(ro.options)) {
+				c.add(ro.key+"."+key, "unknown key %q: %s has no options", ro.key+"."+key, ro.code)
+			}
+			continue
+		}
+		if err := cr.ValidateConfig(ro.options); err != nil {
+			c.add(ro.key, "%s: %v", ro.code, err)
+		}
+	}
+}
+
+// checkPatterns reports include/exclude patterns that match no known rule.
+func (c *checker) checkPatterns(prefix string, rulesTable map[string]any) {
+	for _, field := range []string{"include", "exclude"} {
+		patterns, _ := rulesTable[field].([]any)
+		for _, p := range patterns {
+			pattern, ok := p.(string)
+			if !ok {
+				continue // reported by the schema
+			}
+			if !slices.ContainsFunc(c.catalog, func(ri linter.RuleInfo) bool {
+				return config.MatchesRulePattern(ri.Code, pattern)
+			}) {
+				c.add(prefix+"."+field, "%s.%s: pattern %q matches no rule", prefix, field, pattern)
+			}
+		}
+	}
+}
+
+// lookup finds a rule by exact code.
+func (c *checker) lookup(code string) (linter.RuleInfo, bool) {
+	i := slices.IndexFunc(c.catalog, func(ri linter.RuleInfo) bool { return ri.Code == code })
+	if i < 0 {
+		return linter.RuleInfo{}, false
+	}
+	return c.catalog[i], true
+}
+
+// checkDurations reports duration settings that time.ParseDuration rejects.
+func (c *checker) checkDurations(raw map[string]any) {
+	for _, key := range durationKeys {
+		section, name, _ := strings.Cut(key, ".")
+		table, _ := raw[section].(map[string]any)
+		s, ok := table[name].(string)
+		if !ok {
+			continue
+		}
+		if _, err := time.ParseDuration(s); err != nil {
+			c.add(key, "%s: invalid duration %q (use e.g. 30s, 5m, 24h)", key, s)
+		}
+	}
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
@@ -0,0 +1,146 @@
+package configcheck
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func loadSchema(t *testing.T) []byte {
+	t.Helper()
+	schema, err := os.ReadFile(filepath.Join("..", "..", "schema.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return schema
+}
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+	schema := loadSchema(t)
+
+	tests := []struct {
+		name    string
+		content string
+		want    []string // substrings of each expected problem, in order
+	}{
+		{
+			name: "valid",
+			content: `extends = ["preset:recommended"]
+
+[rules]
+include = ["buildkit/*"]
+exclude = ["hadolint/DL3006"]
+
+[rules.tally.max-lines]
+severity = "error"
+max = 100
+
+[slow-checks]
+timeout = "30s"
+
+[[overrides]]
+files = ["legacy/**"]
+
+[overrides.rules.tally.max-lines]
+max = 300
+`,
+		},
+		{
+			name:    "syntax error",
+			content: "[output]\nformat = \n",
+			want:    []string{"2:10: toml: incomplete number"},
+		},
+		{
+			name:    "unknown top-level key",
+			content: "\n\nverbose = true\n",
+			want:    []string{`3:1: unknown key "verbose"`},
+		},
+		{
+			name:    "unknown nested key",
+			content: "[output]\nformat = \"text\"\ncolour = true\n",
+			want:    []string{`3:1: unknown key "output.colour"`},
+		},
+		{
+			name:    "bad enum",
+			content: "[output]\nfail-level = \"fatal\"\n",
+			want: []string{
+				`2:1: output.fail-level: value must be one of 'error', 'warning', 'info', 'style', 'none'`,
+			},
+		},
+		{
+			name:    "bad rule severity",
+			content: "[rules.hadolint.DL3006]\nseverity = \"loud\"\n",
+			want: []string{
+				`2:1: rules.hadolint.DL3006.severity: value must be one of 'off', 'error', 'warning', 'info', 'style'`,
+			},
+		},
+		{
+			name:    "bad duration",
+			content: "[slow-checks]\ncache-ttl = \"1 day\"\n",
+			want:    []string{`2:1: slow-checks.cache-ttl: invalid duration "1 day" (use e.g. 30s, 5m, 24h)`},
+		},
+		{
+			name:    "unknown rule",
+			content: "[rules.hadolint.DL9999]\nseverity = \"off\"\n",
+			want:    []string{`1:2: unknown rule "hadolint/DL9999"`},
+		},
+		{
+			name:    "unknown rule with different case",
+			content: "[rules.hadolint]\ndl3006 = { severity = \"off\" }\n",
+			want:    []string{`2:1: unknown rule "hadolint/dl3006" (did you mean "hadolint/DL3006"?)`},
+		},
+		{
+			name:    "pattern matching no rule",
+			content: "[rules]\ninclude = [\"hadolnt/*\"]\n",
+			want:    []string{`2:1: rules.include: pattern "hadolnt/*" matches no rule`},
+		},
+		{
+			name:    "invalid rule option",
+			content: "[rules.tally.max-lines]\nmax = -1\n",
+			want:    []string{`1:2: tally/max-lines: 'oneOf' failed`},
+		},
+		{
+			name:    "option on rule without options",
+			content: "[rules.hadolint.DL3006]\nstrict = true\n",
+			want:    []string{`2:1: unknown key "rules.hadolint.DL3006.strict": hadolint/DL3006 has no options`},
+		},
+		{
+			name:    "override block",
+			content: "[[overrides]]\nfiles = [\"a\"]\n\n[[overrides]]\nfiles = [\"b\"]\nsevere = true\n",
+			want:    []string{`6:1: unknown key "overrides.1.severe"`},
+		},
+		{
+			name:    "unknown preset",
+			content: "extends = [\"preset:nope\"]\n",
+			want:    []string{`unknown preset "nope"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			path := filepath.Join(t.TempDir(), ".tally.toml")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			problems, err := Check(path, schema)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, p := range problems {
+				got = append(got, p.String())
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Check() = %q, want %q", got, tt.want)
+			}
+			for i := range got {
+				if !strings.Contains(got[i], tt.want[i]) {
+					t.Errorf("problem %d = %q, want it to contain %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package configcheck
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2/unstable"
+)
+
+// position is a 1-based line and column in a config file.
+type position struct {
+	Line   int
+	Column int
+}
+
+// keyPositions maps the dotted keys of a TOML document to where they are
+// defined. Array-of-tables entries are addressed by index, e.g.
+// "overrides.0.rules". Keys with dots inside quotes are not distinguished.
+type keyPositions map[string]position
+
+// indexKeys parses data and records the position of every table header and
+// key, including keys of inline tables. It stops silently at syntax errors;
+// those are reported by the decoder.
+func indexKeys(data []byte) keyPositions {
+	idx := make(keyPositions)
+	arrays := make(map[string]int) // array table path -> entries seen
+
+	p := unstable.Parser{}
+	p.Reset(data)
+	var prefix []string
+	for p.NextExpression() {
+		expr := p.Expression()
+		switch expr.Kind {
+		case unstable.Table, unstable.ArrayTable:
+			keys, first := keyParts(expr.Key())
+			prefix = prefix[:0]
+			for _, k := range keys {
+				prefix = append(prefix, k)
+				if n, ok := arrays[strings.Join(prefix, ".")]; ok && (expr.Kind == unstable.Table || len(prefix) < len(keys)) {
+					prefix = append(prefix, strconv.Itoa(n-1))
+				}
+			}
+			if expr.Kind == unstable.ArrayTable {
+				path := strings.Join(prefix, ".")
+				prefix = append(prefix, strconv.Itoa(arrays[path]))
+				arrays[path]++
+			}
+			idx.add(&p, prefix, first)
+		case unstable.KeyValue:
+			idx.addKeyValue(&p, prefix, expr)
+		default:
+		}
+	}
+	return idx
+}
+
+// addKeyValue records a key/value pair under prefix, recursing into inline tables.
+func (idx keyPositions) addKeyValue(p *unstable.Parser, prefix []string, kv *unstable.Node) {
+	keys, first := keyParts(kv.Key())
+	full := append(append([]string(nil), prefix...), keys...)
+	idx.add(p, full, first)
+
+	value := kv.Value()
+	if value.Kind != unstable.InlineTable {
+		return
+	}
+	it := value.Children()
+	for it.Next() {
+		if child := it.Node(); child.Kind == unstable.KeyValue {
+			idx.addKeyValue(p, full, child)
+		}
+	}
+}
+
+func (idx keyPositions) add(p *unstable.Parser, keys []string, node *unstable.Node) {
+	if node == nil || len(keys) == 0 {
+		return
+	}
+	path := strings.Join(keys, ".")
+	if _, ok := idx[path]; ok {
+		return
+	}
+	start := p.Shape(node.Raw).Start
+	idx[path] = position{Line: start.Line, Column: start.Column}
+}
+
+// keyParts returns the segments of a (possibly dotted) key and its first node.
+func keyParts(it unstable.Iterator) ([]string, *unstable.Node) {
+	var parts []string
+	var first *unstable.Node
+	for it.Next() {
+		n := it.Node()
+		if first == nil {
+			first = n
+		}
+		parts = append(parts, string(n.Data))
+	}
+	return parts, first
+}
+
+// lookup returns the position of key, or of its closest defined parent.
+func (idx keyPositions) lookup(key string) position {
+	for key != "" {
+		if pos, ok := idx[key]; ok {
+			return pos
+		}
+		i := strings.LastIndex(key, ".")
+		if i < 0 {
+			break
+		}
+		key = key[:i]
+	}
+	return position{}
+}
@@ -3,6 +3,7 @@ package context
 import (
 	"os"
 	"path/filepath"
+	"slices"
 
 	"github.com/moby/patternmatcher/ignorefile"
 )
@@ -14,6 +15,12 @@ var dockerignoreNames = []string{
 	".containerignore",
 }
 
+// IgnoreFileNames returns the names of the ignore files looked up in a build
+// context directory, in order of preference.
+func IgnoreFileNames() []string {
+	return slices.Clone(dockerignoreNames)
+}
+
 // LoadDockerignore reads ignore patterns from the first existing ignore file
 // (.dockerignore preferred, then .containerignore). Returns nil if no ignore file exists.
 // An empty ignore file is valid and means "ignore no files" - we don't fall through
@@ -2,12 +2,14 @@
 //
 // This package implements comment-based suppression compatible with:
 //   - tally:    # tally ignore=RULE1,RULE2 or # tally global ignore=...
+//     and block-scoped # tally disable=RULE1,RULE2 ... # tally enable=RULE1,RULE2
 //   - hadolint: # hadolint ignore=RULE1,RULE2 (migration compatibility)
 //   - buildx:   # check=skip=RULE1,RULE2 (Docker buildx compatibility)
 //
 // Directives can be:
 //   - Next-line: Affects the next non-comment line only
 //   - Global: Affects the entire file
+//   - Range: Affects the lines between a disable and the matching enable
 package directive
 
 import (
@@ -23,6 +25,9 @@ const (
 	TypeNextLine DirectiveType = iota
 	// TypeGlobal affects the entire file.
 	TypeGlobal
+	// TypeRange affects the lines between a disable directive and the
+	// matching enable directive (or the end of the file).
+	TypeRange
 )
 
 // String returns a human-readable name for the directive type.
@@ -32,6 +37,8 @@ func (t DirectiveType) String() string {
 		return "next-line"
 	case TypeGlobal:
 		return "global"
+	case TypeRange:
+		return "range"
 	default:
 		return "unknown"
 	}
@@ -43,7 +50,7 @@ type LineRange struct {
 	// Start is the 0-based line number (inclusive).
 	Start int
 	// End is the 0-based line number (inclusive).
-	// For global directives, this is math.MaxInt.
+	// For global directives and unterminated ranges, this is math.MaxInt.
 	End int
 }
 
@@ -59,7 +66,7 @@ func GlobalRange() LineRange {
 
 // Directive represents a parsed inline suppression directive.
 type Directive struct {
-	// Type indicates whether this is a next-line or global directive.
+	// Type indicates whether this is a next-line, global or range directive.
 	Type DirectiveType
 
 	// Rules contains the rule codes to suppress.
@@ -67,11 +74,16 @@ type Directive struct {
 	Rules []string
 
 	// Line is the 0-based line number where the directive appears.
+	// For range directives, this is the line of the disable comment.
 	Line int
 
 	// AppliesTo is the range of lines affected by this directive.
 	AppliesTo LineRange
 
+	// Unterminated is set for range directives without a matching enable
+	// directive; they apply until the end of the file.
+	Unterminated bool
+
 	// Used is set to true when this directive suppresses at least one violation.
 	// Used for unused directive detection.
 	Used bool
@@ -139,6 +151,31 @@ func matchesRule(pattern, ruleCode string) bool {
 	return false
 }
 
+// Format renders a next-line or global ignore directive as comment text that
+// parses back to the same type, rules and reason:
+// # tally [global] ignore=RULE1,RULE2[;reason=explanation].
+// Directives without a source are rendered in tally syntax.
+func (d *Directive) Format() string {
+	source := d.Source
+	if source == "" || source == SourceBuildx {
+		source = SourceTally
+	}
+
+	var b strings.Builder
+	b.WriteString("# ")
+	b.WriteString(string(source))
+	if d.Type == TypeGlobal {
+		b.WriteString(" global")
+	}
+	b.WriteString(" ignore=")
+	b.WriteString(strings.Join(d.Rules, ","))
+	if d.Reason != "" {
+		b.WriteString(";reason=")
+		b.WriteString(d.Reason)
+	}
+	return b.String()
+}
+
 // SuppressesLine returns true if this directive suppresses violations on the given line.
 // Line is 0-based.
 func (d *Directive) SuppressesLine(line int) bool {
@@ -2,6 +2,7 @@ package directive
 
 import (
 	"math"
+	"slices"
 	"testing"
 
 	"github.com/tinovyatkin/tally/internal/rules"
@@ -567,6 +568,7 @@ func TestDirectiveType_String(t *testing.T) {
 	}{
 		{TypeNextLine, "next-line"},
 		{TypeGlobal, "global"},
+		{TypeRange, "range"},
 		{DirectiveType(99), "unknown"},
 	}
 
@@ -914,3 +916,188 @@ func TestParseShellDirectiveNoMatch(t *testing.T) {
 		})
 	}
 }
+
+func TestParseRange(t *testing.T) {
+	t.Parallel()
+	content := `FROM alpine
+# tally disable=DL3018,SC2086;reason=vendored installer
+RUN apk add curl
+RUN <<EOF
+install.sh $ARGS
+EOF
+# tally enable=DL3018,SC2086
+RUN apk add git`
+	sm := sourcemap.New([]byte(content))
+	result := Parse(sm, nil)
+
+	if len(result.Errors) != 0 {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+	if len(result.Directives) != 1 {
+		t.Fatalf("expected 1 directive, got %d", len(result.Directives))
+	}
+	d := result.Directives[0]
+	if d.Type != TypeRange {
+		t.Errorf("expected TypeRange, got %v", d.Type)
+	}
+	if d.AppliesTo.Start != 2 || d.AppliesTo.End != 5 {
+		t.Errorf("expected AppliesTo {2, 5}, got %v", d.AppliesTo)
+	}
+	if d.Reason != "vendored installer" {
+		t.Errorf("expected reason, got %q", d.Reason)
+	}
+	if d.Unterminated {
+		t.Error("range with enable directive should not be unterminated")
+	}
+}
+
+func TestParseRangeUnterminated(t *testing.T) {
+	t.Parallel()
+	content := `FROM alpine
+# tally disable=DL3018
+RUN apk add curl`
+	result := Parse(sourcemap.New([]byte(content)), nil)
+
+	if len(result.Directives) != 1 {
+		t.Fatalf("expected 1 directive, got %d", len(result.Directives))
+	}
+	d := result.Directives[0]
+	if !d.Unterminated {
+		t.Error("expected unterminated range")
+	}
+	if d.AppliesTo.Start != 2 || d.AppliesTo.End != math.MaxInt {
+		t.Errorf("expected AppliesTo {2, MaxInt}, got %v", d.AppliesTo)
+	}
+}
+
+func TestParseRangePartialEnable(t *testing.T) {
+	t.Parallel()
+	content := `# tally disable=DL3018,DL3019
+RUN apk add curl
+# tally enable=hadolint/DL3018
+RUN apk add git`
+	result := Parse(sourcemap.New([]byte(content)), nil)
+
+	if len(result.Directives) != 2 {
+		t.Fatalf("expected 2 directives, got %d: %+v", len(result.Directives), result.Directives)
+	}
+	byRule := make(map[string]Directive)
+	for _, d := range result.Directives {
+		if len(d.Rules) != 1 {
+			t.Fatalf("expected one rule per split directive, got %v", d.Rules)
+		}
+		byRule[d.Rules[0]] = d
+	}
+	if r := byRule["DL3018"].AppliesTo; r.Start != 1 || r.End != 1 {
+		t.Errorf("DL3018 range = %v, want {1, 1}", r)
+	}
+	if d := byRule["DL3019"]; !d.Unterminated || d.AppliesTo.End != math.MaxInt {
+		t.Errorf("DL3019 should stay disabled to end of file, got %+v", d)
+	}
+}
+
+func TestParseRangeEnableAll(t *testing.T) {
+	t.Parallel()
+	content := `# tally disable=DL3018
+# tally disable=all
+RUN apk add curl
+# tally enable=all
+RUN apk add git`
+	result := Parse(sourcemap.New([]byte(content)), nil)
+
+	if len(result.Directives) != 2 {
+		t.Fatalf("expected 2 directives, got %d", len(result.Directives))
+	}
+	for _, d := range result.Directives {
+		if d.Unterminated || d.AppliesTo.End != 2 {
+			t.Errorf("directive %v should end on line 2, got %+v", d.Rules, d.AppliesTo)
+		}
+	}
+}
+
+func TestParseRangeEnableWithoutDisable(t *testing.T) {
+	t.Parallel()
+	content := `# tally disable=DL3018
+RUN apk add curl
+# tally enable=DL3018,DL3019`
+	result := Parse(sourcemap.New([]byte(content)), nil)
+
+	if len(result.Errors) != 1 {
+		t.Fatalf("expected 1 error, got %v", result.Errors)
+	}
+	if result.Errors[0].Line != 2 || result.Errors[0].Message != "no open disable directive for: DL3019" {
+		t.Errorf("unexpected error: %+v", result.Errors[0])
+	}
+}
+
+func TestFilterRange(t *testing.T) {
+	t.Parallel()
+	content := `FROM alpine
+# tally disable=DL3018
+RUN apk add curl
+RUN apk add wget
+# tally enable=DL3018
+RUN apk add git`
+	directives := Parse(sourcemap.New([]byte(content)), nil).Directives
+
+	violations := []rules.Violation{
+		rules.NewViolation(rules.NewLineLocation("Dockerfile", 3), "hadolint/DL3018", "pin", rules.SeverityWarning),
+		rules.NewViolation(rules.NewLineLocation("Dockerfile", 4), "hadolint/DL3018", "pin", rules.SeverityWarning),
+		rules.NewViolation(rules.NewLineLocation("Dockerfile", 4), "hadolint/DL3019", "cache", rules.SeverityWarning),
+		rules.NewViolation(rules.NewLineLocation("Dockerfile", 6), "hadolint/DL3018", "pin", rules.SeverityWarning),
+	}
+	result := Filter(violations, directives)
+
+	if len(result.Suppressed) != 2 {
+		t.Errorf("expected 2 suppressed, got %d", len(result.Suppressed))
+	}
+	if len(result.Violations) != 2 {
+		t.Fatalf("expected 2 remaining violations, got %d", len(result.Violations))
+	}
+	if result.Violations[0].RuleCode != "hadolint/DL3019" || result.Violations[1].Line() != 6 {
+		t.Errorf("unexpected remaining violations: %+v", result.Violations)
+	}
+}
+
+func TestDirectiveFormat(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		directive Directive
+		want      string
+	}{
+		{
+			directive: Directive{Type: TypeNextLine, Rules: []string{"hadolint/DL3008"}},
+			want:      "# tally ignore=hadolint/DL3008",
+		},
+		{
+			directive: Directive{
+				Type:   TypeGlobal,
+				Rules:  []string{"DL3006", "tally/max-lines"},
+				Source: SourceTally,
+				Reason: "Generated file, size is expected",
+			},
+			want: "# tally global ignore=DL3006,tally/max-lines;reason=Generated file, size is expected",
+		},
+		{
+			directive: Directive{Type: TypeNextLine, Rules: []string{"DL3018"}, Source: SourceHadolint},
+			want:      "# hadolint ignore=DL3018",
+		},
+	}
+	for _, tt := range tests {
+		got := tt.directive.Format()
+		if got != tt.want {
+			t.Errorf("Format() = %q, want %q", got, tt.want)
+			continue
+		}
+
+		// Round trip through the parser.
+		result := Parse(sourcemap.New([]byte(got+"\nFROM alpine")), nil)
+		if len(result.Directives) != 1 {
+			t.Fatalf("expected 1 directive parsing %q, got %d", got, len(result.Directives))
+		}
+		d := result.Directives[0]
+		if d.Type != tt.directive.Type || d.Reason != tt.directive.Reason || !slices.Equal(d.Rules, tt.directive.Rules) {
+			t.Errorf("parsing %q: got %+v", got, d)
+		}
+	}
+}
@@ -1,7 +1,10 @@
 package directive
 
 import (
+	"cmp"
+	"math"
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/tinovyatkin/tally/internal/sourcemap"
@@ -17,6 +20,10 @@ var (
 	tallyPattern = regexp.MustCompile(
 		`(?i)#\s*tally\s+(global\s+)?ignore\s*=\s*([A-Za-z0-9_,\s/.-]+?)(?:;reason\s*=\s*(.*))?$`)
 
+	// # tally disable=RULE1,RULE2[;reason=explanation] / # tally enable=RULE1,RULE2
+	tallyRangePattern = regexp.MustCompile(
+		`(?i)#\s*tally\s+(disable|enable)\s*=\s*([A-Za-z0-9_,\s/.-]+?)(?:;reason\s*=\s*(.*))?$`)
+
 	// # hadolint [global] ignore=RULE1,RULE2[;reason=explanation]
 	// Note: ;reason= is a tally extension, not part of hadolint's native syntax
 	hadolintPattern = regexp.MustCompile(
@@ -47,12 +54,17 @@ type RuleValidator func(string) bool
 func Parse(sm *sourcemap.SourceMap, validator RuleValidator) *ParseResult {
 	result := &ParseResult{}
 	comments := sm.Comments()
+	var ranges rangeTracker
 
 	for _, comment := range comments {
 		if !comment.IsDirective {
 			continue
 		}
 
+		if ranges.parse(comment, result) {
+			continue
+		}
+
 		// Try ignore directive patterns first
 		if d, err := parseTally(comment, sm); d != nil || err != nil {
 			if err != nil {
@@ -96,9 +108,125 @@ func Parse(sm *sourcemap.SourceMap, validator RuleValidator) *ParseResult {
 		}
 	}
 
+	// Disable blocks that are never re-enabled apply to the rest of the file.
+	for _, d := range ranges.open {
+		d.Unterminated = true
+		ranges.closed = append(ranges.closed, d)
+	}
+	if len(ranges.closed) > 0 {
+		for i := range ranges.closed {
+			validateDirective(&ranges.closed[i], validator, result)
+		}
+		// Keep directives in source order for deterministic first-match-wins filtering.
+		slices.SortStableFunc(result.Directives, func(a, b Directive) int {
+			return cmp.Compare(a.Line, b.Line)
+		})
+	}
+
 	return result
 }
 
+// rangeTracker pairs # tally disable=... and # tally enable=... directives
+// while comments are scanned in source order.
+type rangeTracker struct {
+	// open holds disable directives awaiting their enable directive.
+	open []Directive
+	// closed holds completed (or split-off) range directives.
+	closed []Directive
+}
+
+// parse handles a disable or enable directive. It returns false if the
+// comment is neither.
+func (t *rangeTracker) parse(comment sourcemap.Comment, result *ParseResult) bool {
+	matches := tallyRangePattern.FindStringSubmatch(comment.Text)
+	if matches == nil {
+		return false
+	}
+
+	rules, err := parseRuleList(matches[2])
+	if err != nil {
+		result.Errors = append(result.Errors, ParseError{
+			Line:    comment.Line,
+			Message: err.Error(),
+			RawText: comment.Text,
+		})
+		return true
+	}
+
+	if strings.EqualFold(matches[1], "disable") {
+		t.open = append(t.open, Directive{
+			Type:      TypeRange,
+			Rules:     rules,
+			Line:      comment.Line,
+			AppliesTo: LineRange{Start: comment.Line + 1, End: math.MaxInt},
+			RawText:   comment.Text,
+			Source:    SourceTally,
+			Reason:    strings.TrimSpace(matches[3]),
+		})
+		return true
+	}
+
+	if unmatched := t.enable(rules, comment.Line); len(unmatched) > 0 {
+		result.Errors = append(result.Errors, ParseError{
+			Line:    comment.Line,
+			Message: "no open disable directive for: " + strings.Join(unmatched, ", "),
+			RawText: comment.Text,
+		})
+	}
+	return true
+}
+
+// enable ends the disable blocks for rules on the line before line. A block
+// disabling several rules is split when only some of them are re-enabled.
+// It returns the rules that had no open block.
+func (t *rangeTracker) enable(rules []string, line int) []string {
+	enableAll := slices.Contains(rules, "all")
+	matched := make(map[string]bool, len(rules))
+
+	stillOpen := t.open[:0]
+	for _, d := range t.open {
+		var ended, remaining []string
+		for _, r := range d.Rules {
+			idx := slices.IndexFunc(rules, func(e string) bool { return matchesRule(e, r) })
+			switch {
+			case enableAll:
+				ended = append(ended, r)
+			case idx >= 0:
+				ended = append(ended, r)
+				matched[rules[idx]] = true
+			default:
+				remaining = append(remaining, r)
+			}
+		}
+		if len(ended) == 0 {
+			stillOpen = append(stillOpen, d)
+			continue
+		}
+
+		done := d
+		done.Rules = ended
+		done.AppliesTo.End = line - 1
+		t.closed = append(t.closed, done)
+
+		if len(remaining) > 0 {
+			d.Rules = remaining
+			stillOpen = append(stillOpen, d)
+		}
+	}
+	t.open = stillOpen
+
+	if enableAll {
+		return nil
+	}
+	var unmatched []string
+	for _, r := range rules {
+		if !matched[r] {
+			unmatched = append(unmatched, r)
+		}
+	}
+	return unmatched
+}
+
 // validateDirective validates rule codes and adds the directive or errors.
 func validateDirective(d *Directive, validator RuleValidator, result *ParseResult) {
 	if validator != nil {
@@ -39,6 +39,10 @@ type Options struct {
 	// ContextDir is the build context directory to use for all discovered files.
 	// If empty, no context is set.
 	ContextDir string
+
+	// Include, if set, reports whether a file (by absolute path) should be
+	// kept. It applies to explicit file inputs as well as discovered files.
+	Include func(absPath string) bool
 }
 
 // DefaultPatterns returns the default Dockerfile patterns.
@@ -135,7 +139,7 @@ func discoverFile(path string, opts Options, seen map[string]bool) ([]Discovered
 	}
 
 	// Check for exclusion
-	if isExcluded(absPath, opts.ExcludePatterns) {
+	if isExcluded(absPath, opts.ExcludePatterns) || !isIncluded(absPath, opts) {
 		return nil, nil
 	}
 
@@ -201,7 +205,7 @@ func globMatches(pattern string, opts Options, seen map[string]bool) ([]Discover
 		}
 
 		// Check for exclusion
-		if isExcluded(absPath, opts.ExcludePatterns) {
+		if isExcluded(absPath, opts.ExcludePatterns) || !isIncluded(absPath, opts) {
 			continue
 		}
 
@@ -222,6 +226,11 @@ func globMatches(pattern string, opts Options, seen map[string]bool) ([]Discover
 	return results, nil
 }
 
+// isIncluded applies the optional Include filter.
+func isIncluded(absPath string, opts Options) bool {
+	return opts.Include == nil || opts.Include(absPath)
+}
+
 // isExcluded checks if a path matches any exclusion pattern.
 // Patterns use doublestar glob syntax (**, *, ?, [...]).
 //
@@ -223,6 +223,28 @@ func TestDiscoverContextDir(t *testing.T) {
 	}
 }
 
+func TestDiscoverInclude(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+	for _, name := range []string{"Dockerfile", "Dockerfile.dev"} {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte("FROM alpine\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	opts := Options{
+		Include: func(absPath string) bool { return filepath.Base(absPath) == "Dockerfile.dev" },
+	}
+	results, err := Discover([]string{tmpDir, filepath.Join(tmpDir, "Dockerfile")}, opts)
+	if err != nil {
+		t.Fatalf("Discover() error: %v", err)
+	}
+
+	if len(results) != 1 || filepath.Base(results[0].Path) != "Dockerfile.dev" {
+		t.Errorf("expected only Dockerfile.dev, got %+v", results)
+	}
+}
+
 func TestDiscoverDeduplication(t *testing.T) {
 	t.Parallel()
 	// Create a temporary directory with a Dockerfile
@@ -293,33 +293,37 @@ func buildLinterConfig(cfg *config.Config, warnFunc linter.LintWarnFunc) *linter
 		return lintCfg
 	}
 
-	// Determine if buildkit/* is explicitly included.
-	// If so, enable all experimental rules without maintaining a separate list.
-	includeAllBuildkit := slices.Contains(cfg.Rules.Include, "buildkit/*")
-	if includeAllBuildkit {
-		lintCfg.ExperimentalAll = true
-	}
-
-	// Check Exclude patterns for buildkit rules
-	for _, pattern := range cfg.Rules.Exclude {
-		// Handle "buildkit/*" - skip all buildkit rules
-		if pattern == "buildkit/*" {
-			// Can't skip all at once in BuildKit, but this is rare
-			// Individual rules will be filtered by our processor
-			continue
+	for _, sel := range cfg.Rules.Selections() {
+		// Determine if buildkit/* is explicitly included.
+		// If so, enable all experimental rules without maintaining a separate list.
+		if slices.Contains(sel.Include, "buildkit/*") {
+			lintCfg.ExperimentalAll = true
 		}
-		// Handle specific buildkit rule: "buildkit/StageNameCasing"
-		if ns, name := parseRuleCode(pattern); ns == "buildkit" && name != "" {
-			lintCfg.SkipRules = append(lintCfg.SkipRules, name)
+
+		// Check Exclude patterns for buildkit rules
+		for _, pattern := range sel.Exclude {
+			// Handle "buildkit/*" - skip all buildkit rules
+			if pattern == "buildkit/*" {
+				// Can't skip all at once in BuildKit, but this is rare
+				// Individual rules will be filtered by our processor
+				continue
+			}
+			// Handle specific buildkit rule: "buildkit/StageNameCasing",
+			// unless a later layer enables it again.
+			if ns, name := parseRuleCode(pattern); ns == "buildkit" && name != "" && isDisabled(cfg, pattern) &&
+				!slices.Contains(lintCfg.SkipRules, name) {
+				lintCfg.SkipRules = append(lintCfg.SkipRules, name)
+			}
 		}
-	}
 
-	// Check Include patterns for experimental rules.
-	// We don't need to know which rules are experimental: adding the name is enough.
-	for _, pattern := range cfg.Rules.Include {
-		// Handle specific buildkit rule: "buildkit/InvalidDefinitionDescription"
-		if ns, name := parseRuleCode(pattern); ns == "buildkit" && name != "" && name != "*" {
-			lintCfg.ExperimentalRules = append(lintCfg.ExperimentalRules, name)
+		// Check Include patterns for experimental rules.
+		// We don't need to know which rules are experimental: adding the name is enough.
+		for _, pattern := range sel.Include {
+			// Handle specific buildkit rule: "buildkit/InvalidDefinitionDescription"
+			if ns, name := parseRuleCode(pattern); ns == "buildkit" && name != "" && name != "*" &&
+				!isDisabled(cfg, pattern) && !slices.Contains(lintCfg.ExperimentalRules, name) {
+				lintCfg.ExperimentalRules = append(lintCfg.ExperimentalRules, name)
+			}
 		}
 	}
 
@@ -336,6 +340,12 @@ func buildLinterConfig(cfg *config.Config, warnFunc linter.LintWarnFunc) *linter
 	return lintCfg
 }
 
+// isDisabled reports whether the rule selections resolve to disabling ruleCode.
+func isDisabled(cfg *config.Config, ruleCode string) bool {
+	enabled := cfg.Rules.IsEnabled(ruleCode)
+	return enabled != nil && !*enabled
+}
+
 // parseRuleCode parses a rule code into namespace and name.
 func parseRuleCode(ruleCode string) (string, string) {
 	if idx := strings.Index(ruleCode, "/"); idx > 0 {
@@ -0,0 +1,100 @@
+package dockerfile
+
+import (
+	"strings"
+
+	"github.com/moby/buildkit/frontend/dockerfile/instructions"
+
+	"github.com/tinovyatkin/tally/internal/sourcemap"
+)
+
+// RunSourceScript extracts the original source for a RUN instruction
+// and replaces "RUN " and its flags with spaces to preserve column positions
+// for shell parsing.
+// Returns the script and the 1-based start line number.
+func RunSourceScript(run *instructions.RunCommand, sm *sourcemap.SourceMap) (string, int) {
+	runLoc := run.Location()
+	if len(runLoc) == 0 {
+		return "", 0
+	}
+
+	// BuildKit uses 1-based lines
+	startLine := runLoc[0].Start.Line
+	endLine := runLoc[len(runLoc)-1].End.Line
+
+	// Extract original source lines (SourceMap uses 0-based)
+	var lines []string
+	for lineIdx := startLine - 1; lineIdx < endLine; lineIdx++ {
+		if lineIdx >= 0 && lineIdx < sm.LineCount() {
+			lines = append(lines, sm.Line(lineIdx))
+		}
+	}
+
+	if len(lines) == 0 {
+		return "", 0
+	}
+
+	// Replace the instruction prefix with spaces to preserve column positions for
+	// shell parsing. Handles "RUN " and "ONBUILD RUN " patterns.
+	firstLine := lines[0]
+	upper := strings.ToUpper(firstLine)
+	if idx := strings.Index(upper, "RUN"); idx >= 0 {
+		// Check that RUN is followed by whitespace (space or tab)
+		afterRun := idx + 3
+		if afterRun < len(firstLine) && (firstLine[afterRun] == ' ' || firstLine[afterRun] == '\t') {
+			// Count contiguous whitespace after RUN
+			wsEnd := afterRun
+			for wsEnd < len(firstLine) && (firstLine[wsEnd] == ' ' || firstLine[wsEnd] == '\t') {
+				wsEnd++
+			}
+			// For ONBUILD RUN, also blank out the "ONBUILD" keyword and any
+			// leading content before "RUN" so the shell parser sees only the
+			// script. Column positions are preserved since we replace 1:1 with spaces.
+			replaceStart := idx
+			if idx > 0 {
+				// Check for ONBUILD prefix (possibly with leading whitespace)
+				prefix := strings.TrimSpace(upper[:idx])
+				if prefix == "ONBUILD" {
+					replaceStart = 0
+				}
+			}
+			replaceLen := wsEnd - replaceStart
+			lines[0] = firstLine[:replaceStart] + strings.Repeat(" ", replaceLen) + firstLine[wsEnd:]
+			blankRunFlags(lines, wsEnd)
+		}
+	}
+
+	return strings.Join(lines, "\n"), startLine
+}
+
+// blankRunFlags replaces the RUN instruction flags (--mount, --network, ...)
+// that start at column col of the first line with spaces, so the shell parser
+// sees only the script. Flags may continue on the following lines after a
+// line continuation.
+func blankRunFlags(lines []string, col int) {
+	for i := range lines {
+		line := []byte(lines[i])
+		for {
+			for col < len(line) && (line[col] == ' ' || line[col] == '\t') {
+				col++
+			}
+			if !strings.HasPrefix(string(line[col:]), "--") {
+				break
+			}
+			for col < len(line) && line[col] != ' ' && line[col] != '\t' {
+				line[col] = ' '
+				col++
+			}
+		}
+		// Flags continue on the next line only after a bare line continuation,
+		// which must be blanked too so the script doesn't start with it.
+		if strings.TrimSpace(string(line[col:])) == "\\" {
+			line[col+strings.IndexByte(string(line[col:]), '\\')] = ' '
+			lines[i] = string(line)
+			col = 0
+			continue
+		}
+		lines[i] = string(line)
+		return
+	}
+}
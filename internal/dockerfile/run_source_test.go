@@ -0,0 +1,75 @@
+package dockerfile
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/moby/buildkit/frontend/dockerfile/instructions"
+
+	"github.com/tinovyatkin/tally/internal/sourcemap"
+)
+
+func TestRunSourceScript(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		content  string
+		want     string
+		wantLine int
+	}{
+		{
+			name:     "single line",
+			content:  "FROM alpine\nRUN echo hi",
+			want:     "    echo hi",
+			wantLine: 2,
+		},
+		{
+			name:     "flags",
+			content:  "FROM alpine\nRUN --network=none --mount=type=cache,target=/root/.cache make",
+			want:     strings.Repeat(" ", len("RUN --network=none --mount=type=cache,target=/root/.cache ")) + "make",
+			wantLine: 2,
+		},
+		{
+			name:    "flags on continuation lines",
+			content: "FROM alpine\nRUN --network=none \\\n    --mount=type=tmpfs,target=/tmp \\\n    make",
+			want: strings.Repeat(" ", len("RUN --network=none \\")) + "\n" +
+				strings.Repeat(" ", len("    --mount=type=tmpfs,target=/tmp \\")) + "\n    make",
+			wantLine: 2,
+		},
+		{
+			name:     "continuation lines",
+			content:  "FROM alpine\nrun cd /app && \\\n    make",
+			want:     "    cd /app && \\\n    make",
+			wantLine: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			result, err := Parse(strings.NewReader(tt.content), nil)
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			run := findRun(result)
+			if run == nil {
+				t.Fatal("no RUN instruction")
+			}
+			got, line := RunSourceScript(run, sourcemap.New([]byte(tt.content)))
+			if got != tt.want || line != tt.wantLine {
+				t.Errorf("RunSourceScript() = %q, %d, want %q, %d", got, line, tt.want, tt.wantLine)
+			}
+		})
+	}
+}
+
+// findRun returns the first RUN instruction.
+func findRun(result *ParseResult) *instructions.RunCommand {
+	for _, stage := range result.Stages {
+		for _, cmd := range stage.Commands {
+			if run, ok := cmd.(*instructions.RunCommand); ok {
+				return run
+			}
+		}
+	}
+	return nil
+}
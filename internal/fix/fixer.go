@@ -142,7 +142,8 @@ func (f *Fixer) classifyViolations(violations []rules.Violation, changes map[str
 			recordSkipped(changes, v, SkipRuleFilter, "")
 			continue
 		}
-		if v.SuggestedFix.Safety > f.SafetyThreshold {
+		sf := f.selectFix(v)
+		if sf == nil {
 			recordSkipped(changes, v, SkipSafety, "")
 			continue
 		}
@@ -151,8 +152,8 @@ func (f *Fixer) classifyViolations(violations []rules.Violation, changes map[str
 			continue
 		}
 
-		candidate := &fixCandidate{violation: v, fix: v.SuggestedFix}
-		if v.SuggestedFix.NeedsResolve {
+		candidate := &fixCandidate{violation: v, fix: sf}
+		if sf.NeedsResolve {
 			asyncCandidates = append(asyncCandidates, candidate)
 		} else {
 			syncCandidates = append(syncCandidates, candidate)
@@ -162,6 +163,25 @@ func (f *Fixer) classifyViolations(violations []rules.Violation, changes map[str
 	return syncCandidates, asyncCandidates
 }
 
+// selectFix picks the fix to apply for a violation among its suggested and
+// alternative fixes: the first preferred fix within the safety threshold,
+// or else the first fix within it. Returns nil when every fix is too unsafe.
+func (f *Fixer) selectFix(v *rules.Violation) *rules.SuggestedFix {
+	var selected *rules.SuggestedFix
+	for _, sf := range v.Fixes() {
+		if sf.Safety > f.SafetyThreshold {
+			continue
+		}
+		if sf.IsPreferred {
+			return sf
+		}
+		if selected == nil {
+			selected = sf
+		}
+	}
+	return selected
+}
+
 // applyCandidatesToFiles groups candidates by file and applies them.
 func (f *Fixer) applyCandidatesToFiles(changes map[string]*FileChange, candidates []*fixCandidate) {
 	byFile := make(map[string][]*fixCandidate)
@@ -137,6 +137,60 @@ func TestFixer_Apply_SafetyFilter(t *testing.T) {
 	}
 }
 
+func TestFixer_Apply_AlternativeFixes(t *testing.T) {
+	t.Parallel()
+	sources := map[string][]byte{
+		"Dockerfile": []byte("RUN yum install -y httpd"),
+	}
+	violation := rules.Violation{
+		Location: rules.NewLineLocation("Dockerfile", 1),
+		RuleCode: "hadolint/DL3032",
+		Message:  "yum clean all missing",
+		SuggestedFix: &rules.SuggestedFix{
+			Description: "Append yum clean all",
+			Safety:      rules.FixSafe,
+			Edits: []rules.TextEdit{{
+				Location: rules.NewRangeLocation("Dockerfile", 1, 24, 1, 24),
+				NewText:  " && yum clean all",
+			}},
+		},
+		AlternativeFixes: []*rules.SuggestedFix{{
+			Description: "Use a cache mount",
+			Safety:      rules.FixSuggestion,
+			IsPreferred: true,
+			Edits: []rules.TextEdit{{
+				Location: rules.NewRangeLocation("Dockerfile", 1, 4, 1, 4),
+				NewText:  "--mount=type=cache,target=/var/cache/yum ",
+			}},
+		}},
+	}
+
+	tests := []struct {
+		name      string
+		threshold FixSafety
+		want      string
+	}{
+		{name: "safe threshold", threshold: FixSafe, want: "RUN yum install -y httpd && yum clean all"},
+		{name: "preferred alternative", threshold: FixSuggestion, want: "RUN --mount=type=cache,target=/var/cache/yum yum install -y httpd"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			fixer := &Fixer{SafetyThreshold: tt.threshold}
+			result, err := fixer.Apply(context.Background(), []rules.Violation{violation}, sources)
+			if err != nil {
+				t.Fatalf("Apply error: %v", err)
+			}
+			if result.TotalApplied() != 1 {
+				t.Errorf("TotalApplied() = %d, want 1", result.TotalApplied())
+			}
+			if got := string(result.Changes["Dockerfile"].ModifiedContent); got != tt.want {
+				t.Errorf("content = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestFixer_Apply_RuleFilter(t *testing.T) {
 	t.Parallel()
 	sources := map[string][]byte{
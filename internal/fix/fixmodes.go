@@ -32,6 +32,9 @@ func BuildFixModes(cfg *config.Config) map[string]FixMode {
 	if cfg.Rules.Hadolint != nil {
 		addFromNamespace("hadolint", cfg.Rules.Hadolint)
 	}
+	if cfg.Rules.Shellcheck != nil {
+		addFromNamespace("shellcheck", cfg.Rules.Shellcheck)
+	}
 
 	return modes
 }
@@ -0,0 +1,235 @@
+// Package gitdiff reports which files and lines changed relative to a git
+// revision.
+//
+// It runs the local git binary against the working tree, so it needs no
+// network access. Changes include committed, staged and unstaged edits since
+// the merge base of the revision and HEAD, plus untracked files (which count as entirely changed).
+// Renames are not detected: a renamed file counts as entirely new.
+package gitdiff
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"maps"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// LineRange is an inclusive range of 1-based line numbers.
+type LineRange struct {
+	Start int
+	End   int
+}
+
+// Changes records changed files and their changed lines.
+type Changes struct {
+	// files maps cleaned absolute paths to added or modified line ranges
+	// in the working tree version of the file.
+	files map[string][]LineRange
+
+	// whole marks files whose every line counts as changed (untracked files).
+	whole map[string]bool
+}
+
+// Load computes the changes in the repository containing dir since rev.
+//
+// Like git diff rev...HEAD, changes are taken relative to the merge base of
+// rev and HEAD, so commits that landed on rev after the current branch
+// forked from it are not reported as changes.
+func Load(ctx context.Context, dir, rev string) (*Changes, error) {
+	return LoadAll(ctx, []string{dir}, rev)
+}
+
+// LoadAll computes the changes since rev in every repository containing one
+// of dirs. Each repository is read once.
+func LoadAll(ctx context.Context, dirs []string, rev string) (*Changes, error) {
+	c := &Changes{files: make(map[string][]LineRange), whole: make(map[string]bool)}
+	seen := make(map[string]bool)
+	for _, dir := range dirs {
+		root, err := repoRoot(ctx, dir)
+		if err != nil {
+			return nil, err
+		}
+		if seen[root] {
+			continue
+		}
+		seen[root] = true
+		rc, err := load(ctx, root, rev)
+		if err != nil {
+			return nil, err
+		}
+		maps.Copy(c.files, rc.files)
+		maps.Copy(c.whole, rc.whole)
+	}
+	return c, nil
+}
+
+// repoRoot returns the top-level directory of the repository containing dir.
+func repoRoot(ctx context.Context, dir string) (string, error) {
+	top, err := git(ctx, dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", err
+	}
+	return resolve(strings.TrimSpace(string(top))), nil
+}
+
+// load computes the changes in the repository at root since the merge base
+// of rev and HEAD.
+func load(ctx context.Context, root, rev string) (*Changes, error) {
+	// Validate rev up front for a clearer error than git diff gives.
+	if _, err := git(ctx, root, "rev-parse", "--verify", "--quiet", rev+"^{commit}"); err != nil {
+		return nil, fmt.Errorf("unknown git revision %q in %s", rev, root)
+	}
+	base, err := git(ctx, root, "merge-base", rev, "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("git revision %q has no common ancestor with HEAD in %s", rev, root)
+	}
+
+	diff, err := git(ctx, root, "-c", "core.quotePath=false",
+		"diff", "--no-color", "--no-ext-diff", "--no-renames", "--unified=0",
+		"--src-prefix=a/", "--dst-prefix=b/", strings.TrimSpace(string(base)), "--")
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := Parse(diff, root)
+	if err != nil {
+		return nil, err
+	}
+
+	untracked, err := git(ctx, root, "ls-files", "-z", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, err
+	}
+	for name := range strings.SplitSeq(string(untracked), "\x00") {
+		if name != "" {
+			c.whole[filepath.Join(root, filepath.FromSlash(name))] = true
+		}
+	}
+	return c, nil
+}
+
+// git runs a git command in dir and returns its standard output.
+func git(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return nil, fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return out, nil
+}
+
+// Parse reads a unified diff (as produced by git diff --unified=0) and
+// records the added line ranges of each file. Paths are resolved against root.
+func Parse(diff []byte, root string) (*Changes, error) {
+	c := &Changes{files: make(map[string][]LineRange), whole: make(map[string]bool)}
+
+	var current string
+	sc := bufio.NewScanner(bytes.NewReader(diff))
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := sc.Text()
+		switch {
+		case strings.HasPrefix(line, "+++ "):
+			current = ""
+			name := strings.TrimSuffix(line[len("+++ "):], "\t")
+			if unquoted, err := strconv.Unquote(name); err == nil {
+				name = unquoted
+			}
+			if name == "/dev/null" {
+				continue // deleted file
+			}
+			name = strings.TrimPrefix(name, "b/")
+			current = filepath.Join(root, filepath.FromSlash(name))
+			if _, ok := c.files[current]; !ok {
+				c.files[current] = nil
+			}
+		case strings.HasPrefix(line, "@@ ") && current != "":
+			r, ok, err := parseHunkHeader(line)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				c.files[current] = append(c.files[current], r)
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// parseHunkHeader parses the new-file side of "@@ -a,b +c,d @@". It reports
+// false for hunks that only delete lines.
+func parseHunkHeader(line string) (LineRange, bool, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 3 || !strings.HasPrefix(fields[2], "+") {
+		return LineRange{}, false, fmt.Errorf("malformed hunk header %q", line)
+	}
+	startStr, countStr, hasCount := strings.Cut(fields[2][1:], ",")
+	start, err := strconv.Atoi(startStr)
+	if err != nil {
+		return LineRange{}, false, fmt.Errorf("malformed hunk header %q", line)
+	}
+	count := 1
+	if hasCount {
+		if count, err = strconv.Atoi(countStr); err != nil {
+			return LineRange{}, false, fmt.Errorf("malformed hunk header %q", line)
+		}
+	}
+	if count == 0 {
+		return LineRange{}, false, nil
+	}
+	return LineRange{Start: start, End: start + count - 1}, true, nil
+}
+
+// key normalizes a path for lookup.
+func key(path string) string {
+	if abs, err := filepath.Abs(filepath.FromSlash(path)); err == nil {
+		return resolve(abs)
+	}
+	return filepath.Clean(path)
+}
+
+// resolve follows symlinks so that paths under a symlinked directory (such as
+// macOS's /tmp) match the paths git reports.
+func resolve(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	return path
+}
+
+// FileChanged reports whether the file at path changed.
+func (c *Changes) FileChanged(path string) bool {
+	k := key(path)
+	_, ok := c.files[k]
+	return ok || c.whole[k]
+}
+
+// RangeChanged reports whether any line in [start, end] of the file at path
+// was added or modified.
+func (c *Changes) RangeChanged(path string, start, end int) bool {
+	k := key(path)
+	if c.whole[k] {
+		return true
+	}
+	end = max(end, start)
+	for _, r := range c.files[k] {
+		if r.Start <= end && start <= r.End {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,198 @@
+package gitdiff
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	diff := `diff --git a/Dockerfile b/Dockerfile
+index 1111111..2222222 100644
+--- a/Dockerfile
++++ b/Dockerfile
+@@ -2 +2,2 @@ FROM alpine
+-RUN apk add curl
++RUN apk add curl git
++RUN echo hi
+@@ -5,0 +7 @@ RUN true
++USER app
+@@ -9,2 +10,0 @@ USER app
+-RUN a
+-RUN b
+diff --git a/old.Dockerfile b/old.Dockerfile
+deleted file mode 100644
+--- a/old.Dockerfile
++++ /dev/null
+@@ -1 +0,0 @@
+-FROM alpine
+diff --git "a/with\ttab/Dockerfile" "b/with\ttab/Dockerfile"
+--- "a/with\ttab/Dockerfile"
++++ "b/with\ttab/Dockerfile"
+@@ -1 +1 @@
+-FROM alpine
++FROM alpine:3.20
+`
+	c, err := Parse([]byte(diff), root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dockerfile := filepath.Join(root, "Dockerfile")
+	tests := []struct {
+		start, end int
+		want       bool
+	}{
+		{1, 1, false},
+		{2, 2, true},
+		{3, 3, true},
+		{4, 6, false},
+		{7, 7, true},
+		{6, 8, true},
+		{10, 10, false}, // deletion-only hunk
+	}
+	for _, tt := range tests {
+		if got := c.RangeChanged(dockerfile, tt.start, tt.end); got != tt.want {
+			t.Errorf("RangeChanged(%d, %d) = %v, want %v", tt.start, tt.end, got, tt.want)
+		}
+	}
+
+	if c.FileChanged(filepath.Join(root, "old.Dockerfile")) {
+		t.Error("deleted file should not count as changed")
+	}
+	if !c.RangeChanged(filepath.Join(root, "with\ttab", "Dockerfile"), 1, 1) {
+		t.Error("quoted path should be unquoted")
+	}
+}
+
+func TestParseMalformedHunk(t *testing.T) {
+	t.Parallel()
+	_, err := Parse([]byte("+++ b/Dockerfile\n@@ -1 +x @@\n"), t.TempDir())
+	if err == nil {
+		t.Error("expected error for malformed hunk header")
+	}
+}
+
+// testRepo is a git repository in a temporary directory.
+type testRepo struct {
+	t   *testing.T
+	dir string
+}
+
+func newTestRepo(t *testing.T) *testRepo {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	r := &testRepo{t: t, dir: t.TempDir()}
+	r.run("init", "-q", "-b", "main")
+	return r
+}
+
+func (r *testRepo) run(args ...string) {
+	r.t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = r.dir
+	cmd.Env = append(os.Environ(), "GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_NOSYSTEM=1")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		r.t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+func (r *testRepo) write(name, content string) {
+	r.t.Helper()
+	if err := os.WriteFile(filepath.Join(r.dir, name), []byte(content), 0o644); err != nil {
+		r.t.Fatal(err)
+	}
+}
+
+func (r *testRepo) commit(msg string) {
+	r.t.Helper()
+	r.run("add", "-A")
+	r.run("-c", "user.name=t", "-c", "user.email=t@example.com", "commit", "-q", "-m", msg)
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+	r := newTestRepo(t)
+	dir, write := r.dir, r.write
+
+	write("Dockerfile", "FROM alpine\nRUN true\n")
+	write("Unchanged.Dockerfile", "FROM alpine\n")
+	r.commit("init")
+
+	write("Dockerfile", "FROM alpine\nUSER app\nRUN true\n")
+	write("New.Dockerfile", "FROM alpine\n")
+
+	c, err := Load(context.Background(), dir, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dockerfile := filepath.Join(dir, "Dockerfile")
+	if !c.RangeChanged(dockerfile, 2, 2) || c.RangeChanged(dockerfile, 3, 3) {
+		t.Error("expected only line 2 of Dockerfile to be changed")
+	}
+	if c.FileChanged(filepath.Join(dir, "Unchanged.Dockerfile")) {
+		t.Error("unchanged file reported as changed")
+	}
+	if !c.RangeChanged(filepath.Join(dir, "New.Dockerfile"), 1, 1) {
+		t.Error("untracked file should count as entirely changed")
+	}
+
+	if _, err := Load(context.Background(), dir, "no-such-rev"); err == nil {
+		t.Error("expected error for unknown revision")
+	}
+}
+
+func TestLoad_MergeBase(t *testing.T) {
+	t.Parallel()
+	r := newTestRepo(t)
+	r.write("Dockerfile", "FROM alpine\n")
+	r.commit("init")
+	r.run("checkout", "-q", "-b", "feature")
+	r.write("Feature.Dockerfile", "FROM alpine\n")
+	r.commit("feature")
+	r.run("checkout", "-q", "main")
+	r.write("Main.Dockerfile", "FROM alpine\n")
+	r.commit("main")
+	r.run("checkout", "-q", "feature")
+
+	c, err := Load(context.Background(), r.dir, "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.FileChanged(filepath.Join(r.dir, "Feature.Dockerfile")) {
+		t.Error("file added on the branch should be changed")
+	}
+	if c.FileChanged(filepath.Join(r.dir, "Main.Dockerfile")) {
+		t.Error("file added on main after the branch point should not be changed")
+	}
+}
+
+func TestLoadAll(t *testing.T) {
+	t.Parallel()
+	a, b := newTestRepo(t), newTestRepo(t)
+	for _, r := range []*testRepo{a, b} {
+		r.write("Dockerfile", "FROM alpine\n")
+		r.commit("init")
+		r.write("Dockerfile", "FROM alpine\nUSER app\n")
+	}
+	if err := os.Mkdir(filepath.Join(b.dir, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := LoadAll(context.Background(), []string{a.dir, b.dir, filepath.Join(b.dir, "sub")}, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range []*testRepo{a, b} {
+		if !c.RangeChanged(filepath.Join(r.dir, "Dockerfile"), 2, 2) {
+			t.Errorf("expected line 2 of %s/Dockerfile to be changed", r.dir)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package integration
+
+import (
+	"encoding/json/v2"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigShow(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, ".tally.toml")
+	if err := os.WriteFile(configPath, []byte(`
+[rules.tally.max-lines]
+max = 100
+
+[[overrides]]
+files = ["legacy/**"]
+
+[overrides.rules.tally.max-lines]
+max = 300
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(binaryPath, "config", "show", "--format", "json", "--fail-level", "error",
+		filepath.Join(dir, "legacy", "Dockerfile"))
+	cmd.Env = append(os.Environ(),
+		"GOCOVERDIR="+coverageDir,
+		"TALLY_OUTPUT_PATH=stderr",
+	)
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("config show failed: %v\noutput: %s", err, output)
+	}
+
+	var report struct {
+		ConfigFile string `json:"configFile"`
+		Values     []struct {
+			Key    string `json:"key"`
+			Value  any    `json:"value"`
+			Source string `json:"source"`
+			Detail string `json:"detail"`
+		} `json:"values"`
+	}
+	if err := json.Unmarshal(output, &report); err != nil {
+		t.Fatalf("invalid JSON output: %v\noutput: %s", err, output)
+	}
+	if report.ConfigFile != configPath {
+		t.Errorf("configFile = %q, want %q", report.ConfigFile, configPath)
+	}
+
+	want := map[string]string{
+		"output.format":             "default",
+		"output.path":               "env (TALLY_OUTPUT_PATH)",
+		"output.fail-level":         "cli",
+		"rules.tally.max-lines.max": "override (overrides[0])",
+	}
+	for _, v := range report.Values {
+		w, ok := want[v.Key]
+		if !ok {
+			continue
+		}
+		got := v.Source
+		if v.Detail != "" {
+			got += " (" + v.Detail + ")"
+		}
+		if got != w {
+			t.Errorf("source of %s = %q, want %q", v.Key, got, w)
+		}
+		delete(want, v.Key)
+	}
+	for key := range want {
+		t.Errorf("missing key %s", key)
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.toml")
+	invalid := filepath.Join(dir, "invalid.toml")
+	if err := os.WriteFile(valid, []byte("[rules.tally.max-lines]\nmax = 100\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(invalid, []byte("[output]\nformat = \"xml\"\n\n[rules.hadolint.DL9999]\nseverity = \"off\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(binaryPath, "config", "validate", valid)
+	cmd.Env = append(os.Environ(), "GOCOVERDIR="+coverageDir)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("config validate failed on a valid file: %v\noutput: %s", err, output)
+	}
+
+	cmd = exec.Command(binaryPath, "config", "validate", invalid)
+	cmd.Env = append(os.Environ(), "GOCOVERDIR="+coverageDir)
+	output, err := cmd.CombinedOutput()
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 2 {
+		t.Fatalf("expected exit code 2, got %v\noutput: %s", err, output)
+	}
+	for _, want := range []string{
+		"invalid.toml:2:1: output.format: value must be one of",
+		`invalid.toml:4:2: unknown rule "hadolint/DL9999"`,
+	} {
+		if !strings.Contains(string(output), want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+}
+
+func TestLintCachePerFileConfig(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	userCache := filepath.Join(dir, "user-cache")
+	for name, cfg := range map[string]string{
+		"cached":   "[cache]\ndir = \"cache\"\n",
+		"uncached": "[cache]\nenabled = false\n",
+	} {
+		if err := os.MkdirAll(filepath.Join(dir, name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name, ".tally.toml"), []byte(cfg), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name, "Dockerfile"), []byte("FROM scratch\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The suite-wide TALLY_CACHE_DIR would override the configs.
+	env := []string{"GOCOVERDIR=" + coverageDir, "XDG_CACHE_HOME=" + userCache}
+	for _, kv := range os.Environ() {
+		if !strings.HasPrefix(kv, "TALLY_CACHE_DIR=") {
+			env = append(env, kv)
+		}
+	}
+	cmd := exec.Command(binaryPath, "lint", filepath.Join(dir, "uncached"), filepath.Join(dir, "cached"))
+	cmd.Dir = dir
+	cmd.Env = env
+	if output, err := cmd.CombinedOutput(); err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			t.Fatalf("lint failed: %v\noutput: %s", err, output)
+		}
+	}
+
+	// A relative dir is resolved against the config file's directory.
+	if _, err := os.Stat(filepath.Join(dir, "cached", "cache", "CACHEDIR.TAG")); err != nil {
+		t.Errorf("cache of cached/Dockerfile not written next to its config: %v", err)
+	}
+	for _, path := range []string{userCache, filepath.Join(dir, ".tally_cache"), filepath.Join(dir, "uncached", ".tally_cache")} {
+		if _, err := os.Stat(path); err == nil {
+			t.Errorf("unexpected cache directory %s", path)
+		}
+	}
+}
+
+func TestSelectionFlagsOverridePathOverrides(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "legacy"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".tally.toml"), []byte(`
+[[overrides]]
+files = ["legacy/**"]
+
+[overrides.rules]
+include = ["hadolint/DL3006"]
+exclude = ["buildkit/MaintainerDeprecated"]
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	dockerfile := filepath.Join(dir, "legacy", "Dockerfile")
+	if err := os.WriteFile(dockerfile, []byte("FROM alpine\nMAINTAINER me\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	lintRules := func(args ...string) map[string]bool {
+		t.Helper()
+		cmd := exec.Command(binaryPath, append([]string{"lint", "--format", "json"}, append(args, dockerfile)...)...)
+		cmd.Env = append(os.Environ(), "GOCOVERDIR="+coverageDir)
+		output, err := cmd.Output()
+		var exitErr *exec.ExitError
+		if err != nil && !errors.As(err, &exitErr) {
+			t.Fatalf("lint failed: %v\noutput: %s", err, output)
+		}
+		var report struct {
+			Files []struct {
+				Violations []struct {
+					Rule string `json:"rule"`
+				} `json:"violations"`
+			} `json:"files"`
+		}
+		if err := json.Unmarshal(output, &report); err != nil {
+			t.Fatalf("invalid JSON output: %v\noutput: %s", err, output)
+		}
+		found := make(map[string]bool)
+		for _, f := range report.Files {
+			for _, v := range f.Violations {
+				found[v.Rule] = true
+			}
+		}
+		return found
+	}
+
+	found := lintRules()
+	if !found["hadolint/DL3006"] || found["buildkit/MaintainerDeprecated"] {
+		t.Fatalf("override not applied: %v", found)
+	}
+
+	// --ignore and --select win over the matching override.
+	found = lintRules("--ignore", "hadolint/DL3006", "--select", "buildkit/MaintainerDeprecated")
+	if found["hadolint/DL3006"] {
+		t.Error("hadolint/DL3006 reported despite --ignore")
+	}
+	if !found["buildkit/MaintainerDeprecated"] {
+		t.Error("buildkit/MaintainerDeprecated not reported despite --select")
+	}
+}
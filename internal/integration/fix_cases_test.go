@@ -40,7 +40,7 @@ func fixCases(t *testing.T) []fixCase {
 		{
 			name:        "dl3027-apt-to-apt-get",
 			input:       "FROM ubuntu:22.04\nRUN apt update && apt install -y curl\n",
-			args:        []string{"--fix"},
+			args:        []string{"--fix", "--ignore", "hadolint/DL3009"},
 			wantApplied: 1, // Single violation with multiple edits
 		},
 		// DL3046: useradd with high UID -> useradd -l
@@ -50,6 +50,27 @@ func fixCases(t *testing.T) []fixCase {
 			args:        []string{"--fix"},
 			wantApplied: 1,
 		},
+		// DL3009: the safe fix removes the apt lists in the same RUN
+		{
+			name:        "dl3009-remove-apt-lists",
+			input:       "FROM ubuntu:22.04\nRUN apt-get update && apt-get install -y --no-install-recommends curl=8.5.0-2ubuntu10\n",
+			args:        []string{"--fix"},
+			wantApplied: 1,
+		},
+		// DL3009: with --fix-unsafe the preferred cache mount replaces the cleanup
+		{
+			name:        "dl3009-cache-mount",
+			input:       "FROM ubuntu:22.04\nRUN apt-get update && apt-get install -y --no-install-recommends curl=8.5.0-2ubuntu10\n",
+			args:        []string{"--fix", "--fix-unsafe"},
+			wantApplied: 1,
+		},
+		// DL3042: the safe fix adds --no-cache-dir to every pip install
+		{
+			name:        "dl3042-pip-no-cache-dir",
+			input:       "FROM python:3.12\nRUN pip install flask==3.0.3 && pip install gunicorn==22.0.0\n",
+			args:        []string{"--fix"},
+			wantApplied: 1,
+		},
 		// DL3047: wget -> wget --progress=dot:giga
 		{
 			name:        "dl3047-wget-progress",
@@ -128,13 +149,13 @@ func fixCases(t *testing.T) []fixCase {
 		{
 			name:        "no-empty-continuation-single",
 			input:       "FROM alpine:3.18\nRUN apk update && \\\n\n    apk add curl\n",
-			args:        []string{"--fix"},
+			args:        []string{"--fix", "--ignore", "hadolint/DL3019"},
 			wantApplied: 1,
 		},
 		{
 			name:        "no-empty-continuation-multiple",
 			input:       "FROM alpine:3.18\nRUN apk update && \\\n\n    apk add \\\n\n    curl\n",
-			args:        []string{"--fix"},
+			args:        []string{"--fix", "--ignore", "hadolint/DL3019"},
 			wantApplied: 1, // Single violation covers all empty lines
 		},
 		// ConsistentInstructionCasing: Normalize instruction casing
@@ -426,6 +447,7 @@ severity = "error"
 			args: []string{
 				"--fix-unsafe",
 				"--fix",
+				"--ignore", "*",
 				"--select", "tally/prefer-run-heredoc",
 			},
 			wantApplied: 1,
@@ -455,6 +477,7 @@ severity = "error"
 			args: []string{
 				"--fix-unsafe",
 				"--fix",
+				"--ignore", "*",
 				"--select", "tally/prefer-copy-heredoc",
 				"--select", "tally/prefer-run-heredoc",
 			},
@@ -488,6 +488,14 @@ func lintCases(t *testing.T) []lintCase {
 			args:     append([]string{"--format", "json"}, mustSelectRules("hadolint/DL3026")...),
 			wantExit: 1,
 		},
+		{
+			name: "label-schema",
+			dir:  "label-schema",
+			args: append([]string{"--format", "json"}, mustSelectRules(
+				"hadolint/DL3048", "hadolint/DL3049", "hadolint/DL3051", "hadolint/DL3052", "hadolint/DL3053",
+				"hadolint/DL3054", "hadolint/DL3055", "hadolint/DL3056", "hadolint/DL3058")...),
+			wantExit: 1,
+		},
 		{
 			name:     "avoid-latest-tag",
 			dir:      "avoid-latest-tag",
@@ -1,6 +1,14 @@
 package integration
 
-import "testing"
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
 
 func TestLint(t *testing.T) {
 	t.Parallel()
@@ -11,3 +19,50 @@ func TestLint(t *testing.T) {
 		})
 	}
 }
+
+func TestLintJobsDeterministic(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	files := map[string]string{
+		".tally.toml": "[rules.tally.max-lines]\nmax = 100\n",
+		// This service's own config differs from the root one.
+		"svc-3/.tally.toml": "[rules.tally.max-lines]\nmax = 2\n\n[rules.hadolint.DL3006]\nseverity = \"error\"\n",
+	}
+	for i := range 8 {
+		files[fmt.Sprintf("svc-%d/Dockerfile", i)] = fmt.Sprintf(
+			"FROM alpine\nMAINTAINER svc-%d\nRUN cd /tmp && echo $HOME\nEXPOSE %d/TCP\n", i, 8000+i)
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	lint := func(jobs, format string) []byte {
+		t.Helper()
+		cmd := exec.Command(binaryPath, "lint", "--no-cache", "--jobs", jobs, "--format", format, ".")
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GOCOVERDIR="+coverageDir)
+		output, err := cmd.Output()
+		var exitErr *exec.ExitError
+		if err != nil && !errors.As(err, &exitErr) {
+			t.Fatalf("lint --jobs %s failed: %v\noutput: %s", jobs, err, output)
+		}
+		return output
+	}
+
+	for _, format := range []string{"text", "json"} {
+		sequential := lint("1", format)
+		if !bytes.Contains(sequential, []byte("tally/max-lines")) {
+			t.Fatalf("svc-3's own config was not applied:\n%s", sequential)
+		}
+		if parallel := lint("4", format); !bytes.Equal(parallel, sequential) {
+			t.Errorf("--format %s output differs between --jobs 4 and --jobs 1\n--jobs 4:\n%s\n--jobs 1:\n%s",
+				format, parallel, sequential)
+		}
+	}
+}
@@ -45,6 +45,12 @@ func runIntegrationTestMain(m *testing.M) (int, error) {
 		return 0, err
 	}
 
+	// Keep the lint result cache out of the source tree. Subprocesses inherit
+	// the environment, so every test run starts from an empty cache.
+	if err := os.Setenv("TALLY_CACHE_DIR", filepath.Join(tmpDir, "cache")); err != nil {
+		return 0, fmt.Errorf("set cache directory: %w", err)
+	}
+
 	if err := buildIntegrationAcpAgent(tmpDir); err != nil {
 		return 0, err
 	}
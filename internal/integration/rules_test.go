@@ -0,0 +1,54 @@
+package integration
+
+import (
+	"encoding/json/v2"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestRulesList(t *testing.T) {
+	t.Parallel()
+	cmd := exec.Command(binaryPath, "rules", "list", "--namespace", "hadolint", "--fixable", "--format", "json")
+	cmd.Env = append(os.Environ(),
+		"GOCOVERDIR="+coverageDir,
+	)
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("rules list failed: %v\noutput: %s", err, output)
+	}
+
+	var list []struct {
+		Code    string `json:"code"`
+		Fixable bool   `json:"fixable"`
+	}
+	if err := json.Unmarshal(output, &list); err != nil {
+		t.Fatalf("invalid JSON output: %v\noutput: %s", err, output)
+	}
+	if len(list) == 0 {
+		t.Fatal("expected fixable hadolint rules, got none")
+	}
+	for _, r := range list {
+		if !strings.HasPrefix(r.Code, "hadolint/") || !r.Fixable {
+			t.Errorf("unexpected rule in filtered list: %+v", r)
+		}
+	}
+}
+
+func TestRulesExplain(t *testing.T) {
+	t.Parallel()
+	cmd := exec.Command(binaryPath, "rules", "explain", "max-lines")
+	cmd.Env = append(os.Environ(),
+		"GOCOVERDIR="+coverageDir,
+	)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("rules explain failed: %v\noutput: %s", err, output)
+	}
+	for _, want := range []string{"tally/max-lines", "Effective severity:", "Default configuration:", "Configuration schema:"} {
+		if !strings.Contains(string(output), want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+}
@@ -0,0 +1,393 @@
+// Package lintcache provides a persistent on-disk cache of lint results.
+//
+// Entries are keyed by the file path, file content, effective configuration,
+// enabled rule set and tally version, so a cache hit lets callers skip
+// linter.LintFile entirely. The layout mirrors tools like ruff:
+//
+//	<cache dir>/            (see config.Config.CacheDir)
+//	  CACHEDIR.TAG
+//	  .gitignore
+//	  v1/ab/ab12...ef.json
+//	  registry/...          (see SubDir)
+package lintcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/tinovyatkin/tally/internal/ai/autofixdata"
+	"github.com/tinovyatkin/tally/internal/async"
+	"github.com/tinovyatkin/tally/internal/config"
+	"github.com/tinovyatkin/tally/internal/registry"
+	"github.com/tinovyatkin/tally/internal/rules"
+	"github.com/tinovyatkin/tally/internal/version"
+)
+
+// formatVersion is bumped whenever the on-disk entry format changes.
+const formatVersion = "v1"
+
+// tagFile marks the directory as a cache (https://bford.info/cachedir/).
+// Clean refuses to remove directories without it.
+const tagFile = "CACHEDIR.TAG"
+
+const tagContent = "Signature: 8a477f597d28d172789f06886806bc55\n" +
+	"# This file is a cache directory tag created by tally.\n"
+
+// Entry is a cached lint result for one file.
+type Entry struct {
+	// Violations are the raw violations returned by the linter.
+	Violations []rules.Violation
+
+	// AsyncPlan holds the planned async checks. Result handlers cannot be
+	// persisted, so requests restored from disk have a nil Handler; callers
+	// that execute the plan must re-plan it with linter.PlanAsync instead.
+	AsyncPlan []async.CheckRequest
+}
+
+// Cache is an on-disk lint result cache rooted at a directory.
+// It is safe for concurrent use by multiple goroutines and processes.
+type Cache struct {
+	dir string
+}
+
+// New returns a cache rooted at dir. The directory is created on first write.
+func New(dir string) *Cache {
+	return &Cache{dir: dir}
+}
+
+// Dir returns the cache root directory.
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+// SubDir returns a directory inside the cache root for other persistent data,
+// such as registry metadata, creating it if needed. Keeping it under the
+// root means tally cache clean removes it too.
+func (c *Cache) SubDir(name string) (string, error) {
+	if err := c.ensureDir(); err != nil {
+		return "", err
+	}
+	dir := filepath.Join(c.dir, name)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// KeyInput lists everything that determines the lint result of a file.
+type KeyInput struct {
+	// Path is the file path as reported in violation locations.
+	Path string
+
+	// Content is the file content being linted.
+	Content []byte
+
+	// Config is the effective configuration for the file.
+	Config *config.Config
+
+	// EnabledRules is the set of enabled rule codes.
+	EnabledRules []string
+}
+
+// Key computes the cache key for a lint invocation.
+func Key(in KeyInput) (string, error) {
+	cfgJSON, err := configFingerprint(in.Config)
+	if err != nil {
+		return "", fmt.Errorf("hash config: %w", err)
+	}
+	enabled := slices.Clone(in.EnabledRules)
+	slices.Sort(enabled)
+
+	h := sha256.New()
+	for _, part := range [][]byte{
+		[]byte(formatVersion),
+		[]byte(toolVersion()),
+		[]byte(in.Path),
+		in.Content,
+		cfgJSON,
+	} {
+		writeField(h, part)
+	}
+	for _, code := range enabled {
+		writeField(h, []byte(code))
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// writeField writes a length-prefixed field so that adjacent fields
+// cannot collide ("ab"+"c" vs "a"+"bc").
+func writeField(h io.Writer, b []byte) {
+	_, _ = fmt.Fprintf(h, "%d:", len(b))
+	_, _ = h.Write(b)
+}
+
+// configFingerprint serializes the parts of cfg that influence lint results.
+// Rule options and the scoped and CLI rule selections are tagged json:"-",
+// so they are added explicitly.
+func configFingerprint(cfg *config.Config) ([]byte, error) {
+	if cfg == nil {
+		cfg = config.Default()
+	}
+	c := *cfg
+	// Settings that only affect reporting or scheduling do not change the
+	// raw violations and are left out so they don't invalidate entries.
+	c.Output = config.OutputConfig{}
+	c.Jobs = 0
+	c.Cache = config.CacheConfig{}
+	c.SlowChecks = config.SlowChecksConfig{}
+	c.ConfigFile = ""
+	c.ConfigChain = nil
+	// Matching [[overrides]] are already merged into c.Rules; the rule
+	// selections they add are serialized explicitly below.
+	c.Overrides = nil
+
+	options := make(map[string]map[string]any)
+	for ns, m := range map[string]map[string]config.RuleConfig{
+		"tally":      c.Rules.Tally,
+		"buildkit":   c.Rules.Buildkit,
+		"hadolint":   c.Rules.Hadolint,
+		"shellcheck": c.Rules.Shellcheck,
+	} {
+		for name, rc := range m {
+			if len(rc.Options) > 0 {
+				options[ns+"/"+name] = rc.Options
+			}
+		}
+	}
+
+	return json.Marshal(struct {
+		Config   config.Config             `json:"config"`
+		Options  map[string]map[string]any `json:"options"`
+		Scoped   []config.RuleSelection    `json:"scoped,omitempty"`
+		Selected config.RuleSelection      `json:"selected"`
+	}{c, options, c.Rules.Scoped, c.Rules.Selected}, json.Deterministic(true))
+}
+
+// toolVersion identifies the tally build. Development builds all report
+// "dev", so the executable's size and modification time are mixed in to
+// avoid serving results produced by a different build of the rules.
+func toolVersion() string {
+	info := version.GetInfo()
+	v := info.Version + "+" + info.GitCommit
+	if info.Version != "dev" {
+		return v
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return v
+	}
+	st, err := os.Stat(exe)
+	if err != nil {
+		return v
+	}
+	return fmt.Sprintf("%s+%d+%d", v, st.Size(), st.ModTime().UnixNano())
+}
+
+// record is the on-disk representation of an Entry.
+type record struct {
+	Violations []violationRecord `json:"violations"`
+	AsyncPlan  []requestRecord   `json:"asyncPlan,omitempty"`
+}
+
+// violationRecord keeps fields that rules.Violation does not serialize.
+type violationRecord struct {
+	Violation    rules.Violation `json:"violation"`
+	StageIndex   int             `json:"stageIndex"`
+	ResolverData jsontext.Value  `json:"resolverData,omitempty"`
+}
+
+// resolverDataTypes maps fix resolver IDs to constructors for their
+// SuggestedFix.ResolverData, so it can be restored with its concrete type.
+// Violations carrying resolver data of any other resolver are not cached.
+var resolverDataTypes = map[string]func() any{
+	rules.HeredocResolverID: func() any { return &rules.HeredocResolveData{} },
+	autofixdata.ResolverID:  func() any { return &autofixdata.MultiStageResolveData{} },
+}
+
+// requestRecord is the persistable part of an async.CheckRequest.
+type requestRecord struct {
+	RuleCode   string         `json:"rule"`
+	Category   async.Category `json:"category"`
+	Key        string         `json:"key"`
+	ResolverID string         `json:"resolverId"`
+	Ref        string         `json:"ref"`
+	Platform   string         `json:"platform"`
+	Timeout    time.Duration  `json:"timeout,omitzero"`
+	File       string         `json:"file"`
+	StageIndex int            `json:"stageIndex"`
+}
+
+// Get returns the cached entry for key, if present and readable.
+// Corrupt or unreadable entries are treated as misses.
+func (c *Cache) Get(key string) (*Entry, bool) {
+	data, err := os.ReadFile(c.entryPath(key))
+	if err != nil {
+		return nil, false
+	}
+	var rec record
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, false
+	}
+
+	entry := &Entry{Violations: make([]rules.Violation, 0, len(rec.Violations))}
+	for _, vr := range rec.Violations {
+		v := vr.Violation
+		v.StageIndex = vr.StageIndex
+		if len(vr.ResolverData) > 0 {
+			if v.SuggestedFix == nil {
+				return nil, false
+			}
+			newData, ok := resolverDataTypes[v.SuggestedFix.ResolverID]
+			if !ok {
+				return nil, false
+			}
+			data := newData()
+			if err := json.Unmarshal(vr.ResolverData, data); err != nil {
+				return nil, false
+			}
+			v.SuggestedFix.ResolverData = data
+		}
+		entry.Violations = append(entry.Violations, v)
+	}
+	for _, rr := range rec.AsyncPlan {
+		entry.AsyncPlan = append(entry.AsyncPlan, async.CheckRequest{
+			RuleCode:   rr.RuleCode,
+			Category:   rr.Category,
+			Key:        rr.Key,
+			ResolverID: rr.ResolverID,
+			Data:       &registry.ResolveRequest{Ref: rr.Ref, Platform: rr.Platform},
+			Timeout:    rr.Timeout,
+			File:       rr.File,
+			StageIndex: rr.StageIndex,
+		})
+	}
+	return entry, true
+}
+
+// Put stores entry under key. Entries that cannot be restored faithfully
+// (resolver or async request data of an unknown type) are silently skipped.
+func (c *Cache) Put(key string, entry *Entry) error {
+	rec, ok := toRecord(entry)
+	if !ok {
+		return nil
+	}
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	if err := c.ensureDir(); err != nil {
+		return err
+	}
+	path := c.entryPath(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	// Write to a temp file and rename so concurrent readers never observe
+	// a partially written entry.
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+func toRecord(entry *Entry) (record, bool) {
+	rec := record{Violations: make([]violationRecord, 0, len(entry.Violations))}
+	for _, v := range entry.Violations {
+		vr := violationRecord{Violation: v, StageIndex: v.StageIndex}
+		if v.SuggestedFix != nil && v.SuggestedFix.ResolverData != nil {
+			if _, ok := resolverDataTypes[v.SuggestedFix.ResolverID]; !ok {
+				return record{}, false
+			}
+			data, err := json.Marshal(v.SuggestedFix.ResolverData)
+			if err != nil {
+				return record{}, false
+			}
+			vr.ResolverData = data
+		}
+		rec.Violations = append(rec.Violations, vr)
+	}
+	for _, req := range entry.AsyncPlan {
+		data, ok := req.Data.(*registry.ResolveRequest)
+		if !ok || data == nil {
+			return record{}, false
+		}
+		rec.AsyncPlan = append(rec.AsyncPlan, requestRecord{
+			RuleCode:   req.RuleCode,
+			Category:   req.Category,
+			Key:        req.Key,
+			ResolverID: req.ResolverID,
+			Ref:        data.Ref,
+			Platform:   data.Platform,
+			Timeout:    req.Timeout,
+			File:       req.File,
+			StageIndex: req.StageIndex,
+		})
+	}
+	return rec, true
+}
+
+func (c *Cache) entryPath(key string) string {
+	return filepath.Join(c.dir, formatVersion, key[:2], key+".json")
+}
+
+// ensureDir creates the cache root with its tag and .gitignore files.
+func (c *Cache) ensureDir() error {
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return err
+	}
+	for name, content := range map[string]string{
+		tagFile:      tagContent,
+		".gitignore": "# Automatically created by tally.\n*\n",
+	} {
+		path := filepath.Join(c.dir, name)
+		if _, err := os.Stat(path); err == nil {
+			continue
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ErrNotCacheDir is returned by Clean when the directory exists but was not
+// created by tally.
+var ErrNotCacheDir = errors.New("not a tally cache directory")
+
+// Clean removes the cache directory. A missing directory is not an error.
+// To avoid deleting unrelated data through a misconfigured path, directories
+// without a CACHEDIR.TAG file are left alone.
+func Clean(dir string) error {
+	if _, err := os.Stat(dir); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if _, err := os.Stat(filepath.Join(dir, tagFile)); err != nil {
+		return fmt.Errorf("%s: %w", dir, ErrNotCacheDir)
+	}
+	return os.RemoveAll(dir)
+}
@@ -0,0 +1,170 @@
+package lintcache
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tinovyatkin/tally/internal/async"
+	"github.com/tinovyatkin/tally/internal/config"
+	"github.com/tinovyatkin/tally/internal/registry"
+	"github.com/tinovyatkin/tally/internal/rules"
+)
+
+func mustKey(t *testing.T, in KeyInput) string {
+	t.Helper()
+	key, err := Key(in)
+	if err != nil {
+		t.Fatalf("Key() error = %v", err)
+	}
+	return key
+}
+
+func TestKey(t *testing.T) {
+	t.Parallel()
+
+	base := KeyInput{
+		Path:         "Dockerfile",
+		Content:      []byte("FROM alpine\n"),
+		Config:       config.Default(),
+		EnabledRules: []string{"hadolint/DL3006", "buildkit/StageNameCasing"},
+	}
+	baseKey := mustKey(t, base)
+
+	reordered := base
+	reordered.EnabledRules = []string{"buildkit/StageNameCasing", "hadolint/DL3006"}
+	if got := mustKey(t, reordered); got != baseKey {
+		t.Error("rule order should not change the key")
+	}
+
+	outputOnly := base
+	outputOnly.Config = config.Default()
+	outputOnly.Config.Output.Format = "json"
+	outputOnly.Config.Jobs = 8
+	outputOnly.Config.SlowChecks.Mode = "off"
+	if got := mustKey(t, outputOnly); got != baseKey {
+		t.Error("output, scheduling and slow-checks settings should not change the key")
+	}
+
+	ruleOptions := base
+	ruleOptions.Config = config.Default()
+	ruleOptions.Config.Rules.Set("tally/max-lines", config.RuleConfig{Options: map[string]any{"max": 10}})
+
+	changes := map[string]KeyInput{
+		"content":       {Path: base.Path, Content: []byte("FROM alpine:3.20\n"), Config: base.Config, EnabledRules: base.EnabledRules},
+		"path":          {Path: "other/Dockerfile", Content: base.Content, Config: base.Config, EnabledRules: base.EnabledRules},
+		"enabled rules": {Path: base.Path, Content: base.Content, Config: base.Config, EnabledRules: base.EnabledRules[:1]},
+		"rule options":  ruleOptions,
+	}
+	for name, in := range changes {
+		if got := mustKey(t, in); got == baseKey {
+			t.Errorf("changing %s should change the key", name)
+		}
+	}
+}
+
+func TestPutGet(t *testing.T) {
+	t.Parallel()
+	c := New(filepath.Join(t.TempDir(), ".tally_cache"))
+	key := mustKey(t, KeyInput{Path: "Dockerfile", Content: []byte("FROM alpine\n"), Config: config.Default()})
+
+	if _, ok := c.Get(key); ok {
+		t.Fatal("Get() on empty cache should miss")
+	}
+
+	v := rules.NewViolation(rules.NewLineLocation("Dockerfile", 2), "tally/prefer-run-heredoc", "use heredoc", rules.SeverityStyle)
+	v.StageIndex = 1
+	v.SuggestedFix = &rules.SuggestedFix{
+		Description:  "Convert to heredoc",
+		NeedsResolve: true,
+		ResolverID:   rules.HeredocResolverID,
+		ResolverData: &rules.HeredocResolveData{StageIndex: 1, MinCommands: 3},
+	}
+	entry := &Entry{
+		Violations: []rules.Violation{v},
+		AsyncPlan: []async.CheckRequest{{
+			RuleCode:   "buildkit/InvalidBaseImagePlatform",
+			Key:        "alpine|linux/amd64",
+			ResolverID: registry.RegistryResolverID(),
+			Data:       &registry.ResolveRequest{Ref: "alpine", Platform: "linux/amd64"},
+			File:       "Dockerfile",
+			StageIndex: 1,
+		}},
+	}
+	if err := c.Put(key, entry); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	got, ok := c.Get(key)
+	if !ok {
+		t.Fatal("Get() after Put() should hit")
+	}
+	if len(got.Violations) != 1 {
+		t.Fatalf("got %d violations, want 1", len(got.Violations))
+	}
+	gv := got.Violations[0]
+	if gv.RuleCode != v.RuleCode || gv.StageIndex != 1 || gv.Location != v.Location {
+		t.Errorf("violation = %+v, want %+v", gv, v)
+	}
+	data, ok := gv.SuggestedFix.ResolverData.(*rules.HeredocResolveData)
+	if !ok || data.MinCommands != 3 || data.StageIndex != 1 {
+		t.Errorf("ResolverData = %#v, want restored *rules.HeredocResolveData", gv.SuggestedFix.ResolverData)
+	}
+
+	if len(got.AsyncPlan) != 1 {
+		t.Fatalf("got %d async requests, want 1", len(got.AsyncPlan))
+	}
+	req := got.AsyncPlan[0]
+	if req.Handler != nil {
+		t.Error("restored requests should have no handler")
+	}
+	if rr, ok := req.Data.(*registry.ResolveRequest); !ok || rr.Ref != "alpine" || rr.Platform != "linux/amd64" {
+		t.Errorf("Data = %#v, want restored ResolveRequest", req.Data)
+	}
+
+	if _, err := os.Stat(filepath.Join(c.Dir(), ".gitignore")); err != nil {
+		t.Errorf("cache dir should contain a .gitignore: %v", err)
+	}
+}
+
+func TestPutSkipsUnknownResolverData(t *testing.T) {
+	t.Parallel()
+	c := New(filepath.Join(t.TempDir(), "cache"))
+	key := mustKey(t, KeyInput{Path: "Dockerfile", Config: config.Default()})
+
+	v := rules.NewViolation(rules.NewLineLocation("Dockerfile", 1), "custom", "msg", rules.SeverityInfo)
+	v.SuggestedFix = &rules.SuggestedFix{NeedsResolve: true, ResolverID: "unknown", ResolverData: struct{}{}}
+	if err := c.Put(key, &Entry{Violations: []rules.Violation{v}}); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if _, ok := c.Get(key); ok {
+		t.Error("entries with unknown resolver data should not be cached")
+	}
+}
+
+func TestClean(t *testing.T) {
+	t.Parallel()
+	dir := filepath.Join(t.TempDir(), ".tally_cache")
+
+	if err := Clean(dir); err != nil {
+		t.Errorf("Clean() of missing dir error = %v", err)
+	}
+
+	c := New(dir)
+	key := mustKey(t, KeyInput{Path: "Dockerfile", Config: config.Default()})
+	if err := c.Put(key, &Entry{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Clean(dir); err != nil {
+		t.Fatalf("Clean() error = %v", err)
+	}
+	if _, err := os.Stat(dir); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("cache dir still exists after Clean(): %v", err)
+	}
+
+	other := t.TempDir()
+	if err := Clean(other); !errors.Is(err, ErrNotCacheDir) {
+		t.Errorf("Clean() of untagged dir error = %v, want ErrNotCacheDir", err)
+	}
+}
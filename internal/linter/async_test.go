@@ -0,0 +1,44 @@
+package linter
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/tinovyatkin/tally/internal/async"
+	"github.com/tinovyatkin/tally/internal/rules"
+)
+
+func TestMergeAsyncViolations(t *testing.T) {
+	t.Parallel()
+
+	violation := func(code string, line, stage int) rules.Violation {
+		v := rules.NewViolation(rules.NewLineLocation("Dockerfile", line), code, "message", rules.SeverityWarning)
+		v.StageIndex = stage
+		return v
+	}
+	fast := []rules.Violation{
+		violation("buildkit/UndefinedVar", 2, 0),
+		violation("buildkit/UndefinedVar", 5, 1),
+		violation("hadolint/DL3006", 1, 0),
+	}
+
+	if got := MergeAsyncViolations(fast, nil); !slices.EqualFunc(got, fast, sameViolation) {
+		t.Errorf("MergeAsyncViolations(fast, nil) = %v, want fast violations", got)
+	}
+
+	// Stage 0 resolved without findings: its fast UndefinedVar is a false positive.
+	// Stage 1 is untouched; the async violation is appended.
+	platform := violation("buildkit/InvalidBaseImagePlatform", 4, 1)
+	got := MergeAsyncViolations(fast, &async.RunResult{
+		Violations: []any{platform},
+		Completed:  []async.CompletedCheck{{RuleCode: "buildkit/UndefinedVar", File: "Dockerfile", StageIndex: 0}},
+	})
+	want := []rules.Violation{fast[1], fast[2], platform}
+	if !slices.EqualFunc(got, want, sameViolation) {
+		t.Errorf("MergeAsyncViolations() = %v, want %v", got, want)
+	}
+}
+
+func sameViolation(a, b rules.Violation) bool {
+	return a.RuleCode == b.RuleCode && a.Location == b.Location && a.StageIndex == b.StageIndex
+}
@@ -0,0 +1,106 @@
+package linter
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/tinovyatkin/tally/internal/config"
+	"github.com/tinovyatkin/tally/internal/rules"
+	"github.com/tinovyatkin/tally/internal/rules/buildkit"
+	"github.com/tinovyatkin/tally/internal/rules/buildkit/fixes"
+)
+
+// RuleInfo describes a rule tally can report, for listing and documentation.
+type RuleInfo struct {
+	rules.RuleMetadata
+
+	// Rule is the registered implementation, or nil for BuildKit checks that
+	// are captured from the parser rather than implemented by tally.
+	Rule rules.Rule
+}
+
+// Namespace returns the rule's namespace (e.g. "hadolint" for "hadolint/DL3006").
+func (ri RuleInfo) Namespace() string {
+	ns, _, found := strings.Cut(ri.Code, "/")
+	if !found {
+		return ""
+	}
+	return ns
+}
+
+// Catalog returns every rule tally can report, sorted by code: registered
+// rules plus BuildKit parse-time checks captured during parsing.
+// BuildKit rules are annotated with the fix information from the fix enricher.
+func Catalog() []RuleInfo {
+	registry := rules.DefaultRegistry()
+	all := registry.All()
+	out := make([]RuleInfo, 0, len(all)+len(buildkit.CapturedRuleNames))
+	for _, rule := range all {
+		out = append(out, newRuleInfo(rule.Metadata(), rule))
+	}
+	for _, info := range buildkit.Captured() {
+		meta := buildkit.GetMetadata(info.Name)
+		if meta == nil || registry.Has(meta.Code) {
+			continue
+		}
+		out = append(out, newRuleInfo(*meta, nil))
+	}
+	slices.SortFunc(out, func(a, b RuleInfo) int { return cmp.Compare(a.Code, b.Code) })
+	return out
+}
+
+func newRuleInfo(meta rules.RuleMetadata, rule rules.Rule) RuleInfo {
+	if name, ok := strings.CutPrefix(meta.Code, rules.BuildKitRulePrefix); ok && !meta.Fixable {
+		if safety, fixable := fixes.FixSafety(name); fixable {
+			meta.Fixable = true
+			meta.FixSafety = safety
+		}
+	}
+	return RuleInfo{RuleMetadata: meta, Rule: rule}
+}
+
+// LookupRule finds a rule in the catalog by code. The namespace may be omitted
+// ("DL3006") when the bare name is unambiguous; matching is case-insensitive.
+func LookupRule(code string) (RuleInfo, bool) {
+	catalog := Catalog()
+	for _, ri := range catalog {
+		if strings.EqualFold(ri.Code, code) {
+			return ri, true
+		}
+	}
+	var match RuleInfo
+	matches := 0
+	for _, ri := range catalog {
+		if _, name, _ := strings.Cut(ri.Code, "/"); strings.EqualFold(name, code) {
+			match = ri
+			matches++
+		}
+	}
+	return match, matches == 1
+}
+
+// EffectiveSeverity returns the severity a rule reports under cfg and whether
+// the rule is enabled. It mirrors the SeverityOverride and EnableFilter
+// processors: explicit severity overrides win, and "off" rules with options
+// configured are auto-enabled as warnings. Disabled rules report SeverityOff.
+func EffectiveSeverity(meta rules.RuleMetadata, cfg *config.Config) (rules.Severity, bool) {
+	if !isRuleEnabled(meta.Code, meta.DefaultSeverity, cfg) {
+		return rules.SeverityOff, false
+	}
+	if cfg == nil {
+		return meta.DefaultSeverity, true
+	}
+
+	if override := cfg.Rules.GetSeverity(meta.Code); override != "" {
+		if sev, err := rules.ParseSeverity(override); err == nil {
+			return sev, true
+		}
+	}
+	if meta.DefaultSeverity == rules.SeverityOff {
+		if ruleCfg := cfg.Rules.Get(meta.Code); ruleCfg != nil && len(ruleCfg.Options) > 0 {
+			return rules.SeverityWarning, true
+		}
+	}
+	return meta.DefaultSeverity, true
+}
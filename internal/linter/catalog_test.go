@@ -0,0 +1,109 @@
+package linter
+
+import (
+	"testing"
+
+	"github.com/tinovyatkin/tally/internal/config"
+	"github.com/tinovyatkin/tally/internal/rules"
+)
+
+func TestCatalog(t *testing.T) {
+	t.Parallel()
+
+	seen := make(map[string]bool)
+	for _, ri := range Catalog() {
+		if seen[ri.Code] {
+			t.Errorf("duplicate rule %s", ri.Code)
+		}
+		seen[ri.Code] = true
+		if ri.Namespace() == "" {
+			t.Errorf("rule %s has no namespace", ri.Code)
+		}
+	}
+
+	for _, code := range []string{"hadolint/DL3006", "tally/max-lines", "buildkit/StageNameCasing"} {
+		if !seen[code] {
+			t.Errorf("Catalog() missing %s", code)
+		}
+	}
+}
+
+func TestLookupRule(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		code   string
+		want   string
+		wantOK bool
+	}{
+		{code: "hadolint/DL3006", want: "hadolint/DL3006", wantOK: true},
+		{code: "dl3006", want: "hadolint/DL3006", wantOK: true},
+		{code: "max-lines", want: "tally/max-lines", wantOK: true},
+		{code: "StageNameCasing", want: "buildkit/StageNameCasing", wantOK: true},
+		{code: "unknown-rule", wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			t.Parallel()
+			ri, ok := LookupRule(tt.code)
+			if ok != tt.wantOK {
+				t.Fatalf("LookupRule(%q) ok = %v, want %v", tt.code, ok, tt.wantOK)
+			}
+			if ok && ri.Code != tt.want {
+				t.Errorf("LookupRule(%q) = %s, want %s", tt.code, ri.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestCatalog_BuildKitFixSafety(t *testing.T) {
+	t.Parallel()
+
+	ri, ok := LookupRule("buildkit/JSONArgsRecommended")
+	if !ok {
+		t.Fatal("buildkit/JSONArgsRecommended not found")
+	}
+	if !ri.Fixable || ri.FixSafety != rules.FixSuggestion {
+		t.Errorf("Fixable = %v, FixSafety = %v; want fixable suggestion", ri.Fixable, ri.FixSafety)
+	}
+}
+
+func TestEffectiveSeverity(t *testing.T) {
+	t.Parallel()
+
+	warn := rules.RuleMetadata{Code: "hadolint/DL3006", DefaultSeverity: rules.SeverityWarning}
+	off := rules.RuleMetadata{Code: "tally/max-lines", DefaultSeverity: rules.SeverityOff}
+
+	overridden := config.Default()
+	overridden.Rules.Set("hadolint/DL3006", config.RuleConfig{Severity: "error"})
+
+	excluded := config.Default()
+	excluded.Rules.Exclude = []string{"hadolint/*"}
+
+	withOptions := config.Default()
+	withOptions.Rules.Set("tally/max-lines", config.RuleConfig{Options: map[string]any{"max": 10}})
+
+	tests := []struct {
+		name        string
+		meta        rules.RuleMetadata
+		cfg         *config.Config
+		want        rules.Severity
+		wantEnabled bool
+	}{
+		{name: "nil config", meta: warn, cfg: nil, want: rules.SeverityWarning, wantEnabled: true},
+		{name: "default", meta: warn, cfg: config.Default(), want: rules.SeverityWarning, wantEnabled: true},
+		{name: "severity override", meta: warn, cfg: overridden, want: rules.SeverityError, wantEnabled: true},
+		{name: "excluded", meta: warn, cfg: excluded, want: rules.SeverityOff, wantEnabled: false},
+		{name: "off by default", meta: off, cfg: config.Default(), want: rules.SeverityOff, wantEnabled: false},
+		{name: "auto-enabled by options", meta: off, cfg: withOptions, want: rules.SeverityWarning, wantEnabled: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, enabled := EffectiveSeverity(tt.meta, tt.cfg)
+			if got != tt.want || enabled != tt.wantEnabled {
+				t.Errorf("EffectiveSeverity() = %v, %v; want %v, %v", got, enabled, tt.want, tt.wantEnabled)
+			}
+		})
+	}
+}
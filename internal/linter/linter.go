@@ -56,6 +56,10 @@ type Input struct {
 
 	// Channel receives progress and diagnostic output. Nil means silent.
 	Channel Channel
+
+	// Analysis is the result of [Analyze] for Content and Config, if the
+	// caller already has it. If nil, LintFile analyzes the content itself.
+	Analysis *Analysis
 }
 
 // Result contains the output of [LintFile].
@@ -74,6 +78,50 @@ type Result struct {
 	Config *config.Config
 }
 
+// Analysis is a parsed Dockerfile and its semantic model.
+type Analysis struct {
+	// ParseResult is the parsed Dockerfile (AST, stages, source, BuildKit warnings).
+	ParseResult *dockerfile.ParseResult
+
+	// Semantic is the semantic model of the build configured in cfg.Build.
+	Semantic *semantic.Model
+
+	// UnknownTarget is the configured build target when the Dockerfile has no
+	// such stage. Semantic then models the default build (the last stage),
+	// and tally/unknown-build-target reports it.
+	UnknownTarget string
+}
+
+// Analyze parses content and builds its semantic model the way LintFile
+// does, without running any rules. It is used by editor features that need
+// the model of a document but not its violations.
+func Analyze(filePath string, content []byte, cfg *config.Config) (*Analysis, error) {
+	parseResult, err := dockerfile.Parse(bytes.NewReader(content), cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	sm := sourcemap.New(content)
+	directiveResult := directive.Parse(sm, nil)
+
+	// Build args and target come from the [build] config section (or the
+	// equivalent --build-arg/--target CLI flags) so that semantic analysis
+	// models the build that is actually run.
+	build := func(target string) *semantic.Model {
+		return semantic.NewBuilder(parseResult, cfg.Build.Args, filePath).
+			WithShellDirectives(directiveResult.ShellDirectives).
+			WithTarget(target).
+			Build()
+	}
+	analysis := &Analysis{ParseResult: parseResult, Semantic: build(cfg.Build.Target)}
+	if cfg.Build.Target != "" && analysis.Semantic.TargetStageIndex() < 0 {
+		// A target from a shared config need not exist in every Dockerfile.
+		analysis.UnknownTarget = cfg.Build.Target
+		analysis.Semantic = build("")
+	}
+	return analysis, nil
+}
+
 // LintFile runs the full lint pipeline for one file.
 // It returns raw violations before processor filtering.
 func LintFile(input Input) (*Result, error) {
@@ -96,34 +144,18 @@ func LintFile(input Input) (*Result, error) {
 		}
 	}
 
-	parseResult, err := dockerfile.Parse(bytes.NewReader(content), cfg)
-	if err != nil {
-		return nil, err
+	analysis := input.Analysis
+	if analysis == nil {
+		var err error
+		analysis, err = Analyze(input.FilePath, content, cfg)
+		if err != nil {
+			return nil, err
+		}
 	}
-	// Ensure Source is set (Parse reads from a reader, source is captured internally).
-	// If the caller passed Content, the parse result's Source should already match.
-
-	sm := sourcemap.New(content)
-	directiveResult := directive.Parse(sm, nil)
+	parseResult, sem := analysis.ParseResult, analysis.Semantic
 
-	var buildArgs map[string]string
-	sem := semantic.NewBuilder(parseResult, buildArgs, input.FilePath).
-		WithShellDirectives(directiveResult.ShellDirectives).
-		Build()
-
-	enabledRules := EnabledRuleCodes(cfg)
-
-	baseInput := rules.LintInput{
-		File:               input.FilePath,
-		AST:                parseResult.AST,
-		Stages:             parseResult.Stages,
-		MetaArgs:           parseResult.MetaArgs,
-		Source:             content,
-		Semantic:           sem,
-		Context:            input.BuildContext,
-		EnabledRules:       enabledRules,
-		HeredocMinCommands: heredocMinCommands(cfg),
-	}
+	baseInput := newLintInput(input.FilePath, content, cfg, analysis)
+	baseInput.Context = input.BuildContext
 
 	// Collect construction-time violations from semantic analysis.
 	violations := make([]rules.Violation, 0,
@@ -153,8 +185,44 @@ func LintFile(input Input) (*Result, error) {
 	// Enrich BuildKit violations with auto-fix suggestions.
 	fixes.EnrichBuildKitFixes(violations, sem, content)
 
-	// Plan async checks from AsyncRule implementations.
-	// Only plan for rules that are enabled (respects --select/--ignore/config).
+	return &Result{
+		Violations:  violations,
+		AsyncPlan:   planAsync(cfg, baseInput),
+		ParseResult: parseResult,
+		Config:      cfg,
+	}, nil
+}
+
+// PlanAsync plans the async checks of a file without running the other
+// rules. Result handlers of planned checks cannot be persisted, so callers
+// serving cached violations use it to rebuild the plan of the file.
+func PlanAsync(filePath string, content []byte, cfg *config.Config) ([]async.CheckRequest, error) {
+	analysis, err := Analyze(filePath, content, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return planAsync(cfg, newLintInput(filePath, content, cfg, analysis)), nil
+}
+
+// newLintInput returns the rule input shared by all rules of a file.
+func newLintInput(filePath string, content []byte, cfg *config.Config, analysis *Analysis) rules.LintInput {
+	return rules.LintInput{
+		File:               filePath,
+		AST:                analysis.ParseResult.AST,
+		Stages:             analysis.ParseResult.Stages,
+		MetaArgs:           analysis.ParseResult.MetaArgs,
+		Source:             content,
+		Semantic:           analysis.Semantic,
+		EnabledRules:       EnabledRuleCodes(cfg),
+		HeredocMinCommands: heredocMinCommands(cfg),
+		LabelSchema:        cfg.LabelSchema,
+		BuildTarget:        cfg.Build.Target,
+	}
+}
+
+// planAsync plans async checks from AsyncRule implementations.
+// Only plan for rules that are enabled (respects --select/--ignore/config).
+func planAsync(cfg *config.Config, baseInput rules.LintInput) []async.CheckRequest {
 	var asyncPlan []async.CheckRequest
 	for _, rule := range rules.All() {
 		ar, ok := rule.(rules.AsyncRule)
@@ -174,11 +242,5 @@ func LintFile(input Input) (*Result, error) {
 		ruleInput.Config = cfg.Rules.GetOptions(code)
 		asyncPlan = append(asyncPlan, ar.PlanAsync(ruleInput)...)
 	}
-
-	return &Result{
-		Violations:  violations,
-		AsyncPlan:   asyncPlan,
-		ParseResult: parseResult,
-		Config:      cfg,
-	}, nil
+	return asyncPlan
 }
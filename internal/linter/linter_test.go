@@ -0,0 +1,45 @@
+package linter
+
+import (
+	"testing"
+
+	"github.com/tinovyatkin/tally/internal/config"
+	"github.com/tinovyatkin/tally/internal/rules"
+)
+
+func TestLintFile_UnknownTarget(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.Default()
+	cfg.Build.Target = "release"
+	result, err := LintFile(Input{
+		FilePath: "Dockerfile",
+		Content:  []byte("FROM alpine:3.20 AS build\nFROM alpine:3.20\nCOPY --from=build /etc/os-release /\n"),
+		Config:   cfg,
+	})
+	if err != nil {
+		t.Fatalf("LintFile() error = %v, want the missing target reported as a violation", err)
+	}
+
+	var found *rules.Violation
+	for i, v := range result.Violations {
+		if v.RuleCode == "tally/unknown-build-target" {
+			found = &result.Violations[i]
+		}
+	}
+	if found == nil {
+		t.Fatalf("no tally/unknown-build-target violation in %v", result.Violations)
+	}
+	if found.Severity != rules.SeverityWarning || !found.Location.IsFileLevel() {
+		t.Errorf("violation = %+v, want a file-level warning", *found)
+	}
+
+	analysis, err := Analyze("Dockerfile", []byte("FROM alpine:3.20 AS build\nFROM alpine:3.20\n"), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if analysis.UnknownTarget != "release" || analysis.Semantic.TargetStageIndex() != 1 {
+		t.Errorf("Analyze() = target %q, index %d; want unknown target release, default (last) stage",
+			analysis.UnknownTarget, analysis.Semantic.TargetStageIndex())
+	}
+}
@@ -2,11 +2,36 @@ package linter
 
 import "github.com/tinovyatkin/tally/internal/processor"
 
+// CLIOption configures the CLI processor chain.
+type CLIOption func(*cliOptions)
+
+type cliOptions struct {
+	diff     *processor.DiffFilter
+	baseline *processor.BaselineFilter
+}
+
+// WithDiffFilter keeps only violations on changed lines.
+// A nil filter is ignored.
+func WithDiffFilter(f *processor.DiffFilter) CLIOption {
+	return func(o *cliOptions) { o.diff = f }
+}
+
+// WithBaselineFilter removes violations recorded in a baseline file.
+// A nil filter is ignored.
+func WithBaselineFilter(f *processor.BaselineFilter) CLIOption {
+	return func(o *cliOptions) { o.baseline = f }
+}
+
 // CLIProcessors returns the standard CLI processor chain and the inline directive
 // filter (the caller needs it for [processor.InlineDirectiveFilter.AdditionalViolations]).
-func CLIProcessors() (*processor.Chain, *processor.InlineDirectiveFilter) {
+func CLIProcessors(opts ...CLIOption) (*processor.Chain, *processor.InlineDirectiveFilter) {
+	var o cliOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	inlineFilter := processor.NewInlineDirectiveFilter()
-	chain := processor.NewChain(
+	processors := []processor.Processor{
 		processor.NewPathNormalization(),   // Normalize paths for cross-platform consistency
 		processor.NewSeverityOverride(),    // Apply severity overrides (must run before EnableFilter)
 		processor.NewEnableFilter(),        // Filter rules with severity="off"
@@ -14,10 +39,20 @@ func CLIProcessors() (*processor.Chain, *processor.InlineDirectiveFilter) {
 		inlineFilter,                       // Apply inline ignore directives
 		processor.NewSupersession(),        // Drop lower-severity when error exists
 		processor.NewDeduplication(),       // Remove duplicate violations
-		processor.NewSorting(),             // Stable output ordering
-		processor.NewSnippetAttachment(),   // Attach source code snippets
+	}
+	// The baseline runs before the diff filter: it must see the violations on
+	// unchanged lines too, or their entries would be reported as fixed.
+	if o.baseline != nil {
+		processors = append(processors, o.baseline) // Drop violations recorded in the baseline
+	}
+	if o.diff != nil {
+		processors = append(processors, o.diff) // Drop violations outside changed lines
+	}
+	processors = append(processors,
+		processor.NewSorting(),           // Stable output ordering
+		processor.NewSnippetAttachment(), // Attach source code snippets
 	)
-	return chain, inlineFilter
+	return processor.NewChain(processors...), inlineFilter
 }
 
 // LSPProcessors returns the LSP processor chain.
@@ -0,0 +1,54 @@
+package linter
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/tinovyatkin/tally/internal/baseline"
+	"github.com/tinovyatkin/tally/internal/config"
+	"github.com/tinovyatkin/tally/internal/gitdiff"
+	"github.com/tinovyatkin/tally/internal/processor"
+	"github.com/tinovyatkin/tally/internal/rules"
+	"github.com/tinovyatkin/tally/internal/sourcemap"
+)
+
+func TestCLIProcessors_DiffOnlyWithBaseline(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	file := filepath.Join(dir, "Dockerfile")
+	source := []byte("FROM alpine:3.20\nRUN apk add curl\nRUN apk add git\n")
+	violation := func(line int) rules.Violation {
+		return rules.NewViolation(rules.NewLineLocation(file, line), "hadolint/DL3018", "msg", rules.SeverityWarning)
+	}
+
+	// Line 2 is baselined and unchanged; line 3 is new and changed.
+	b, err := baseline.New(filepath.Join(dir, "tally-baseline.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.Add([]rules.Violation{violation(2)}, func(string) *sourcemap.SourceMap { return sourcemap.New(source) })
+	changes, err := gitdiff.Parse([]byte(`diff --git a/Dockerfile b/Dockerfile
+--- a/Dockerfile
++++ b/Dockerfile
+@@ -2,0 +3 @@ RUN apk add curl
++RUN apk add git
+`), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	baselineFilter := processor.NewBaselineFilter(b)
+	chain, _ := CLIProcessors(
+		WithDiffFilter(processor.NewDiffFilter(changes, false)),
+		WithBaselineFilter(baselineFilter),
+	)
+	ctx := processor.NewContext(nil, config.Default(), map[string][]byte{file: source})
+	got := chain.Process([]rules.Violation{violation(2), violation(3)}, ctx)
+
+	if len(got) != 1 || got[0].Location.Start.Line != 3 {
+		t.Errorf("Process() = %+v, want only the violation on line 3", got)
+	}
+	if fixed := baselineFilter.Fixed(); len(fixed) != 0 {
+		t.Errorf("Fixed() = %+v, want none: the baselined violation is outside the diff, not fixed", fixed)
+	}
+}
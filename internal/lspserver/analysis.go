@@ -0,0 +1,240 @@
+package lspserver
+
+import (
+	"log"
+	"strings"
+
+	protocol "github.com/tinovyatkin/tally/internal/lsp/protocol"
+
+	"github.com/tinovyatkin/tally/internal/config"
+	"github.com/tinovyatkin/tally/internal/linter"
+	"github.com/tinovyatkin/tally/internal/semantic"
+)
+
+// documentAnalysis is an open document parsed with its semantic model, for
+// the language features that work on the Dockerfile structure (hover, ...).
+type documentAnalysis struct {
+	*linter.Analysis
+
+	// Config is the effective config of the document.
+	Config *config.Config
+
+	// Lines are the document lines, without line terminators.
+	Lines []string
+
+	// CacheDir is the document's cache directory, or "" when caching is
+	// disabled.
+	CacheDir string
+}
+
+// analyzeDocument parses a document and builds its semantic model with the
+// document's effective config (build args and target included). Returns nil
+// if the document cannot be parsed.
+func (s *Server) analyzeDocument(doc *Document) *documentAnalysis {
+	filePath := uriToPath(doc.URI)
+	cfg := s.resolveConfig(filePath)
+	if cfg == nil {
+		cfg = config.Default()
+	}
+	analysis, err := linter.Analyze(filePath, []byte(doc.Content), cfg)
+	if err != nil {
+		log.Printf("lsp: parse error for %s: %v", filePath, err)
+		return nil
+	}
+	return &documentAnalysis{
+		Analysis: analysis,
+		Config:   cfg,
+		Lines:    strings.Split(strings.ReplaceAll(doc.Content, "\r\n", "\n"), "\n"),
+		CacheDir: s.cacheDir(cfg, filePath),
+	}
+}
+
+// line returns the text of a 0-based line, or "" if it is out of range.
+func (a *documentAnalysis) line(n uint32) string {
+	if int(n) >= len(a.Lines) {
+		return ""
+	}
+	return a.Lines[n]
+}
+
+// escapeToken returns the Dockerfile's escape character.
+func (a *documentAnalysis) escapeToken() rune {
+	if a.ParseResult.AST != nil && a.ParseResult.AST.EscapeToken != 0 {
+		return a.ParseResult.AST.EscapeToken
+	}
+	return '\\'
+}
+
+// stageAt returns the index of the stage containing a 0-based line, or -1
+// for lines before the first FROM.
+func (a *documentAnalysis) stageAt(line uint32) int {
+	stageIdx := -1
+	for i, stage := range a.ParseResult.Stages {
+		if len(stage.Location) == 0 || stage.Location[0].Start.Line-1 > int(line) {
+			break
+		}
+		stageIdx = i
+	}
+	return stageIdx
+}
+
+// fromStageAt returns the index of the stage whose FROM instruction spans a
+// 0-based line, or -1 if the line is not part of a FROM.
+func (a *documentAnalysis) fromStageAt(line uint32) int {
+	for i, stage := range a.ParseResult.Stages {
+		if len(stage.Location) == 0 {
+			continue
+		}
+		start, end := stage.Location[0].Start.Line-1, stage.Location[len(stage.Location)-1].End.Line-1
+		if int(line) >= start && int(line) <= end {
+			return i
+		}
+	}
+	return -1
+}
+
+// fromBaseRange returns the 0-based line and the byte offsets of the base
+// image name in the FROM instruction of a stage.
+func (a *documentAnalysis) fromBaseRange(stageIdx int) (uint32, int, int, bool) {
+	info := a.Semantic.StageInfo(stageIdx)
+	if info == nil || info.BaseImage == nil || info.BaseImage.Raw == "" || len(info.BaseImage.Location) == 0 {
+		return 0, 0, 0, false
+	}
+	lineNum := clampUint32(info.BaseImage.Location[0].Start.Line - 1)
+	line := a.line(lineNum)
+	keywordEnd := instructionKeywordEnd(line)
+	if keywordEnd < 0 {
+		return 0, 0, 0, false
+	}
+	idx := strings.Index(line[keywordEnd:], info.BaseImage.Raw)
+	if idx < 0 {
+		return 0, 0, 0, false
+	}
+	start := keywordEnd + idx
+	return lineNum, start, start + len(info.BaseImage.Raw), true
+}
+
+// instructionKeywordEnd returns the byte offset just past the instruction
+// keyword of a line, or -1 if the line has nothing after the keyword.
+func instructionKeywordEnd(line string) int {
+	trimmed := strings.TrimLeft(line, " \t")
+	end := strings.IndexAny(trimmed, " \t")
+	if end < 0 {
+		return -1
+	}
+	return end + len(line) - len(trimmed)
+}
+
+// scopeAt returns the stage whose variables are visible at a 0-based line:
+// -1 (the global scope) for FROM instructions and lines before the first
+// FROM, the enclosing stage otherwise.
+func (a *documentAnalysis) scopeAt(line uint32) int {
+	if a.fromStageAt(line) >= 0 {
+		return -1
+	}
+	return a.stageAt(line)
+}
+
+// variableRef is a $NAME or ${NAME...} reference on a line.
+type variableRef struct {
+	// Name is the variable name.
+	Name string
+
+	// Start and End are the byte offsets of the reference on its line,
+	// from the $ to the end of the name or the closing brace.
+	Start, End int
+}
+
+// variableRefs returns the variable references on a line. Dollars preceded
+// by the escape character are literal and skipped.
+func variableRefs(line string, escape rune) []variableRef {
+	var refs []variableRef
+	for i := 0; i < len(line); i++ {
+		switch {
+		case rune(line[i]) == escape:
+			i++ // skip the escaped character
+		case line[i] == '$' && i+1 < len(line):
+			if ref, ok := parseVariableRef(line, i); ok {
+				refs = append(refs, ref)
+				i = ref.End - 1
+			}
+		}
+	}
+	return refs
+}
+
+// parseVariableRef parses the reference starting at the $ at line[start].
+func parseVariableRef(line string, start int) (variableRef, bool) {
+	i := start + 1
+	braced := line[i] == '{'
+	if braced {
+		i++
+	}
+	nameStart := i
+	for i < len(line) && isVariableNameByte(line[i], i == nameStart) {
+		i++
+	}
+	if i == nameStart {
+		return variableRef{}, false
+	}
+	ref := variableRef{Name: line[nameStart:i], Start: start, End: i}
+	if braced {
+		// Include the modifier (${NAME:-default}) up to the matching brace.
+		depth := 1
+		for ; i < len(line) && depth > 0; i++ {
+			switch line[i] {
+			case '{':
+				depth++
+			case '}':
+				depth--
+			}
+		}
+		ref.End = i
+	}
+	return ref, true
+}
+
+func isVariableNameByte(c byte, first bool) bool {
+	switch {
+	case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+		return true
+	case c >= '0' && c <= '9':
+		return !first
+	default:
+		return false
+	}
+}
+
+// variableRefAt returns the variable reference at a position, if any.
+func (a *documentAnalysis) variableRefAt(pos protocol.Position) (variableRef, bool) {
+	line := a.line(pos.Line)
+	if strings.HasPrefix(strings.TrimSpace(line), "#") {
+		return variableRef{}, false
+	}
+	for _, ref := range variableRefs(line, a.escapeToken()) {
+		if int(pos.Character) >= ref.Start && int(pos.Character) < ref.End {
+			return ref, true
+		}
+	}
+	return variableRef{}, false
+}
+
+// lookupVariable resolves a variable in the scope visible at a 0-based line:
+// declarations after the line, or in the instruction containing it, are not
+// visible.
+func (a *documentAnalysis) lookupVariable(line uint32, name string) (semantic.VariableDefinition, bool) {
+	return a.Semantic.LookupVariableAt(a.scopeAt(line), int(line)+1, name)
+}
+
+// variablesAt returns the variable scope visible at a 0-based line, or nil.
+func (a *documentAnalysis) variablesAt(line uint32) *semantic.VariableScope {
+	return a.Semantic.ScopeAt(a.scopeAt(line), int(line)+1)
+}
+
+// lineRange returns the range of [start, end) on a 0-based line.
+func lineRange(line uint32, start, end int) *protocol.Range {
+	return &protocol.Range{
+		Start: protocol.Position{Line: line, Character: clampUint32(start)},
+		End:   protocol.Position{Line: line, Character: clampUint32(end)},
+	}
+}
@@ -1,6 +1,7 @@
 package lspserver
 
 import (
+	"slices"
 	"strings"
 
 	protocol "github.com/tinovyatkin/tally/internal/lsp/protocol"
@@ -8,7 +9,8 @@ import (
 	"github.com/tinovyatkin/tally/internal/rules"
 )
 
-// codeActionsForDocument returns quick-fix code actions for the given range.
+// codeActionsForDocument returns quick-fix code actions for the given range:
+// the fixes of the violations in it, then the actions suppressing their rules.
 func (s *Server) codeActionsForDocument(
 	doc *Document,
 	params *protocol.CodeActionParams,
@@ -29,37 +31,53 @@ func (s *Server) codeActionsForDocument(
 	actions := make([]protocol.CodeAction, 0, len(violations)+1)
 
 	if includeQuickFix {
+		// Parsed on demand, for the suppression actions.
+		var analysis *documentAnalysis
+		analyzed := false
+		suppressed := make(map[string]bool)
 		for _, v := range violations {
-			if v.SuggestedFix == nil || v.SuggestedFix.NeedsResolve {
-				continue
-			}
-			if len(v.SuggestedFix.Edits) == 0 {
-				continue
-			}
-
 			vRange := violationRange(v)
 			if !rangesOverlap(vRange, params.Range) {
 				continue
 			}
 
-			edits := convertTextEdits(v.SuggestedFix.Edits)
-			if len(edits) == 0 {
-				continue
+			fixes := v.Fixes()
+			explicitPreference := slices.ContainsFunc(fixes, func(sf *rules.SuggestedFix) bool {
+				return sf.IsPreferred
+			})
+			for _, sf := range fixes {
+				if sf.NeedsResolve || len(sf.Edits) == 0 {
+					continue
+				}
+
+				edits := convertTextEdits(sf.Edits)
+				if len(edits) == 0 {
+					continue
+				}
+
+				// When a rule marks a fix as preferred, only that fix is;
+				// otherwise every safe fix is.
+				preferred := sf.IsPreferred || (!explicitPreference && sf.Safety == rules.FixSafe)
+				matchedDiags := matchingDiagnostics(v, params.Context.Diagnostics)
+				action := protocol.CodeAction{
+					Title:       sf.Description,
+					Kind:        ptrTo(protocol.CodeActionKindQuickFix),
+					IsPreferred: new(preferred),
+					Diagnostics: &matchedDiags,
+					Edit: &protocol.WorkspaceEdit{
+						Changes: new(map[protocol.DocumentUri][]*protocol.TextEdit{
+							params.TextDocument.Uri: edits,
+						}),
+					},
+				}
+				actions = append(actions, action)
 			}
 
-			matchedDiags := matchingDiagnostics(v, params.Context.Diagnostics)
-			action := protocol.CodeAction{
-				Title:       v.SuggestedFix.Description,
-				Kind:        ptrTo(protocol.CodeActionKindQuickFix),
-				IsPreferred: new(v.SuggestedFix.IsPreferred || v.SuggestedFix.Safety == rules.FixSafe),
-				Diagnostics: &matchedDiags,
-				Edit: &protocol.WorkspaceEdit{
-					Changes: new(map[protocol.DocumentUri][]*protocol.TextEdit{
-						params.TextDocument.Uri: edits,
-					}),
-				},
+			if !analyzed {
+				analysis, analyzed = s.analyzeDocument(doc), true
 			}
-			actions = append(actions, action)
+			actions = append(actions,
+				s.suppressActions(analysis, doc, v, matchingDiagnostics(v, params.Context.Diagnostics), suppressed)...)
 		}
 	}
 
@@ -0,0 +1,72 @@
+package lspserver
+
+import (
+	"fmt"
+	"slices"
+
+	protocol "github.com/tinovyatkin/tally/internal/lsp/protocol"
+)
+
+// handleCodeLens handles textDocument/codeLens: a "Fix all in file" lens at
+// the top of a document with safe fixes, and the relationships of each stage
+// above its FROM instruction.
+func (s *Server) handleCodeLens(params *protocol.CodeLensParams) (any, error) {
+	doc := s.documents.Get(string(params.TextDocument.Uri))
+	if doc == nil {
+		return nil, nil //nolint:nilnil // LSP: null result is valid for "no code lenses"
+	}
+
+	lenses := []protocol.CodeLens{}
+	if s.fixAllCodeAction(doc) != nil {
+		lenses = append(lenses, protocol.CodeLens{
+			Range: *lineRange(0, 0, 0),
+			Command: &protocol.Command{
+				Title:     "Fix all in file",
+				Command:   applyAllFixesCommand,
+				Arguments: &[]any{map[string]any{"uri": doc.URI}},
+			},
+		})
+	}
+	if a := s.analyzeDocument(doc); a != nil {
+		lenses = append(lenses, a.stageCodeLenses()...)
+	}
+	return lenses, nil
+}
+
+// stageCodeLenses returns the lenses above each FROM instruction: how many
+// stages use the stage (COPY --from or FROM <stage>), and whether it is
+// unreachable from the build target. They are informational: their command
+// does nothing.
+func (a *documentAnalysis) stageCodeLenses() []protocol.CodeLens {
+	graph := a.Semantic.Graph()
+	if graph == nil {
+		return nil
+	}
+	var unreachable []int
+	if a.Semantic.TargetStageIndex() >= 0 {
+		unreachable = graph.UnreachableStages()
+	}
+
+	var lenses []protocol.CodeLens
+	for i, stage := range a.ParseResult.Stages {
+		if len(stage.Location) == 0 {
+			continue
+		}
+		line := clampUint32(stage.Location[0].Start.Line - 1)
+		rng := *lineRange(line, 0, len(a.line(line)))
+		info := func(title string) {
+			lenses = append(lenses, protocol.CodeLens{Range: rng, Command: &protocol.Command{Title: title}})
+		}
+
+		dependents := slices.Compact(slices.Sorted(slices.Values(graph.DirectDependents(i))))
+		if len(dependents) == 1 {
+			info("used by 1 stage")
+		} else {
+			info(fmt.Sprintf("used by %d stages", len(dependents)))
+		}
+		if slices.Contains(unreachable, i) {
+			info("unreachable from target")
+		}
+	}
+	return lenses
+}
@@ -0,0 +1,53 @@
+package lspserver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	protocol "github.com/tinovyatkin/tally/internal/lsp/protocol"
+)
+
+func TestCodeLens(t *testing.T) {
+	t.Parallel()
+	const content = `FROM alpine:3.20 AS base
+FROM base AS build
+COPY --from=base /etc/os-release /
+COPY --from=base /etc/hostname /
+FROM alpine:3.20 AS docs
+FROM base
+COPY --from=build /etc/os-release /
+maintainer me@example.com
+`
+	s, doc := openTestDocument(t, content, "")
+	result, err := s.handleCodeLens(&protocol.CodeLensParams{
+		TextDocument: protocol.TextDocumentIdentifier{Uri: protocol.DocumentUri(doc.URI)},
+	})
+	require.NoError(t, err)
+	require.IsType(t, []protocol.CodeLens{}, result)
+	lenses, _ := result.([]protocol.CodeLens)
+
+	type lens struct {
+		line    uint32
+		title   string
+		command string
+	}
+	got := make([]lens, 0, len(lenses))
+	for _, l := range lenses {
+		require.NotNil(t, l.Command)
+		got = append(got, lens{l.Range.Start.Line, l.Command.Title, l.Command.Command})
+	}
+	assert.Equal(t, []lens{
+		{0, "Fix all in file", applyAllFixesCommand},
+		{0, "used by 2 stages", ""},
+		{1, "used by 1 stage", ""},
+		{4, "used by 0 stages", ""},
+		{4, "unreachable from target", ""},
+		{5, "used by 0 stages", ""},
+	}, got)
+
+	args, ok := (*lenses[0].Command.Arguments)[0].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, doc.URI, args["uri"])
+}
@@ -0,0 +1,460 @@
+package lspserver
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/moby/buildkit/frontend/dockerfile/instructions"
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+
+	protocol "github.com/tinovyatkin/tally/internal/lsp/protocol"
+
+	"github.com/tinovyatkin/tally/internal/linter"
+	"github.com/tinovyatkin/tally/internal/semantic"
+)
+
+// completionTriggerCharacters start a completion request without the user
+// asking for one: variables ($, ${), flags (--), flag values and mount
+// options (=, ,).
+var completionTriggerCharacters = []string{"$", "{", "-", "=", ","}
+
+// tallyDirectivePrefix matches a "# tally [global] ignore=" (or disable=,
+// enable=) directive up to a partially typed rule code.
+var tallyDirectivePrefix = regexp.MustCompile(
+	`(?i)^\s*#\s*tally\s+(?:global\s+)?(?:ignore|disable|enable)\s*=\s*((?:[A-Za-z0-9_/.-]+\s*,\s*)*)([A-Za-z0-9_/.-]*)$`)
+
+// handleCompletion handles textDocument/completion. Depending on the
+// position it completes instruction keywords, instruction flags and RUN
+// --mount options, stage names, ARG/ENV variables in scope and rule codes in
+// tally directives.
+func (s *Server) handleCompletion(params *protocol.CompletionParams) (any, error) {
+	doc := s.documents.Get(string(params.TextDocument.Uri))
+	if doc == nil {
+		return nil, nil //nolint:nilnil // LSP: null result is valid for "no completions"
+	}
+	items := s.newCompletionRequest(doc, params.Position).items()
+	if len(items) == 0 {
+		return nil, nil //nolint:nilnil // LSP: null result is valid for "no completions"
+	}
+	return &protocol.CompletionList{Items: items}, nil
+}
+
+// completionRequest is a completion position with the text around it.
+type completionRequest struct {
+	// a is the document analysis, or nil if the document can't be parsed.
+	a *documentAnalysis
+
+	lines  []string
+	escape rune
+	line   uint32
+
+	// prefix is the text of the line before the cursor.
+	prefix string
+}
+
+// newCompletionRequest analyzes a document for completion. Documents are
+// often incomplete while typing (e.g. "FROM " with no image yet), so if the
+// document can't be parsed it is analyzed again without the cursor line.
+func (s *Server) newCompletionRequest(doc *Document, pos protocol.Position) *completionRequest {
+	lines := strings.Split(strings.ReplaceAll(doc.Content, "\r\n", "\n"), "\n")
+	r := &completionRequest{lines: lines, escape: '\\', line: pos.Line}
+	if int(pos.Line) < len(lines) {
+		line := lines[pos.Line]
+		r.prefix = line[:min(int(pos.Character), len(line))]
+	}
+
+	r.a = s.analyzeDocument(doc)
+	if r.a == nil && int(pos.Line) < len(lines) {
+		edited := slices.Clone(lines)
+		edited[pos.Line] = ""
+		r.a = s.analyzeDocument(&Document{URI: doc.URI, Version: doc.Version, Content: strings.Join(edited, "\n")})
+		if r.a != nil {
+			r.a.Lines = lines
+		}
+	}
+	if r.a != nil {
+		r.escape = r.a.escapeToken()
+	}
+	return r
+}
+
+// items returns the completion items for the request position.
+func (r *completionRequest) items() []*protocol.CompletionItem {
+	if strings.HasPrefix(strings.TrimSpace(r.prefix), "#") {
+		return r.ruleCodeItems()
+	}
+	if items, ok := r.variableItems(); ok {
+		return items
+	}
+
+	start := r.instructionStart()
+	if r.inHeredoc(start) {
+		return nil
+	}
+	if start == r.line && isKeywordPrefix(strings.TrimLeft(r.prefix, " \t")) {
+		return keywordItems(r.replaceRange(len(strings.TrimLeft(r.prefix, " \t"))), false)
+	}
+
+	keyword, args, word := r.instructionWords(start)
+	if keyword == "ONBUILD" {
+		if len(args) == 0 && isKeywordPrefix(word) {
+			return keywordItems(r.replaceRange(len(word)), true)
+		}
+		if len(args) == 0 {
+			return nil
+		}
+		keyword, args = strings.ToUpper(args[0]), args[1:]
+	}
+
+	inFlags := !slices.ContainsFunc(args, func(arg string) bool { return !strings.HasPrefix(arg, "--") })
+	if !inFlags {
+		return nil
+	}
+	if name, value, ok := strings.Cut(word, "="); ok && strings.HasPrefix(name, "--") {
+		return r.flagValueItems(keyword, strings.TrimPrefix(name, "--"), value)
+	}
+	if strings.HasPrefix(word, "-") {
+		return flagItems(keyword, r.replaceRange(len(word)))
+	}
+
+	var items []*protocol.CompletionItem
+	if keyword == "FROM" {
+		items = r.stageItems(r.replaceRange(len(word)), r.a.fromStageAtOr(r.line))
+	}
+	if word == "" {
+		items = append(items, flagItems(keyword, r.replaceRange(0))...)
+	}
+	return items
+}
+
+// replaceRange returns the range of the n bytes before the cursor, replaced
+// by a completion.
+func (r *completionRequest) replaceRange(n int) protocol.Range {
+	end := len(r.prefix)
+	return *lineRange(r.line, end-n, end)
+}
+
+// instructionStart returns the first line of the instruction containing the
+// cursor line, following line continuations backwards.
+func (r *completionRequest) instructionStart() uint32 {
+	start := r.line
+	for start > 0 && int(start) <= len(r.lines) && continuesOnNextLine(r.lines[start-1], r.escape) {
+		start--
+	}
+	return start
+}
+
+// continuesOnNextLine reports whether an instruction line ends with the
+// escape character.
+func continuesOnNextLine(line string, escape rune) bool {
+	trimmed := strings.TrimRight(line, " \t")
+	return strings.HasSuffix(trimmed, string(escape)) && !strings.HasPrefix(strings.TrimSpace(trimmed), "#")
+}
+
+// inHeredoc reports whether the cursor line belongs to an instruction that
+// starts before the logical line at start, i.e. to a heredoc body.
+func (r *completionRequest) inHeredoc(start uint32) bool {
+	if r.a == nil || r.a.ParseResult.AST == nil || r.a.ParseResult.AST.AST == nil {
+		return false
+	}
+	for _, node := range r.a.ParseResult.AST.AST.Children {
+		if node.StartLine-1 < int(start) && node.EndLine-1 >= int(r.line) {
+			return true
+		}
+	}
+	return false
+}
+
+// isKeywordPrefix reports whether s could be the start of an instruction
+// keyword.
+func isKeywordPrefix(s string) bool {
+	for _, c := range s {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+// instructionWords splits the instruction up to the cursor into its
+// uppercase keyword, the complete arguments and the word being typed.
+func (r *completionRequest) instructionWords(start uint32) (string, []string, string) {
+	var text strings.Builder
+	for n := start; n < r.line; n++ {
+		line := strings.TrimRight(r.lines[n], " \t")
+		text.WriteString(strings.TrimSuffix(line, string(r.escape)))
+		text.WriteByte(' ')
+	}
+	text.WriteString(r.prefix)
+
+	fields := strings.Fields(text.String())
+	word := ""
+	if len(fields) > 0 && !strings.HasSuffix(r.prefix, " ") && !strings.HasSuffix(r.prefix, "\t") && r.prefix != "" {
+		word = fields[len(fields)-1]
+		fields = fields[:len(fields)-1]
+	}
+	if len(fields) == 0 {
+		return "", nil, word
+	}
+	return strings.ToUpper(fields[0]), fields[1:], word
+}
+
+// keywordItems completes instruction keywords. ONBUILD triggers can't be
+// ONBUILD, FROM or MAINTAINER.
+func keywordItems(replace protocol.Range, onbuild bool) []*protocol.CompletionItem {
+	items := make([]*protocol.CompletionItem, 0, len(instructionDocs))
+	for _, inst := range instructionDocs {
+		if onbuild && (inst.Keyword == "ONBUILD" || inst.Keyword == "FROM" || inst.Keyword == "MAINTAINER") {
+			continue
+		}
+		item := completionItem(inst.Keyword, protocol.CompletionItemKindKeyword, replace, inst.Keyword+" ",
+			fmt.Sprintf("%s\n\n[Dockerfile reference](%s#%s)", inst.Doc, dockerfileReferenceURL, strings.ToLower(inst.Keyword)))
+		if inst.Deprecated {
+			item.Tags = &[]protocol.CompletionItemTag{protocol.CompletionItemTagDeprecated}
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// flagItems completes the flags of an instruction.
+func flagItems(keyword string, replace protocol.Range) []*protocol.CompletionItem {
+	flags := instructionFlags[keyword]
+	items := make([]*protocol.CompletionItem, 0, len(flags))
+	for _, flag := range flags {
+		insert := "--" + flag.Name
+		if !flag.Bool {
+			insert += "="
+		}
+		items = append(items, completionItem("--"+flag.Name, protocol.CompletionItemKindProperty, replace, insert, flag.Doc))
+	}
+	return items
+}
+
+// flagValueItems completes the value of an instruction flag: stage names
+// for COPY --from, the options of RUN --mount and enumerated values.
+func (r *completionRequest) flagValueItems(keyword, flag, value string) []*protocol.CompletionItem {
+	if keyword == "RUN" && flag == "mount" {
+		return r.mountItems(value)
+	}
+	if keyword == "COPY" && flag == "from" {
+		return r.stageItems(r.replaceRange(len(value)), r.a.stageAtOr(r.line))
+	}
+	idx := slices.IndexFunc(instructionFlags[keyword], func(f flagDoc) bool { return f.Name == flag })
+	if idx < 0 {
+		return nil
+	}
+	return valueItems(instructionFlags[keyword][idx].Values, r.replaceRange(len(value)))
+}
+
+// mountItems completes the comma-separated key=value options of RUN --mount.
+// Keys are limited to the ones the mount type accepts (bind by default).
+func (r *completionRequest) mountItems(value string) []*protocol.CompletionItem {
+	options := strings.Split(value, ",")
+	current := options[len(options)-1]
+	mountType := instructions.MountTypeBind
+	used := make(map[string]bool)
+	for _, opt := range options[:len(options)-1] {
+		key, val, _ := strings.Cut(opt, "=")
+		used[strings.ToLower(key)] = true
+		if strings.EqualFold(key, "type") {
+			mountType = instructions.MountType(strings.ToLower(val))
+		}
+	}
+
+	if key, val, ok := strings.Cut(current, "="); ok {
+		replace := r.replaceRange(len(val))
+		if strings.EqualFold(key, "from") {
+			return r.stageItems(replace, r.a.stageAtOr(r.line))
+		}
+		idx := slices.IndexFunc(mountOptions, func(o mountOption) bool { return strings.EqualFold(o.Key, key) })
+		if idx < 0 {
+			return nil
+		}
+		return valueItems(mountOptions[idx].Values, replace)
+	}
+
+	replace := r.replaceRange(len(current))
+	var items []*protocol.CompletionItem
+	for _, opt := range mountOptions {
+		if used[opt.Key] || len(opt.Types) > 0 && !slices.Contains(opt.Types, mountType) {
+			continue
+		}
+		insert := opt.Key
+		if !opt.Bool {
+			insert += "="
+		}
+		items = append(items, completionItem(opt.Key, protocol.CompletionItemKindProperty, replace, insert, opt.Doc))
+	}
+	return items
+}
+
+// valueItems completes enumerated values.
+func valueItems(values []string, replace protocol.Range) []*protocol.CompletionItem {
+	items := make([]*protocol.CompletionItem, 0, len(values))
+	for _, v := range values {
+		items = append(items, completionItem(v, protocol.CompletionItemKindEnumMember, replace, v, ""))
+	}
+	return items
+}
+
+// stageItems completes the names of the stages before stage before.
+func (r *completionRequest) stageItems(replace protocol.Range, before int) []*protocol.CompletionItem {
+	if r.a == nil {
+		return nil
+	}
+	var items []*protocol.CompletionItem
+	for i := range before {
+		stage := r.a.Semantic.Stage(i)
+		if stage == nil || stage.Name == "" {
+			continue
+		}
+		detail := fmt.Sprintf("stage %d", i)
+		doc := ""
+		if line, _, _, ok := r.a.stageNameRange(i); ok {
+			doc = fmt.Sprintf("```dockerfile\n%s\n```", strings.TrimSpace(r.a.line(line)))
+		}
+		item := completionItem(stage.Name, protocol.CompletionItemKindModule, replace, stage.Name, doc)
+		item.Detail = &detail
+		items = append(items, item)
+	}
+	return items
+}
+
+// stageAtOr returns the stage containing a line, or -1 without an analysis.
+func (a *documentAnalysis) stageAtOr(line uint32) int {
+	if a == nil {
+		return -1
+	}
+	return a.stageAt(line)
+}
+
+// fromStageAtOr returns the stage defined by the FROM instruction being
+// typed on a line: the stage whose FROM spans the line, or the next stage.
+func (a *documentAnalysis) fromStageAtOr(line uint32) int {
+	if a == nil {
+		return -1
+	}
+	if idx := a.fromStageAt(line); idx >= 0 {
+		return idx
+	}
+	return a.stageAt(line) + 1
+}
+
+// variableItems completes the ARG and ENV variables in scope after $ or ${.
+// It reports false if the cursor is not in a variable reference.
+func (r *completionRequest) variableItems() ([]*protocol.CompletionItem, bool) {
+	nameStart := len(r.prefix)
+	for nameStart > 0 && isVariableNameByte(r.prefix[nameStart-1], false) {
+		nameStart--
+	}
+	dollar := nameStart - 1
+	if dollar >= 0 && r.prefix[dollar] == '{' {
+		dollar--
+	}
+	if dollar < 0 || r.prefix[dollar] != '$' || dollar > 0 && rune(r.prefix[dollar-1]) == r.escape {
+		return nil, false
+	}
+	if nameStart < len(r.prefix) && !isVariableNameByte(r.prefix[nameStart], true) {
+		return nil, false
+	}
+	if r.a == nil {
+		return nil, true
+	}
+
+	scopeIdx := r.a.scopeAt(r.line)
+	scope := r.a.variablesAt(r.line)
+	if scope == nil {
+		return nil, true
+	}
+	replace := r.replaceRange(len(r.prefix) - nameStart)
+	seen := make(map[string]bool)
+	var items []*protocol.CompletionItem
+	add := func(name string, source semantic.VariableSource, location []parser.Range) {
+		if seen[name] || len(location) == 0 || location[0].Start.Line-1 >= int(r.line) {
+			return
+		}
+		seen[name] = true
+		detail := source.String()
+		item := completionItem(name, protocol.CompletionItemKindVariable, replace, name,
+			r.a.variableHover(r.line, variableRef{Name: name}))
+		item.Detail = &detail
+		items = append(items, item)
+	}
+	for _, env := range scope.Envs() {
+		add(env.Name, semantic.VariableSourceEnv, env.Location)
+	}
+	argSource := semantic.VariableSourceArg
+	if scopeIdx < 0 {
+		argSource = semantic.VariableSourceGlobalArg
+	}
+	for _, arg := range scope.Args() {
+		add(arg.Name, argSource, arg.Location)
+	}
+	return items, true
+}
+
+// ruleCodeItems completes rule codes in tally ignore, disable and enable
+// directives, skipping the codes already listed.
+func (r *completionRequest) ruleCodeItems() []*protocol.CompletionItem {
+	m := tallyDirectivePrefix.FindStringSubmatch(r.prefix)
+	if m == nil {
+		return nil
+	}
+	listed := make(map[string]bool)
+	for code := range strings.SplitSeq(m[1], ",") {
+		listed[strings.ToLower(strings.TrimSpace(code))] = true
+	}
+	replace := r.replaceRange(len(m[2]))
+	catalog := linter.Catalog()
+	items := make([]*protocol.CompletionItem, 0, len(catalog))
+	for _, ri := range catalog {
+		if listed[strings.ToLower(ri.Code)] {
+			continue
+		}
+		item := completionItem(ri.Code, protocol.CompletionItemKindConstant, replace, ri.Code, ruleDocumentation(ri))
+		if ri.Name != "" {
+			item.Detail = &ri.Name
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// ruleDocumentation describes a rule for completion: its description and a
+// link to the rule documentation.
+func ruleDocumentation(ri linter.RuleInfo) string {
+	var parts []string
+	if ri.Description != "" {
+		parts = append(parts, ri.Description)
+	}
+	if ri.DocURL != "" {
+		parts = append(parts, fmt.Sprintf("[Documentation](%s)", ri.DocURL))
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// completionItem builds a completion item replacing a range with insert,
+// with optional Markdown documentation.
+func completionItem(
+	label string,
+	kind protocol.CompletionItemKind,
+	replace protocol.Range,
+	insert, doc string,
+) *protocol.CompletionItem {
+	item := &protocol.CompletionItem{
+		Label: label,
+		Kind:  &kind,
+		TextEdit: &protocol.TextEditOrInsertReplaceEdit{
+			TextEdit: &protocol.TextEdit{Range: replace, NewText: insert},
+		},
+	}
+	if doc != "" {
+		item.Documentation = &protocol.StringOrMarkupContent{
+			MarkupContent: &protocol.MarkupContent{Kind: protocol.MarkupKindMarkdown, Value: doc},
+		}
+	}
+	return item
+}
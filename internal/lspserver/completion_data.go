@@ -0,0 +1,152 @@
+package lspserver
+
+import (
+	"github.com/moby/buildkit/frontend/dockerfile/instructions"
+)
+
+// dockerfileReferenceURL is the Dockerfile reference; instruction sections
+// are anchored by the lowercase keyword (#run).
+const dockerfileReferenceURL = "https://docs.docker.com/reference/dockerfile/"
+
+// instructionDoc describes a Dockerfile instruction for completion.
+type instructionDoc struct {
+	Keyword    string
+	Doc        string
+	Deprecated bool
+}
+
+// instructionDocs lists the Dockerfile instructions in alphabetical order,
+// summarized from the Dockerfile reference.
+var instructionDocs = []instructionDoc{
+	{Keyword: "ADD", Doc: "Add local or remote files, directories, Git repositories and archives to the image."},
+	{Keyword: "ARG", Doc: "Declare a build variable that can be set at build time with `--build-arg`."},
+	{Keyword: "CMD", Doc: "Set the default command the container runs."},
+	{Keyword: "COPY", Doc: "Copy files and directories from the build context, another stage or an image."},
+	{Keyword: "ENTRYPOINT", Doc: "Set the executable the container runs."},
+	{Keyword: "ENV", Doc: "Set environment variables for the following instructions and the container."},
+	{Keyword: "EXPOSE", Doc: "Document the ports the application listens on."},
+	{Keyword: "FROM", Doc: "Start a build stage from a base image or an earlier stage."},
+	{Keyword: "HEALTHCHECK", Doc: "Set how the container is checked to still be working."},
+	{Keyword: "LABEL", Doc: "Add key-value metadata to the image."},
+	{Keyword: "MAINTAINER", Doc: "Set the image author. Deprecated: use `LABEL org.opencontainers.image.authors` instead.", Deprecated: true},
+	{Keyword: "ONBUILD", Doc: "Add an instruction that runs when the image is used as the base of another build."},
+	{Keyword: "RUN", Doc: "Run a command in a new layer."},
+	{Keyword: "SHELL", Doc: "Set the shell used by the shell form of the following instructions."},
+	{Keyword: "STOPSIGNAL", Doc: "Set the signal sent to the container to stop it."},
+	{Keyword: "USER", Doc: "Set the user (and group) for the following instructions and the container."},
+	{Keyword: "VOLUME", Doc: "Create a mount point for externally mounted volumes."},
+	{Keyword: "WORKDIR", Doc: "Set the working directory for the following instructions and the container."},
+}
+
+// flagDoc describes an instruction flag (--name) for completion.
+type flagDoc struct {
+	Name string
+	Doc  string
+
+	// Bool flags take no value (--link).
+	Bool bool
+
+	// Values are the accepted values of enumerated flags.
+	Values []string
+}
+
+// instructionFlags lists the flags each instruction accepts, as parsed by
+// BuildKit's instructions package, with descriptions from the Dockerfile
+// reference.
+var instructionFlags = map[string][]flagDoc{
+	"ADD": {
+		{Name: "chown", Doc: "Set the user and group owning the added files (`user:group`)."},
+		{Name: "chmod", Doc: "Set the permissions of the added files, in octal or symbolic notation."},
+		{Name: "link", Bool: true, Doc: "Add the files in an independent layer that survives changes to the previous layers."},
+		{Name: "keep-git-dir", Bool: true, Doc: "Keep the `.git` directory when adding a Git repository."},
+		{Name: "checksum", Doc: "Verify the checksum of a remote source (`sha256:<hash>`)."},
+		{Name: "unpack", Bool: true, Doc: "Extract archives even when they come from a URL."},
+		{Name: "exclude", Doc: "Exclude files matching a pattern from the sources."},
+	},
+	"COPY": {
+		{Name: "from", Doc: "Copy from a build stage, an image or a named context instead of the build context."},
+		{Name: "chown", Doc: "Set the user and group owning the copied files (`user:group`)."},
+		{Name: "chmod", Doc: "Set the permissions of the copied files, in octal or symbolic notation."},
+		{Name: "link", Bool: true, Doc: "Copy the files in an independent layer that survives changes to the previous layers."},
+		{Name: "parents", Bool: true, Doc: "Preserve the parent directories of the sources in the destination."},
+		{Name: "exclude", Doc: "Exclude files matching a pattern from the sources."},
+	},
+	"FROM": {
+		{Name: "platform", Doc: "Select the platform of a multi-platform base image (`linux/amd64`, `$BUILDPLATFORM`).",
+			Values: []string{"$BUILDPLATFORM", "$TARGETPLATFORM"}},
+	},
+	"HEALTHCHECK": {
+		{Name: "interval", Doc: "Time between checks (default `30s`)."},
+		{Name: "timeout", Doc: "Time after which a check is considered failed (default `30s`)."},
+		{Name: "start-period", Doc: "Initialization time during which failures don't count (default `0s`)."},
+		{Name: "start-interval", Doc: "Time between checks during the start period (default `5s`)."},
+		{Name: "retries", Doc: "Consecutive failures needed to report the container unhealthy (default `3`)."},
+	},
+	"RUN": {
+		{Name: "mount", Doc: "Mount a cache, secret, SSH agent socket, tmpfs or files from another stage while the command runs."},
+		{Name: "network", Doc: "Network the command runs in.",
+			Values: []string{instructions.NetworkDefault, instructions.NetworkNone, instructions.NetworkHost}},
+		{Name: "security", Doc: "Run in the default sandbox or, with the `security.insecure` entitlement, with elevated privileges.",
+			Values: []string{instructions.SecuritySandbox, instructions.SecurityInsecure}},
+	},
+}
+
+// mountOption describes a RUN --mount option (key=value) for completion.
+type mountOption struct {
+	Key string
+	Doc string
+
+	// Bool options may be given without a value (readonly).
+	Bool bool
+
+	// Values are the accepted values of enumerated options.
+	Values []string
+
+	// Types are the mount types accepting the option; empty means all.
+	Types []instructions.MountType
+}
+
+// mountOptions lists the RUN --mount options, as parsed by BuildKit.
+var mountOptions = []mountOption{
+	{Key: "type", Doc: "Mount type (default `bind`).", Values: []string{
+		string(instructions.MountTypeBind), string(instructions.MountTypeCache), string(instructions.MountTypeTmpfs),
+		string(instructions.MountTypeSecret), string(instructions.MountTypeSSH),
+	}},
+	{Key: "target", Doc: "Mount path in the container."},
+	{Key: "source", Doc: "Source path in `from`.", Types: []instructions.MountType{
+		instructions.MountTypeBind, instructions.MountTypeCache,
+	}},
+	{Key: "from", Doc: "Build stage, image or named context to mount from.", Types: []instructions.MountType{
+		instructions.MountTypeBind, instructions.MountTypeCache,
+	}},
+	{Key: "rw", Bool: true, Doc: "Allow writes to a bind mount (discarded after the command).", Types: []instructions.MountType{
+		instructions.MountTypeBind,
+	}},
+	{Key: "ro", Bool: true, Doc: "Mount the cache read-only.", Types: []instructions.MountType{
+		instructions.MountTypeCache,
+	}},
+	{Key: "id", Doc: "Cache, secret or SSH agent ID.", Types: []instructions.MountType{
+		instructions.MountTypeCache, instructions.MountTypeSecret, instructions.MountTypeSSH,
+	}},
+	{Key: "sharing", Doc: "How concurrent builds share the cache (default `shared`).", Values: []string{
+		string(instructions.MountSharingShared), string(instructions.MountSharingPrivate), string(instructions.MountSharingLocked),
+	}, Types: []instructions.MountType{instructions.MountTypeCache}},
+	{Key: "size", Doc: "Size limit of the tmpfs.", Types: []instructions.MountType{
+		instructions.MountTypeTmpfs,
+	}},
+	{Key: "env", Doc: "Expose the secret as this environment variable.", Types: []instructions.MountType{
+		instructions.MountTypeSecret,
+	}},
+	{Key: "required", Bool: true, Doc: "Fail the build if the secret or SSH agent is not available.", Types: []instructions.MountType{
+		instructions.MountTypeSecret, instructions.MountTypeSSH,
+	}},
+	{Key: "mode", Doc: "File mode of the mount, in octal.", Types: []instructions.MountType{
+		instructions.MountTypeCache, instructions.MountTypeSecret, instructions.MountTypeSSH,
+	}},
+	{Key: "uid", Doc: "User ID owning the mount.", Types: []instructions.MountType{
+		instructions.MountTypeCache, instructions.MountTypeSecret, instructions.MountTypeSSH,
+	}},
+	{Key: "gid", Doc: "Group ID owning the mount.", Types: []instructions.MountType{
+		instructions.MountTypeCache, instructions.MountTypeSecret, instructions.MountTypeSSH,
+	}},
+}
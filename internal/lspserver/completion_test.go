@@ -0,0 +1,224 @@
+package lspserver
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	protocol "github.com/tinovyatkin/tally/internal/lsp/protocol"
+)
+
+// complete requests completion at the position marked by "|" in content.
+func complete(t *testing.T, content string) []*protocol.CompletionItem {
+	t.Helper()
+	before, after, found := strings.Cut(content, "|")
+	require.True(t, found, "content has no cursor marker")
+	lines := strings.Split(before, "\n")
+	pos := protocol.Position{
+		Line:      clampUint32(len(lines) - 1),
+		Character: clampUint32(len(lines[len(lines)-1])),
+	}
+
+	s, doc := openTestDocument(t, before+after, "")
+	result, err := s.handleCompletion(&protocol.CompletionParams{
+		TextDocument: protocol.TextDocumentIdentifier{Uri: protocol.DocumentUri(doc.URI)},
+		Position:     pos,
+	})
+	require.NoError(t, err)
+	if result == nil {
+		return nil
+	}
+	require.IsType(t, &protocol.CompletionList{}, result)
+	list, _ := result.(*protocol.CompletionList)
+	return list.Items
+}
+
+// labels returns the labels of completion items.
+func labels(items []*protocol.CompletionItem) []string {
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		out = append(out, item.Label)
+	}
+	return out
+}
+
+// findItem returns the completion item with a label.
+func findItem(t *testing.T, items []*protocol.CompletionItem, label string) *protocol.CompletionItem {
+	t.Helper()
+	for _, item := range items {
+		if item.Label == label {
+			return item
+		}
+	}
+	require.Failf(t, "completion item not found", "%q not in %v", label, labels(items))
+	return nil
+}
+
+func TestCompletion_Keywords(t *testing.T) {
+	t.Parallel()
+	items := complete(t, "FROM alpine:3.20\nR|\n")
+	assert.Len(t, items, len(instructionDocs))
+
+	run := findItem(t, items, "RUN")
+	assert.Equal(t, protocol.CompletionItemKindKeyword, *run.Kind)
+	assert.Equal(t, &protocol.TextEdit{Range: *lineRange(1, 0, 1), NewText: "RUN "}, run.TextEdit.TextEdit)
+	assert.Contains(t, run.Documentation.MarkupContent.Value, "https://docs.docker.com/reference/dockerfile/#run")
+
+	maintainer := findItem(t, items, "MAINTAINER")
+	require.NotNil(t, maintainer.Tags)
+	assert.Equal(t, []protocol.CompletionItemTag{protocol.CompletionItemTagDeprecated}, *maintainer.Tags)
+}
+
+func TestCompletion_OnbuildKeywords(t *testing.T) {
+	t.Parallel()
+	got := labels(complete(t, "FROM alpine:3.20\nONBUILD |\n"))
+	assert.Contains(t, got, "RUN")
+	assert.NotContains(t, got, "ONBUILD")
+	assert.NotContains(t, got, "FROM")
+	assert.NotContains(t, got, "MAINTAINER")
+}
+
+func TestCompletion_NoKeywords(t *testing.T) {
+	t.Parallel()
+	tests := map[string]string{
+		"continuation line": "FROM alpine:3.20\nRUN apk add \\\n    |\n",
+		"heredoc body":      "FROM alpine:3.20\nRUN <<EOF\n|\nEOF\n",
+		"arguments":         "FROM alpine:3.20\nRUN apk add |\n",
+		"comment":           "FROM alpine:3.20\n# |\n",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Empty(t, complete(t, content))
+		})
+	}
+}
+
+func TestCompletion_Flags(t *testing.T) {
+	t.Parallel()
+	items := complete(t, "FROM alpine:3.20\nADD --c|\n")
+	assert.Equal(t, []string{
+		"--chown", "--chmod", "--link", "--keep-git-dir", "--checksum", "--unpack", "--exclude",
+	}, labels(items))
+	assert.Equal(t, &protocol.TextEdit{Range: *lineRange(1, 4, 7), NewText: "--checksum="},
+		findItem(t, items, "--checksum").TextEdit.TextEdit)
+	assert.Equal(t, "--link", findItem(t, items, "--link").TextEdit.TextEdit.NewText)
+
+	assert.Equal(t, []string{"--mount", "--network", "--security"},
+		labels(complete(t, "FROM alpine:3.20\nRUN --network=none \\\n    --|\n")))
+	assert.Equal(t, []string{"default", "none", "host"},
+		labels(complete(t, "FROM alpine:3.20\nRUN --network=|\n")))
+	assert.Empty(t, complete(t, "FROM alpine:3.20\nRUN echo --|\n"), "after the command")
+}
+
+func TestCompletion_MountOptions(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name:    "bind options by default",
+			content: "FROM alpine:3.20\nRUN --mount=|\n",
+			want:    []string{"type", "target", "source", "from", "rw"},
+		},
+		{
+			name:    "cache options",
+			content: "FROM alpine:3.20\nRUN --mount=type=cache,target=/root/.cache,|\n",
+			want:    []string{"source", "from", "ro", "id", "sharing", "mode", "uid", "gid"},
+		},
+		{
+			name:    "secret options",
+			content: "FROM alpine:3.20\nRUN --mount=type=secret,|\n",
+			want:    []string{"target", "id", "env", "required", "mode", "uid", "gid"},
+		},
+		{
+			name:    "mount types",
+			content: "FROM alpine:3.20\nRUN --mount=type=|\n",
+			want:    []string{"bind", "cache", "tmpfs", "secret", "ssh"},
+		},
+		{
+			name:    "sharing modes",
+			content: "FROM alpine:3.20\nRUN --mount=type=cache,sharing=|\n",
+			want:    []string{"shared", "private", "locked"},
+		},
+		{
+			name:    "stage to mount from",
+			content: "FROM golang:1.22 AS builder\nFROM alpine:3.20\nRUN --mount=from=b|\n",
+			want:    []string{"builder"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, labels(complete(t, tt.content)))
+		})
+	}
+
+	items := complete(t, "FROM alpine:3.20\nRUN --mount=type=cache,ta|\n")
+	assert.Equal(t, &protocol.TextEdit{Range: *lineRange(1, 23, 25), NewText: "target="},
+		findItem(t, items, "target").TextEdit.TextEdit)
+}
+
+func TestCompletion_StageNames(t *testing.T) {
+	t.Parallel()
+	const stages = "FROM golang:1.22 AS builder\nFROM alpine:3.20\nFROM alpine:3.20 AS base\n"
+
+	items := complete(t, stages+"FROM alpine:3.20\nCOPY --from=|\n")
+	assert.Equal(t, []string{"builder", "base"}, labels(items))
+	builder := findItem(t, items, "builder")
+	assert.Equal(t, "stage 0", *builder.Detail)
+	assert.Equal(t, "```dockerfile\nFROM golang:1.22 AS builder\n```", builder.Documentation.MarkupContent.Value)
+
+	// "FROM " alone doesn't parse; stages still come from the rest of the file.
+	assert.Equal(t, []string{"builder", "base", "--platform"}, labels(complete(t, stages+"FROM |\n")))
+	assert.Equal(t, []string{"builder"}, labels(complete(t, "FROM golang:1.22 AS builder\nFROM --platform=$BUILDPLATFORM b|\n")))
+	assert.Empty(t, complete(t, stages+"FROM base AS |\n"))
+}
+
+func TestCompletion_Variables(t *testing.T) {
+	t.Parallel()
+	const content = "ARG GO_VERSION=1.22\nARG BASE\nFROM golang:${%s}\nARG GO_VERSION\nARG PORT=8080\nENV APP=/app\n" +
+		"RUN echo $%s\nENV LATER=1\n"
+
+	items := complete(t, fmt.Sprintf(content, "|", ""))
+	assert.Equal(t, []string{"GO_VERSION", "BASE"}, labels(items))
+	assert.Equal(t, "global ARG", *items[0].Detail)
+	assert.Equal(t, &protocol.TextEdit{Range: *lineRange(2, 14, 14), NewText: "GO_VERSION"}, items[0].TextEdit.TextEdit)
+
+	// Stage variables declared before the cursor; global ARGs only if redeclared.
+	items = complete(t, fmt.Sprintf(content, "", "|"))
+	assert.Equal(t, []string{"APP", "GO_VERSION", "PORT"}, labels(items))
+	app := findItem(t, items, "APP")
+	assert.Equal(t, protocol.CompletionItemKindVariable, *app.Kind)
+	assert.Equal(t, "ENV", *app.Detail)
+	assert.Contains(t, app.Documentation.MarkupContent.Value, "**APP** = `/app`")
+
+	items = complete(t, "FROM alpine:3.20\nENV APP=/app\nRUN echo ${AP|}\n")
+	assert.Equal(t, &protocol.TextEdit{Range: *lineRange(2, 11, 13), NewText: "APP"},
+		findItem(t, items, "APP").TextEdit.TextEdit)
+
+	assert.Empty(t, complete(t, "FROM alpine:3.20\nENV APP=/app\nRUN echo \\$|\n"), "escaped dollar")
+}
+
+func TestCompletion_RuleCodes(t *testing.T) {
+	t.Parallel()
+	items := complete(t, "# tally ignore=hadolint/DL3006, hadolint/DL30|\nFROM alpine\n")
+	got := labels(items)
+	assert.Contains(t, got, "hadolint/DL3008")
+	assert.NotContains(t, got, "hadolint/DL3006", "already listed")
+
+	dl3008 := findItem(t, items, "hadolint/DL3008")
+	assert.Equal(t, protocol.CompletionItemKindConstant, *dl3008.Kind)
+	require.NotNil(t, dl3008.Detail)
+	assert.NotEmpty(t, *dl3008.Detail)
+	assert.Contains(t, dl3008.Documentation.MarkupContent.Value, "[Documentation](")
+	assert.Equal(t, *lineRange(0, 32, 45), dl3008.TextEdit.TextEdit.Range)
+
+	assert.NotEmpty(t, complete(t, "FROM alpine:3.20\n# tally global ignore=|\n"))
+	assert.Empty(t, complete(t, "FROM alpine:3.20\n# install tally ignore=|\n"))
+}
@@ -9,8 +9,12 @@ import (
 func (s *Server) configureDiagnosticsMode(params *protocol.InitializeParams) {
 	supportsPull := false
 	supportsRefresh := false
+	supportsProgress := false
 
 	if params != nil && params.Capabilities != nil {
+		if w := params.Capabilities.Window; w != nil && w.WorkDoneProgress != nil && *w.WorkDoneProgress {
+			supportsProgress = true
+		}
 		if td := params.Capabilities.TextDocument; td != nil && td.Diagnostic != nil {
 			supportsPull = true
 		}
@@ -33,6 +37,7 @@ func (s *Server) configureDiagnosticsMode(params *protocol.InitializeParams) {
 	s.pushDiagnostics = push
 	s.supportsDiagnosticPullMode = supportsPull
 	s.supportsDiagnosticRefresh = supportsRefresh
+	s.supportsWorkDoneProgress = supportsProgress
 	s.diagMu.Unlock()
 
 	if push {
@@ -53,3 +58,9 @@ func (s *Server) diagnosticRefreshSupported() bool {
 	defer s.diagMu.RUnlock()
 	return s.supportsDiagnosticPullMode && s.supportsDiagnosticRefresh
 }
+
+func (s *Server) workDoneProgressSupported() bool {
+	s.diagMu.RLock()
+	defer s.diagMu.RUnlock()
+	return s.supportsWorkDoneProgress
+}
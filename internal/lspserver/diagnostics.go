@@ -68,19 +68,19 @@ func (c *lintResultCache) clear() {
 }
 
 // publishDiagnostics lints a document and publishes diagnostics to the client.
+// Slow checks results follow in a second publication when they complete.
 func (s *Server) publishDiagnostics(ctx context.Context, doc *Document) {
-	docURI := doc.URI
-	content := doc.Content
-
-	violations := s.lintContent(docURI, []byte(content))
-	s.lintCache.set(docURI, doc.Version, violations)
-	diagnostics := convertDiagnostics(violations)
+	violations, _ := s.documentViolations(doc)
+	s.lintCache.set(doc.URI, doc.Version, violations)
+	s.sendDiagnostics(ctx, doc.URI, doc.Version, violations)
+}
 
-	version := doc.Version
+// sendDiagnostics publishes the diagnostics of a document version.
+func (s *Server) sendDiagnostics(ctx context.Context, docURI string, version int32, violations []rules.Violation) {
 	if err := lspNotify(ctx, s.conn, string(protocol.MethodTextDocumentPublishDiagnostics), &protocol.PublishDiagnosticsParams{
 		Uri:         protocol.DocumentUri(docURI),
 		Version:     &version,
-		Diagnostics: diagnostics,
+		Diagnostics: convertDiagnostics(violations),
 	}); err != nil {
 		log.Printf("lsp: failed to publish diagnostics for %s: %v", docURI, err)
 	}
@@ -100,67 +100,86 @@ func clearDiagnostics(ctx context.Context, conn *jsonrpc2.Connection, docURI str
 
 // handleDiagnostic handles textDocument/diagnostic (pull diagnostics).
 func (s *Server) handleDiagnostic(params *protocol.DocumentDiagnosticParams) (any, error) {
-	uri := string(params.TextDocument.Uri)
-
-	// Check if the document is open in the editor.
-	if doc := s.documents.Get(uri); doc != nil {
-		resultID := fmt.Sprintf("v%d", doc.Version)
-		if params.PreviousResultId != nil && *params.PreviousResultId == resultID {
-			return &protocol.DocumentDiagnosticResponse{
-				UnchangedDocumentDiagnosticReport: &protocol.RelatedUnchangedDocumentDiagnosticReport{
-					ResultId: resultID,
-				},
-			}, nil
-		}
-
-		violations := s.lintContent(uri, []byte(doc.Content))
-		diagnostics := convertDiagnostics(violations)
-
+	d, ok := s.pullDiagnostics(string(params.TextDocument.Uri), params.PreviousResultId)
+	switch {
+	case !ok:
+		// Return empty full report if the file cannot be read.
 		return &protocol.DocumentDiagnosticResponse{
 			FullDocumentDiagnosticReport: &protocol.RelatedFullDocumentDiagnosticReport{
-				ResultId: &resultID,
-				Items:    diagnostics,
+				Items: []*protocol.Diagnostic{},
 			},
 		}, nil
-	}
-
-	// Document not open — read from disk.
-	filePath := uriToPath(uri)
-	return s.pullDiagnosticsFromDisk(uri, filePath, params.PreviousResultId)
-}
-
-// pullDiagnosticsFromDisk reads content from disk and returns a diagnostic report.
-//
-//nolint:nilerr // gracefully returns empty diagnostics for unreadable files
-func (s *Server) pullDiagnosticsFromDisk(docURI, filePath string, previousResultID *string) (any, error) {
-	content, err := os.ReadFile(filePath)
-	if err != nil {
-		// Return empty full report if file cannot be read.
+	case d.unchanged:
 		return &protocol.DocumentDiagnosticResponse{
-			FullDocumentDiagnosticReport: &protocol.RelatedFullDocumentDiagnosticReport{
-				Items: []*protocol.Diagnostic{},
+			UnchangedDocumentDiagnosticReport: &protocol.RelatedUnchangedDocumentDiagnosticReport{
+				ResultId: d.resultID,
 			},
 		}, nil
-	}
-
-	resultID := contentHash(content)
-	if previousResultID != nil && *previousResultID == resultID {
+	default:
 		return &protocol.DocumentDiagnosticResponse{
-			UnchangedDocumentDiagnosticReport: &protocol.RelatedUnchangedDocumentDiagnosticReport{
-				ResultId: resultID,
+			FullDocumentDiagnosticReport: &protocol.RelatedFullDocumentDiagnosticReport{
+				ResultId: &d.resultID,
+				Items:    d.items,
 			},
 		}, nil
 	}
+}
+
+// pulledDiagnostics are the diagnostics of a document for a pull request.
+type pulledDiagnostics struct {
+	resultID string
+
+	// version is the version of an open document, nil for a file on disk.
+	version *int32
+
+	// unchanged reports that resultID is the previous result ID; items is nil.
+	unchanged bool
+	items     []*protocol.Diagnostic
+}
+
+// pullDiagnostics returns the diagnostics of an open document, or of a file on
+// disk if it is not open. It reports false if the file cannot be read.
+func (s *Server) pullDiagnostics(docURI string, previousResultID *string) (pulledDiagnostics, bool) {
+	if doc := s.documents.Get(docURI); doc != nil {
+		version := doc.Version
+		slowResult, _ := s.slowChecks.get(docURI, contentHash([]byte(doc.Content)))
+		resultID := s.diagnosticResultID(doc, slowResult != nil)
+		if previousResultID != nil && *previousResultID == resultID {
+			return pulledDiagnostics{resultID: resultID, version: &version, unchanged: true}, true
+		}
+
+		violations, slow := s.documentViolations(doc)
+		return pulledDiagnostics{
+			resultID: s.diagnosticResultID(doc, slow),
+			version:  &version,
+			items:    convertDiagnostics(violations),
+		}, true
+	}
 
-	violations := s.lintContent(docURI, content)
-	diagnostics := convertDiagnostics(violations)
+	// Document not open — read from disk.
+	content, err := os.ReadFile(uriToPath(docURI))
+	if err != nil {
+		return pulledDiagnostics{}, false
+	}
+	resultID := fmt.Sprintf("c%d-%s", s.configGeneration.Load(), contentHash(content))
+	if previousResultID != nil && *previousResultID == resultID {
+		return pulledDiagnostics{resultID: resultID, unchanged: true}, true
+	}
+	return pulledDiagnostics{
+		resultID: resultID,
+		items:    convertDiagnostics(s.lintContent(docURI, content)),
+	}, true
+}
 
-	return &protocol.DocumentDiagnosticResponse{
-		FullDocumentDiagnosticReport: &protocol.RelatedFullDocumentDiagnosticReport{
-			ResultId: &resultID,
-			Items:    diagnostics,
-		},
-	}, nil
+// diagnosticResultID identifies the diagnostics of an open document version,
+// which change once the slow checks complete or when settings or config files
+// change.
+func (s *Server) diagnosticResultID(doc *Document, slow bool) string {
+	id := fmt.Sprintf("c%d-v%d", s.configGeneration.Load(), doc.Version)
+	if slow {
+		id += "+slow"
+	}
+	return id
 }
 
 // contentHash returns a truncated SHA-256 hex digest of content (16 hex chars).
@@ -174,10 +193,12 @@ func contentHash(content []byte) string {
 // but designed for future workspace/didChangeConfiguration support.
 func (s *Server) lintInput(docURI string, content []byte) linter.Input {
 	filePath := uriToPath(docURI)
+	cfg := s.resolveConfig(filePath)
 	return linter.Input{
-		FilePath: filePath,
-		Content:  content,
-		Config:   s.resolveConfig(filePath),
+		FilePath:     filePath,
+		Content:      content,
+		Config:       cfg,
+		BuildContext: s.buildContext(filePath, content, cfg),
 	}
 }
 
@@ -195,21 +216,44 @@ func (s *Server) resolveConfig(filePath string) *config.Config {
 	return cfg
 }
 
-// lintContent runs the shared lint pipeline and applies LSP-specific processors.
-func (s *Server) lintContent(docURI string, content []byte) []rules.Violation {
+// lintRun is a lint pass over one document: the raw violations and the slow
+// checks planned for it, kept so that slow check results can be merged into
+// the raw violations before processing.
+type lintRun struct {
+	input  linter.Input
+	result *linter.Result
+}
+
+// lint runs the shared lint pipeline. Returns nil if the document can't be linted.
+func (s *Server) lint(docURI string, content []byte) *lintRun {
 	input := s.lintInput(docURI, content)
 	result, err := linter.LintFile(input)
 	if err != nil {
 		log.Printf("lsp: lint error for %s: %v", input.FilePath, err)
 		return nil
 	}
+	return &lintRun{input: input, result: result}
+}
+
+// process applies the LSP-specific processors to violations of the lint pass.
+func (r *lintRun) process(violations []rules.Violation) []rules.Violation {
 	chain := linter.LSPProcessors()
 	ctx := processor.NewContext(
-		map[string]*config.Config{input.FilePath: result.Config},
-		result.Config,
-		map[string][]byte{input.FilePath: content},
+		map[string]*config.Config{r.input.FilePath: r.result.Config},
+		r.result.Config,
+		map[string][]byte{r.input.FilePath: r.input.Content},
 	)
-	return chain.Process(result.Violations, ctx)
+	return chain.Process(violations, ctx)
+}
+
+// lintContent runs the shared lint pipeline and applies LSP-specific
+// processors. Slow checks are not run.
+func (s *Server) lintContent(docURI string, content []byte) []rules.Violation {
+	lr := s.lint(docURI, content)
+	if lr == nil {
+		return nil
+	}
+	return lr.process(lr.result.Violations)
 }
 
 // convertDiagnostics converts tally violations to LSP diagnostics.
@@ -313,3 +357,13 @@ func uriToPath(docURI string) string {
 	}
 	return filepath.FromSlash(path)
 }
+
+// pathToURI converts a local file path to a file:// URI.
+func pathToURI(path string) string {
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		// Drive-letter paths: C:/path → /C:/path.
+		path = "/" + path
+	}
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
@@ -16,7 +16,11 @@ func (s *Server) handleExecuteCommand(params *protocol.ExecuteCommandParams) (an
 	if params == nil {
 		return nil, nil //nolint:nilnil // LSP: null result is valid
 	}
-	if params.Command != applyAllFixesCommand {
+	switch params.Command {
+	case applyAllFixesCommand:
+	case suppressRuleCommand:
+		return s.executeSuppressRule(params)
+	default:
 		return nil, jsonrpc2.NewError(int64(protocol.ErrorCodeInvalidParams), "unknown command: "+params.Command)
 	}
 
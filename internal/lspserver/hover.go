@@ -0,0 +1,304 @@
+package lspserver
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/distribution/reference"
+	"github.com/moby/buildkit/frontend/dockerfile/instructions"
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+
+	protocol "github.com/tinovyatkin/tally/internal/lsp/protocol"
+
+	"github.com/tinovyatkin/tally/internal/linter"
+	"github.com/tinovyatkin/tally/internal/registry"
+	"github.com/tinovyatkin/tally/internal/rules"
+	"github.com/tinovyatkin/tally/internal/semantic"
+)
+
+// hoverSeparator separates the sections of a hover (e.g. a variable and the
+// diagnostics on it).
+const hoverSeparator = "\n\n---\n\n"
+
+// handleHover handles textDocument/hover. Depending on the position it shows
+// the value of a build variable, the details of a FROM image and the rules of
+// the diagnostics there.
+func (s *Server) handleHover(ctx context.Context, params *protocol.HoverParams) (any, error) {
+	doc := s.documents.Get(string(params.TextDocument.Uri))
+	if doc == nil {
+		return nil, nil //nolint:nilnil // LSP: null result is valid for "no hover"
+	}
+	hover := s.hover(ctx, doc, params.Position)
+	if hover == nil {
+		return nil, nil //nolint:nilnil // LSP: null result is valid for "no hover"
+	}
+	return hover, nil
+}
+
+// hover builds the hover for a position, or returns nil if there is nothing to show.
+func (s *Server) hover(ctx context.Context, doc *Document, pos protocol.Position) *protocol.Hover {
+	var sections []string
+	var hoverRange *protocol.Range
+
+	if a := s.analyzeDocument(doc); a != nil {
+		if ref, ok := a.variableRefAt(pos); ok {
+			sections = append(sections, a.variableHover(pos.Line, ref))
+			hoverRange = lineRange(pos.Line, ref.Start, ref.End)
+		} else if text, r, ok := a.imageHover(ctx, pos); ok {
+			sections = append(sections, text)
+			hoverRange = r
+		}
+	}
+
+	violations, ok := s.lintCache.get(doc.URI, doc.Version)
+	if !ok {
+		violations = s.lintContent(doc.URI, []byte(doc.Content))
+	}
+	seen := make(map[string]bool)
+	for _, v := range violations {
+		if seen[v.RuleCode] || !rangeContains(violationRange(v), pos) {
+			continue
+		}
+		seen[v.RuleCode] = true
+		sections = append(sections, ruleHover(v))
+	}
+
+	if len(sections) == 0 {
+		return nil
+	}
+	return &protocol.Hover{
+		Contents: protocol.MarkupContentOrStringOrMarkedStringWithLanguageOrMarkedStrings{
+			MarkupContent: &protocol.MarkupContent{
+				Kind:  protocol.MarkupKindMarkdown,
+				Value: strings.Join(sections, hoverSeparator),
+			},
+		},
+		Range: hoverRange,
+	}
+}
+
+// rangeContains reports whether a position is inside a range (end exclusive).
+func rangeContains(r protocol.Range, pos protocol.Position) bool {
+	if pos.Line < r.Start.Line || pos.Line == r.Start.Line && pos.Character < r.Start.Character {
+		return false
+	}
+	return pos.Line < r.End.Line || pos.Line == r.End.Line && pos.Character < r.End.Character
+}
+
+// ruleHover describes the rule of a violation: its metadata, the violation
+// detail and a link to the rule documentation.
+func ruleHover(v rules.Violation) string {
+	var b strings.Builder
+	ri, known := linter.LookupRule(v.RuleCode)
+	if known && ri.Name != "" {
+		fmt.Fprintf(&b, "**%s**: %s\n\n", v.RuleCode, ri.Name)
+	} else {
+		fmt.Fprintf(&b, "**%s**\n\n", v.RuleCode)
+	}
+	if known && ri.Description != "" {
+		fmt.Fprintf(&b, "%s\n\n", ri.Description)
+	}
+	if v.Detail != "" {
+		fmt.Fprintf(&b, "%s\n\n", v.Detail)
+	}
+	if known {
+		fmt.Fprintf(&b, "Category: %s · Severity: %s", ri.Category, v.Severity)
+		if ri.Fixable {
+			fmt.Fprintf(&b, " · Auto-fix: %s", ri.FixSafety)
+		}
+		b.WriteString("\n\n")
+	}
+	docURL := v.DocURL
+	if docURL == "" && known {
+		docURL = ri.DocURL
+	}
+	if docURL != "" {
+		fmt.Fprintf(&b, "[Documentation](%s)\n", docURL)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// variableHover describes the value of a variable reference and where it is
+// defined, as resolved by the semantic model for the enclosing scope.
+func (a *documentAnalysis) variableHover(line uint32, ref variableRef) string {
+	def, ok := a.lookupVariable(line, ref.Name)
+	if !ok {
+		if decl := a.argDecl(line, ref.Name); len(decl) > 0 {
+			return fmt.Sprintf("**%s** has no value\n\nDeclared without a default by the ARG on line %d:\n\n%s",
+				ref.Name, decl[0].Start.Line, a.instructionSnippet(decl))
+		}
+		return fmt.Sprintf("**%s** is not set by an ARG or ENV instruction in this scope", ref.Name)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "**%s** = %s\n\n", ref.Name, inlineCode(def.Value))
+	if len(def.Location) == 0 {
+		fmt.Fprintf(&b, "From a %s", def.Source)
+		return b.String()
+	}
+	defLine := def.Location[0].Start.Line
+	if def.Source == semantic.VariableSourceBuildArg {
+		fmt.Fprintf(&b, "From a build arg (`[build] args` or `--build-arg`), declared by the ARG on line %d:\n\n", defLine)
+	} else {
+		fmt.Fprintf(&b, "From the %s on line %d:\n\n", def.Source, defLine)
+	}
+	b.WriteString(a.instructionSnippet(def.Location))
+	return b.String()
+}
+
+// argDecl returns the ARG instruction before a line declaring name in its
+// scope, or nil. Global ARGs are not visible in a stage that doesn't
+// redeclare them.
+func (a *documentAnalysis) argDecl(line uint32, name string) []parser.Range {
+	if a.scopeAt(line) < 0 {
+		for _, arg := range a.ParseResult.MetaArgs {
+			if slices.ContainsFunc(arg.Args, func(kv instructions.KeyValuePairOptional) bool { return kv.Key == name }) {
+				return arg.Location()
+			}
+		}
+		return nil
+	}
+	vars := a.variablesAt(line)
+	if vars == nil {
+		return nil
+	}
+	for _, arg := range vars.Args() {
+		if arg.Name == name && len(arg.Location) > 0 {
+			return arg.Location
+		}
+	}
+	return nil
+}
+
+// instructionSnippet returns the source of an instruction as a Dockerfile
+// code block.
+func (a *documentAnalysis) instructionSnippet(loc []parser.Range) string {
+	start, end := loc[0].Start.Line-1, loc[len(loc)-1].End.Line-1
+	if start < 0 || end >= len(a.Lines) || end < start {
+		return ""
+	}
+	return "```dockerfile\n" + strings.Join(a.Lines[start:end+1], "\n") + "\n```"
+}
+
+// imageHover describes the FROM image under a position: the parsed
+// reference, or the stage it refers to. Registry metadata (digest,
+// platforms, environment) is added when it is in the registry cache; the
+// registry itself is never contacted.
+func (a *documentAnalysis) imageHover(ctx context.Context, pos protocol.Position) (string, *protocol.Range, bool) {
+	stageIdx := a.fromStageAt(pos.Line)
+	if stageIdx < 0 {
+		return "", nil, false
+	}
+	info := a.Semantic.StageInfo(stageIdx)
+	if info == nil || info.BaseImage == nil || info.BaseImage.Raw == "" {
+		return "", nil, false
+	}
+	base := info.BaseImage
+
+	line, start, end, ok := a.fromBaseRange(stageIdx)
+	if !ok || line != pos.Line || int(pos.Character) < start || int(pos.Character) >= end {
+		return "", nil, false
+	}
+	r := lineRange(line, start, end)
+
+	if base.IsStageRef && base.StageIndex >= 0 {
+		text := "**Stage** " + inlineCode(base.Raw)
+		if ref := a.Semantic.StageInfo(base.StageIndex); ref != nil && ref.Stage != nil && len(ref.Stage.Location) > 0 {
+			text += fmt.Sprintf("\n\nBuilt by the FROM on line %d", ref.Stage.Location[0].Start.Line)
+			if ref.BaseImage != nil && ref.BaseImage.Resolved != "" {
+				text += ", based on " + inlineCode(ref.BaseImage.Resolved)
+			}
+		}
+		return text, r, true
+	}
+	if strings.EqualFold(base.Raw, "scratch") {
+		return "**scratch**\n\nAn empty image without a filesystem or environment", r, true
+	}
+	if base.Unresolved {
+		return fmt.Sprintf("**Image** %s\n\nDepends on build args without a value", inlineCode(base.Raw)), r, true
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "**Image** %s", inlineCode(base.Resolved))
+	if base.Resolved != base.Raw {
+		fmt.Fprintf(&b, " (from %s)", inlineCode(base.Raw))
+	}
+	b.WriteString("\n\n")
+
+	named, err := reference.ParseNormalizedNamed(base.Resolved)
+	if err != nil {
+		fmt.Fprintf(&b, "Invalid image reference: %v", err)
+		return b.String(), r, true
+	}
+	fmt.Fprintf(&b, "| | |\n|---|---|\n| Registry | %s |\n| Repository | %s |\n",
+		reference.Domain(named), reference.Path(named))
+	if tagged, ok := named.(reference.Tagged); ok {
+		fmt.Fprintf(&b, "| Tag | %s |\n", tagged.Tag())
+	}
+	if digested, ok := named.(reference.Digested); ok {
+		fmt.Fprintf(&b, "| Digest | `%s` |\n", digested.Digest())
+	}
+	platform, _ := semantic.ExpectedPlatform(info, a.Semantic)
+	if platform != "" {
+		fmt.Fprintf(&b, "| Platform | %s |\n", platform)
+	}
+
+	if cfg, ok := a.cachedImageConfig(ctx, base.Resolved, platform); ok {
+		b.WriteString("\n" + imageConfigMarkdown(cfg))
+	}
+	return strings.TrimRight(b.String(), "\n"), r, true
+}
+
+// cachedImageConfig looks an image up in the registry metadata cache that
+// slow checks fill ([cache] dir, "registry" subdirectory).
+func (a *documentAnalysis) cachedImageConfig(ctx context.Context, ref, platform string) (registry.ImageConfig, bool) {
+	if platform == "" || a.CacheDir == "" {
+		return registry.ImageConfig{}, false
+	}
+	resolver := registry.NewCachingResolver(nil, filepath.Join(a.CacheDir, "registry"), registry.WithOffline(true))
+	cfg, err := resolver.ResolveConfig(ctx, ref, platform)
+	if err != nil {
+		return registry.ImageConfig{}, false
+	}
+	return cfg, true
+}
+
+// imageConfigMarkdown renders cached registry metadata.
+func imageConfigMarkdown(cfg registry.ImageConfig) string {
+	var b strings.Builder
+	b.WriteString("From the registry cache:\n\n")
+	if cfg.Digest != "" {
+		fmt.Fprintf(&b, "- Digest: `%s`\n", cfg.Digest)
+	}
+	if len(cfg.Platforms) > 0 {
+		fmt.Fprintf(&b, "- Platforms: %s\n", strings.Join(cfg.Platforms, ", "))
+	}
+	if len(cfg.Env) > 0 {
+		b.WriteString("- Environment:\n\n```sh\n")
+		for _, key := range slices.Sorted(maps.Keys(cfg.Env)) {
+			fmt.Fprintf(&b, "%s=%s\n", key, cfg.Env[key])
+		}
+		b.WriteString("```\n")
+	}
+	return b.String()
+}
+
+// inlineCode formats s as a Markdown code span, using a longer backtick
+// fence when s contains backticks.
+func inlineCode(s string) string {
+	if s == "" {
+		return "`\"\"`"
+	}
+	fence := "`"
+	for strings.Contains(s, fence) {
+		fence += "`"
+	}
+	if len(fence) > 1 || strings.HasPrefix(s, "`") || strings.HasSuffix(s, "`") {
+		return fence + " " + s + " " + fence
+	}
+	return fence + s + fence
+}
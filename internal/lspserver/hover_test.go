@@ -0,0 +1,235 @@
+package lspserver
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	protocol "github.com/tinovyatkin/tally/internal/lsp/protocol"
+	"github.com/tinovyatkin/tally/internal/registry"
+	"github.com/tinovyatkin/tally/internal/semantic"
+)
+
+// openTestDocument opens a Dockerfile in a temp directory, next to an
+// optional .tally.toml, and returns the server and the document.
+func openTestDocument(t *testing.T, content, tallyToml string) (*Server, *Document) {
+	t.Helper()
+	dir := t.TempDir()
+	if tallyToml != "" {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, ".tally.toml"), []byte(tallyToml), 0o644))
+	}
+	uri := "file://" + filepath.ToSlash(filepath.Join(dir, "Dockerfile"))
+	s := New()
+	s.documents.Open(uri, "dockerfile", 1, content)
+	return s, s.documents.Get(uri)
+}
+
+// hoverText returns the markdown of the hover at a position, or "" if there is none.
+func hoverText(t *testing.T, s *Server, doc *Document, line, char uint32) string {
+	t.Helper()
+	hover := s.hover(t.Context(), doc, protocol.Position{Line: line, Character: char})
+	if hover == nil {
+		return ""
+	}
+	require.NotNil(t, hover.Contents.MarkupContent)
+	assert.Equal(t, protocol.MarkupKindMarkdown, hover.Contents.MarkupContent.Kind)
+	return hover.Contents.MarkupContent.Value
+}
+
+func TestVariableRefs(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		line   string
+		escape rune
+		want   []variableRef
+	}{
+		{line: "RUN echo hello"},
+		{line: "RUN echo $HOME", escape: '\\', want: []variableRef{{Name: "HOME", Start: 9, End: 14}}},
+		{line: "ENV PATH=${GOPATH}/bin:$PATH", escape: '\\', want: []variableRef{
+			{Name: "GOPATH", Start: 9, End: 18},
+			{Name: "PATH", Start: 23, End: 28},
+		}},
+		{line: "RUN echo ${A:-${B}}", escape: '\\', want: []variableRef{{Name: "A", Start: 9, End: 19}}},
+		{line: "RUN echo \\$HOME $1 $", escape: '\\'},
+		{line: "RUN echo `$HOME", escape: '`'},
+		{line: "RUN echo $_x1-", escape: '\\', want: []variableRef{{Name: "_x1", Start: 9, End: 13}}},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, variableRefs(tt.line, tt.escape), tt.line)
+	}
+}
+
+func TestHover_Variable(t *testing.T) {
+	t.Parallel()
+	const content = `ARG VERSION=1.2
+FROM alpine:${VERSION}
+ARG VERSION
+ARG TOKEN
+ENV APP_HOME=/app
+RUN echo $VERSION $APP_HOME $TOKEN $MISSING
+FROM scratch
+ENV X=$VERSION
+`
+	s, doc := openTestDocument(t, content, "[build.args]\nTOKEN = \"secret\"\n")
+
+	tests := []struct {
+		name       string
+		line, char uint32
+		contains   []string
+	}{
+		{
+			name:     "global ARG in FROM",
+			line:     1,
+			char:     14,
+			contains: []string{"**VERSION** = `1.2`", "From the global ARG on line 1:", "```dockerfile\nARG VERSION=1.2\n```"},
+		},
+		{
+			name:     "global ARG redeclared in the stage",
+			line:     5,
+			char:     10,
+			contains: []string{"**VERSION** = `1.2`", "From the global ARG on line 1:"},
+		},
+		{
+			name:     "ENV",
+			line:     5,
+			char:     20,
+			contains: []string{"**APP_HOME** = `/app`", "From the ENV on line 5:", "ENV APP_HOME=/app"},
+		},
+		{
+			name:     "build arg",
+			line:     5,
+			char:     30,
+			contains: []string{"**TOKEN** = `secret`", "From a build arg", "declared by the ARG on line 4"},
+		},
+		{
+			name:     "undefined",
+			line:     5,
+			char:     37,
+			contains: []string{"**MISSING** is not set by an ARG or ENV instruction in this scope"},
+		},
+		{
+			name:     "global ARG not redeclared in the stage",
+			line:     7,
+			char:     8,
+			contains: []string{"**VERSION** is not set"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			text := hoverText(t, s, doc, tt.line, tt.char)
+			for _, want := range tt.contains {
+				assert.Contains(t, text, want)
+			}
+		})
+	}
+
+	hover := s.hover(t.Context(), doc, protocol.Position{Line: 5, Character: 20})
+	require.NotNil(t, hover)
+	assert.Equal(t, lineRange(5, 18, 27), hover.Range)
+}
+
+func TestHover_VariableWithoutValue(t *testing.T) {
+	t.Parallel()
+	s, doc := openTestDocument(t, "FROM alpine\nARG TOKEN\nRUN echo $TOKEN\n", "")
+	text := hoverText(t, s, doc, 2, 11)
+	assert.Contains(t, text, "**TOKEN** has no value")
+	assert.Contains(t, text, "Declared without a default by the ARG on line 2:")
+}
+
+func TestHover_VariableByPosition(t *testing.T) {
+	t.Parallel()
+	const content = "FROM alpine\nENV V=one\nRUN echo $V $B\nENV V=two\nRUN echo $V $B\nARG B=late\n"
+	s, doc := openTestDocument(t, content, "")
+
+	text := hoverText(t, s, doc, 2, 10)
+	assert.Contains(t, text, "**V** = `one`")
+	assert.Contains(t, text, "From the ENV on line 2:")
+	text = hoverText(t, s, doc, 4, 10)
+	assert.Contains(t, text, "**V** = `two`")
+	assert.Contains(t, text, "From the ENV on line 4:")
+	assert.Contains(t, hoverText(t, s, doc, 2, 13), "**B** is not set", "ARG after the reference")
+}
+
+func TestHover_Image(t *testing.T) {
+	t.Parallel()
+	const content = `ARG BASE=golang
+FROM ${BASE}:1.22 AS build
+FROM build
+FROM scratch
+`
+	s, doc := openTestDocument(t, content, "")
+
+	text := hoverText(t, s, doc, 1, 13)
+	assert.Contains(t, text, "**Image** `golang:1.22` (from `${BASE}:1.22`)")
+	assert.Contains(t, text, "| Registry | docker.io |")
+	assert.Contains(t, text, "| Repository | library/golang |")
+	assert.Contains(t, text, "| Tag | 1.22 |")
+	assert.NotContains(t, text, "registry cache")
+
+	text = hoverText(t, s, doc, 2, 6)
+	assert.Contains(t, text, "**Stage** `build`")
+	assert.Contains(t, text, "Built by the FROM on line 2, based on `golang:1.22`")
+
+	assert.Contains(t, hoverText(t, s, doc, 3, 6), "**scratch**")
+	assert.NotContains(t, hoverText(t, s, doc, 1, 1), "**Image**", "FROM keyword")
+}
+
+// stubResolver returns the same image config for every ref.
+type stubResolver struct {
+	cfg registry.ImageConfig
+}
+
+func (r stubResolver) ResolveConfig(context.Context, string, string) (registry.ImageConfig, error) {
+	return r.cfg, nil
+}
+
+func TestHover_ImageFromRegistryCache(t *testing.T) {
+	t.Parallel()
+	cacheDir := t.TempDir()
+	s, doc := openTestDocument(t, "FROM alpine:3.20\n",
+		"[cache]\ndir = \""+filepath.ToSlash(cacheDir)+"\"\n")
+
+	a := s.analyzeDocument(doc)
+	require.NotNil(t, a)
+	platform, _ := semantic.ExpectedPlatform(a.Semantic.StageInfo(0), a.Semantic)
+	require.NotEmpty(t, platform)
+
+	// Fill the cache like a slow-checks run would.
+	cached := registry.NewCachingResolver(stubResolver{cfg: registry.ImageConfig{
+		Env:       map[string]string{"PATH": "/usr/bin", "LANG": "C.UTF-8"},
+		Digest:    "sha256:0123456789abcdef",
+		Platforms: []string{"linux/amd64", "linux/arm64/v8"},
+	}}, filepath.Join(cacheDir, "registry"))
+	_, err := cached.ResolveConfig(t.Context(), "alpine:3.20", platform)
+	require.NoError(t, err)
+
+	text := hoverText(t, s, doc, 0, 7)
+	assert.Contains(t, text, "From the registry cache:")
+	assert.Contains(t, text, "- Digest: `sha256:0123456789abcdef`")
+	assert.Contains(t, text, "- Platforms: linux/amd64, linux/arm64/v8")
+	assert.Contains(t, text, "```sh\nLANG=C.UTF-8\nPATH=/usr/bin\n```")
+}
+
+func TestHover_Rule(t *testing.T) {
+	t.Parallel()
+	s, doc := openTestDocument(t, "FROM alpine:3.20\nMAINTAINER me@example.com\n", "")
+
+	text := hoverText(t, s, doc, 1, 2)
+	assert.Contains(t, text, "**buildkit/MaintainerDeprecated**")
+	assert.Contains(t, text, "Category: ")
+	assert.Contains(t, text, "[Documentation](")
+
+	assert.Empty(t, hoverText(t, s, doc, 0, 0))
+}
+
+func TestInlineCode(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "`abc`", inlineCode("abc"))
+	assert.Equal(t, "`\"\"`", inlineCode(""))
+	assert.Equal(t, "`` a`b ``", inlineCode("a`b"))
+}
@@ -0,0 +1,74 @@
+package lspserver
+
+import (
+	"slices"
+	"strings"
+
+	protocol "github.com/tinovyatkin/tally/internal/lsp/protocol"
+)
+
+// inlayHintInstructions are the instructions whose variable references get
+// their resolved value as an inlay hint.
+var inlayHintInstructions = []string{"from", "copy", "env", "label"}
+
+// handleInlayHint handles textDocument/inlayHint: the resolved value of the
+// variables referenced in FROM, COPY, ENV and LABEL instructions.
+func (s *Server) handleInlayHint(params *protocol.InlayHintParams) (any, error) {
+	doc := s.documents.Get(string(params.TextDocument.Uri))
+	if doc == nil {
+		return nil, nil //nolint:nilnil // LSP: null result is valid for "no inlay hints"
+	}
+	a := s.analyzeDocument(doc)
+	if a == nil {
+		return nil, nil //nolint:nilnil // LSP: null result is valid for "no inlay hints"
+	}
+	return a.inlayHints(params.Range), nil
+}
+
+// inlayHints returns the hints of the lines in a range. A hint follows each
+// plain $NAME or ${NAME} reference to a variable declared before it, with
+// the value the declaration expands to; references with a modifier
+// (${NAME:-default}) do not expand to the variable's value.
+func (a *documentAnalysis) inlayHints(rng protocol.Range) []protocol.InlayHint {
+	hints := []protocol.InlayHint{}
+	if a.ParseResult.AST == nil || a.ParseResult.AST.AST == nil {
+		return hints
+	}
+	escape := a.escapeToken()
+	for _, node := range a.ParseResult.AST.AST.Children {
+		if !slices.Contains(inlayHintInstructions, strings.ToLower(node.Value)) {
+			continue
+		}
+		heredocs := a.heredocBodies(node)
+		for n := max(node.StartLine-1, int(rng.Start.Line)); n < node.EndLine && n <= int(rng.End.Line); n++ {
+			line := clampUint32(n)
+			text := a.line(line)
+			inHeredoc := slices.ContainsFunc(heredocs, func(span [2]int) bool { return n > span[0] && n <= span[1] })
+			if inHeredoc || strings.HasPrefix(strings.TrimSpace(text), "#") {
+				continue
+			}
+			for _, ref := range variableRefs(text, escape) {
+				if !isPlainVariableRef(text[ref.Start:ref.End], ref.Name) {
+					continue
+				}
+				def, ok := a.lookupVariable(line, ref.Name)
+				if !ok {
+					continue
+				}
+				value := def.Expanded
+				hints = append(hints, protocol.InlayHint{
+					Position:    protocol.Position{Line: line, Character: clampUint32(ref.End)},
+					Label:       protocol.StringOrInlayHintLabelParts{String: new("= " + truncateDetail(value))},
+					Tooltip:     &protocol.StringOrMarkupContent{String: new(ref.Name + "=" + value)},
+					PaddingLeft: new(true),
+				})
+			}
+		}
+	}
+	return hints
+}
+
+// isPlainVariableRef reports whether a reference is $NAME or ${NAME}.
+func isPlainVariableRef(ref, name string) bool {
+	return ref == "$"+name || ref == "${"+name+"}"
+}
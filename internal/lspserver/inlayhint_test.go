@@ -0,0 +1,70 @@
+package lspserver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	protocol "github.com/tinovyatkin/tally/internal/lsp/protocol"
+)
+
+const inlayHintDockerfile = `ARG BASE=alpine
+ARG VERSION=3.20
+FROM $BASE:${VERSION} AS build
+ARG VERSION
+ENV APP_VERSION=${VERSION} \
+    HOME_DIR=/home/$USER
+COPY --chmod=644 config-${VERSION}.json ${HOME_DIR:-/root}/
+LABEL version="$APP_VERSION"
+RUN echo $APP_VERSION
+COPY <<EOF /etc/motd
+$APP_VERSION
+EOF
+`
+
+func TestInlayHint(t *testing.T) {
+	t.Parallel()
+	s, doc := openTestDocument(t, inlayHintDockerfile, "")
+	result, err := s.handleInlayHint(&protocol.InlayHintParams{
+		TextDocument: protocol.TextDocumentIdentifier{Uri: protocol.DocumentUri(doc.URI)},
+		Range:        protocol.Range{End: protocol.Position{Line: 20}},
+	})
+	require.NoError(t, err)
+	matchJSON(t, result)
+}
+
+func TestInlayHint_Range(t *testing.T) {
+	t.Parallel()
+	s, doc := openTestDocument(t, inlayHintDockerfile, "")
+	result, err := s.handleInlayHint(&protocol.InlayHintParams{
+		TextDocument: protocol.TextDocumentIdentifier{Uri: protocol.DocumentUri(doc.URI)},
+		Range:        protocol.Range{Start: protocol.Position{Line: 7}, End: protocol.Position{Line: 7}},
+	})
+	require.NoError(t, err)
+	require.IsType(t, []protocol.InlayHint{}, result)
+	hints, _ := result.([]protocol.InlayHint)
+	require.Len(t, hints, 1)
+	require.Equal(t, uint32(7), hints[0].Position.Line)
+	require.Equal(t, "= 3.20", *hints[0].Label.String)
+}
+
+func TestInlayHint_Redefined(t *testing.T) {
+	t.Parallel()
+	const content = `FROM alpine
+ENV PATH=/opt/bin:$PATH
+LABEL path=$PATH
+ENV X=a
+ENV X=${X}b
+LABEL x=$X
+ENV V=one
+LABEL v=$V
+ENV V=two
+`
+	s, doc := openTestDocument(t, content, "")
+	result, err := s.handleInlayHint(&protocol.InlayHintParams{
+		TextDocument: protocol.TextDocumentIdentifier{Uri: protocol.DocumentUri(doc.URI)},
+		Range:        protocol.Range{End: protocol.Position{Line: 9}},
+	})
+	require.NoError(t, err)
+	matchJSON(t, result)
+}
@@ -0,0 +1,24 @@
+package lspserver
+
+import (
+	"os"
+	"testing"
+)
+
+// TestMain points the user cache directory at a temporary directory so that
+// documents without a [cache] dir don't write to the real user cache.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "tally-lspserver-cache")
+	if err != nil {
+		panic(err)
+	}
+	for _, key := range []string{"XDG_CACHE_HOME", "LocalAppData", "HOME"} {
+		if err := os.Setenv(key, dir); err != nil {
+			panic(err)
+		}
+	}
+
+	code := m.Run()
+	_ = os.RemoveAll(dir)
+	os.Exit(code)
+}
@@ -0,0 +1,75 @@
+package lspserver
+
+import (
+	protocol "github.com/tinovyatkin/tally/internal/lsp/protocol"
+)
+
+// handleDefinition handles textDocument/definition for stage references
+// (FROM <stage>, COPY --from, RUN --mount=from) and variable references.
+func (s *Server) handleDefinition(params *protocol.DefinitionParams) (any, error) {
+	a, occ, ok := s.symbolAt(params.TextDocument, params.Position)
+	if !ok {
+		return nil, nil //nolint:nilnil // LSP: null result is valid for "no definition"
+	}
+	def, ok := a.definitionOf(occ.symbol)
+	if !ok {
+		return nil, nil //nolint:nilnil // LSP: null result is valid for "no definition"
+	}
+	return &protocol.Location{Uri: params.TextDocument.Uri, Range: def.Range()}, nil
+}
+
+// handleReferences handles textDocument/references. For a stage it lists
+// every consumer (FROM, COPY --from and RUN --mount=from), for a variable
+// every reference resolving to the same ARG or ENV.
+func (s *Server) handleReferences(params *protocol.ReferenceParams) (any, error) {
+	a, occ, ok := s.symbolAt(params.TextDocument, params.Position)
+	if !ok {
+		return nil, nil //nolint:nilnil // LSP: null result is valid for "no references"
+	}
+	includeDeclaration := params.Context != nil && params.Context.IncludeDeclaration
+	locations := []protocol.Location{}
+	for _, o := range a.occurrencesOf(occ.symbol) {
+		if o.Declaration && !includeDeclaration {
+			continue
+		}
+		locations = append(locations, protocol.Location{Uri: params.TextDocument.Uri, Range: o.Range()})
+	}
+	return locations, nil
+}
+
+// handleDocumentHighlight handles textDocument/documentHighlight: the
+// occurrences of the symbol under the cursor, declarations as writes and
+// references as reads.
+func (s *Server) handleDocumentHighlight(params *protocol.DocumentHighlightParams) (any, error) {
+	a, occ, ok := s.symbolAt(params.TextDocument, params.Position)
+	if !ok {
+		return nil, nil //nolint:nilnil // LSP: null result is valid for "no highlights"
+	}
+	occurrences := a.occurrencesOf(occ.symbol)
+	highlights := make([]protocol.DocumentHighlight, 0, len(occurrences))
+	for _, o := range occurrences {
+		kind := protocol.DocumentHighlightKindRead
+		if o.Declaration {
+			kind = protocol.DocumentHighlightKindWrite
+		}
+		highlights = append(highlights, protocol.DocumentHighlight{Range: o.Range(), Kind: &kind})
+	}
+	return highlights, nil
+}
+
+// symbolAt analyzes an open document and returns the symbol under a position.
+func (s *Server) symbolAt(
+	td protocol.TextDocumentIdentifier,
+	pos protocol.Position,
+) (*documentAnalysis, symbolOccurrence, bool) {
+	doc := s.documents.Get(string(td.Uri))
+	if doc == nil {
+		return nil, symbolOccurrence{}, false
+	}
+	a := s.analyzeDocument(doc)
+	if a == nil {
+		return nil, symbolOccurrence{}, false
+	}
+	occ, ok := a.symbolAt(pos)
+	return a, occ, ok
+}
@@ -0,0 +1,210 @@
+package lspserver
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	protocol "github.com/tinovyatkin/tally/internal/lsp/protocol"
+)
+
+const navigationDockerfile = `ARG GO_VERSION=1.22
+FROM golang:${GO_VERSION} AS builder
+ARG GO_VERSION
+ENV CGO_ENABLED=0
+RUN echo "$GO_VERSION $CGO_ENABLED"
+FROM builder AS test
+RUN --mount=type=cache,target=/root/.cache,from=builder go test ./...
+FROM alpine:3.20
+COPY --from=builder /app /app
+COPY --from=0 /etc/ssl /etc/ssl
+`
+
+// rangeOf returns the range of the first occurrence of substr on a line.
+func rangeOf(t *testing.T, content string, line uint32, substr string) protocol.Range {
+	t.Helper()
+	start := strings.Index(strings.Split(content, "\n")[line], substr)
+	require.GreaterOrEqual(t, start, 0, "%q not found on line %d", substr, line)
+	return *lineRange(line, start, start+len(substr))
+}
+
+// positionOf returns the position of the first occurrence of substr on a line.
+func positionOf(t *testing.T, content string, line uint32, substr string) protocol.Position {
+	t.Helper()
+	return rangeOf(t, content, line, substr).Start
+}
+
+func TestDefinition(t *testing.T) {
+	t.Parallel()
+	const content = navigationDockerfile
+	s, doc := openTestDocument(t, content, "")
+	uri := protocol.DocumentUri(doc.URI)
+
+	tests := []struct {
+		name string
+		pos  protocol.Position
+		want protocol.Range
+	}{
+		{
+			name: "FROM stage",
+			pos:  positionOf(t, content, 5, "builder"),
+			want: rangeOf(t, content, 1, "builder"),
+		},
+		{
+			name: "RUN --mount from",
+			pos:  positionOf(t, content, 6, "builder"),
+			want: rangeOf(t, content, 1, "builder"),
+		},
+		{
+			name: "COPY --from name",
+			pos:  positionOf(t, content, 8, "builder"),
+			want: rangeOf(t, content, 1, "builder"),
+		},
+		{
+			name: "COPY --from index",
+			pos:  positionOf(t, content, 9, "0"),
+			want: rangeOf(t, content, 1, "builder"),
+		},
+		{
+			name: "global ARG in FROM",
+			pos:  positionOf(t, content, 1, "GO_VERSION"),
+			want: rangeOf(t, content, 0, "GO_VERSION"),
+		},
+		{
+			name: "stage ARG inheriting the global default",
+			pos:  positionOf(t, content, 4, "GO_VERSION"),
+			want: rangeOf(t, content, 0, "GO_VERSION"),
+		},
+		{
+			name: "ENV",
+			pos:  positionOf(t, content, 4, "CGO_ENABLED"),
+			want: rangeOf(t, content, 3, "CGO_ENABLED"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			result, err := s.handleDefinition(&protocol.DefinitionParams{
+				TextDocument: protocol.TextDocumentIdentifier{Uri: uri},
+				Position:     tt.pos,
+			})
+			require.NoError(t, err)
+			require.IsType(t, &protocol.Location{}, result)
+			loc, _ := result.(*protocol.Location)
+			assert.Equal(t, uri, loc.Uri)
+			assert.Equal(t, tt.want, loc.Range)
+		})
+	}
+
+	result, err := s.handleDefinition(&protocol.DefinitionParams{
+		TextDocument: protocol.TextDocumentIdentifier{Uri: uri},
+		Position:     protocol.Position{Line: 4, Character: 1},
+	})
+	require.NoError(t, err)
+	assert.Nil(t, result, "instruction keyword")
+}
+
+func TestDefinition_UnnamedStage(t *testing.T) {
+	t.Parallel()
+	const content = "FROM golang:1.22\nRUN go build -o /app .\nFROM alpine:3.20\nCOPY --from=0 /app /app\n"
+	s, doc := openTestDocument(t, content, "")
+
+	result, err := s.handleDefinition(&protocol.DefinitionParams{
+		TextDocument: protocol.TextDocumentIdentifier{Uri: protocol.DocumentUri(doc.URI)},
+		Position:     positionOf(t, content, 3, "0"),
+	})
+	require.NoError(t, err)
+	require.IsType(t, &protocol.Location{}, result)
+	loc, _ := result.(*protocol.Location)
+	assert.Equal(t, rangeOf(t, content, 0, "FROM golang:1.22"), loc.Range)
+}
+
+func TestDefinition_VariableByPosition(t *testing.T) {
+	t.Parallel()
+	const content = "FROM alpine\nENV V=one\nRUN echo $V $B\nENV V=two\nRUN echo $V $B\nARG B=late\n"
+	s, doc := openTestDocument(t, content, "")
+	definition := func(pos protocol.Position) any {
+		t.Helper()
+		result, err := s.handleDefinition(&protocol.DefinitionParams{
+			TextDocument: protocol.TextDocumentIdentifier{Uri: protocol.DocumentUri(doc.URI)},
+			Position:     pos,
+		})
+		require.NoError(t, err)
+		return result
+	}
+
+	for line, want := range map[uint32]uint32{2: 1, 4: 3} {
+		result := definition(positionOf(t, content, line, "V"))
+		require.IsType(t, &protocol.Location{}, result)
+		loc, _ := result.(*protocol.Location)
+		assert.Equal(t, *lineRange(want, 4, 5), loc.Range, "$V on line %d", line)
+	}
+	assert.Nil(t, definition(positionOf(t, content, 2, "B")), "ARG after the reference")
+	assert.Nil(t, definition(positionOf(t, content, 4, "B")), "ARG after the reference")
+}
+
+func TestReferences(t *testing.T) {
+	t.Parallel()
+	const content = navigationDockerfile
+	s, doc := openTestDocument(t, content, "")
+	uri := protocol.DocumentUri(doc.URI)
+
+	references := func(pos protocol.Position, includeDeclaration bool) []protocol.Range {
+		t.Helper()
+		result, err := s.handleReferences(&protocol.ReferenceParams{
+			TextDocument: protocol.TextDocumentIdentifier{Uri: uri},
+			Position:     pos,
+			Context:      &protocol.ReferenceContext{IncludeDeclaration: includeDeclaration},
+		})
+		require.NoError(t, err)
+		require.IsType(t, []protocol.Location{}, result)
+		locations, _ := result.([]protocol.Location)
+		ranges := make([]protocol.Range, 0, len(locations))
+		for _, loc := range locations {
+			assert.Equal(t, uri, loc.Uri)
+			ranges = append(ranges, loc.Range)
+		}
+		return ranges
+	}
+
+	consumers := []protocol.Range{
+		rangeOf(t, content, 5, "builder"),
+		rangeOf(t, content, 6, "builder"),
+		rangeOf(t, content, 8, "builder"),
+		rangeOf(t, content, 9, "0"),
+	}
+	assert.Equal(t, consumers, references(positionOf(t, content, 1, "builder"), false))
+	assert.Equal(t, append([]protocol.Range{rangeOf(t, content, 1, "builder")}, consumers...),
+		references(positionOf(t, content, 8, "builder"), true))
+
+	assert.Equal(t, []protocol.Range{
+		rangeOf(t, content, 0, "GO_VERSION"),
+		rangeOf(t, content, 1, "GO_VERSION"),
+		rangeOf(t, content, 2, "GO_VERSION"),
+		rangeOf(t, content, 4, "GO_VERSION"),
+	}, references(positionOf(t, content, 4, "GO_VERSION"), true))
+
+	assert.Empty(t, references(positionOf(t, content, 5, "test"), false), "stage without consumers")
+}
+
+func TestDocumentHighlight(t *testing.T) {
+	t.Parallel()
+	const content = navigationDockerfile
+	s, doc := openTestDocument(t, content, "")
+
+	result, err := s.handleDocumentHighlight(&protocol.DocumentHighlightParams{
+		TextDocument: protocol.TextDocumentIdentifier{Uri: protocol.DocumentUri(doc.URI)},
+		Position:     positionOf(t, content, 3, "CGO_ENABLED"),
+	})
+	require.NoError(t, err)
+	require.IsType(t, []protocol.DocumentHighlight{}, result)
+	highlights, _ := result.([]protocol.DocumentHighlight)
+
+	write, read := protocol.DocumentHighlightKindWrite, protocol.DocumentHighlightKindRead
+	assert.Equal(t, []protocol.DocumentHighlight{
+		{Range: rangeOf(t, content, 3, "CGO_ENABLED"), Kind: &write},
+		{Range: rangeOf(t, content, 4, "CGO_ENABLED"), Kind: &read},
+	}, highlights)
+}
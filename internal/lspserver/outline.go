@@ -0,0 +1,365 @@
+package lspserver
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+
+	protocol "github.com/tinovyatkin/tally/internal/lsp/protocol"
+)
+
+// maxSymbolDetailLength caps the instruction arguments shown as the detail
+// of a document symbol.
+const maxSymbolDetailLength = 80
+
+// stageOutline is a stage's FROM node and the nodes of its instructions.
+type stageOutline struct {
+	// from is the FROM instruction.
+	from *parser.Node
+
+	// nodes are the instructions following FROM, in order.
+	nodes []*parser.Node
+}
+
+// lastNode returns the last instruction of the stage.
+func (s stageOutline) lastNode() *parser.Node {
+	if len(s.nodes) == 0 {
+		return s.from
+	}
+	return s.nodes[len(s.nodes)-1]
+}
+
+// outline splits the BuildKit AST into the instructions before the first
+// FROM (global ARGs) and the stages. Stage i of the outline is stage i of
+// the semantic model.
+func (a *documentAnalysis) outline() ([]*parser.Node, []stageOutline) {
+	if a.ParseResult.AST == nil || a.ParseResult.AST.AST == nil {
+		return nil, nil
+	}
+	var globals []*parser.Node
+	var stages []stageOutline
+	for _, node := range a.ParseResult.AST.AST.Children {
+		switch {
+		case strings.EqualFold(node.Value, "from"):
+			stages = append(stages, stageOutline{from: node})
+		case len(stages) == 0:
+			globals = append(globals, node)
+		default:
+			stages[len(stages)-1].nodes = append(stages[len(stages)-1].nodes, node)
+		}
+	}
+	return globals, stages
+}
+
+// linesRange returns the range from the first non-blank character of a
+// 1-based start line to the end of a 1-based end line.
+func (a *documentAnalysis) linesRange(startLine, endLine int) protocol.Range {
+	start := clampUint32(startLine - 1)
+	end := clampUint32(endLine - 1)
+	first := a.line(start)
+	return protocol.Range{
+		Start: protocol.Position{Line: start, Character: clampUint32(len(first) - len(strings.TrimLeft(first, " \t")))},
+		End:   protocol.Position{Line: end, Character: clampUint32(len(a.line(end)))},
+	}
+}
+
+// nodeRange returns the range of an instruction, continuation lines and
+// heredocs included.
+func (a *documentAnalysis) nodeRange(node *parser.Node) protocol.Range {
+	return a.linesRange(node.StartLine, node.EndLine)
+}
+
+// handleDocumentSymbol handles textDocument/documentSymbol: global ARGs,
+// then the stages with their instructions as children.
+func (s *Server) handleDocumentSymbol(params *protocol.DocumentSymbolParams) (any, error) {
+	doc := s.documents.Get(string(params.TextDocument.Uri))
+	if doc == nil {
+		return nil, nil //nolint:nilnil // LSP: null result is valid for "no symbols"
+	}
+	a := s.analyzeDocument(doc)
+	if a == nil {
+		return nil, nil //nolint:nilnil // LSP: null result is valid for "no symbols"
+	}
+	return a.documentSymbols(), nil
+}
+
+// documentSymbols returns the document outline.
+func (a *documentAnalysis) documentSymbols() []*protocol.DocumentSymbol {
+	symbols := []*protocol.DocumentSymbol{}
+	for _, arg := range a.ParseResult.MetaArgs {
+		r := a.linesRange(arg.Location()[0].Start.Line, arg.Location()[len(arg.Location())-1].End.Line)
+		for _, kv := range arg.Args {
+			sym := &protocol.DocumentSymbol{
+				Name:           kv.Key,
+				Detail:         new("global ARG"),
+				Kind:           protocol.SymbolKindVariable,
+				Range:          r,
+				SelectionRange: r,
+			}
+			if kv.Value != nil {
+				sym.Detail = new("global ARG = " + truncateDetail(*kv.Value))
+			}
+			if line, start, end, ok := a.declaredNameRange(arg.Location(), kv.Key); ok {
+				sym.SelectionRange = *lineRange(line, start, end)
+			}
+			symbols = append(symbols, sym)
+		}
+	}
+
+	_, stages := a.outline()
+	for i, stage := range stages {
+		symbols = append(symbols, a.stageSymbol(i, stage))
+	}
+	return symbols
+}
+
+// stageSymbol returns the symbol of a stage: its name (or index), base image
+// and instructions.
+func (a *documentAnalysis) stageSymbol(stageIdx int, stage stageOutline) *protocol.DocumentSymbol {
+	r := a.linesRange(stage.from.StartLine, stage.lastNode().EndLine)
+	sym := &protocol.DocumentSymbol{
+		Name:           fmt.Sprintf("stage %d", stageIdx),
+		Kind:           protocol.SymbolKindModule,
+		Range:          r,
+		SelectionRange: a.nodeRange(stage.from),
+	}
+	if info := a.Semantic.StageInfo(stageIdx); info != nil {
+		if info.Stage != nil && info.Stage.Name != "" {
+			sym.Name = info.Stage.Name
+		}
+		if info.BaseImage != nil && info.BaseImage.Raw != "" {
+			sym.Detail = new("FROM " + info.BaseImage.Raw)
+		}
+	}
+	if line, start, end, ok := a.stageNameRange(stageIdx); ok {
+		sym.SelectionRange = *lineRange(line, start, end)
+	} else if line, start, end, ok := a.fromBaseRange(stageIdx); ok {
+		sym.SelectionRange = *lineRange(line, start, end)
+	}
+
+	children := make([]*protocol.DocumentSymbol, 0, len(stage.nodes))
+	for _, node := range stage.nodes {
+		nr := a.nodeRange(node)
+		child := &protocol.DocumentSymbol{
+			Name:           strings.ToUpper(node.Value),
+			Kind:           protocol.SymbolKindFunction,
+			Range:          nr,
+			SelectionRange: nr,
+		}
+		switch strings.ToLower(node.Value) {
+		case "arg", "env":
+			child.Kind = protocol.SymbolKindVariable
+		case "label":
+			child.Kind = protocol.SymbolKindProperty
+		}
+		if detail := a.instructionArgs(node); detail != "" {
+			child.Detail = new(detail)
+		}
+		children = append(children, child)
+	}
+	sym.Children = &children
+	return sym
+}
+
+// instructionArgs returns the arguments on the first line of an
+// instruction, shortened for display.
+func (a *documentAnalysis) instructionArgs(node *parser.Node) string {
+	line := a.line(clampUint32(node.StartLine - 1))
+	end := instructionKeywordEnd(line)
+	if end < 0 {
+		return ""
+	}
+	args := strings.TrimSpace(line[end:])
+	args = strings.TrimSpace(strings.TrimSuffix(args, string(a.escapeToken())))
+	return truncateDetail(args)
+}
+
+// truncateDetail shortens s to maxSymbolDetailLength runes.
+func truncateDetail(s string) string {
+	if utf8.RuneCountInString(s) <= maxSymbolDetailLength {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:maxSymbolDetailLength-1]) + "…"
+}
+
+// handleFoldingRange handles textDocument/foldingRange: stages, multi-line
+// instructions (continuations and heredocs), each heredoc body and blocks
+// of comment lines.
+func (s *Server) handleFoldingRange(params *protocol.FoldingRangeParams) (any, error) {
+	doc := s.documents.Get(string(params.TextDocument.Uri))
+	if doc == nil {
+		return nil, nil //nolint:nilnil // LSP: null result is valid for "no folding ranges"
+	}
+	a := s.analyzeDocument(doc)
+	if a == nil {
+		return nil, nil //nolint:nilnil // LSP: null result is valid for "no folding ranges"
+	}
+	return a.foldingRanges(), nil
+}
+
+// foldingRanges returns the folding ranges of the document.
+func (a *documentAnalysis) foldingRanges() []protocol.FoldingRange {
+	ranges := []protocol.FoldingRange{}
+	seen := make(map[[2]uint32]bool)
+	add := func(start, end int, kind protocol.FoldingRangeKind) {
+		// start and end are 0-based lines.
+		if end <= start {
+			return
+		}
+		key := [2]uint32{clampUint32(start), clampUint32(end)}
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		r := protocol.FoldingRange{StartLine: key[0], EndLine: key[1]}
+		if kind != "" {
+			r.Kind = &kind
+		}
+		ranges = append(ranges, r)
+	}
+
+	globals, stages := a.outline()
+	addNode := func(node *parser.Node) {
+		add(node.StartLine-1, node.EndLine-1, "")
+		for _, h := range a.heredocBodies(node) {
+			add(h[0], h[1], "")
+		}
+	}
+	for _, node := range globals {
+		addNode(node)
+	}
+	for _, stage := range stages {
+		add(stage.from.StartLine-1, stage.lastNode().EndLine-1, protocol.FoldingRangeKindRegion)
+		addNode(stage.from)
+		for _, node := range stage.nodes {
+			addNode(node)
+		}
+	}
+
+	// Blocks of comment lines.
+	start := -1
+	for i := 0; i <= len(a.Lines); i++ {
+		if i < len(a.Lines) && strings.HasPrefix(strings.TrimSpace(a.Lines[i]), "#") {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			add(start, i-1, protocol.FoldingRangeKindComment)
+			start = -1
+		}
+	}
+	return ranges
+}
+
+// heredocBodies returns the 0-based line spans of the heredocs of an
+// instruction, from the line opening each heredoc (or the line after the
+// previous one) to its terminator.
+func (a *documentAnalysis) heredocBodies(node *parser.Node) [][2]int {
+	var spans [][2]int
+	next := node.StartLine - 1
+	for _, h := range node.Heredocs {
+		opener := next
+		for n := next; n < node.EndLine; n++ {
+			if strings.Contains(a.line(clampUint32(n)), "<<") && strings.Contains(a.line(clampUint32(n)), h.Name) {
+				opener = n
+				break
+			}
+		}
+		for n := max(opener+1, next); n < node.EndLine; n++ {
+			if strings.TrimLeft(a.line(clampUint32(n)), "\t") == h.Name {
+				spans = append(spans, [2]int{max(opener, next), n})
+				next = n + 1
+				break
+			}
+		}
+	}
+	return spans
+}
+
+// handleSelectionRange handles textDocument/selectionRange. From the inside
+// out, a selection grows from the name or word under the cursor to the
+// line, the instruction, the stage and the whole document.
+func (s *Server) handleSelectionRange(params *protocol.SelectionRangeParams) (any, error) {
+	doc := s.documents.Get(string(params.TextDocument.Uri))
+	if doc == nil {
+		return nil, nil //nolint:nilnil // LSP: null result is valid for "no selection ranges"
+	}
+	a := s.analyzeDocument(doc)
+	if a == nil {
+		return nil, nil //nolint:nilnil // LSP: null result is valid for "no selection ranges"
+	}
+	result := make([]*protocol.SelectionRange, 0, len(params.Positions))
+	for _, pos := range params.Positions {
+		result = append(result, a.selectionRange(pos))
+	}
+	return result, nil
+}
+
+// selectionRange returns the selection ranges around a position, innermost first.
+func (a *documentAnalysis) selectionRange(pos protocol.Position) *protocol.SelectionRange {
+	// Outermost first; ranges that don't grow the selection are skipped.
+	lastLine := clampUint32(max(len(a.Lines)-1, 0))
+	chain := []protocol.Range{{
+		End: protocol.Position{Line: lastLine, Character: clampUint32(len(a.line(lastLine)))},
+	}}
+
+	nodes, stages := a.outline()
+	for _, stage := range stages {
+		if int(pos.Line) >= stage.from.StartLine-1 && int(pos.Line) <= stage.lastNode().EndLine-1 {
+			chain = append(chain, a.linesRange(stage.from.StartLine, stage.lastNode().EndLine))
+			nodes = append([]*parser.Node{stage.from}, stage.nodes...)
+			break
+		}
+	}
+	for _, node := range nodes {
+		if int(pos.Line) >= node.StartLine-1 && int(pos.Line) <= node.EndLine-1 {
+			chain = append(chain, a.nodeRange(node))
+			break
+		}
+	}
+	chain = append(chain, a.linesRange(int(pos.Line)+1, int(pos.Line)+1))
+	if o, ok := a.symbolAt(pos); ok {
+		chain = append(chain, o.Range())
+	} else if start, end, ok := wordAt(a.line(pos.Line), int(pos.Character)); ok {
+		chain = append(chain, *lineRange(pos.Line, start, end))
+	}
+
+	var sel *protocol.SelectionRange
+	for _, r := range chain {
+		if sel != nil && (r == sel.Range || !rangeContainsRange(sel.Range, r)) {
+			continue
+		}
+		sel = &protocol.SelectionRange{Range: r, Parent: sel}
+	}
+	return sel
+}
+
+// wordAt returns the byte offsets of the whitespace-delimited word at a
+// byte offset of a line.
+func wordAt(line string, offset int) (int, int, bool) {
+	if offset < 0 || offset >= len(line) || isSpace(line[offset]) {
+		return 0, 0, false
+	}
+	start, end := offset, offset
+	for start > 0 && !isSpace(line[start-1]) {
+		start--
+	}
+	for end < len(line) && !isSpace(line[end]) {
+		end++
+	}
+	return start, end, true
+}
+
+// rangeContainsRange reports whether inner is within outer.
+func rangeContainsRange(outer, inner protocol.Range) bool {
+	return !positionBefore(inner.Start, outer.Start) && !positionBefore(outer.End, inner.End)
+}
+
+func positionBefore(a, b protocol.Position) bool {
+	return a.Line < b.Line || a.Line == b.Line && a.Character < b.Character
+}
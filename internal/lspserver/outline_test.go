@@ -0,0 +1,113 @@
+package lspserver
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	protocol "github.com/tinovyatkin/tally/internal/lsp/protocol"
+)
+
+const outlineDockerfile = `# syntax=docker/dockerfile:1
+ARG GO_VERSION=1.22
+# Build the binary
+# with cgo disabled
+FROM golang:${GO_VERSION} AS builder
+WORKDIR /src
+RUN apt-get update && \
+    apt-get install -y git && \
+    rm -rf /var/lib/apt/lists/*
+RUN <<ONE && bash <<TWO
+echo one
+ONE
+echo two
+TWO
+
+FROM alpine:3.20
+ENV APP_HOME=/app
+COPY --from=builder /app /app
+`
+
+// matchJSON snapshots v as indented JSON with sorted keys.
+func matchJSON(t *testing.T, v any) {
+	t.Helper()
+	snaps.WithConfig(
+		snaps.JSON(snaps.JSONConfig{
+			SortKeys: true,
+			Indent:   " ",
+		}),
+	).MatchStandaloneJSON(t, v)
+}
+
+func TestDocumentSymbol(t *testing.T) {
+	t.Parallel()
+	s, doc := openTestDocument(t, outlineDockerfile, "")
+	result, err := s.handleDocumentSymbol(&protocol.DocumentSymbolParams{
+		TextDocument: protocol.TextDocumentIdentifier{Uri: protocol.DocumentUri(doc.URI)},
+	})
+	require.NoError(t, err)
+	matchJSON(t, result)
+}
+
+func TestFoldingRange(t *testing.T) {
+	t.Parallel()
+	s, doc := openTestDocument(t, outlineDockerfile, "")
+	result, err := s.handleFoldingRange(&protocol.FoldingRangeParams{
+		TextDocument: protocol.TextDocumentIdentifier{Uri: protocol.DocumentUri(doc.URI)},
+	})
+	require.NoError(t, err)
+	matchJSON(t, result)
+}
+
+func TestSelectionRange(t *testing.T) {
+	t.Parallel()
+	const content = outlineDockerfile
+	s, doc := openTestDocument(t, content, "")
+	result, err := s.handleSelectionRange(&protocol.SelectionRangeParams{
+		TextDocument: protocol.TextDocumentIdentifier{Uri: protocol.DocumentUri(doc.URI)},
+		Positions: []protocol.Position{
+			positionOf(t, content, 7, "install"),
+			positionOf(t, content, 17, "builder"),
+		},
+	})
+	require.NoError(t, err)
+	require.IsType(t, []*protocol.SelectionRange{}, result)
+	selections, _ := result.([]*protocol.SelectionRange)
+	require.Len(t, selections, 2)
+
+	ranges := func(sel *protocol.SelectionRange) []protocol.Range {
+		var rs []protocol.Range
+		for ; sel != nil; sel = sel.Parent {
+			rs = append(rs, sel.Range)
+		}
+		return rs
+	}
+	document := protocol.Range{End: protocol.Position{Line: 18}}
+
+	// Word, line, multi-line RUN, stage, document.
+	assert.Equal(t, []protocol.Range{
+		rangeOf(t, content, 7, "install"),
+		rangeOf(t, content, 7, "apt-get install -y git && \\"),
+		{Start: protocol.Position{Line: 6}, End: protocol.Position{Line: 8, Character: 31}},
+		{Start: protocol.Position{Line: 4}, End: protocol.Position{Line: 13, Character: 3}},
+		document,
+	}, ranges(selections[0]))
+
+	// Stage name, then the COPY line (also the whole instruction), stage, document.
+	assert.Equal(t, []protocol.Range{
+		rangeOf(t, content, 17, "builder"),
+		rangeOf(t, content, 17, "COPY --from=builder /app /app"),
+		{Start: protocol.Position{Line: 15}, End: protocol.Position{Line: 17, Character: 29}},
+		document,
+	}, ranges(selections[1]))
+}
+
+func TestTruncateDetail(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "short", truncateDetail("short"))
+	long := truncateDetail(string(make([]byte, 100)))
+	assert.Equal(t, maxSymbolDetailLength, len([]rune(long)))
+	assert.Equal(t, '…', []rune(long)[maxSymbolDetailLength-1])
+}
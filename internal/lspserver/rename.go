@@ -0,0 +1,114 @@
+package lspserver
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/jsonrpc2"
+
+	protocol "github.com/tinovyatkin/tally/internal/lsp/protocol"
+
+	"github.com/tinovyatkin/tally/internal/rules/buildkit"
+)
+
+var (
+	// validStageName matches BuildKit's stage name syntax (checked on the
+	// lowercased name).
+	validStageName = regexp.MustCompile(`^[a-z][a-z0-9-_.]*$`)
+
+	// validVariableName matches the names ARG and ENV can declare and
+	// $NAME can reference.
+	validVariableName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+)
+
+// handlePrepareRename handles textDocument/prepareRename: it accepts stage
+// names and ARG/ENV variables, and returns the range and current name.
+func (s *Server) handlePrepareRename(params *protocol.PrepareRenameParams) (any, error) {
+	a, occ, ok := s.symbolAt(params.TextDocument, params.Position)
+	if !ok {
+		return nil, nil //nolint:nilnil // LSP: null result means "nothing to rename here"
+	}
+	name, err := a.symbolName(occ.symbol)
+	if err != nil {
+		return nil, renameError(err)
+	}
+	return &protocol.PrepareRenamePlaceholder{Range: occ.Range(), Placeholder: name}, nil
+}
+
+// handleRename handles textDocument/rename. Every occurrence of the stage or
+// variable is renamed in a single WorkspaceEdit. Stage references by index
+// (COPY --from=0) are left alone, since they don't depend on the name.
+func (s *Server) handleRename(params *protocol.RenameParams) (any, error) {
+	a, occ, ok := s.symbolAt(params.TextDocument, params.Position)
+	if !ok {
+		return nil, nil //nolint:nilnil // LSP: null result means "nothing to rename here"
+	}
+	if _, err := a.symbolName(occ.symbol); err != nil {
+		return nil, renameError(err)
+	}
+	if err := a.validateRename(occ.symbol, params.NewName); err != nil {
+		return nil, renameError(err)
+	}
+
+	var edits []*protocol.TextEdit
+	for _, o := range a.occurrencesOf(occ.symbol) {
+		if _, err := strconv.Atoi(a.line(o.Line)[o.Start:o.End]); err == nil && o.symbol.isStage() {
+			continue
+		}
+		edits = append(edits, &protocol.TextEdit{Range: o.Range(), NewText: params.NewName})
+	}
+	return &protocol.WorkspaceEdit{
+		Changes: &map[protocol.DocumentUri][]*protocol.TextEdit{params.TextDocument.Uri: edits},
+	}, nil
+}
+
+// renameError wraps a refused rename as an LSP RequestFailed error, which
+// clients show to the user.
+func renameError(err error) error {
+	return jsonrpc2.NewError(int64(protocol.ErrorCodeRequestFailed), err.Error())
+}
+
+// symbolName returns the current name of a symbol, or an error if it can't
+// be renamed.
+func (a *documentAnalysis) symbolName(sym symbol) (string, error) {
+	if !sym.isStage() {
+		return sym.Name, nil
+	}
+	if _, _, _, ok := a.stageNameRange(sym.Stage); !ok {
+		return "", fmt.Errorf("stage %d has no name (FROM ... AS <name>) to rename", sym.Stage)
+	}
+	return a.Semantic.Stage(sym.Stage).Name, nil
+}
+
+// validateRename checks that newName is a valid name for the symbol and
+// doesn't collide with another stage or variable.
+func (a *documentAnalysis) validateRename(sym symbol, newName string) error {
+	if sym.isStage() {
+		normalized := strings.ToLower(newName)
+		if !validStageName.MatchString(normalized) {
+			return fmt.Errorf("%q is not a valid stage name", newName)
+		}
+		if buildkit.IsReservedStageName(normalized) {
+			return fmt.Errorf("%q is a reserved stage name", newName)
+		}
+		if idx, ok := a.Semantic.StageIndexByName(normalized); ok && idx != sym.Stage {
+			return fmt.Errorf("stage %q already exists", newName)
+		}
+		return nil
+	}
+
+	if !validVariableName.MatchString(newName) {
+		return fmt.Errorf("%q is not a valid variable name", newName)
+	}
+	if newName == sym.Name {
+		return nil
+	}
+	for _, o := range a.occurrencesOf(sym) {
+		if decl := a.variableDecl(o.Line, newName); len(decl) > 0 {
+			return fmt.Errorf("variable %q is already declared on line %d", newName, decl[0].Start.Line)
+		}
+	}
+	return nil
+}
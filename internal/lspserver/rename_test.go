@@ -0,0 +1,166 @@
+package lspserver
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	protocol "github.com/tinovyatkin/tally/internal/lsp/protocol"
+)
+
+// rename renames the symbol at a position and returns the edited document.
+func rename(t *testing.T, content string, pos protocol.Position, newName string) (string, error) {
+	t.Helper()
+	s, doc := openTestDocument(t, content, "")
+	uri := protocol.DocumentUri(doc.URI)
+	result, err := s.handleRename(&protocol.RenameParams{
+		TextDocument: protocol.TextDocumentIdentifier{Uri: uri},
+		Position:     pos,
+		NewName:      newName,
+	})
+	if err != nil {
+		return "", err
+	}
+	require.IsType(t, &protocol.WorkspaceEdit{}, result)
+	edit, _ := result.(*protocol.WorkspaceEdit)
+	require.NotNil(t, edit.Changes)
+	require.Len(t, *edit.Changes, 1)
+
+	// Apply the single-line edits from the end of the document.
+	edits := slices.Clone((*edit.Changes)[uri])
+	slices.SortFunc(edits, func(x, y *protocol.TextEdit) int {
+		if x.Range.Start.Line != y.Range.Start.Line {
+			return int(y.Range.Start.Line) - int(x.Range.Start.Line)
+		}
+		return int(y.Range.Start.Character) - int(x.Range.Start.Character)
+	})
+	lines := strings.Split(content, "\n")
+	for _, e := range edits {
+		line := lines[e.Range.Start.Line]
+		lines[e.Range.Start.Line] = line[:e.Range.Start.Character] + e.NewText + line[e.Range.End.Character:]
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+func TestRename_Stage(t *testing.T) {
+	t.Parallel()
+	const content = navigationDockerfile
+	got, err := rename(t, content, positionOf(t, content, 8, "builder"), "build")
+	require.NoError(t, err)
+	assert.Equal(t, `ARG GO_VERSION=1.22
+FROM golang:${GO_VERSION} AS build
+ARG GO_VERSION
+ENV CGO_ENABLED=0
+RUN echo "$GO_VERSION $CGO_ENABLED"
+FROM build AS test
+RUN --mount=type=cache,target=/root/.cache,from=build go test ./...
+FROM alpine:3.20
+COPY --from=build /app /app
+COPY --from=0 /etc/ssl /etc/ssl
+`, got)
+}
+
+func TestRename_StageCaseInsensitive(t *testing.T) {
+	t.Parallel()
+	const content = "FROM golang:1.22 AS Builder\nFROM alpine:3.20\nCOPY --from=BUILDER /app /app\n"
+	got, err := rename(t, content, positionOf(t, content, 0, "Builder"), "compile")
+	require.NoError(t, err)
+	assert.Equal(t, "FROM golang:1.22 AS compile\nFROM alpine:3.20\nCOPY --from=compile /app /app\n", got)
+}
+
+func TestRename_Variable(t *testing.T) {
+	t.Parallel()
+	const content = navigationDockerfile
+	got, err := rename(t, content, positionOf(t, content, 1, "GO_VERSION"), "GOLANG_VERSION")
+	require.NoError(t, err)
+	assert.Equal(t, `ARG GOLANG_VERSION=1.22
+FROM golang:${GOLANG_VERSION} AS builder
+ARG GOLANG_VERSION
+ENV CGO_ENABLED=0
+RUN echo "$GOLANG_VERSION $CGO_ENABLED"
+FROM builder AS test
+RUN --mount=type=cache,target=/root/.cache,from=builder go test ./...
+FROM alpine:3.20
+COPY --from=builder /app /app
+COPY --from=0 /etc/ssl /etc/ssl
+`, got)
+}
+
+func TestRename_Refused(t *testing.T) {
+	t.Parallel()
+	const content = navigationDockerfile
+	tests := []struct {
+		name    string
+		pos     protocol.Position
+		newName string
+		want    string
+	}{
+		{
+			name:    "reserved stage name",
+			pos:     positionOf(t, content, 1, "builder"),
+			newName: "Scratch",
+			want:    `"Scratch" is a reserved stage name`,
+		},
+		{
+			name:    "existing stage",
+			pos:     positionOf(t, content, 1, "builder"),
+			newName: "TEST",
+			want:    `stage "TEST" already exists`,
+		},
+		{
+			name:    "invalid stage name",
+			pos:     positionOf(t, content, 1, "builder"),
+			newName: "1st",
+			want:    `"1st" is not a valid stage name`,
+		},
+		{
+			name:    "invalid variable name",
+			pos:     positionOf(t, content, 3, "CGO_ENABLED"),
+			newName: "CGO-ENABLED",
+			want:    `"CGO-ENABLED" is not a valid variable name`,
+		},
+		{
+			name:    "existing variable",
+			pos:     positionOf(t, content, 3, "CGO_ENABLED"),
+			newName: "GO_VERSION",
+			want:    `variable "GO_VERSION" is already declared on line 1`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := rename(t, content, tt.pos, tt.newName)
+			require.Error(t, err)
+			assert.EqualError(t, err, tt.want)
+		})
+	}
+}
+
+func TestPrepareRename(t *testing.T) {
+	t.Parallel()
+	const content = "FROM golang:1.22\nFROM golang:1.22 AS builder\nFROM alpine:3.20\nCOPY --from=0 /a /a\nCOPY --from=1 /b /b\n"
+	s, doc := openTestDocument(t, content, "")
+	prepare := func(pos protocol.Position) (any, error) {
+		return s.handlePrepareRename(&protocol.PrepareRenameParams{
+			TextDocument: protocol.TextDocumentIdentifier{Uri: protocol.DocumentUri(doc.URI)},
+			Position:     pos,
+		})
+	}
+
+	result, err := prepare(positionOf(t, content, 4, "1"))
+	require.NoError(t, err)
+	assert.Equal(t, &protocol.PrepareRenamePlaceholder{
+		Range:       rangeOf(t, content, 4, "1"),
+		Placeholder: "builder",
+	}, result)
+
+	_, err = prepare(positionOf(t, content, 3, "0"))
+	require.Error(t, err, "unnamed stage")
+
+	result, err = prepare(protocol.Position{Line: 2, Character: 1})
+	require.NoError(t, err)
+	assert.Nil(t, result)
+}
@@ -1,8 +1,14 @@
 // Package lspserver implements a Language Server Protocol server for tally.
 //
 // The server provides Dockerfile linting diagnostics, quick-fix code actions,
-// and document formatting through the LSP protocol. It reuses the same lint
-// pipeline as the CLI (dockerfile.Parse, semantic model, rules, processors).
+// document formatting, hover, navigation (definition, references,
+// highlights), rename, completion, document structure (symbols, folding
+// and selection ranges), inlay hints, code lenses and workspace diagnostics
+// through the LSP protocol.
+// It reuses the same lint pipeline as the CLI (dockerfile.Parse, semantic
+// model, rules, processors). Slow (registry-backed) checks run in the
+// background and their diagnostics follow the fast ones. Changes to config
+// and ignore files are watched to lint affected documents again.
 //
 // Transport: stdio only (--stdio).
 // Protocol: LSP 3.17 types via internal/lsp/protocol, JSON-RPC via golang.org/x/exp/jsonrpc2.
@@ -16,11 +22,15 @@ import (
 	"os"
 	"strconv"
 	"sync"
+	"sync/atomic"
 
 	jsonv2 "encoding/json/v2"
 	"golang.org/x/exp/jsonrpc2"
 
 	protocol "github.com/tinovyatkin/tally/internal/lsp/protocol"
+
+	"github.com/tinovyatkin/tally/internal/config"
+	"github.com/tinovyatkin/tally/internal/registry"
 	"github.com/tinovyatkin/tally/internal/version"
 )
 
@@ -36,8 +46,13 @@ type Server struct {
 	conn   *jsonrpc2.Connection
 	exitCh chan struct{} // closed when the "exit" notification is received
 
-	documents *DocumentStore
-	lintCache *lintResultCache
+	documents  *DocumentStore
+	lintCache  *lintResultCache
+	slowChecks *slowCheckStore
+
+	// newImageResolver creates the registry resolver for slow checks, caching
+	// metadata under cacheDir unless it is empty.
+	newImageResolver func(cfg *config.Config, cacheDir string) registry.ImageResolver
 
 	settingsMu sync.RWMutex
 	settings   clientSettings
@@ -46,15 +61,28 @@ type Server struct {
 	pushDiagnostics            bool
 	supportsDiagnosticRefresh  bool
 	supportsDiagnosticPullMode bool
+	supportsWorkDoneProgress   bool
+
+	workspaceMu          sync.RWMutex
+	workspaceFolders     []string
+	supportsWatchedFiles bool
+	supportsApplyEdit    bool
+	supportsCreateFiles  bool
+
+	// configGeneration counts the changes of settings and config files,
+	// which invalidate diagnostics of unchanged documents.
+	configGeneration atomic.Uint64
 }
 
 // New creates a new LSP server.
 func New() *Server {
 	return &Server{
-		exitCh:    make(chan struct{}),
-		documents: NewDocumentStore(),
-		lintCache: newLintResultCache(),
-		settings:  defaultClientSettings(),
+		exitCh:           make(chan struct{}),
+		documents:        NewDocumentStore(),
+		lintCache:        newLintResultCache(),
+		slowChecks:       newSlowCheckStore(),
+		newImageResolver: newImageResolver,
+		settings:         defaultClientSettings(),
 		// Default to push diagnostics (publishDiagnostics). If the client supports
 		// the LSP 3.17 pull model, we switch to pull to avoid duplicate diagnostics.
 		pushDiagnostics: true,
@@ -105,9 +133,13 @@ func (s *Server) handle(ctx context.Context, req *jsonrpc2.Request) (any, error)
 	// Lifecycle
 	case "initialize":
 		return unmarshalAndCall(req, s.handleInitialize)
-	case "initialized", "$/setTrace":
+	case "initialized":
+		s.handleInitialized(ctx)
+		return nil, nil //nolint:nilnil // LSP: notifications have no result
+	case "$/setTrace":
 		return nil, nil //nolint:nilnil // LSP: notifications have no result
 	case "shutdown":
+		s.slowChecks.shutdown(shutdownTimeout)
 		return jsonNull, nil
 	case "exit":
 		select {
@@ -142,12 +174,44 @@ func (s *Server) handle(ctx context.Context, req *jsonrpc2.Request) (any, error)
 		return unmarshalAndCall(req, s.handleDiagnostic)
 	case string(protocol.MethodTextDocumentFormatting):
 		return unmarshalAndCall(req, s.handleFormatting)
+	case string(protocol.MethodTextDocumentHover):
+		return unmarshalAndCall(req, func(p *protocol.HoverParams) (any, error) {
+			return s.handleHover(ctx, p)
+		})
+	case string(protocol.MethodTextDocumentDefinition):
+		return unmarshalAndCall(req, s.handleDefinition)
+	case string(protocol.MethodTextDocumentReferences):
+		return unmarshalAndCall(req, s.handleReferences)
+	case string(protocol.MethodTextDocumentDocumentHighlight):
+		return unmarshalAndCall(req, s.handleDocumentHighlight)
+	case string(protocol.MethodTextDocumentPrepareRename):
+		return unmarshalAndCall(req, s.handlePrepareRename)
+	case string(protocol.MethodTextDocumentRename):
+		return unmarshalAndCall(req, s.handleRename)
+	case string(protocol.MethodTextDocumentDocumentSymbol):
+		return unmarshalAndCall(req, s.handleDocumentSymbol)
+	case string(protocol.MethodTextDocumentFoldingRange):
+		return unmarshalAndCall(req, s.handleFoldingRange)
+	case string(protocol.MethodTextDocumentSelectionRange):
+		return unmarshalAndCall(req, s.handleSelectionRange)
+	case string(protocol.MethodTextDocumentCompletion):
+		return unmarshalAndCall(req, s.handleCompletion)
+	case string(protocol.MethodTextDocumentInlayHint):
+		return unmarshalAndCall(req, s.handleInlayHint)
+	case string(protocol.MethodTextDocumentCodeLens):
+		return unmarshalAndCall(req, s.handleCodeLens)
 
 	// Workspace
 	case "workspace/didChangeConfiguration":
 		return nil, unmarshalAndNotify(req, func(p *protocol.DidChangeConfigurationParams) {
 			s.handleDidChangeConfiguration(ctx, p)
 		})
+	case string(protocol.MethodWorkspaceDidChangeWatchedFiles):
+		return nil, unmarshalAndNotify(req, func(p *protocol.DidChangeWatchedFilesParams) {
+			s.handleDidChangeWatchedFiles(ctx, p)
+		})
+	case string(protocol.MethodWorkspaceDiagnostic):
+		return unmarshalAndCall(req, s.handleWorkspaceDiagnostic)
 	case string(protocol.MethodWorkspaceExecuteCommand):
 		return unmarshalAndCall(req, s.handleExecuteCommand)
 
@@ -209,6 +273,7 @@ func (s *Server) handleInitialize(params *protocol.InitializeParams) (any, error
 	log.Printf("lsp: initialize from %s", clientInfoString(params))
 
 	s.configureDiagnosticsMode(params)
+	s.configureWorkspace(params)
 
 	ver := version.RawVersion()
 
@@ -234,14 +299,47 @@ func (s *Server) handleInitialize(params *protocol.InitializeParams) (any, error
 			DocumentFormattingProvider: &protocol.BooleanOrDocumentFormattingOptions{
 				Boolean: new(true),
 			},
+			HoverProvider: &protocol.BooleanOrHoverOptions{
+				Boolean: new(true),
+			},
+			DefinitionProvider: &protocol.BooleanOrDefinitionOptions{
+				Boolean: new(true),
+			},
+			ReferencesProvider: &protocol.BooleanOrReferenceOptions{
+				Boolean: new(true),
+			},
+			DocumentHighlightProvider: &protocol.BooleanOrDocumentHighlightOptions{
+				Boolean: new(true),
+			},
+			RenameProvider: &protocol.BooleanOrRenameOptions{
+				RenameOptions: &protocol.RenameOptions{PrepareProvider: new(true)},
+			},
+			DocumentSymbolProvider: &protocol.BooleanOrDocumentSymbolOptions{
+				Boolean: new(true),
+			},
+			FoldingRangeProvider: &protocol.BooleanOrFoldingRangeOptionsOrFoldingRangeRegistrationOptions{
+				Boolean: new(true),
+			},
+			SelectionRangeProvider: &protocol.BooleanOrSelectionRangeOptionsOrSelectionRangeRegistrationOptions{
+				Boolean: new(true),
+			},
+			CompletionProvider: &protocol.CompletionOptions{
+				TriggerCharacters: &completionTriggerCharacters,
+			},
+			InlayHintProvider: &protocol.BooleanOrInlayHintOptionsOrInlayHintRegistrationOptions{
+				Boolean: new(true),
+			},
+			CodeLensProvider: &protocol.CodeLensOptions{},
 			DiagnosticProvider: &protocol.DiagnosticOptionsOrRegistrationOptions{
 				Options: &protocol.DiagnosticOptions{
-					Identifier: new("tally"),
+					Identifier:           new("tally"),
+					WorkspaceDiagnostics: true,
 				},
 			},
 			ExecuteCommandProvider: &protocol.ExecuteCommandOptions{
 				Commands: []string{
 					applyAllFixesCommand,
+					suppressRuleCommand,
 				},
 			},
 		},
@@ -269,6 +367,9 @@ func (s *Server) handleDidOpen(ctx context.Context, params *protocol.DidOpenText
 func (s *Server) handleDidChange(ctx context.Context, params *protocol.DidChangeTextDocumentParams) {
 	uri := string(params.TextDocument.Uri)
 
+	// Slow checks of the previous content are obsolete.
+	s.slowChecks.cancel(uri)
+
 	// With full sync, there's exactly one content change containing the full text.
 	for _, change := range params.ContentChanges {
 		switch {
@@ -306,6 +407,7 @@ func (s *Server) handleDidClose(ctx context.Context, params *protocol.DidCloseTe
 	}
 	s.documents.Close(uri)
 	s.lintCache.delete(uri)
+	s.slowChecks.cancel(uri)
 	if s.pushDiagnosticsEnabled() {
 		clearDiagnostics(ctx, s.conn, uri, docVersion)
 	}
@@ -62,6 +62,8 @@ func (s *Server) handleDidChangeConfiguration(
 
 	// Settings affect lint results, so clear caches.
 	s.lintCache.clear()
+	s.slowChecks.cancelAll()
+	s.configGeneration.Add(1)
 
 	// Push model: recompute and publish diagnostics immediately.
 	if s.pushDiagnosticsEnabled() {
@@ -72,12 +74,19 @@ func (s *Server) handleDidChangeConfiguration(
 	}
 
 	// Pull model: request a refresh so the client re-pulls diagnostics.
-	if s.diagnosticRefreshSupported() {
-		reqCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
-		defer cancel()
-		if err := s.conn.Call(reqCtx, string(protocol.MethodWorkspaceDiagnosticRefresh), nil).Await(reqCtx, nil); err != nil {
-			log.Printf("lsp: workspace/diagnostic/refresh failed: %v", err)
-		}
+	s.refreshDiagnostics(ctx)
+}
+
+// refreshDiagnostics asks a pull-model client to pull diagnostics again, if
+// it supports workspace/diagnostic/refresh.
+func (s *Server) refreshDiagnostics(ctx context.Context) {
+	if !s.diagnosticRefreshSupported() {
+		return
+	}
+	reqCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	if err := s.conn.Call(reqCtx, string(protocol.MethodWorkspaceDiagnosticRefresh), nil).Await(reqCtx, nil); err != nil {
+		log.Printf("lsp: workspace/diagnostic/refresh failed: %v", err)
 	}
 }
 
@@ -0,0 +1,327 @@
+package lspserver
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	protocol "github.com/tinovyatkin/tally/internal/lsp/protocol"
+
+	"github.com/tinovyatkin/tally/internal/async"
+	"github.com/tinovyatkin/tally/internal/config"
+	"github.com/tinovyatkin/tally/internal/lintcache"
+	"github.com/tinovyatkin/tally/internal/linter"
+	"github.com/tinovyatkin/tally/internal/registry"
+	"github.com/tinovyatkin/tally/internal/rules"
+)
+
+// defaultSlowChecksTimeout is the slow checks budget when the config sets
+// none, as in the CLI.
+const defaultSlowChecksTimeout = 20 * time.Second
+
+// shutdownTimeout bounds how long shutdown waits for canceled slow check runs
+// to exit.
+const shutdownTimeout = 2 * time.Second
+
+// slowCheckRun is the background run of the slow (registry-backed) checks
+// planned for one version of a document's content.
+type slowCheckRun struct {
+	// hash is the content hash the checks were planned for.
+	hash   string
+	cancel context.CancelFunc
+
+	// done is closed when the run's goroutine exits, canceled or not.
+	done chan struct{}
+
+	// result is set when the run completes without being canceled.
+	result *async.RunResult
+}
+
+// slowCheckStore tracks the slow check run of each document.
+// It is safe for concurrent access.
+type slowCheckStore struct {
+	mu   sync.Mutex
+	runs map[string]*slowCheckRun
+}
+
+func newSlowCheckStore() *slowCheckStore {
+	return &slowCheckStore{runs: make(map[string]*slowCheckRun)}
+}
+
+// get returns the result of the run for a document's content, nil while
+// it is running, and whether there is such a run.
+func (c *slowCheckStore) get(uri, hash string) (*async.RunResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	run, ok := c.runs[uri]
+	if !ok || run.hash != hash {
+		return nil, false
+	}
+	return run.result, true
+}
+
+// start records a new run for a document, canceling the previous one.
+func (c *slowCheckStore) start(uri string, run *slowCheckRun) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if prev, ok := c.runs[uri]; ok {
+		prev.cancel()
+	}
+	c.runs[uri] = run
+}
+
+// finish stores the result of a run. It reports false if the run was
+// replaced or canceled in the meantime.
+func (c *slowCheckStore) finish(uri string, run *slowCheckRun, result *async.RunResult) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.runs[uri] != run {
+		return false
+	}
+	run.result = result
+	return true
+}
+
+// cancel stops and forgets the run of a document.
+func (c *slowCheckStore) cancel(uri string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if run, ok := c.runs[uri]; ok {
+		run.cancel()
+		delete(c.runs, uri)
+	}
+}
+
+// cancelAll stops and forgets every run. It returns the done channels of
+// the stopped runs.
+func (c *slowCheckStore) cancelAll() []<-chan struct{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	done := make([]<-chan struct{}, 0, len(c.runs))
+	for uri, run := range c.runs {
+		run.cancel()
+		done = append(done, run.done)
+		delete(c.runs, uri)
+	}
+	return done
+}
+
+// shutdown stops every run and waits for them to exit, at most for timeout.
+func (c *slowCheckStore) shutdown(timeout time.Duration) {
+	deadline := time.After(timeout)
+	for _, done := range c.cancelAll() {
+		select {
+		case <-done:
+		case <-deadline:
+			return
+		}
+	}
+}
+
+// documentViolations lints an open document. If the slow checks already ran
+// for its content, their results are merged in and slow reports true;
+// otherwise the slow checks are started in the background and the fast
+// violations are returned.
+func (s *Server) documentViolations(doc *Document) ([]rules.Violation, bool) {
+	content := []byte(doc.Content)
+	lr := s.lint(doc.URI, content)
+	if lr == nil {
+		s.slowChecks.cancel(doc.URI)
+		return nil, false
+	}
+
+	hash := contentHash(content)
+	if result, _ := s.slowChecks.get(doc.URI, hash); result != nil {
+		return lr.process(linter.MergeAsyncViolations(lr.result.Violations, result)), true
+	}
+	fast := lr.process(lr.result.Violations)
+	s.startSlowChecks(doc, hash, lr, fast)
+	return fast, false
+}
+
+// startSlowChecks runs the slow checks planned by a lint pass in the
+// background, unless they already run for the same content or are disabled
+// by the [slow-checks] config. When they complete, the merged diagnostics are
+// pushed, or the client is asked to pull them again.
+func (s *Server) startSlowChecks(doc *Document, hash string, lr *lintRun, fast []rules.Violation) {
+	if _, ok := s.slowChecks.get(doc.URI, hash); ok {
+		return
+	}
+	plans, timeout := slowCheckPlans(lr.result.Config, lr.result.AsyncPlan, fast)
+	if len(plans) == 0 {
+		s.slowChecks.cancel(doc.URI)
+		return
+	}
+	resolver := s.newImageResolver(lr.result.Config, s.cacheDir(lr.result.Config, uriToPath(doc.URI)))
+	if resolver == nil {
+		log.Printf("lsp: slow checks not available (missing build tags)")
+		s.slowChecks.cancel(doc.URI)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	run := &slowCheckRun{hash: hash, cancel: cancel, done: make(chan struct{})}
+	s.slowChecks.start(doc.URI, run)
+
+	asyncResolver := registry.NewAsyncImageResolver(resolver)
+	rt := &async.Runtime{
+		Concurrency: 4,
+		Timeout:     timeout,
+		Resolvers:   map[string]async.Resolver{asyncResolver.ID(): asyncResolver},
+	}
+	go func() {
+		defer close(run.done)
+		defer cancel()
+		progress := s.beginProgress(ctx, "Running slow checks", fmt.Sprintf("%d check(s)", len(plans)))
+		result := rt.Run(ctx, plans)
+		progress.end(context.WithoutCancel(ctx), fmt.Sprintf("%d skipped", len(result.Skipped)))
+		if ctx.Err() != nil || !s.slowChecks.finish(doc.URI, run, result) {
+			return
+		}
+		s.slowChecksCompleted(ctx, doc.URI, hash, lr, result)
+	}()
+}
+
+// slowChecksCompleted publishes the diagnostics of a document once its slow
+// checks completed, if the document still has the content they ran for.
+func (s *Server) slowChecksCompleted(
+	ctx context.Context,
+	docURI, hash string,
+	lr *lintRun,
+	result *async.RunResult,
+) {
+	doc := s.documents.Get(docURI)
+	if doc == nil || contentHash([]byte(doc.Content)) != hash {
+		return
+	}
+	violations := lr.process(linter.MergeAsyncViolations(lr.result.Violations, result))
+	s.lintCache.set(docURI, doc.Version, violations)
+
+	if s.conn == nil {
+		return
+	}
+	if s.pushDiagnosticsEnabled() {
+		s.sendDiagnostics(ctx, docURI, doc.Version, violations)
+		return
+	}
+	s.refreshDiagnostics(ctx)
+}
+
+// slowCheckPlans applies the [slow-checks] config of a document to the slow
+// checks planned for it: the mode, fail-fast (skip when the fast checks
+// already report errors) and the timeout.
+func slowCheckPlans(
+	cfg *config.Config,
+	plans []async.CheckRequest,
+	fast []rules.Violation,
+) ([]async.CheckRequest, time.Duration) {
+	if cfg == nil || len(plans) == 0 || !cfg.SlowChecks.Enabled() {
+		return nil, 0
+	}
+	if cfg.SlowChecks.FailFast {
+		for _, v := range fast {
+			if v.Severity == rules.SeverityError {
+				return nil, 0
+			}
+		}
+	}
+
+	timeout := defaultSlowChecksTimeout
+	d, err := time.ParseDuration(cfg.SlowChecks.Timeout)
+	if err != nil || d <= 0 {
+		return plans, timeout
+	}
+	out := make([]async.CheckRequest, 0, len(plans))
+	for _, req := range plans {
+		req.Timeout = d
+		out = append(out, req)
+	}
+	return out, max(timeout, d)
+}
+
+// newImageResolver returns the registry resolver for slow checks. Like the
+// CLI, resolved metadata is cached under the [cache] directory unless caching
+// is disabled, and in offline mode only that cache is consulted. Returns nil
+// when no resolver is available (binary built without registry support and
+// not offline).
+func newImageResolver(cfg *config.Config, cacheDir string) registry.ImageResolver {
+	slowCfg := cfg.SlowChecks
+
+	var inner registry.ImageResolver
+	if !slowCfg.Offline && registry.NewDefaultResolver != nil {
+		inner = registry.NewDefaultResolver()
+	}
+	if inner == nil && !slowCfg.Offline {
+		return nil
+	}
+
+	var registryDir string
+	if cacheDir != "" {
+		dir, err := lintcache.New(cacheDir).SubDir("registry")
+		if err != nil {
+			log.Printf("lsp: registry metadata cache disabled: %v", err)
+		}
+		registryDir = dir
+	}
+	if registryDir == "" && !slowCfg.Offline {
+		return inner
+	}
+
+	ttl := registry.DefaultCacheTTL
+	if d, err := time.ParseDuration(slowCfg.CacheTTL); err == nil {
+		ttl = d
+	}
+	return registry.NewCachingResolver(inner, registryDir,
+		registry.WithCacheTTL(ttl),
+		registry.WithOffline(slowCfg.Offline))
+}
+
+// progressTokens numbers the work done progress tokens created by the server.
+var progressTokens atomic.Int64
+
+// workDoneProgress is a server-initiated progress reported with $/progress.
+// The zero value reports nothing (client without progress support).
+type workDoneProgress struct {
+	s     *Server
+	token *protocol.IntegerOrString
+}
+
+// beginProgress creates a progress token on the client and reports the
+// beginning of a task.
+func (s *Server) beginProgress(ctx context.Context, title, message string) workDoneProgress {
+	if s.conn == nil || !s.workDoneProgressSupported() {
+		return workDoneProgress{}
+	}
+	token := &protocol.IntegerOrString{String: new(fmt.Sprintf("tally/%d", progressTokens.Add(1)))}
+
+	reqCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	if err := s.conn.Call(reqCtx, string(protocol.MethodWindowWorkDoneProgressCreate),
+		&protocol.WorkDoneProgressCreateParams{Token: *token}).Await(reqCtx, nil); err != nil {
+		log.Printf("lsp: window/workDoneProgress/create failed: %v", err)
+		return workDoneProgress{}
+	}
+	p := workDoneProgress{s: s, token: token}
+	p.notify(ctx, &protocol.WorkDoneProgressBegin{Title: title, Message: &message})
+	return p
+}
+
+// end reports the end of the task.
+func (p workDoneProgress) end(ctx context.Context, message string) {
+	p.notify(ctx, &protocol.WorkDoneProgressEnd{Message: &message})
+}
+
+func (p workDoneProgress) notify(ctx context.Context, value any) {
+	if p.token == nil {
+		return
+	}
+	if err := lspNotify(ctx, p.s.conn, string(protocol.MethodProgress), &protocol.ProgressParams{
+		Token: *p.token,
+		Value: value,
+	}); err != nil {
+		log.Printf("lsp: $/progress failed: %v", err)
+	}
+}
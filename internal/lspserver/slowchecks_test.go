@@ -0,0 +1,122 @@
+package lspserver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tinovyatkin/tally/internal/async"
+	"github.com/tinovyatkin/tally/internal/config"
+	"github.com/tinovyatkin/tally/internal/registry"
+	"github.com/tinovyatkin/tally/internal/rules"
+)
+
+// waitSlowChecks waits for the slow check run of a document to complete.
+func waitSlowChecks(t *testing.T, s *Server, uri string) {
+	t.Helper()
+	s.slowChecks.mu.Lock()
+	run, ok := s.slowChecks.runs[uri]
+	s.slowChecks.mu.Unlock()
+	require.True(t, ok, "no slow checks running")
+	select {
+	case <-run.done:
+	case <-time.After(10 * time.Second):
+		require.FailNow(t, "slow checks did not complete")
+	}
+}
+
+func hasRule(violations []rules.Violation, code string) bool {
+	for _, v := range violations {
+		if v.RuleCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+func TestDocumentViolations_SlowChecks(t *testing.T) {
+	t.Parallel()
+	s, doc := openTestDocument(t, "FROM alpine:3.20\nRUN echo hi\n", "[slow-checks]\nmode = \"on\"\n")
+	s.newImageResolver = func(*config.Config, string) registry.ImageResolver {
+		return stubResolver{cfg: registry.ImageConfig{OS: "windows", Arch: "amd64"}}
+	}
+
+	violations, slow := s.documentViolations(doc)
+	assert.False(t, slow)
+	assert.False(t, hasRule(violations, "buildkit/InvalidBaseImagePlatform"))
+
+	waitSlowChecks(t, s, doc.URI)
+	violations, slow = s.documentViolations(doc)
+	assert.True(t, slow)
+	assert.True(t, hasRule(violations, "buildkit/InvalidBaseImagePlatform"))
+
+	// Editing the document cancels and forgets the run.
+	s.slowChecks.cancel(doc.URI)
+	result, ok := s.slowChecks.get(doc.URI, contentHash([]byte(doc.Content)))
+	assert.Nil(t, result)
+	assert.False(t, ok)
+}
+
+func TestDocumentViolations_SlowChecksOff(t *testing.T) {
+	t.Parallel()
+	s, doc := openTestDocument(t, "FROM alpine:3.20\n", "[slow-checks]\nmode = \"off\"\n")
+	s.newImageResolver = func(*config.Config, string) registry.ImageResolver {
+		require.FailNow(t, "resolver created with slow checks off")
+		return nil
+	}
+
+	_, slow := s.documentViolations(doc)
+	assert.False(t, slow)
+	_, ok := s.slowChecks.get(doc.URI, contentHash([]byte(doc.Content)))
+	assert.False(t, ok)
+}
+
+func TestSlowCheckPlans(t *testing.T) {
+	t.Parallel()
+	plans := []async.CheckRequest{{RuleCode: "buildkit/InvalidBaseImagePlatform", Timeout: time.Second}}
+	errs := []rules.Violation{{RuleCode: "hadolint/DL3006", Severity: rules.SeverityError}}
+
+	cfg := &config.Config{SlowChecks: config.SlowChecksConfig{Mode: "off"}}
+	got, _ := slowCheckPlans(cfg, plans, nil)
+	assert.Empty(t, got, "mode off")
+
+	cfg = &config.Config{SlowChecks: config.SlowChecksConfig{Mode: "on", FailFast: true}}
+	got, _ = slowCheckPlans(cfg, plans, errs)
+	assert.Empty(t, got, "fail-fast with errors")
+
+	got, timeout := slowCheckPlans(cfg, plans, nil)
+	require.Len(t, got, 1)
+	assert.Equal(t, time.Second, got[0].Timeout)
+	assert.Equal(t, defaultSlowChecksTimeout, timeout)
+
+	cfg.SlowChecks.Timeout = "45s"
+	got, timeout = slowCheckPlans(cfg, plans, errs[:0])
+	require.Len(t, got, 1)
+	assert.Equal(t, 45*time.Second, got[0].Timeout)
+	assert.Equal(t, 45*time.Second, timeout)
+	assert.Equal(t, time.Second, plans[0].Timeout, "plans are not modified")
+}
+
+func TestSlowCheckStore_Shutdown(t *testing.T) {
+	t.Parallel()
+	store := newSlowCheckStore()
+	ctx, cancel := context.WithCancel(context.Background())
+	run := &slowCheckRun{hash: "h", cancel: cancel, done: make(chan struct{})}
+	store.start("file:///Dockerfile", run)
+	go func() {
+		defer close(run.done)
+		<-ctx.Done()
+	}()
+
+	store.shutdown(10 * time.Second)
+	select {
+	case <-run.done:
+	default:
+		require.FailNow(t, "shutdown returned before the run exited")
+	}
+	_, ok := store.get("file:///Dockerfile", "h")
+	assert.False(t, ok)
+}
@@ -0,0 +1,399 @@
+package lspserver
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"golang.org/x/exp/jsonrpc2"
+
+	protocol "github.com/tinovyatkin/tally/internal/lsp/protocol"
+
+	"github.com/tinovyatkin/tally/internal/config"
+	"github.com/tinovyatkin/tally/internal/directive"
+	"github.com/tinovyatkin/tally/internal/rules"
+	"github.com/tinovyatkin/tally/internal/sourcemap"
+)
+
+// suppressRuleCommand inserts an ignore directive. Code actions use it instead
+// of an edit when directives require a reason, so that the client can prompt
+// for one and pass it as the "reason" argument.
+const suppressRuleCommand = "tally.suppressRule"
+
+// suppressScope is where an inline suppression applies.
+type suppressScope string
+
+const (
+	// suppressLine is a next-line ignore directive.
+	suppressLine suppressScope = "line"
+	// suppressFile is a global ignore directive.
+	suppressFile suppressScope = "file"
+)
+
+// configRuleNamespaces are the rule namespaces of the [rules.<namespace>] config tables.
+var configRuleNamespaces = []string{"tally", "buildkit", "hadolint", "shellcheck"}
+
+// parserDirectivePattern matches the parser directives, which must stay
+// before any other comment.
+var parserDirectivePattern = regexp.MustCompile(`(?i)^#\s*(syntax|escape|check)\s*=`)
+
+// suppressRuleArgs are the arguments of the tally.suppressRule command.
+type suppressRuleArgs struct {
+	URI   string
+	Rule  string
+	Scope suppressScope
+
+	// Line is the 0-based line of the violation, for the line scope.
+	Line int
+
+	Reason string
+}
+
+// suppressActions returns the code actions suppressing the rule of a
+// violation: ignore directives for its line and for the file, and disabling
+// the rule in the config file. seen dedupes the actions of the violations of
+// the same rule.
+func (s *Server) suppressActions(
+	a *documentAnalysis,
+	doc *Document,
+	v rules.Violation,
+	diagnostics []*protocol.Diagnostic,
+	seen map[string]bool,
+) []protocol.CodeAction {
+	if !strings.Contains(v.RuleCode, "/") {
+		// Syntax errors and directive problems are not rules.
+		return nil
+	}
+
+	var actions []protocol.CodeAction
+	add := func(action protocol.CodeAction) {
+		if seen[action.Title] {
+			return
+		}
+		seen[action.Title] = true
+		action.Kind = ptrTo(protocol.CodeActionKindQuickFix)
+		action.IsPreferred = new(false)
+		action.Diagnostics = &diagnostics
+		actions = append(actions, action)
+	}
+
+	requireReason := a != nil && a.Config.InlineDirectives.RequireReason && s.applyEditSupported()
+	inline := func(args suppressRuleArgs, title string) {
+		if requireReason {
+			add(protocol.CodeAction{
+				Title: title,
+				Command: &protocol.Command{
+					Title:   title,
+					Command: suppressRuleCommand,
+					Arguments: &[]any{map[string]any{
+						"uri":   args.URI,
+						"rule":  args.Rule,
+						"scope": string(args.Scope),
+						"line":  args.Line,
+					}},
+				},
+			})
+			return
+		}
+		if edit := suppressEdit(doc.Content, args); edit != nil {
+			add(protocol.CodeAction{
+				Title: title,
+				Edit: &protocol.WorkspaceEdit{
+					Changes: new(map[protocol.DocumentUri][]*protocol.TextEdit{
+						protocol.DocumentUri(doc.URI): {edit},
+					}),
+				},
+			})
+		}
+	}
+
+	if line := v.Line() - 1; line >= 0 && !a.inHeredocBody(line) {
+		inline(suppressRuleArgs{URI: doc.URI, Rule: v.RuleCode, Scope: suppressLine, Line: line},
+			fmt.Sprintf("Ignore %s for this line", v.RuleCode))
+	}
+	inline(suppressRuleArgs{URI: doc.URI, Rule: v.RuleCode, Scope: suppressFile},
+		fmt.Sprintf("Ignore %s for this file", v.RuleCode))
+
+	if edit, title := s.disableRuleWorkspaceEdit(doc, v.RuleCode); edit != nil {
+		add(protocol.CodeAction{Title: title, Edit: edit})
+	}
+	return actions
+}
+
+// inHeredocBody reports whether a 0-based line is in a heredoc body, where a
+// comment would become part of the heredoc content.
+func (a *documentAnalysis) inHeredocBody(line int) bool {
+	if a == nil || a.ParseResult.AST == nil {
+		return false
+	}
+	for _, node := range a.ParseResult.AST.AST.Children {
+		for _, span := range a.heredocBodies(node) {
+			if line > span[0] && line <= span[1] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// suppressEdit returns the edit adding a rule to the ignore directive of a
+// line or of the file. The rule is merged into an existing tally directive
+// of the same scope; nil is returned if that directive already ignores it.
+func suppressEdit(content string, args suppressRuleArgs) *protocol.TextEdit {
+	sm := sourcemap.New([]byte(content))
+
+	want := directive.TypeNextLine
+	if args.Scope == suppressFile {
+		want = directive.TypeGlobal
+	}
+	for _, d := range directive.Parse(sm, nil).Directives {
+		if d.Type != want || d.Source != directive.SourceTally ||
+			(want == directive.TypeNextLine && !d.AppliesTo.Contains(args.Line)) {
+			continue
+		}
+		if d.SuppressesRule(args.Rule) {
+			return nil
+		}
+		d.Rules = append(slices.Clone(d.Rules), args.Rule)
+		if d.Reason == "" {
+			d.Reason = args.Reason
+		}
+		text := strings.TrimRight(sm.Line(d.Line), "\r")
+		return &protocol.TextEdit{
+			Range:   *lineRange(clampUint32(d.Line), strings.Index(text, "#"), len(text)),
+			NewText: d.Format(),
+		}
+	}
+
+	d := directive.Directive{Type: want, Rules: []string{args.Rule}, Source: directive.SourceTally, Reason: args.Reason}
+	if want == directive.TypeGlobal {
+		line := 0
+		for line < sm.LineCount() && parserDirectivePattern.MatchString(strings.TrimSpace(sm.Line(line))) {
+			line++
+		}
+		return &protocol.TextEdit{Range: *lineRange(clampUint32(line), 0, 0), NewText: d.Format() + "\n"}
+	}
+
+	text := sm.Line(args.Line)
+	indent := text[:len(text)-len(strings.TrimLeft(text, " \t"))]
+	return &protocol.TextEdit{
+		Range:   *lineRange(clampUint32(args.Line), 0, 0),
+		NewText: indent + d.Format() + "\n",
+	}
+}
+
+// disableRuleWorkspaceEdit returns the edit turning a rule off in the config
+// file of a document, and its code action title. Without a config file, a
+// .tally.toml is created in the document's workspace folder (or directory)
+// if the client can create files.
+func (s *Server) disableRuleWorkspaceEdit(doc *Document, ruleCode string) (*protocol.WorkspaceEdit, string) {
+	ns, _, _ := strings.Cut(ruleCode, "/")
+	if !slices.Contains(configRuleNamespaces, ns) {
+		return nil, ""
+	}
+	filePath := uriToPath(doc.URI)
+
+	if configPath := config.Discover(filePath); configPath != "" {
+		configURI := pathToURI(configPath)
+		content, err := s.contentForURI(configURI)
+		if err != nil {
+			return nil, ""
+		}
+		edit := disableRuleEdit(string(content), ruleCode)
+		if edit == nil {
+			return nil, ""
+		}
+		return &protocol.WorkspaceEdit{
+			Changes: new(map[protocol.DocumentUri][]*protocol.TextEdit{
+				protocol.DocumentUri(configURI): {edit},
+			}),
+		}, fmt.Sprintf("Disable %s in %s", ruleCode, filepath.Base(configPath))
+	}
+
+	if !s.createFilesSupported() {
+		return nil, ""
+	}
+	dir := s.workspaceRoot(filePath)
+	name := config.ConfigFileNames[0]
+	configURI := protocol.DocumentUri(pathToURI(filepath.Join(dir, name)))
+	return &protocol.WorkspaceEdit{
+		DocumentChanges: &[]protocol.TextDocumentEditOrCreateFileOrRenameFileOrDeleteFile{
+			{CreateFile: &protocol.CreateFile{Uri: configURI}},
+			{TextDocumentEdit: &protocol.TextDocumentEdit{
+				TextDocument: protocol.OptionalVersionedTextDocumentIdentifier{Uri: configURI},
+				Edits: []protocol.TextEditOrAnnotatedTextEditOrSnippetTextEdit{
+					{TextEdit: disableRuleEdit("", ruleCode)},
+				},
+			}},
+		},
+	}, fmt.Sprintf("Disable %s in %s", ruleCode, name)
+}
+
+var (
+	// tomlTablePattern matches a TOML table header, capturing its key.
+	tomlTablePattern = regexp.MustCompile(`^\s*\[\s*([^\[\]]+?)\s*\]`)
+	// tomlArrayTablePattern matches a TOML array of tables header.
+	tomlArrayTablePattern = regexp.MustCompile(`^\s*\[\[`)
+	// tomlSeverityPattern matches a severity key line.
+	tomlSeverityPattern = regexp.MustCompile(`^(\s*)severity\s*=`)
+	// tomlBareKeyPattern matches the keys that need no quotes.
+	tomlBareKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+)
+
+// disableRuleEdit returns the edit of a TOML config setting the severity of
+// a rule to "off" in its [rules.<namespace>.<name>] table, adding the table or
+// the key as needed. Returns nil if the rule is already off, or if it is
+// configured in a way this text edit cannot handle (dotted keys, inline
+// tables), which the result is checked against.
+func disableRuleEdit(content, ruleCode string) *protocol.TextEdit {
+	ns, name, _ := strings.Cut(ruleCode, "/")
+	if ruleSeverity(content, ns, name) == "off" {
+		return nil
+	}
+	lines := strings.Split(content, "\n")
+
+	var edit *protocol.TextEdit
+	if i := slices.IndexFunc(lines, func(line string) bool {
+		m := tomlTablePattern.FindStringSubmatch(line)
+		return m != nil && !tomlArrayTablePattern.MatchString(line) &&
+			slices.Equal(tomlKeyPath(m[1]), []string{"rules", ns, name})
+	}); i >= 0 {
+		edit = &protocol.TextEdit{Range: *lineRange(clampUint32(i+1), 0, 0), NewText: "severity = \"off\"\n"}
+		if i == len(lines)-1 {
+			// The header is the last line, without a line terminator.
+			edit = &protocol.TextEdit{Range: *lineRange(clampUint32(i), len(lines[i]), len(lines[i])), NewText: "\nseverity = \"off\""}
+		}
+		for j := i + 1; j < len(lines) && !tomlTablePattern.MatchString(lines[j]); j++ {
+			if m := tomlSeverityPattern.FindStringSubmatch(lines[j]); m != nil {
+				edit = &protocol.TextEdit{
+					Range:   *lineRange(clampUint32(j), 0, len(strings.TrimRight(lines[j], "\r"))),
+					NewText: m[1] + "severity = \"off\"",
+				}
+				break
+			}
+		}
+	} else {
+		table := "[rules." + ns + "." + tomlKey(name) + "]\nseverity = \"off\"\n"
+		switch {
+		case strings.TrimSpace(content) == "":
+		case strings.HasSuffix(content, "\n"):
+			table = "\n" + table
+		default:
+			table = "\n\n" + table
+		}
+		last := len(lines) - 1
+		edit = &protocol.TextEdit{
+			Range:   *lineRange(clampUint32(last), len(lines[last]), len(lines[last])),
+			NewText: table,
+		}
+	}
+
+	if ruleSeverity(applyLineEdit(lines, edit), ns, name) != "off" {
+		return nil
+	}
+	return edit
+}
+
+// ruleSeverity returns the severity a TOML config sets for a rule, or "" if
+// it sets none or is invalid.
+func ruleSeverity(content, ns, name string) string {
+	var cfg struct {
+		Rules map[string]map[string]struct {
+			Severity string `toml:"severity"`
+		} `toml:"rules"`
+	}
+	if err := toml.Unmarshal([]byte(content), &cfg); err != nil {
+		return ""
+	}
+	return cfg.Rules[ns][name].Severity
+}
+
+// tomlKeyPath splits a dotted TOML key into its unquoted parts.
+func tomlKeyPath(key string) []string {
+	parts := strings.Split(key, ".")
+	for i, p := range parts {
+		parts[i] = strings.Trim(strings.TrimSpace(p), `"'`)
+	}
+	return parts
+}
+
+// tomlKey quotes a TOML key unless it is a bare key.
+func tomlKey(key string) string {
+	if tomlBareKeyPattern.MatchString(key) {
+		return key
+	}
+	return fmt.Sprintf("%q", key)
+}
+
+// applyLineEdit applies an edit starting and ending on the same line.
+func applyLineEdit(lines []string, edit *protocol.TextEdit) string {
+	out := slices.Clone(lines)
+	line := int(edit.Range.Start.Line)
+	if line == len(out) {
+		out = append(out, "")
+	}
+	text := out[line]
+	out[line] = text[:edit.Range.Start.Character] + edit.NewText + text[edit.Range.End.Character:]
+	return strings.Join(out, "\n")
+}
+
+// parseSuppressRuleArgs parses the arguments of the tally.suppressRule command.
+func parseSuppressRuleArgs(args *[]any) (suppressRuleArgs, bool) {
+	if args == nil || len(*args) == 0 {
+		return suppressRuleArgs{}, false
+	}
+	m, ok := (*args)[0].(map[string]any)
+	if !ok {
+		return suppressRuleArgs{}, false
+	}
+	var out suppressRuleArgs
+	out.URI, _ = m["uri"].(string)
+	out.Rule, _ = m["rule"].(string)
+	scope, _ := m["scope"].(string)
+	out.Scope = suppressScope(scope)
+	if line, ok := m["line"].(float64); ok {
+		out.Line = int(line)
+	}
+	reason, _ := m["reason"].(string)
+	// The reason is the last directive option; it cannot span lines.
+	out.Reason = strings.Join(strings.Fields(reason), " ")
+	valid := out.URI != "" && out.Rule != "" && (out.Scope == suppressLine || out.Scope == suppressFile)
+	return out, valid
+}
+
+// executeSuppressRule applies the ignore directive of a tally.suppressRule
+// command with workspace/applyEdit.
+func (s *Server) executeSuppressRule(params *protocol.ExecuteCommandParams) (any, error) {
+	args, ok := parseSuppressRuleArgs(params.Arguments)
+	if !ok {
+		return nil, jsonrpc2.NewError(int64(protocol.ErrorCodeInvalidParams), "invalid command arguments")
+	}
+	content, err := s.contentForURI(args.URI)
+	if err != nil {
+		return nil, nil //nolint:nilnil,nilerr // gracefully do nothing when the file can't be read
+	}
+	edit := suppressEdit(string(content), args)
+	if edit == nil || s.conn == nil {
+		return nil, nil //nolint:nilnil // no changes
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := s.conn.Call(ctx, string(protocol.MethodWorkspaceApplyEdit), &protocol.ApplyWorkspaceEditParams{
+		Label: new("Ignore " + args.Rule),
+		Edit: &protocol.WorkspaceEdit{
+			Changes: new(map[protocol.DocumentUri][]*protocol.TextEdit{
+				protocol.DocumentUri(args.URI): {edit},
+			}),
+		},
+	}).Await(ctx, nil); err != nil {
+		log.Printf("lsp: workspace/applyEdit failed: %v", err)
+	}
+	return nil, nil //nolint:nilnil // LSP: null result is valid
+}
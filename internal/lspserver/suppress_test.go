@@ -0,0 +1,170 @@
+package lspserver
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	protocol "github.com/tinovyatkin/tally/internal/lsp/protocol"
+)
+
+// applyEdit applies a single-line edit to content.
+func applyEdit(content string, edit *protocol.TextEdit) string {
+	return applyLineEdit(strings.Split(content, "\n"), edit)
+}
+
+func TestSuppressEdit(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		content string
+		args    suppressRuleArgs
+		want    string
+	}{
+		{
+			name:    "line",
+			content: "FROM alpine:3.20\n  RUN apk add curl\n",
+			args:    suppressRuleArgs{Rule: "hadolint/DL3018", Scope: suppressLine, Line: 1},
+			want:    "FROM alpine:3.20\n  # tally ignore=hadolint/DL3018\n  RUN apk add curl\n",
+		},
+		{
+			name:    "line merge",
+			content: "FROM alpine:3.20\n# tally ignore=tally/no-curl;reason=legacy\n\nRUN apk add curl\n",
+			args:    suppressRuleArgs{Rule: "hadolint/DL3018", Scope: suppressLine, Line: 3},
+			want:    "FROM alpine:3.20\n# tally ignore=tally/no-curl,hadolint/DL3018;reason=legacy\n\nRUN apk add curl\n",
+		},
+		{
+			name:    "hadolint directive is not merged",
+			content: "FROM alpine:3.20\n# hadolint ignore=DL3003\nRUN apk add curl\n",
+			args:    suppressRuleArgs{Rule: "hadolint/DL3018", Scope: suppressLine, Line: 2, Reason: "pinned by base"},
+			want:    "FROM alpine:3.20\n# hadolint ignore=DL3003\n# tally ignore=hadolint/DL3018;reason=pinned by base\nRUN apk add curl\n",
+		},
+		{
+			name:    "file after parser directives",
+			content: "# syntax=docker/dockerfile:1\n# escape=`\n# a comment\nFROM alpine:3.20\n",
+			args:    suppressRuleArgs{Rule: "tally/max-lines", Scope: suppressFile},
+			want:    "# syntax=docker/dockerfile:1\n# escape=`\n# tally global ignore=tally/max-lines\n# a comment\nFROM alpine:3.20\n",
+		},
+		{
+			name:    "file merge",
+			content: "# tally global ignore=DL3006\nFROM alpine\n",
+			args:    suppressRuleArgs{Rule: "tally/max-lines", Scope: suppressFile},
+			want:    "# tally global ignore=DL3006,tally/max-lines\nFROM alpine\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			edit := suppressEdit(tt.content, tt.args)
+			require.NotNil(t, edit)
+			assert.Equal(t, tt.want, applyEdit(tt.content, edit))
+		})
+	}
+
+	// Already ignored.
+	assert.Nil(t, suppressEdit("FROM alpine\n# tally ignore=DL3018\nRUN apk add curl\n",
+		suppressRuleArgs{Rule: "hadolint/DL3018", Scope: suppressLine, Line: 2}))
+}
+
+func TestDisableRuleEdit(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "empty",
+			content: "",
+			want:    "[rules.hadolint.DL3008]\nseverity = \"off\"\n",
+		},
+		{
+			name:    "append",
+			content: "[output]\nformat = \"json\"",
+			want:    "[output]\nformat = \"json\"\n\n[rules.hadolint.DL3008]\nseverity = \"off\"\n",
+		},
+		{
+			name:    "replace severity",
+			content: "[rules.hadolint.DL3008]\n  severity = \"error\"\n\n[output]\n",
+			want:    "[rules.hadolint.DL3008]\n  severity = \"off\"\n\n[output]\n",
+		},
+		{
+			name:    "insert severity",
+			content: "[rules.hadolint.\"DL3008\"]\nexclude = { paths = [\"test/**\"] }\n",
+			want:    "[rules.hadolint.\"DL3008\"]\nseverity = \"off\"\nexclude = { paths = [\"test/**\"] }\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			edit := disableRuleEdit(tt.content, "hadolint/DL3008")
+			require.NotNil(t, edit)
+			assert.Equal(t, tt.want, applyEdit(tt.content, edit))
+		})
+	}
+
+	assert.Nil(t, disableRuleEdit("[rules.hadolint]\nDL3008 = { severity = \"off\" }\n", "hadolint/DL3008"), "already off")
+	assert.Nil(t, disableRuleEdit("[rules.hadolint]\nDL3008 = { severity = \"error\" }\n", "hadolint/DL3008"),
+		"inline table cannot be edited")
+}
+
+func TestSuppressActions(t *testing.T) {
+	t.Parallel()
+	s, doc := openTestDocument(t, "FROM alpine:3.20\nMAINTAINER me@example.com\n", "[output]\nformat = \"text\"\n")
+
+	params := &protocol.CodeActionParams{
+		TextDocument: protocol.TextDocumentIdentifier{Uri: protocol.DocumentUri(doc.URI)},
+		Range:        *lineRange(1, 0, 5),
+		Context:      &protocol.CodeActionContext{},
+	}
+	titles := func() map[string]protocol.CodeAction {
+		actions := make(map[string]protocol.CodeAction)
+		for _, action := range s.codeActionsForDocument(doc, params) {
+			actions[action.Title] = action
+		}
+		return actions
+	}
+
+	actions := titles()
+	line := actions["Ignore buildkit/MaintainerDeprecated for this line"]
+	require.NotNil(t, line.Edit)
+	assert.False(t, *line.IsPreferred)
+	assert.Contains(t, actions, "Ignore buildkit/MaintainerDeprecated for this file")
+
+	config := actions["Disable buildkit/MaintainerDeprecated in .tally.toml"]
+	require.NotNil(t, config.Edit)
+	configURI := protocol.DocumentUri(pathToURI(filepath.Join(filepath.Dir(uriToPath(doc.URI)), ".tally.toml")))
+	require.Contains(t, *config.Edit.Changes, configURI)
+
+	// With require-reason, the inline actions are commands that prompt for it.
+	s, doc = openTestDocument(t, doc.Content, "[inline-directives]\nrequire-reason = true\n")
+	s.configureWorkspace(&protocol.InitializeParams{Capabilities: &protocol.ClientCapabilities{
+		Workspace: &protocol.WorkspaceClientCapabilities{ApplyEdit: new(true)},
+	}})
+	actions = titles()
+	line = actions["Ignore buildkit/MaintainerDeprecated for this line"]
+	assert.Nil(t, line.Edit)
+	require.NotNil(t, line.Command)
+	assert.Equal(t, suppressRuleCommand, line.Command.Command)
+	args, ok := parseSuppressRuleArgs(new([]any{map[string]any{
+		"uri": doc.URI, "rule": "buildkit/MaintainerDeprecated", "scope": "line", "line": float64(1),
+		"reason": "  legacy\nimage ",
+	}}))
+	require.True(t, ok)
+	assert.Equal(t, suppressRuleArgs{
+		URI: doc.URI, Rule: "buildkit/MaintainerDeprecated", Scope: suppressLine, Line: 1, Reason: "legacy image",
+	}, args)
+}
+
+func TestSuppressActions_HeredocBody(t *testing.T) {
+	t.Parallel()
+	s, doc := openTestDocument(t, "FROM alpine:3.20\nRUN <<EOF\napk add curl\nEOF\n", "")
+	a := s.analyzeDocument(doc)
+	require.NotNil(t, a)
+	assert.False(t, a.inHeredocBody(1))
+	assert.True(t, a.inHeredocBody(2))
+	assert.True(t, a.inHeredocBody(3))
+}
@@ -0,0 +1,348 @@
+package lspserver
+
+import (
+	"cmp"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/moby/buildkit/frontend/dockerfile/instructions"
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+
+	protocol "github.com/tinovyatkin/tally/internal/lsp/protocol"
+)
+
+// occurrence is a stage name or a variable name on a line.
+type occurrence struct {
+	// Line is the 0-based line.
+	Line uint32
+
+	// Start and End are the byte offsets of the name on the line.
+	Start, End int
+
+	// Declaration is true for the stage name after AS and for the names
+	// declared by ARG and ENV instructions.
+	Declaration bool
+}
+
+// Range returns the range of the occurrence.
+func (o occurrence) Range() protocol.Range {
+	return *lineRange(o.Line, o.Start, o.End)
+}
+
+func (o occurrence) contains(pos protocol.Position) bool {
+	return pos.Line == o.Line && int(pos.Character) >= o.Start && int(pos.Character) < o.End
+}
+
+// symbol is a build stage or a build variable. Variables are identified by
+// the instruction that provides their value, so a global ARG and the stage
+// ARGs inheriting its default are the same symbol.
+type symbol struct {
+	// Stage is the index of a stage, or -1 for a variable.
+	Stage int
+
+	// Name is the variable name.
+	Name string
+
+	// Decl is the ARG or ENV instruction providing the variable's value.
+	Decl []parser.Range
+}
+
+func (s symbol) isStage() bool {
+	return s.Stage >= 0
+}
+
+func (s symbol) equal(other symbol) bool {
+	if s.isStage() || other.isStage() {
+		return s.Stage == other.Stage
+	}
+	return s.Name == other.Name && len(s.Decl) > 0 && len(other.Decl) > 0 &&
+		s.Decl[0].Start.Line == other.Decl[0].Start.Line
+}
+
+// symbolOccurrence is an occurrence of a symbol.
+type symbolOccurrence struct {
+	occurrence
+	symbol symbol
+}
+
+// symbolAt returns the symbol under a position.
+func (a *documentAnalysis) symbolAt(pos protocol.Position) (symbolOccurrence, bool) {
+	for _, o := range a.symbolOccurrences() {
+		if o.contains(pos) {
+			return o, true
+		}
+	}
+	return symbolOccurrence{}, false
+}
+
+// occurrencesOf returns the occurrences of a symbol in document order.
+func (a *documentAnalysis) occurrencesOf(sym symbol) []symbolOccurrence {
+	var result []symbolOccurrence
+	for _, o := range a.symbolOccurrences() {
+		if o.symbol.equal(sym) {
+			result = append(result, o)
+		}
+	}
+	return result
+}
+
+// definitionOf returns the declaration of a symbol: the stage name after AS
+// (the FROM instruction of unnamed stages), or the variable name in the ARG
+// or ENV instruction providing its value.
+func (a *documentAnalysis) definitionOf(sym symbol) (occurrence, bool) {
+	if sym.isStage() {
+		for _, o := range a.stageOccurrences() {
+			if o.Declaration && o.symbol.Stage == sym.Stage {
+				return o.occurrence, true
+			}
+		}
+		stage := a.Semantic.Stage(sym.Stage)
+		if stage == nil || len(stage.Location) == 0 {
+			return occurrence{}, false
+		}
+		line := clampUint32(stage.Location[0].Start.Line - 1)
+		text := a.line(line)
+		start := len(text) - len(strings.TrimLeft(text, " \t"))
+		return occurrence{Line: line, Start: start, End: len(strings.TrimRight(text, " \t")), Declaration: true}, true
+	}
+	for _, o := range a.occurrencesOf(sym) {
+		if o.Declaration && o.Line == clampUint32(sym.Decl[0].Start.Line-1) {
+			return o.occurrence, true
+		}
+	}
+	return occurrence{}, false
+}
+
+// symbolOccurrences returns the stage and variable occurrences of the
+// document, in document order within each kind.
+func (a *documentAnalysis) symbolOccurrences() []symbolOccurrence {
+	return append(a.stageOccurrences(), a.variableOccurrences()...)
+}
+
+// stageOccurrences returns the stage names after AS and the references to
+// stages: FROM <stage>, COPY --from=<stage> and RUN --mount=from=<stage>.
+func (a *documentAnalysis) stageOccurrences() []symbolOccurrence {
+	var result []symbolOccurrence
+	add := func(stageIdx int, o occurrence) {
+		result = append(result, symbolOccurrence{occurrence: o, symbol: symbol{Stage: stageIdx}})
+	}
+	for i, stage := range a.ParseResult.Stages {
+		info := a.Semantic.StageInfo(i)
+		if info == nil {
+			continue
+		}
+		if line, start, end, ok := a.stageNameRange(i); ok {
+			add(i, occurrence{Line: line, Start: start, End: end, Declaration: true})
+		}
+		if base := info.BaseImage; base != nil && base.IsStageRef && base.StageIndex >= 0 {
+			if line, start, end, ok := a.fromBaseRange(i); ok {
+				add(base.StageIndex, occurrence{Line: line, Start: start, End: end})
+			}
+		}
+		for _, ref := range slices.Concat(info.CopyFromRefs, info.OnbuildCopyFromRefs) {
+			if !ref.IsStageRef || ref.StageIndex < 0 {
+				continue
+			}
+			for _, o := range a.flagValues(ref.Location, copyFromPattern) {
+				if o.value == ref.From {
+					add(ref.StageIndex, o.occurrence)
+				}
+			}
+		}
+		for _, cmd := range stage.Commands {
+			run, ok := cmd.(*instructions.RunCommand)
+			if !ok {
+				continue
+			}
+			for _, o := range a.flagValues(run.Location(), mountFromPattern) {
+				if idx, ok := a.stageIndex(o.value); ok && idx < i {
+					add(idx, o.occurrence)
+				}
+			}
+		}
+	}
+	return result
+}
+
+// stageIndex resolves a stage reference (a stage name or index).
+func (a *documentAnalysis) stageIndex(ref string) (int, bool) {
+	if idx, err := strconv.Atoi(ref); err == nil {
+		return idx, idx >= 0 && idx < a.Semantic.StageCount()
+	}
+	return a.Semantic.StageIndexByName(ref)
+}
+
+// stageNamePattern matches the AS clause of a FROM instruction.
+var stageNamePattern = regexp.MustCompile(`(?i)\sAS[ \t]+([^\s]+)`)
+
+// stageNameRange returns the 0-based line and the byte offsets of the name
+// after AS in the FROM instruction of a stage.
+func (a *documentAnalysis) stageNameRange(stageIdx int) (uint32, int, int, bool) {
+	stage := a.Semantic.Stage(stageIdx)
+	if stage == nil || stage.Name == "" {
+		return 0, 0, 0, false
+	}
+	for _, loc := range stage.Location {
+		for n := loc.Start.Line - 1; n <= loc.End.Line-1; n++ {
+			line := a.line(clampUint32(n))
+			for _, m := range stageNamePattern.FindAllStringSubmatchIndex(line, -1) {
+				if strings.EqualFold(line[m[2]:m[3]], stage.Name) {
+					return clampUint32(n), m[2], m[3], true
+				}
+			}
+		}
+	}
+	return 0, 0, 0, false
+}
+
+var (
+	// copyFromPattern matches the value of a COPY/ADD --from flag.
+	copyFromPattern = regexp.MustCompile(`(?i)--from=([^\s]+)`)
+
+	// mountFromPattern matches the value of the from option of a RUN
+	// --mount flag.
+	mountFromPattern = regexp.MustCompile(`(?i)--mount=(?:[^\s]*,)?from=([^,\s]+)`)
+)
+
+// flagValue is a flag value found on an instruction's lines.
+type flagValue struct {
+	occurrence
+	value string
+}
+
+// flagValues returns the values of the first capture group of pattern on
+// the lines of an instruction.
+func (a *documentAnalysis) flagValues(loc []parser.Range, pattern *regexp.Regexp) []flagValue {
+	var result []flagValue
+	for _, r := range loc {
+		for n := r.Start.Line - 1; n <= r.End.Line-1; n++ {
+			line := a.line(clampUint32(n))
+			if strings.HasPrefix(strings.TrimSpace(line), "#") {
+				continue
+			}
+			for _, m := range pattern.FindAllStringSubmatchIndex(line, -1) {
+				result = append(result, flagValue{
+					occurrence: occurrence{Line: clampUint32(n), Start: m[2], End: m[3]},
+					value:      line[m[2]:m[3]],
+				})
+			}
+		}
+	}
+	return result
+}
+
+// variableOccurrences returns the names declared by ARG and ENV
+// instructions and the $NAME and ${NAME} references that resolve to a
+// declaration. References to variables without a declaration are skipped.
+func (a *documentAnalysis) variableOccurrences() []symbolOccurrence {
+	var result []symbolOccurrence
+	// add resolves the variable in the scope of a 0-based line.
+	add := func(name string, o occurrence, scopeLine uint32) {
+		if decl := a.variableDecl(scopeLine, name); len(decl) > 0 {
+			result = append(result, symbolOccurrence{occurrence: o, symbol: symbol{Stage: -1, Name: name, Decl: decl}})
+		}
+	}
+
+	declare := func(loc []parser.Range, names []string) {
+		if len(loc) == 0 {
+			return
+		}
+		// A declaration is visible from the line after its instruction.
+		after := clampUint32(loc[len(loc)-1].End.Line)
+		for _, name := range names {
+			if line, start, end, ok := a.declaredNameRange(loc, name); ok {
+				add(name, occurrence{Line: line, Start: start, End: end, Declaration: true}, after)
+			}
+		}
+	}
+	for _, arg := range a.ParseResult.MetaArgs {
+		declare(arg.Location(), argNames(&arg))
+	}
+	for _, stage := range a.ParseResult.Stages {
+		for _, cmd := range stage.Commands {
+			switch c := cmd.(type) {
+			case *instructions.ArgCommand:
+				declare(c.Location(), argNames(c))
+			case *instructions.EnvCommand:
+				names := make([]string, 0, len(c.Env))
+				for _, kv := range c.Env {
+					names = append(names, kv.Key)
+				}
+				declare(c.Location(), names)
+			}
+		}
+	}
+
+	escape := a.escapeToken()
+	for n, line := range a.Lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		for _, ref := range variableRefs(line, escape) {
+			// The name, without the $ and braces.
+			start := ref.Start + 1
+			if line[start] == '{' {
+				start++
+			}
+			add(ref.Name, occurrence{Line: clampUint32(n), Start: start, End: start + len(ref.Name)}, clampUint32(n))
+		}
+	}
+	sortOccurrences(result)
+	return result
+}
+
+// sortOccurrences sorts occurrences in document order.
+func sortOccurrences(occurrences []symbolOccurrence) {
+	slices.SortFunc(occurrences, func(x, y symbolOccurrence) int {
+		return cmp.Or(cmp.Compare(x.Line, y.Line), cmp.Compare(x.Start, y.Start))
+	})
+}
+
+func argNames(c *instructions.ArgCommand) []string {
+	names := make([]string, 0, len(c.Args))
+	for _, kv := range c.Args {
+		names = append(names, kv.Key)
+	}
+	return names
+}
+
+// variableDecl returns the instruction that declares a variable in the
+// scope of a 0-based line: the ARG or ENV before the line providing its
+// value, or the stage ARG declaring it without a value.
+func (a *documentAnalysis) variableDecl(line uint32, name string) []parser.Range {
+	if def, ok := a.lookupVariable(line, name); ok && len(def.Location) > 0 {
+		return def.Location
+	}
+	return a.argDecl(line, name)
+}
+
+// declaredNameRange returns the position of a name declared by an ARG or
+// ENV instruction: the first word that is the name, alone or followed by =.
+func (a *documentAnalysis) declaredNameRange(loc []parser.Range, name string) (uint32, int, int, bool) {
+	if len(loc) == 0 {
+		return 0, 0, 0, false
+	}
+	first := loc[0].Start.Line - 1
+	for n := first; n <= loc[len(loc)-1].End.Line-1; n++ {
+		line := a.line(clampUint32(n))
+		from := 0
+		if n == first {
+			from = max(instructionKeywordEnd(line), 0)
+		}
+		for i := from; i+len(name) <= len(line); i++ {
+			if !strings.HasPrefix(line[i:], name) || i > 0 && !isSpace(line[i-1]) {
+				continue
+			}
+			if end := i + len(name); end == len(line) || line[end] == '=' || isSpace(line[end]) {
+				return clampUint32(n), i, end, true
+			}
+		}
+	}
+	return 0, 0, 0, false
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t'
+}
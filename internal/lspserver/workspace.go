@@ -0,0 +1,293 @@
+package lspserver
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	protocol "github.com/tinovyatkin/tally/internal/lsp/protocol"
+
+	"github.com/tinovyatkin/tally/internal/config"
+	buildcontext "github.com/tinovyatkin/tally/internal/context"
+	"github.com/tinovyatkin/tally/internal/discovery"
+	"github.com/tinovyatkin/tally/internal/dockerfile"
+	"github.com/tinovyatkin/tally/internal/rules"
+)
+
+// workspaceExcludePatterns are the directories skipped when discovering the
+// Dockerfiles of a workspace.
+var workspaceExcludePatterns = []string{"**/.git/**", "**/node_modules/**"}
+
+// watchedFilesRegistrationID identifies the didChangeWatchedFiles registration.
+const watchedFilesRegistrationID = "tally-watched-files"
+
+// configureWorkspace records the workspace folders (or root) the client opened,
+// whether it can watch files for the server, and how it applies workspace edits.
+func (s *Server) configureWorkspace(params *protocol.InitializeParams) {
+	var folders []string
+	watch, applyEdit, createFiles := false, false, false
+	if params != nil {
+		if params.WorkspaceFolders != nil && params.WorkspaceFolders.WorkspaceFolders != nil {
+			for _, f := range *params.WorkspaceFolders.WorkspaceFolders {
+				folders = append(folders, uriToPath(string(f.Uri)))
+			}
+		} else if params.RootUri.DocumentUri != nil {
+			folders = append(folders, uriToPath(string(*params.RootUri.DocumentUri)))
+		}
+		if params.Capabilities != nil && params.Capabilities.Workspace != nil {
+			w := params.Capabilities.Workspace.DidChangeWatchedFiles
+			watch = w != nil && w.DynamicRegistration != nil && *w.DynamicRegistration
+			applyEdit = params.Capabilities.Workspace.ApplyEdit != nil && *params.Capabilities.Workspace.ApplyEdit
+			if e := params.Capabilities.Workspace.WorkspaceEdit; e != nil {
+				createFiles = e.DocumentChanges != nil && *e.DocumentChanges &&
+					e.ResourceOperations != nil && slices.Contains(*e.ResourceOperations, protocol.ResourceOperationKindCreate)
+			}
+		}
+	}
+
+	s.workspaceMu.Lock()
+	s.workspaceFolders = folders
+	s.supportsWatchedFiles = watch
+	s.supportsApplyEdit = applyEdit
+	s.supportsCreateFiles = createFiles
+	s.workspaceMu.Unlock()
+}
+
+// applyEditSupported reports whether the client applies workspace edits
+// requested by the server (workspace/applyEdit).
+func (s *Server) applyEditSupported() bool {
+	s.workspaceMu.RLock()
+	defer s.workspaceMu.RUnlock()
+	return s.supportsApplyEdit
+}
+
+// createFilesSupported reports whether the client can create files in
+// workspace edits.
+func (s *Server) createFilesSupported() bool {
+	s.workspaceMu.RLock()
+	defer s.workspaceMu.RUnlock()
+	return s.supportsCreateFiles
+}
+
+func (s *Server) workspaceFolderPaths() []string {
+	s.workspaceMu.RLock()
+	defer s.workspaceMu.RUnlock()
+	return slices.Clone(s.workspaceFolders)
+}
+
+// workspaceRoot returns the outermost workspace folder containing a file,
+// or the file's directory when it is outside the workspace.
+func (s *Server) workspaceRoot(filePath string) string {
+	dir := filepath.Dir(filePath)
+	root := dir
+	for _, folder := range s.workspaceFolderPaths() {
+		if isWithin(folder, dir) && len(folder) < len(root) {
+			root = folder
+		}
+	}
+	return root
+}
+
+// cacheDir returns the cache directory of a file with its config, or "" when
+// caching is disabled. A relative [cache] dir of a file without a config
+// file is resolved against the workspace rather than the server's working
+// directory.
+func (s *Server) cacheDir(cfg *config.Config, filePath string) string {
+	if !cfg.Cache.Enabled {
+		return ""
+	}
+	return cfg.CacheDir(s.workspaceRoot(filePath))
+}
+
+// watchedFileNames are the base names of the files that affect lint results
+// besides the Dockerfiles themselves: config files and build context ignore files.
+func watchedFileNames() []string {
+	return slices.Concat(config.ConfigFileNames, buildcontext.IgnoreFileNames())
+}
+
+// handleInitialized asks the client to watch config and ignore files, so that
+// documents are linted again when they change.
+func (s *Server) handleInitialized(ctx context.Context) {
+	s.workspaceMu.RLock()
+	watch := s.supportsWatchedFiles
+	s.workspaceMu.RUnlock()
+	if !watch || s.conn == nil {
+		return
+	}
+
+	names := watchedFileNames()
+	watchers := make([]*protocol.FileSystemWatcher, 0, len(names))
+	for _, name := range names {
+		watchers = append(watchers, &protocol.FileSystemWatcher{
+			GlobPattern: protocol.PatternOrRelativePattern{Pattern: new("**/" + name)},
+		})
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	if err := s.conn.Call(reqCtx, string(protocol.MethodClientRegisterCapability), &protocol.RegistrationParams{
+		Registrations: []*protocol.Registration{{
+			Id:     watchedFilesRegistrationID,
+			Method: string(protocol.MethodWorkspaceDidChangeWatchedFiles),
+			RegisterOptions: &protocol.RegisterOptions{
+				DidChangeWatchedFiles: &protocol.DidChangeWatchedFilesRegistrationOptions{Watchers: watchers},
+			},
+		}},
+	}).Await(reqCtx, nil); err != nil {
+		log.Printf("lsp: client/registerCapability failed: %v", err)
+	}
+}
+
+// handleDidChangeWatchedFiles lints the open documents affected by changed
+// config or ignore files again. A file affects the documents in its directory
+// and below: configs cascade to subdirectories, and an ignore file applies
+// to the build contexts it is found in.
+func (s *Server) handleDidChangeWatchedFiles(ctx context.Context, params *protocol.DidChangeWatchedFilesParams) {
+	names := watchedFileNames()
+	var dirs []string
+	for _, change := range params.Changes {
+		path := uriToPath(string(change.Uri))
+		if slices.Contains(names, filepath.Base(path)) {
+			dirs = append(dirs, filepath.Dir(path))
+		}
+	}
+	if len(dirs) == 0 {
+		return
+	}
+
+	// Closed documents are reported by workspace diagnostics; their result IDs
+	// must change even though their content did not.
+	s.configGeneration.Add(1)
+
+	var affected []*Document
+	for _, doc := range s.documents.All() {
+		path := uriToPath(doc.URI)
+		if slices.ContainsFunc(dirs, func(dir string) bool { return isWithin(dir, path) }) {
+			s.lintCache.delete(doc.URI)
+			s.slowChecks.cancel(doc.URI)
+			affected = append(affected, doc)
+		}
+	}
+
+	if s.pushDiagnosticsEnabled() {
+		for _, doc := range affected {
+			s.publishDiagnostics(ctx, doc)
+		}
+		return
+	}
+	s.refreshDiagnostics(ctx)
+}
+
+// isWithin reports whether path is dir or below it.
+func isWithin(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// handleWorkspaceDiagnostic handles workspace/diagnostic: it reports the
+// Dockerfiles discovered in the workspace folders, including closed ones.
+func (s *Server) handleWorkspaceDiagnostic(params *protocol.WorkspaceDiagnosticParams) (any, error) {
+	report := &protocol.WorkspaceDiagnosticReport{
+		Items: []protocol.WorkspaceFullDocumentDiagnosticReportOrUnchangedDocumentDiagnosticReport{},
+	}
+	folders := s.workspaceFolderPaths()
+	if len(folders) == 0 {
+		return report, nil
+	}
+
+	discovered, err := discovery.Discover(folders, discovery.Options{
+		Patterns:        discovery.DefaultPatterns(),
+		ExcludePatterns: workspaceExcludePatterns,
+	})
+	if err != nil {
+		log.Printf("lsp: workspace discovery failed: %v", err)
+		return report, nil
+	}
+
+	previous := make(map[string]string, len(params.PreviousResultIds))
+	for _, p := range params.PreviousResultIds {
+		previous[string(p.Uri)] = p.Value
+	}
+
+	for _, df := range discovered {
+		uri := pathToURI(df.Path)
+		var previousResultID *string
+		if v, ok := previous[uri]; ok {
+			previousResultID = &v
+		}
+		d, ok := s.pullDiagnostics(uri, previousResultID)
+		if !ok {
+			continue
+		}
+
+		version := protocol.IntegerOrNull{Integer: d.version}
+		if d.unchanged {
+			report.Items = append(report.Items, protocol.WorkspaceFullDocumentDiagnosticReportOrUnchangedDocumentDiagnosticReport{
+				UnchangedDocumentDiagnosticReport: &protocol.WorkspaceUnchangedDocumentDiagnosticReport{
+					ResultId: d.resultID,
+					Uri:      protocol.DocumentUri(uri),
+					Version:  version,
+				},
+			})
+			continue
+		}
+		report.Items = append(report.Items, protocol.WorkspaceFullDocumentDiagnosticReportOrUnchangedDocumentDiagnosticReport{
+			FullDocumentDiagnosticReport: &protocol.WorkspaceFullDocumentDiagnosticReport{
+				ResultId: &d.resultID,
+				Items:    d.items,
+				Uri:      protocol.DocumentUri(uri),
+				Version:  version,
+			},
+		})
+	}
+	return report, nil
+}
+
+// buildContext returns the build context of a Dockerfile for context-aware
+// rules such as buildkit/CopyIgnoredFile, or nil if it has none. The context
+// directory is the nearest directory, from the Dockerfile's up to its
+// workspace folder, that has an ignore file.
+func (s *Server) buildContext(filePath string, content []byte, cfg *config.Config) rules.BuildContext {
+	contextDir := s.buildContextDir(filePath)
+	if contextDir == "" {
+		return nil
+	}
+
+	// Heredoc files are created by the Dockerfile itself and never ignored.
+	parseResult, err := dockerfile.Parse(bytes.NewReader(content), cfg)
+	if err != nil {
+		return nil
+	}
+	buildCtx, err := buildcontext.New(contextDir, filePath,
+		buildcontext.WithHeredocFiles(dockerfile.ExtractHeredocFiles(parseResult.Stages)))
+	if err != nil {
+		log.Printf("lsp: failed to create build context for %s: %v", filePath, err)
+		return nil
+	}
+	return buildCtx
+}
+
+// buildContextDir finds the build context directory of a Dockerfile; see
+// [Server.buildContext].
+func (s *Server) buildContextDir(filePath string) string {
+	dir := filepath.Dir(filePath)
+	root := s.workspaceRoot(filePath)
+
+	for {
+		for _, name := range buildcontext.IgnoreFileNames() {
+			if info, err := os.Stat(filepath.Join(dir, name)); err == nil && !info.IsDir() {
+				return dir
+			}
+		}
+		parent := filepath.Dir(dir)
+		if dir == root || parent == dir || !isWithin(root, parent) {
+			return ""
+		}
+		dir = parent
+	}
+}
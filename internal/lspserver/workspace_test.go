@@ -0,0 +1,203 @@
+package lspserver
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tinovyatkin/tally/internal/config"
+	protocol "github.com/tinovyatkin/tally/internal/lsp/protocol"
+)
+
+// writeFiles writes files (by slash-separated relative path) under dir.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+}
+
+// newWorkspaceServer returns a pull-model server with dir as its workspace folder.
+func newWorkspaceServer(t *testing.T, dir string) *Server {
+	t.Helper()
+	s := New()
+	s.configureWorkspace(&protocol.InitializeParams{
+		WorkspaceFolders: &protocol.WorkspaceFoldersOrNull{WorkspaceFolders: &[]*protocol.WorkspaceFolder{
+			{Uri: protocol.URI(pathToURI(dir)), Name: "ws"},
+		}},
+	})
+	s.pushDiagnostics = false
+	return s
+}
+
+func TestBuildContextDir(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		".dockerignore":             "*.txt\n",
+		"app/Dockerfile":            "FROM alpine:3.20\n",
+		"svc/.containerignore":      "tmp\n",
+		"svc/docker/Dockerfile.dev": "FROM alpine:3.20\n",
+	})
+
+	s := newWorkspaceServer(t, dir)
+	assert.Equal(t, dir, s.buildContextDir(filepath.Join(dir, "app", "Dockerfile")))
+	assert.Equal(t, filepath.Join(dir, "svc"), s.buildContextDir(filepath.Join(dir, "svc", "docker", "Dockerfile.dev")))
+
+	// Outside a workspace folder, only the Dockerfile's directory is a candidate.
+	assert.Empty(t, New().buildContextDir(filepath.Join(dir, "app", "Dockerfile")))
+}
+
+func TestServerCacheDir(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	s := newWorkspaceServer(t, dir)
+	dockerfile := filepath.Join(dir, "svc", "Dockerfile")
+
+	// Without a config file, a relative dir is resolved against the workspace folder.
+	cfg := config.Default()
+	cfg.Cache.Dir = "cache"
+	assert.Equal(t, filepath.Join(dir, "cache"), s.cacheDir(cfg, dockerfile))
+
+	// With a config file, it is resolved against the config file's directory.
+	cfg.ConfigFile = filepath.Join(dir, "svc", ".tally.toml")
+	assert.Equal(t, filepath.Join(dir, "svc", "cache"), s.cacheDir(cfg, dockerfile))
+
+	cfg.Cache.Enabled = false
+	assert.Empty(t, s.cacheDir(cfg, dockerfile))
+}
+
+func TestLintContent_CopyIgnoredFile(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		".dockerignore": "*.txt\n",
+	})
+
+	s := newWorkspaceServer(t, dir)
+	uri := pathToURI(filepath.Join(dir, "Dockerfile"))
+	violations := s.lintContent(uri, []byte("FROM alpine:3.20\nCOPY notes.txt /\nCOPY <<EOF /x.txt\nhi\nEOF\n"))
+	var lines []int
+	for _, v := range violations {
+		if v.RuleCode == "buildkit/CopyIgnoredFile" {
+			lines = append(lines, v.Location.Start.Line)
+		}
+	}
+	assert.Equal(t, []int{2}, lines, "heredoc files are not ignored")
+}
+
+func TestWorkspaceDiagnostic(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"Dockerfile":                    "FROM alpine:3.20\nMAINTAINER me@example.com\n",
+		"api/Containerfile":             "FROM alpine:3.20\n",
+		"node_modules/pkg/Dockerfile":   "FROM alpine:3.20\n",
+		"api/.tally.toml":               "",
+		"notes/Dockerfile.md/README.md": "",
+	})
+	s := newWorkspaceServer(t, dir)
+	rootURI := pathToURI(filepath.Join(dir, "Dockerfile"))
+	apiURI := pathToURI(filepath.Join(dir, "api", "Containerfile"))
+	s.documents.Open(apiURI, "dockerfile", 3, "FROM alpine:3.20\nMAINTAINER me@example.com\n")
+
+	pull := func(previous []protocol.PreviousResultId) map[string]protocol.WorkspaceFullDocumentDiagnosticReportOrUnchangedDocumentDiagnosticReport {
+		t.Helper()
+		result, err := s.handleWorkspaceDiagnostic(&protocol.WorkspaceDiagnosticParams{PreviousResultIds: previous})
+		require.NoError(t, err)
+		report, ok := result.(*protocol.WorkspaceDiagnosticReport)
+		require.True(t, ok)
+		items := make(map[string]protocol.WorkspaceFullDocumentDiagnosticReportOrUnchangedDocumentDiagnosticReport)
+		for _, item := range report.Items {
+			if full := item.FullDocumentDiagnosticReport; full != nil {
+				items[string(full.Uri)] = item
+			} else {
+				items[string(item.UnchangedDocumentDiagnosticReport.Uri)] = item
+			}
+		}
+		return items
+	}
+
+	items := pull(nil)
+	require.Len(t, items, 2)
+	root := items[rootURI].FullDocumentDiagnosticReport
+	require.NotNil(t, root)
+	assert.Nil(t, root.Version.Integer, "closed document")
+	assert.NotEmpty(t, root.Items)
+	api := items[apiURI].FullDocumentDiagnosticReport
+	require.NotNil(t, api)
+	require.NotNil(t, api.Version.Integer)
+	assert.Equal(t, int32(3), *api.Version.Integer)
+	assert.NotEmpty(t, api.Items, "open document content is linted")
+
+	previous := []protocol.PreviousResultId{
+		{Uri: protocol.DocumentUri(rootURI), Value: *root.ResultId},
+		{Uri: protocol.DocumentUri(apiURI), Value: *api.ResultId},
+	}
+	items = pull(previous)
+	assert.NotNil(t, items[rootURI].UnchangedDocumentDiagnosticReport)
+	assert.NotNil(t, items[apiURI].UnchangedDocumentDiagnosticReport)
+
+	// A config change makes every report new.
+	s.handleDidChangeWatchedFiles(t.Context(), &protocol.DidChangeWatchedFilesParams{Changes: []*protocol.FileEvent{
+		{Uri: protocol.DocumentUri(pathToURI(filepath.Join(dir, "api", ".tally.toml"))), Type: protocol.FileChangeTypeChanged},
+	}})
+	items = pull(previous)
+	assert.NotNil(t, items[rootURI].FullDocumentDiagnosticReport)
+	assert.NotNil(t, items[apiURI].FullDocumentDiagnosticReport)
+}
+
+func TestDidChangeWatchedFiles(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	s := newWorkspaceServer(t, dir)
+	appURI := pathToURI(filepath.Join(dir, "app", "Dockerfile"))
+	otherURI := pathToURI(filepath.Join(dir, "other", "Dockerfile"))
+	for _, uri := range []string{appURI, otherURI} {
+		s.documents.Open(uri, "dockerfile", 1, "FROM alpine:3.20\n")
+		s.lintCache.set(uri, 1, nil)
+	}
+
+	change := func(path string) {
+		s.handleDidChangeWatchedFiles(t.Context(), &protocol.DidChangeWatchedFilesParams{Changes: []*protocol.FileEvent{
+			{Uri: protocol.DocumentUri(pathToURI(path)), Type: protocol.FileChangeTypeCreated},
+		}})
+	}
+
+	change(filepath.Join(dir, "app", "Dockerfile.txt"))
+	assert.Zero(t, s.configGeneration.Load(), "not a watched file")
+
+	change(filepath.Join(dir, "app", ".dockerignore"))
+	assert.Equal(t, uint64(1), s.configGeneration.Load())
+	_, ok := s.lintCache.get(appURI, 1)
+	assert.False(t, ok, "affected document")
+	_, ok = s.lintCache.get(otherURI, 1)
+	assert.True(t, ok, "document in another directory")
+
+	change(filepath.Join(dir, "tally.toml"))
+	_, ok = s.lintCache.get(otherURI, 1)
+	assert.False(t, ok, "config of a parent directory")
+}
+
+func TestIsWithin(t *testing.T) {
+	t.Parallel()
+	root := filepath.Join(string(filepath.Separator), "ws")
+	assert.True(t, isWithin(root, root))
+	assert.True(t, isWithin(root, filepath.Join(root, "a", "Dockerfile")))
+	assert.True(t, isWithin(root, filepath.Join(root, "..a", "Dockerfile")))
+	assert.False(t, isWithin(root, filepath.Join(string(filepath.Separator), "ws2", "Dockerfile")))
+	assert.False(t, isWithin(root, string(filepath.Separator)))
+}
+
+func TestPathToURI(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "my dir", "Dockerfile")
+	uri := pathToURI(path)
+	assert.Contains(t, uri, "my%20dir")
+	assert.Equal(t, path, uriToPath(uri))
+}
@@ -565,6 +565,181 @@ func TestLSP_FormattingNoChanges(t *testing.T) {
 	assert.True(t, raw == nil || string(raw) == "null", "expected null response for clean document, got: %s", string(raw))
 }
 
+func TestLSP_Hover(t *testing.T) {
+	t.Parallel()
+	ts := startTestServer(t)
+	ts.initialize(t)
+
+	uri := "file:///tmp/test-hover/Dockerfile"
+	ts.openDocument(t, uri, "FROM alpine:3.18\nENV APP_HOME=/app\nMAINTAINER test@example.com\nWORKDIR $APP_HOME\n")
+
+	// Drain push diagnostics from didOpen.
+	ts.waitDiagnostics(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), diagTimeout)
+	defer cancel()
+
+	hoverAt := func(line, character uint32) *hover {
+		t.Helper()
+		var result *hover
+		err := ts.conn.Call(ctx, "textDocument/hover", &textDocumentPositionParams{
+			TextDocument: textDocumentIdentifier{URI: uri},
+			Position:     position{Line: line, Character: character},
+		}).Await(ctx, &result)
+		require.NoError(t, err)
+		return result
+	}
+
+	// Variable reference.
+	h := hoverAt(3, 10)
+	require.NotNil(t, h)
+	assert.Equal(t, "markdown", h.Contents.Kind)
+	assert.Contains(t, h.Contents.Value, "**APP_HOME** = `/app`")
+	assert.Contains(t, h.Contents.Value, "From the ENV on line 2:")
+	require.NotNil(t, h.Range)
+	assert.Equal(t, lspRange{Start: position{Line: 3, Character: 8}, End: position{Line: 3, Character: 17}}, *h.Range)
+
+	// FROM image.
+	h = hoverAt(0, 7)
+	require.NotNil(t, h)
+	assert.Contains(t, h.Contents.Value, "**Image** `alpine:3.18`")
+	assert.Contains(t, h.Contents.Value, "| Repository | library/alpine |")
+
+	// Diagnostic.
+	h = hoverAt(2, 3)
+	require.NotNil(t, h)
+	assert.Contains(t, h.Contents.Value, "**buildkit/MaintainerDeprecated**")
+
+	// Nothing to show.
+	assert.Nil(t, hoverAt(1, 1))
+}
+
+func TestLSP_DefinitionAndReferences(t *testing.T) {
+	t.Parallel()
+	ts := startTestServer(t)
+	ts.initialize(t)
+
+	uri := "file:///tmp/test-definition/Dockerfile"
+	ts.openDocument(t, uri, "FROM golang:1.22 AS builder\nRUN go build -o /app .\n"+
+		"FROM alpine:3.20\nCOPY --from=builder /app /app\n")
+
+	// Drain push diagnostics from didOpen.
+	ts.waitDiagnostics(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), diagTimeout)
+	defer cancel()
+
+	declaration := lspRange{Start: position{Line: 0, Character: 20}, End: position{Line: 0, Character: 27}}
+	consumer := lspRange{Start: position{Line: 3, Character: 12}, End: position{Line: 3, Character: 19}}
+
+	var def location
+	err := ts.conn.Call(ctx, "textDocument/definition", &textDocumentPositionParams{
+		TextDocument: textDocumentIdentifier{URI: uri},
+		Position:     position{Line: 3, Character: 14},
+	}).Await(ctx, &def)
+	require.NoError(t, err)
+	assert.Equal(t, location{URI: uri, Range: declaration}, def)
+
+	var refs []location
+	err = ts.conn.Call(ctx, "textDocument/references", &referenceParams{
+		TextDocument: textDocumentIdentifier{URI: uri},
+		Position:     position{Line: 0, Character: 22},
+		Context:      referenceContext{IncludeDeclaration: true},
+	}).Await(ctx, &refs)
+	require.NoError(t, err)
+	assert.Equal(t, []location{{URI: uri, Range: declaration}, {URI: uri, Range: consumer}}, refs)
+}
+
+func TestLSP_Completion(t *testing.T) {
+	t.Parallel()
+	ts := startTestServer(t)
+	ts.initialize(t)
+
+	uri := "file:///tmp/test-completion/Dockerfile"
+	ts.openDocument(t, uri, "FROM golang:1.22 AS builder\nENV CGO_ENABLED=0\nRUN echo $\n"+
+		"FROM alpine:3.20\nCOPY --from= /app /app\n")
+
+	// Drain push diagnostics from didOpen.
+	ts.waitDiagnostics(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), diagTimeout)
+	defer cancel()
+
+	var vars completionList
+	err := ts.conn.Call(ctx, "textDocument/completion", &textDocumentPositionParams{
+		TextDocument: textDocumentIdentifier{URI: uri},
+		Position:     position{Line: 2, Character: 10},
+	}).Await(ctx, &vars)
+	require.NoError(t, err)
+	require.Len(t, vars.Items, 1)
+	assert.Equal(t, "CGO_ENABLED", vars.Items[0].Label)
+	assert.Equal(t, "ENV", vars.Items[0].Detail)
+
+	var stages completionList
+	err = ts.conn.Call(ctx, "textDocument/completion", &textDocumentPositionParams{
+		TextDocument: textDocumentIdentifier{URI: uri},
+		Position:     position{Line: 4, Character: 12},
+	}).Await(ctx, &stages)
+	require.NoError(t, err)
+	require.Len(t, stages.Items, 1)
+	assert.Equal(t, "builder", stages.Items[0].Label)
+	require.NotNil(t, stages.Items[0].TextEdit)
+	assert.Equal(t, "builder", stages.Items[0].TextEdit.NewText)
+}
+
+func TestLSP_WorkspaceDiagnostic(t *testing.T) {
+	t.Parallel()
+	ts := startTestServer(t)
+
+	tmpDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "api"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "Dockerfile"), []byte("FROM alpine:3.18\nMAINTAINER test@example.com\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "api", "Containerfile"), []byte("FROM alpine:3.18\n"), 0o644))
+
+	rootPath := filepath.ToSlash(tmpDir)
+	if !strings.HasPrefix(rootPath, "/") {
+		rootPath = "/" + rootPath
+	}
+	rootURI := (&url.URL{Scheme: "file", Path: rootPath}).String()
+	ts.initializeRoot(t, &rootURI)
+
+	ctx, cancel := context.WithTimeout(context.Background(), diagTimeout)
+	defer cancel()
+
+	var report workspaceDiagnosticReport
+	err := ts.conn.Call(ctx, "workspace/diagnostic", &workspaceDiagnosticParams{
+		PreviousResultIDs: []previousResultID{},
+	}).Await(ctx, &report)
+	require.NoError(t, err)
+	require.Len(t, report.Items, 2)
+
+	byURI := make(map[string]workspaceDocumentDiagnosticReport)
+	for _, item := range report.Items {
+		assert.Equal(t, "full", item.Kind)
+		assert.Nil(t, item.Version, "closed documents have no version")
+		byURI[item.URI] = item
+	}
+	root := byURI[rootURI+"/Dockerfile"]
+	codes := make([]string, 0, len(root.Items))
+	for _, d := range root.Items {
+		codes = append(codes, d.Code)
+	}
+	assert.Contains(t, codes, "buildkit/MaintainerDeprecated")
+	require.Contains(t, byURI, rootURI+"/api/Containerfile")
+
+	var again workspaceDiagnosticReport
+	err = ts.conn.Call(ctx, "workspace/diagnostic", &workspaceDiagnosticParams{
+		PreviousResultIDs: []previousResultID{{URI: rootURI + "/Dockerfile", Value: root.ResultID}},
+	}).Await(ctx, &again)
+	require.NoError(t, err)
+	for _, item := range again.Items {
+		if item.URI == rootURI+"/Dockerfile" {
+			assert.Equal(t, "unchanged", item.Kind)
+			assert.Equal(t, root.ResultID, item.ResultID)
+		}
+	}
+}
+
 func TestLSP_MethodNotFound(t *testing.T) {
 	t.Parallel()
 	ts := startTestServer(t)
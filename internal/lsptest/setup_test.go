@@ -149,7 +149,7 @@ func startTestServer(t *testing.T) *testServer {
 	t.Helper()
 
 	cmd := exec.Command(binaryPath, "lsp", "--stdio")
-	cmd.Env = append(os.Environ(), "GOCOVERDIR="+coverageDir)
+	cmd.Env = append(os.Environ(), "GOCOVERDIR="+coverageDir, "TALLY_CACHE_DIR="+t.TempDir())
 
 	stdin, err := cmd.StdinPipe()
 	require.NoError(t, err)
@@ -201,6 +201,12 @@ func startTestServer(t *testing.T) *testServer {
 
 // initialize sends initialize + initialized and returns the server capabilities.
 func (ts *testServer) initialize(t *testing.T) initializeResult {
+	t.Helper()
+	return ts.initializeRoot(t, nil)
+}
+
+// initializeRoot is like initialize, opening rootURI as the workspace.
+func (ts *testServer) initializeRoot(t *testing.T, rootURI *string) initializeResult {
 	t.Helper()
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -208,7 +214,7 @@ func (ts *testServer) initialize(t *testing.T) initializeResult {
 	var result initializeResult
 	err := ts.conn.Call(ctx, "initialize", &initializeParams{
 		ProcessID:    nil,
-		RootURI:      nil,
+		RootURI:      rootURI,
 		Capabilities: jsontext.Value(`{}`),
 		ClientInfo: &clientInfo{
 			Name:    "tally-lsptest",
@@ -437,6 +443,27 @@ type fullDocumentDiagnosticReport struct {
 	Items    []diagnostic `json:"items"`
 }
 
+type workspaceDiagnosticParams struct {
+	PreviousResultIDs []previousResultID `json:"previousResultIds"`
+}
+
+type previousResultID struct {
+	URI   string `json:"uri"`
+	Value string `json:"value"`
+}
+
+type workspaceDiagnosticReport struct {
+	Items []workspaceDocumentDiagnosticReport `json:"items"`
+}
+
+type workspaceDocumentDiagnosticReport struct {
+	Kind     string       `json:"kind"`
+	URI      string       `json:"uri"`
+	Version  *int32       `json:"version"`
+	ResultID string       `json:"resultId,omitempty"`
+	Items    []diagnostic `json:"items"`
+}
+
 type unchangedDocumentDiagnosticReport struct {
 	Kind     string `json:"kind"`
 	ResultID string `json:"resultId"`
@@ -500,6 +527,55 @@ func validateNonOverlapping(t *testing.T, edits []lsp.TextEdit) {
 	}
 }
 
+// Navigation types (textDocument/definition, textDocument/references).
+
+// textDocumentPositionParams are the params of definition and hover requests.
+type textDocumentPositionParams struct {
+	TextDocument textDocumentIdentifier `json:"textDocument"`
+	Position     position               `json:"position"`
+}
+
+type referenceParams struct {
+	TextDocument textDocumentIdentifier `json:"textDocument"`
+	Position     position               `json:"position"`
+	Context      referenceContext       `json:"context"`
+}
+
+type referenceContext struct {
+	IncludeDeclaration bool `json:"includeDeclaration"`
+}
+
+type location struct {
+	URI   string   `json:"uri"`
+	Range lspRange `json:"range"`
+}
+
+// Hover types (textDocument/hover).
+
+type hover struct {
+	Contents markupContent `json:"contents"`
+	Range    *lspRange     `json:"range,omitempty"`
+}
+
+type markupContent struct {
+	Kind  string `json:"kind"`
+	Value string `json:"value"`
+}
+
+// Completion types (textDocument/completion).
+
+type completionList struct {
+	IsIncomplete bool             `json:"isIncomplete"`
+	Items        []completionItem `json:"items"`
+}
+
+type completionItem struct {
+	Label    string    `json:"label"`
+	Kind     int       `json:"kind"`
+	Detail   string    `json:"detail,omitempty"`
+	TextEdit *textEdit `json:"textEdit,omitempty"`
+}
+
 // Formatting types (textDocument/formatting).
 
 type documentFormattingParams struct {
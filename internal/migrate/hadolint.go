@@ -0,0 +1,288 @@
+// Package migrate converts configuration files of other Dockerfile linters
+// into tally configuration.
+package migrate
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+
+	"github.com/tinovyatkin/tally/internal/rules"
+)
+
+// HadolintConfigNames are the file names hadolint looks for in a project
+// directory, in order of preference.
+var HadolintConfigNames = []string{".hadolint.yaml", ".hadolint.yml"}
+
+// hadolintSeverities are the override levels hadolint supports, in the order
+// they are applied (later levels win, like in hadolint).
+var hadolintSeverities = []string{"style", "info", "warning", "error"}
+
+// HadolintConfig is the subset of the hadolint configuration file format
+// (https://github.com/hadolint/hadolint#configure) that can be migrated.
+type HadolintConfig struct {
+	FailureThreshold    string              `yaml:"failure-threshold"`
+	NoFail              bool                `yaml:"no-fail"`
+	Format              string              `yaml:"format"`
+	Ignored             []string            `yaml:"ignored"`
+	Override            map[string][]string `yaml:"override"`
+	TrustedRegistries   []string            `yaml:"trustedRegistries"`
+	LabelSchema         map[string]string   `yaml:"label-schema"`
+	StrictLabels        bool                `yaml:"strict-labels"`
+	DisableIgnorePragma bool                `yaml:"disable-ignore-pragma"`
+}
+
+// HadolintResult is a tally configuration equivalent to a hadolint config.
+type HadolintResult struct {
+	// FailLevel is the output.fail-level setting, or "" to keep the default.
+	FailLevel string
+
+	// Format is the output.format setting, or "" to keep the default.
+	Format string
+
+	// Exclude lists rule codes to disable.
+	Exclude []string
+
+	// Severity maps rule codes to severity overrides.
+	Severity map[string]string
+
+	// TrustedRegistries configures hadolint/DL3026.
+	TrustedRegistries []string
+
+	// LabelSchema is the [label-schema] table: label keys and their value types.
+	LabelSchema map[string]string
+
+	// Unsupported describes settings and rules that could not be migrated.
+	Unsupported []string
+}
+
+// FindHadolintConfig resolves path to a hadolint config file. Directories are
+// searched for the file names in HadolintConfigNames.
+func FindHadolintConfig(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		return path, nil
+	}
+	for _, name := range HadolintConfigNames {
+		candidate := filepath.Join(path, name)
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("no %s found in %s", strings.Join(HadolintConfigNames, " or "), path)
+}
+
+// FromHadolint converts the contents of a hadolint config file.
+func FromHadolint(data []byte) (*HadolintResult, error) {
+	var raw map[string]any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parse hadolint config: %w", err)
+	}
+	var hc HadolintConfig
+	if err := yaml.Unmarshal(data, &hc); err != nil {
+		return nil, fmt.Errorf("parse hadolint config: %w", err)
+	}
+
+	res := &HadolintResult{Severity: make(map[string]string)}
+	for _, key := range slices.Sorted(maps.Keys(raw)) {
+		switch key {
+		case "failure-threshold", "no-fail", "format", "ignored", "override", "trustedRegistries",
+			"label-schema", "strict-labels":
+			// Migrated below.
+		case "disable-ignore-pragma":
+			if hc.DisableIgnorePragma {
+				res.unsupported("disable-ignore-pragma: tally cannot disable only # hadolint comments; " +
+					"set [inline-directives] enabled = false to ignore all inline directives")
+			}
+		case "no-color", "verbose":
+			// Presentation-only settings without a config equivalent.
+			if raw[key] == true {
+				res.unsupported("%s: not configurable in tally", key)
+			}
+		default:
+			res.unsupported("%s: unknown hadolint setting", key)
+		}
+	}
+
+	res.migrateFailLevel(hc)
+	res.migrateFormat(hc.Format)
+
+	for _, code := range hc.Ignored {
+		if rule := res.ruleCode(code, "ignored"); rule != "" && !slices.Contains(res.Exclude, rule) {
+			res.Exclude = append(res.Exclude, rule)
+		}
+	}
+	slices.Sort(res.Exclude)
+
+	for level := range hc.Override {
+		if !slices.Contains(hadolintSeverities, level) {
+			res.unsupported("override.%s: unknown severity", level)
+		}
+	}
+	for _, level := range hadolintSeverities {
+		for _, code := range hc.Override[level] {
+			if rule := res.ruleCode(code, "override."+level); rule != "" {
+				res.Severity[rule] = level
+			}
+		}
+	}
+
+	if len(hc.TrustedRegistries) > 0 {
+		res.TrustedRegistries = slices.Clone(hc.TrustedRegistries)
+	}
+	res.migrateLabelSchema(hc)
+	return res, nil
+}
+
+func (r *HadolintResult) unsupported(format string, args ...any) {
+	r.Unsupported = append(r.Unsupported, fmt.Sprintf(format, args...))
+}
+
+func (r *HadolintResult) migrateFailLevel(hc HadolintConfig) {
+	if hc.NoFail {
+		r.FailLevel = "none"
+		return
+	}
+	switch level := strings.ToLower(hc.FailureThreshold); level {
+	case "":
+	case "error", "warning", "info", "style":
+		r.FailLevel = level
+	case "ignore", "none":
+		r.FailLevel = "none"
+	default:
+		r.unsupported("failure-threshold: unknown value %q", hc.FailureThreshold)
+	}
+}
+
+func (r *HadolintResult) migrateFormat(format string) {
+	switch format {
+	case "", "tty":
+		// tally's default text output.
+	case "json", "sarif":
+		r.Format = format
+	default:
+		r.unsupported("format: %q output is not supported (available: text, json, sarif, github-actions, markdown)", format)
+	}
+}
+
+// migrateLabelSchema copies the label schema, whose value types tally shares
+// with hadolint. strict-labels enables DL3050 (off by default in tally) at
+// hadolint's severity, unless an override already sets one.
+func (r *HadolintResult) migrateLabelSchema(hc HadolintConfig) {
+	for _, key := range slices.Sorted(maps.Keys(hc.LabelSchema)) {
+		typ := hc.LabelSchema[key]
+		if !slices.Contains(rules.LabelTypes, typ) {
+			r.unsupported("label-schema.%s: unknown label type %q (available: %s)", key, typ, strings.Join(rules.LabelTypes, ", "))
+			continue
+		}
+		if r.LabelSchema == nil {
+			r.LabelSchema = make(map[string]string)
+		}
+		r.LabelSchema[key] = typ
+	}
+	if hc.StrictLabels {
+		if _, ok := r.Severity[dl3050]; !ok {
+			r.Severity[dl3050] = "info"
+		}
+	}
+}
+
+// ruleCode maps a hadolint rule code to the tally rule reporting it.
+// Unsupported rules are recorded and yield "".
+func (r *HadolintResult) ruleCode(code, key string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if strings.HasPrefix(code, "SC") {
+		rule := rules.ShellcheckRulePrefix + code
+		if !rules.DefaultRegistry().Has(rule) {
+			r.unsupported("%s: %s is a ShellCheck rule that tally does not implement", key, code)
+			return ""
+		}
+		return rule
+	}
+	rule := rules.HadolintStatus(code).RuleCode()
+	if rule == "" {
+		r.unsupported("%s: %s is not implemented in tally", key, code)
+	}
+	return rule
+}
+
+// TOML renders the result as a .tally.toml document. source is the migrated
+// file name, recorded in the header comment.
+func (r *HadolintResult) TOML(source string) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "# Migrated from %s by tally migrate hadolint.\n", filepath.ToSlash(source))
+
+	if r.FailLevel != "" || r.Format != "" {
+		b.WriteString("\n[output]\n")
+		if r.Format != "" {
+			fmt.Fprintf(&b, "format = %s\n", tomlString(r.Format))
+		}
+		if r.FailLevel != "" {
+			fmt.Fprintf(&b, "fail-level = %s\n", tomlString(r.FailLevel))
+		}
+	}
+
+	if len(r.LabelSchema) > 0 {
+		b.WriteString("\n[label-schema]\n")
+		for _, key := range slices.Sorted(maps.Keys(r.LabelSchema)) {
+			fmt.Fprintf(&b, "%s = %s\n", tomlString(key), tomlString(r.LabelSchema[key]))
+		}
+	}
+
+	if len(r.Exclude) > 0 {
+		fmt.Fprintf(&b, "\n[rules]\nexclude = %s\n", tomlStringArray(r.Exclude))
+	}
+
+	ruleTables := slices.Collect(maps.Keys(r.Severity))
+	if len(r.TrustedRegistries) > 0 && !slices.Contains(ruleTables, dl3026) {
+		ruleTables = append(ruleTables, dl3026)
+	}
+	slices.Sort(ruleTables)
+	for _, code := range ruleTables {
+		ns, name, _ := strings.Cut(code, "/")
+		fmt.Fprintf(&b, "\n[rules.%s.%s]\n", ns, name)
+		if sev, ok := r.Severity[code]; ok {
+			fmt.Fprintf(&b, "severity = %s\n", tomlString(sev))
+		}
+		if code == dl3026 {
+			fmt.Fprintf(&b, "trusted-registries = %s\n", tomlStringArray(r.TrustedRegistries))
+		}
+	}
+
+	if len(r.Unsupported) > 0 {
+		b.WriteString("\n# Not migrated:\n")
+		for _, msg := range r.Unsupported {
+			fmt.Fprintf(&b, "#   %s\n", msg)
+		}
+	}
+	return b.Bytes()
+}
+
+const (
+	dl3026 = rules.HadolintRulePrefix + "DL3026"
+	dl3050 = rules.HadolintRulePrefix + "DL3050"
+)
+
+// tomlString quotes s as a TOML basic string. Go's quoting is compatible
+// for the printable strings found in hadolint configs.
+func tomlString(s string) string {
+	return strconv.Quote(s)
+}
+
+func tomlStringArray(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = tomlString(v)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
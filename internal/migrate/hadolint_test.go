@@ -0,0 +1,182 @@
+package migrate
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/tinovyatkin/tally/internal/config"
+	"github.com/tinovyatkin/tally/internal/rules"
+	_ "github.com/tinovyatkin/tally/internal/rules/all"
+)
+
+const sampleHadolintConfig = `
+failure-threshold: info
+ignored:
+  - DL3000
+  - DL3007
+  - SC2086
+  - SC2016
+  - DL9999
+override:
+  error:
+    - DL3006
+  style:
+    - DL3002
+trustedRegistries:
+  - docker.io
+  - registry.example.com:5000
+label-schema:
+  org.opencontainers.image.version: semver
+  author: text
+  build: number
+strict-labels: true
+`
+
+func TestFromHadolint(t *testing.T) {
+	t.Parallel()
+
+	res, err := FromHadolint([]byte(sampleHadolintConfig))
+	if err != nil {
+		t.Fatalf("FromHadolint() error = %v", err)
+	}
+
+	if res.FailLevel != "info" {
+		t.Errorf("FailLevel = %q, want info", res.FailLevel)
+	}
+	wantExclude := []string{"buildkit/WorkdirRelativePath", "hadolint/DL3007", "shellcheck/SC2086"}
+	if !slices.Equal(res.Exclude, wantExclude) {
+		t.Errorf("Exclude = %v, want %v", res.Exclude, wantExclude)
+	}
+	if res.Severity["hadolint/DL3006"] != "error" || res.Severity["hadolint/DL3002"] != "style" {
+		t.Errorf("Severity = %v", res.Severity)
+	}
+	if len(res.TrustedRegistries) != 2 {
+		t.Errorf("TrustedRegistries = %v", res.TrustedRegistries)
+	}
+
+	wantSchema := map[string]string{"org.opencontainers.image.version": "semver", "author": "text"}
+	if !maps.Equal(res.LabelSchema, wantSchema) {
+		t.Errorf("LabelSchema = %v, want %v", res.LabelSchema, wantSchema)
+	}
+	if res.Severity["hadolint/DL3050"] != "info" {
+		t.Errorf("strict-labels should enable hadolint/DL3050, got %v", res.Severity)
+	}
+
+	unsupported := strings.Join(res.Unsupported, "\n")
+	for _, want := range []string{"SC2016", "DL9999", "label-schema.build"} {
+		if !strings.Contains(unsupported, want) {
+			t.Errorf("Unsupported should mention %s, got:\n%s", want, unsupported)
+		}
+	}
+}
+
+func TestFromHadolint_FailLevel(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		yaml string
+		want string
+	}{
+		{yaml: "failure-threshold: error", want: "error"},
+		{yaml: "failure-threshold: ignore", want: "none"},
+		{yaml: "failure-threshold: style\nno-fail: true", want: "none"},
+		{yaml: "ignored: []", want: ""},
+	}
+	for _, tt := range tests {
+		res, err := FromHadolint([]byte(tt.yaml))
+		if err != nil {
+			t.Fatalf("FromHadolint(%q) error = %v", tt.yaml, err)
+		}
+		if res.FailLevel != tt.want {
+			t.Errorf("FromHadolint(%q).FailLevel = %q, want %q", tt.yaml, res.FailLevel, tt.want)
+		}
+	}
+}
+
+func TestFromHadolint_InvalidYAML(t *testing.T) {
+	t.Parallel()
+	if _, err := FromHadolint([]byte("ignored: [DL3000")); err == nil {
+		t.Error("expected error for invalid YAML")
+	}
+}
+
+func TestHadolintResult_TOMLLoads(t *testing.T) {
+	t.Parallel()
+
+	res, err := FromHadolint([]byte(sampleHadolintConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), ".tally.toml")
+	if err := os.WriteFile(path, res.TOML(".hadolint.yaml"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := config.LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("generated config does not load: %v", err)
+	}
+	if cfg.Output.FailLevel != "info" {
+		t.Errorf("fail-level = %q, want info", cfg.Output.FailLevel)
+	}
+	if enabled := cfg.Rules.IsEnabled("hadolint/DL3007"); enabled == nil || *enabled {
+		t.Error("hadolint/DL3007 should be excluded")
+	}
+	if got := cfg.Rules.GetSeverity("hadolint/DL3006"); got != "error" {
+		t.Errorf("DL3006 severity = %q, want error", got)
+	}
+	if got := cfg.LabelSchema["org.opencontainers.image.version"]; got != "semver" {
+		t.Errorf("label-schema = %v, want the dotted key to be kept", cfg.LabelSchema)
+	}
+	if got := cfg.Rules.GetSeverity("hadolint/DL3050"); got != "info" {
+		t.Errorf("DL3050 severity = %q, want info", got)
+	}
+	opts := cfg.Rules.GetOptions("hadolint/DL3026")
+	if regs, ok := opts["trusted-registries"].([]any); !ok || len(regs) != 2 {
+		t.Errorf("DL3026 options = %v, want two trusted registries", opts)
+	}
+}
+
+func TestFindHadolintConfig(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	if _, err := FindHadolintConfig(dir); err == nil {
+		t.Error("expected error for directory without hadolint config")
+	}
+
+	path := filepath.Join(dir, ".hadolint.yml")
+	if err := os.WriteFile(path, []byte("ignored: []\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := FindHadolintConfig(dir)
+	if err != nil || got != path {
+		t.Errorf("FindHadolintConfig() = %q, %v; want %q", got, err, path)
+	}
+}
+
+// Every rule the status file maps to must exist, or migrated configs would
+// reference unknown rules.
+func TestHadolintStatusRulesExist(t *testing.T) {
+	t.Parallel()
+	for _, code := range []string{"DL3000", "DL3006", "DL3059", "DL4000"} {
+		rule := rules.HadolintStatus(code).RuleCode()
+		if rule == "" {
+			t.Errorf("%s should be supported", code)
+		}
+	}
+	for _, ri := range rules.DefaultRegistry().All() {
+		code := ri.Metadata().Code
+		name, ok := strings.CutPrefix(code, rules.HadolintRulePrefix)
+		if !ok {
+			continue
+		}
+		if got := rules.HadolintStatus(name).RuleCode(); got != code {
+			t.Errorf("HadolintStatus(%s).RuleCode() = %q, want %q", name, got, code)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package processor
+
+import (
+	"github.com/tinovyatkin/tally/internal/baseline"
+	"github.com/tinovyatkin/tally/internal/rules"
+)
+
+// BaselineFilter removes violations recorded in a baseline file, so that only
+// new violations are reported.
+//
+// NOTE: This processor is stateful - entries that no longer match any violation
+// are stored and must be retrieved via Fixed() after Process() completes. The
+// state is reset on each Process() call.
+type BaselineFilter struct {
+	baseline *baseline.Baseline
+	fixed    []baseline.Entry
+}
+
+// NewBaselineFilter creates a baseline filter for the given baseline.
+func NewBaselineFilter(b *baseline.Baseline) *BaselineFilter {
+	return &BaselineFilter{baseline: b}
+}
+
+// Name returns the processor's identifier.
+func (p *BaselineFilter) Name() string {
+	return "baseline-filter"
+}
+
+// Process removes baselined violations and records baseline entries of the
+// linted files that matched fewer violations than recorded.
+func (p *BaselineFilter) Process(violations []rules.Violation, ctx *Context) []rules.Violation {
+	m := p.baseline.NewMatcher()
+	for file := range ctx.FileSources {
+		m.MarkLinted(file)
+	}
+	result := filterViolations(violations, func(v rules.Violation) bool {
+		return !m.Match(v, ctx.GetSourceMap(v.Location.File))
+	})
+	p.fixed = m.Fixed()
+	return result
+}
+
+// Fixed returns the baseline entries that are fixed in the linted files.
+func (p *BaselineFilter) Fixed() []baseline.Entry {
+	return p.fixed
+}
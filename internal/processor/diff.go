@@ -0,0 +1,35 @@
+package processor
+
+import (
+	"github.com/tinovyatkin/tally/internal/gitdiff"
+	"github.com/tinovyatkin/tally/internal/rules"
+)
+
+// DiffFilter keeps only violations on lines changed relative to a git
+// revision. A violation is kept if any line of its range changed.
+// File-level violations are kept for changed files when keepFileLevel is set.
+type DiffFilter struct {
+	changes       *gitdiff.Changes
+	keepFileLevel bool
+}
+
+// NewDiffFilter creates a diff filter for the given changes.
+func NewDiffFilter(changes *gitdiff.Changes, keepFileLevel bool) *DiffFilter {
+	return &DiffFilter{changes: changes, keepFileLevel: keepFileLevel}
+}
+
+// Name returns the processor's identifier.
+func (p *DiffFilter) Name() string {
+	return "diff-filter"
+}
+
+// Process removes violations outside changed lines.
+func (p *DiffFilter) Process(violations []rules.Violation, _ *Context) []rules.Violation {
+	return filterViolations(violations, func(v rules.Violation) bool {
+		loc := v.Location
+		if loc.IsFileLevel() {
+			return p.keepFileLevel && p.changes.FileChanged(loc.File)
+		}
+		return p.changes.RangeChanged(loc.File, loc.Start.Line, loc.End.Line)
+	})
+}
@@ -2,6 +2,7 @@ package processor
 
 import (
 	"path/filepath"
+	"strings"
 
 	"github.com/tinovyatkin/tally/internal/directive"
 	"github.com/tinovyatkin/tally/internal/rules"
@@ -14,6 +15,7 @@ import (
 //   - Parse errors in directives
 //   - Unused directives (if WarnUnused is enabled)
 //   - Missing reason= (if RequireReason is enabled)
+//   - Disable blocks without a matching enable directive
 //
 // NOTE: This processor is stateful - it stores additional violations that must
 // be retrieved via AdditionalViolations() after Process() completes. The state
@@ -164,21 +166,29 @@ func (p *InlineDirectiveFilter) processFile(
 
 		// Report unused directives if configured
 		if cfg.InlineDirectives.WarnUnused {
-			for _, unused := range filterResult.UnusedDirectives {
-				p.additionalViolations = append(p.additionalViolations, rules.NewViolation(
-					rules.NewLineLocation(file, unused.Line+1),
-					"unused-ignore-directive",
-					"ignore directive does not suppress any violations",
-					rules.SeverityWarning,
-				).WithDetail("Directive: "+unused.RawText))
-			}
+			p.reportUnused(file, filterResult.UnusedDirectives)
+		}
+	}
+
+	// Report disable blocks without a matching enable directive
+	for _, d := range directiveResult.Directives {
+		if d.Unterminated {
+			p.additionalViolations = append(p.additionalViolations, rules.NewViolation(
+				rules.NewLineLocation(file, d.Line+1),
+				"unterminated-disable-directive",
+				"disable directive has no matching enable directive; it applies to the end of the file",
+				rules.SeverityWarning,
+			).WithDetail("Directive: "+d.RawText))
 		}
 	}
 
 	// Report directives without reason if configured
 	if cfg.InlineDirectives.RequireReason {
+		// Split range directives share a line; report each comment once.
+		reported := make(map[int]bool)
 		for _, d := range directiveResult.Directives {
-			if d.Source != directive.SourceBuildx && d.Reason == "" {
+			if d.Source != directive.SourceBuildx && d.Reason == "" && !reported[d.Line] {
+				reported[d.Line] = true
 				p.additionalViolations = append(p.additionalViolations, rules.NewViolation(
 					rules.NewLineLocation(file, d.Line+1),
 					"missing-directive-reason",
@@ -191,3 +201,36 @@ func (p *InlineDirectiveFilter) processFile(
 
 	return violations
 }
+
+// reportUnused adds warnings for directives that suppressed nothing.
+// A disable block that was partially re-enabled is split into several range
+// directives; their unused rules are reported once per comment.
+func (p *InlineDirectiveFilter) reportUnused(file string, unused []directive.Directive) {
+	unusedRanges := make(map[int][]string)
+	var rangeLines []int
+	rawText := make(map[int]string)
+	for _, d := range unused {
+		if d.Type == directive.TypeRange {
+			if _, seen := unusedRanges[d.Line]; !seen {
+				rangeLines = append(rangeLines, d.Line)
+				rawText[d.Line] = d.RawText
+			}
+			unusedRanges[d.Line] = append(unusedRanges[d.Line], d.Rules...)
+			continue
+		}
+		p.additionalViolations = append(p.additionalViolations, rules.NewViolation(
+			rules.NewLineLocation(file, d.Line+1),
+			"unused-ignore-directive",
+			"ignore directive does not suppress any violations",
+			rules.SeverityWarning,
+		).WithDetail("Directive: "+d.RawText))
+	}
+	for _, line := range rangeLines {
+		p.additionalViolations = append(p.additionalViolations, rules.NewViolation(
+			rules.NewLineLocation(file, line+1),
+			"unused-ignore-directive",
+			"disable directive does not suppress any violations for: "+strings.Join(unusedRanges[line], ", "),
+			rules.SeverityWarning,
+		).WithDetail("Directive: "+rawText[line]))
+	}
+}
@@ -0,0 +1,51 @@
+package processor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tinovyatkin/tally/internal/config"
+	"github.com/tinovyatkin/tally/internal/rules"
+)
+
+func TestInlineDirectiveFilter_Ranges(t *testing.T) {
+	t.Parallel()
+	source := `FROM alpine
+# tally disable=DL3018,DL3019
+RUN apk add curl
+# tally enable=DL3018
+RUN apk add git
+# tally disable=max-lines
+`
+	cfg := config.Default()
+	cfg.InlineDirectives.WarnUnused = true
+	cfg.InlineDirectives.RequireReason = true
+	ctx := NewContext(nil, cfg, map[string][]byte{"Dockerfile": []byte(source)})
+
+	violations := []rules.Violation{
+		rules.NewViolation(rules.NewLineLocation("Dockerfile", 3), "hadolint/DL3018", "pin", rules.SeverityWarning),
+		rules.NewViolation(rules.NewLineLocation("Dockerfile", 5), "hadolint/DL3018", "pin", rules.SeverityWarning),
+	}
+
+	p := NewInlineDirectiveFilter()
+	result := p.Process(violations, ctx)
+	if len(result) != 1 || result[0].Line() != 5 {
+		t.Fatalf("expected only the line 5 violation to remain, got %+v", result)
+	}
+
+	got := make(map[string][]string)
+	for _, v := range p.AdditionalViolations() {
+		got[v.RuleCode] = append(got[v.RuleCode], v.Message)
+	}
+
+	if unused := got["unused-ignore-directive"]; len(unused) != 2 ||
+		!strings.HasSuffix(unused[0], "for: DL3019") || !strings.HasSuffix(unused[1], "for: max-lines") {
+		t.Errorf("unused-ignore-directive = %q, want DL3019 and max-lines reported once each", unused)
+	}
+	if n := len(got["unterminated-disable-directive"]); n != 2 {
+		t.Errorf("expected 2 unterminated-disable-directive warnings, got %d", n)
+	}
+	if n := len(got["missing-directive-reason"]); n != 2 {
+		t.Errorf("expected 2 missing-directive-reason warnings (one per comment), got %d", n)
+	}
+}
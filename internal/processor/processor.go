@@ -11,8 +11,10 @@
 //  4. PathExclusionFilter - Remove per-rule path exclusions
 //  5. InlineDirectiveFilter - Apply # tally ignore=... etc.
 //  6. Deduplication - Remove duplicate violations
-//  7. Sorting - Stable output ordering
-//  8. SnippetAttachment - Populate SourceCode field
+//  7. DiffFilter - Remove violations outside changed lines (optional)
+//  8. BaselineFilter - Remove violations recorded in a baseline (optional)
+//  9. Sorting - Stable output ordering
+//  10. SnippetAttachment - Populate SourceCode field
 package processor
 
 import (
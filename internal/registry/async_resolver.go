@@ -30,6 +30,7 @@ func (r *AsyncImageResolver) ID() string { return registryResolverID }
 //   - NotFoundError: no retry (permanent)
 //   - AuthError: retry once after backoff
 //   - NetworkError / other: retry with exponential backoff (up to 3 total attempts)
+//   - ErrOffline: no retry (offline mode cache miss)
 func (r *AsyncImageResolver) Resolve(ctx context.Context, data any) (any, error) {
 	req, ok := data.(*ResolveRequest)
 	if !ok {
@@ -59,6 +60,11 @@ func (r *AsyncImageResolver) resolveWithRetry(ctx context.Context, ref, platform
 			return cfg, nil
 		}
 
+		// Offline cache miss: retrying cannot help.
+		if errors.Is(err, ErrOffline) {
+			return ImageConfig{}, backoff.Permanent(err)
+		}
+
 		// PlatformMismatchError: not a skip, return partial config for rule to handle.
 		var platErr *PlatformMismatchError
 		if errors.As(err, &platErr) {
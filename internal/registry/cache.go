@@ -0,0 +1,176 @@
+package registry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json/v2"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultCacheTTL is how long cached image metadata is reused before the
+// registry is queried again.
+const DefaultCacheTTL = 24 * time.Hour
+
+// ErrOffline is wrapped in the NetworkError returned for images that are not
+// cached while running in offline mode.
+var ErrOffline = errors.New("offline mode: image metadata not cached")
+
+// CachingResolver wraps an ImageResolver with a persistent on-disk cache of
+// resolved image metadata, keyed by ref and platform.
+//
+// Successful resolutions and platform mismatches are cached; auth, network
+// and not-found errors are not. In offline mode the inner resolver is never
+// called: cached entries are used regardless of age and everything else fails
+// with a NetworkError wrapping ErrOffline.
+type CachingResolver struct {
+	inner   ImageResolver
+	dir     string
+	ttl     time.Duration
+	offline bool
+	now     func() time.Time
+}
+
+// CacheOption configures a CachingResolver.
+type CacheOption func(*CachingResolver)
+
+// WithCacheTTL sets how long cached entries are fresh. Zero or negative values
+// make every online lookup hit the registry (entries are still written for
+// later offline use).
+func WithCacheTTL(ttl time.Duration) CacheOption {
+	return func(r *CachingResolver) { r.ttl = ttl }
+}
+
+// WithOffline enables offline mode.
+func WithOffline(offline bool) CacheOption {
+	return func(r *CachingResolver) { r.offline = offline }
+}
+
+// NewCachingResolver creates a caching resolver storing entries in dir.
+// inner may be nil, in which case the resolver behaves as if offline.
+// An empty dir disables persistence.
+func NewCachingResolver(inner ImageResolver, dir string, opts ...CacheOption) *CachingResolver {
+	r := &CachingResolver{
+		inner: inner,
+		dir:   dir,
+		ttl:   DefaultCacheTTL,
+		now:   time.Now,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// cacheEntry is the on-disk representation of a resolution result.
+type cacheEntry struct {
+	Ref       string      `json:"ref"`
+	Platform  string      `json:"platform"`
+	FetchedAt time.Time   `json:"fetchedAt"`
+	Config    ImageConfig `json:"config"`
+
+	// Mismatch records a PlatformMismatchError; Available lists the
+	// platforms the image does provide.
+	Mismatch  bool     `json:"mismatch,omitempty"`
+	Available []string `json:"available,omitempty"`
+}
+
+// ResolveConfig implements ImageResolver.
+func (r *CachingResolver) ResolveConfig(ctx context.Context, ref, platform string) (ImageConfig, error) {
+	entry, cached := r.load(ref, platform)
+	if cached && (r.offline || r.inner == nil || r.now().Sub(entry.FetchedAt) < r.ttl) {
+		return entry.result()
+	}
+	if r.offline || r.inner == nil {
+		return ImageConfig{}, &NetworkError{Err: fmt.Errorf("%s (%s): %w", ref, platform, ErrOffline)}
+	}
+
+	cfg, err := r.inner.ResolveConfig(ctx, ref, platform)
+	if err == nil {
+		r.store(&cacheEntry{Ref: ref, Platform: platform, FetchedAt: r.now(), Config: cfg})
+		return cfg, nil
+	}
+
+	var platErr *PlatformMismatchError
+	if errors.As(err, &platErr) {
+		r.store(&cacheEntry{
+			Ref:       ref,
+			Platform:  platform,
+			FetchedAt: r.now(),
+			Config:    cfg,
+			Mismatch:  true,
+			Available: platErr.Available,
+		})
+		return cfg, err
+	}
+
+	// Prefer stale data over no data when the registry is unreachable.
+	var netErr *NetworkError
+	if cached && errors.As(err, &netErr) {
+		return entry.result()
+	}
+	return cfg, err
+}
+
+func (e *cacheEntry) result() (ImageConfig, error) {
+	if e.Mismatch {
+		return e.Config, &PlatformMismatchError{
+			Ref:       e.Ref,
+			Requested: e.Platform,
+			Available: e.Available,
+		}
+	}
+	return e.Config, nil
+}
+
+func (r *CachingResolver) entryPath(ref, platform string) string {
+	sum := sha256.Sum256([]byte(ref + "|" + platform))
+	return filepath.Join(r.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// load reads a cached entry. Missing, corrupt or mismatched entries are misses.
+func (r *CachingResolver) load(ref, platform string) (*cacheEntry, bool) {
+	if r.dir == "" {
+		return nil, false
+	}
+	data, err := os.ReadFile(r.entryPath(ref, platform))
+	if err != nil {
+		return nil, false
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, false
+	}
+	if entry.Ref != ref || entry.Platform != platform {
+		return nil, false
+	}
+	return &entry, true
+}
+
+// store writes an entry atomically. Write failures only cost a future
+// cache miss, so they are ignored.
+func (r *CachingResolver) store(entry *cacheEntry) {
+	if r.dir == "" {
+		return
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(r.dir, 0o755); err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(r.dir, ".tmp-*")
+	if err != nil {
+		return
+	}
+	_, werr := tmp.Write(data)
+	cerr := tmp.Close()
+	if werr != nil || cerr != nil || os.Rename(tmp.Name(), r.entryPath(entry.Ref, entry.Platform)) != nil {
+		_ = os.Remove(tmp.Name())
+	}
+}
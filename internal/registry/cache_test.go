@@ -0,0 +1,195 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tinovyatkin/tally/internal/async"
+)
+
+// countingResolver returns a fixed result and counts calls.
+func countingResolver(calls *atomic.Int32, cfg ImageConfig, err error) *mockImageResolver {
+	return &mockImageResolver{
+		fn: func(context.Context, string, string) (ImageConfig, error) {
+			calls.Add(1)
+			return cfg, err
+		},
+	}
+}
+
+func TestCachingResolver_ReusesFreshEntries(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	var calls atomic.Int32
+	inner := countingResolver(&calls, ImageConfig{OS: "linux", Arch: "amd64", Digest: "sha256:abc", HasHealthcheck: true}, nil)
+
+	r := NewCachingResolver(inner, dir, WithCacheTTL(time.Hour))
+	for range 2 {
+		cfg, err := r.ResolveConfig(context.Background(), "alpine:3.20", "linux/amd64")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.Digest != "sha256:abc" || !cfg.HasHealthcheck {
+			t.Errorf("unexpected config: %+v", cfg)
+		}
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("inner resolver called %d times, want 1", got)
+	}
+
+	// A new resolver (new process) reads the same entry from disk.
+	r2 := NewCachingResolver(inner, dir, WithCacheTTL(time.Hour))
+	if _, err := r2.ResolveConfig(context.Background(), "alpine:3.20", "linux/amd64"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("inner resolver called %d times after reopen, want 1", got)
+	}
+
+	// Different platforms are separate entries.
+	if _, err := r2.ResolveConfig(context.Background(), "alpine:3.20", "linux/arm64"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("inner resolver called %d times, want 2", got)
+	}
+}
+
+func TestCachingResolver_ExpiredEntries(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	var calls atomic.Int32
+	inner := countingResolver(&calls, ImageConfig{OS: "linux"}, nil)
+
+	now := time.Now()
+	r := NewCachingResolver(inner, dir, WithCacheTTL(time.Hour))
+	r.now = func() time.Time { return now }
+	if _, err := r.ResolveConfig(context.Background(), "alpine", "linux/amd64"); err != nil {
+		t.Fatal(err)
+	}
+
+	r.now = func() time.Time { return now.Add(2 * time.Hour) }
+	if _, err := r.ResolveConfig(context.Background(), "alpine", "linux/amd64"); err != nil {
+		t.Fatal(err)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("inner resolver called %d times, want 2 (entry expired)", got)
+	}
+}
+
+func TestCachingResolver_PlatformMismatchCached(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	var calls atomic.Int32
+	inner := countingResolver(&calls, ImageConfig{}, &PlatformMismatchError{
+		Ref: "arm-only", Requested: "linux/amd64", Available: []string{"linux/arm64"},
+	})
+
+	r := NewCachingResolver(inner, dir)
+	for range 2 {
+		_, err := r.ResolveConfig(context.Background(), "arm-only", "linux/amd64")
+		var platErr *PlatformMismatchError
+		if !errors.As(err, &platErr) {
+			t.Fatalf("expected PlatformMismatchError, got %v", err)
+		}
+		if len(platErr.Available) != 1 || platErr.Available[0] != "linux/arm64" {
+			t.Errorf("Available = %v, want [linux/arm64]", platErr.Available)
+		}
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("inner resolver called %d times, want 1", got)
+	}
+}
+
+func TestCachingResolver_ErrorsNotCached(t *testing.T) {
+	t.Parallel()
+	var calls atomic.Int32
+	inner := countingResolver(&calls, ImageConfig{}, &NotFoundError{Ref: "missing", Err: errors.New("404")})
+
+	r := NewCachingResolver(inner, t.TempDir())
+	for range 2 {
+		if _, err := r.ResolveConfig(context.Background(), "missing", "linux/amd64"); err == nil {
+			t.Fatal("expected error")
+		}
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("inner resolver called %d times, want 2", got)
+	}
+}
+
+func TestCachingResolver_StaleOnNetworkError(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	now := time.Now()
+
+	var calls atomic.Int32
+	online := NewCachingResolver(countingResolver(&calls, ImageConfig{Digest: "sha256:old"}, nil), dir, WithCacheTTL(time.Minute))
+	online.now = func() time.Time { return now }
+	if _, err := online.ResolveConfig(context.Background(), "alpine", "linux/amd64"); err != nil {
+		t.Fatal(err)
+	}
+
+	down := NewCachingResolver(
+		countingResolver(&calls, ImageConfig{}, &NetworkError{Err: errors.New("connection refused")}),
+		dir, WithCacheTTL(time.Minute))
+	down.now = func() time.Time { return now.Add(time.Hour) }
+	cfg, err := down.ResolveConfig(context.Background(), "alpine", "linux/amd64")
+	if err != nil {
+		t.Fatalf("expected stale entry, got error: %v", err)
+	}
+	if cfg.Digest != "sha256:old" {
+		t.Errorf("Digest = %q, want stale sha256:old", cfg.Digest)
+	}
+}
+
+func TestCachingResolver_Offline(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	now := time.Now()
+
+	var calls atomic.Int32
+	online := NewCachingResolver(countingResolver(&calls, ImageConfig{OS: "linux"}, nil), dir)
+	online.now = func() time.Time { return now }
+	if _, err := online.ResolveConfig(context.Background(), "alpine", "linux/amd64"); err != nil {
+		t.Fatal(err)
+	}
+
+	offline := NewCachingResolver(countingResolver(&calls, ImageConfig{}, nil), dir,
+		WithCacheTTL(time.Minute), WithOffline(true))
+	offline.now = func() time.Time { return now.Add(48 * time.Hour) }
+
+	// Cached entries are used regardless of age.
+	if cfg, err := offline.ResolveConfig(context.Background(), "alpine", "linux/amd64"); err != nil || cfg.OS != "linux" {
+		t.Errorf("ResolveConfig() = %+v, %v; want cached config", cfg, err)
+	}
+
+	// Uncached images are skipped as network failures without calling the registry.
+	_, err := offline.ResolveConfig(context.Background(), "ubuntu", "linux/amd64")
+	if !errors.Is(err, ErrOffline) {
+		t.Fatalf("expected ErrOffline, got %v", err)
+	}
+	var netErr *NetworkError
+	if !errors.As(err, &netErr) || netErr.SkipReason() != async.SkipNetwork {
+		t.Errorf("expected NetworkError with SkipNetwork, got %v", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("inner resolver called %d times, want 1 (offline must not resolve)", got)
+	}
+}
+
+func TestAsyncImageResolver_OfflineNoRetry(t *testing.T) {
+	t.Parallel()
+	r := NewAsyncImageResolver(NewCachingResolver(nil, t.TempDir(), WithOffline(true)))
+
+	start := time.Now()
+	_, err := r.Resolve(context.Background(), &ResolveRequest{Ref: "alpine", Platform: "linux/amd64"})
+	if !errors.Is(err, ErrOffline) {
+		t.Fatalf("expected ErrOffline, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 400*time.Millisecond {
+		t.Errorf("offline miss took %v; expected no retry backoff", elapsed)
+	}
+}
@@ -145,6 +145,7 @@ func (r *ContainersResolver) resolveFromIndex(
 		return cfg, err
 	}
 	cfg.Digest = chosen.String()
+	cfg.Platforms = collectAvailablePlatforms(list)
 	return cfg, nil
 }
 
@@ -189,6 +190,7 @@ func (r *ContainersResolver) resolveFromManifest(
 		Variant:        ociConfig.Variant,
 		Digest:         manifestDigest.String(),
 		HasHealthcheck: extractHasHealthcheck(configBytes),
+		Platforms:      []string{formatPlatformParts(ociConfig.OS, ociConfig.Architecture, ociConfig.Variant)},
 	}
 
 	// Platform mismatch check for single-manifest images.
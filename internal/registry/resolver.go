@@ -28,25 +28,30 @@ type ImageResolver interface {
 }
 
 // ImageConfig holds resolved image metadata.
+// It is persisted as JSON by CachingResolver.
 type ImageConfig struct {
 	// Env is the image's environment variables (KEY=VALUE parsed to map).
-	Env map[string]string
+	Env map[string]string `json:"env,omitempty"`
 
 	// OS is the image's target OS (e.g., "linux").
-	OS string
+	OS string `json:"os,omitempty"`
 
 	// Arch is the image's target architecture (e.g., "amd64").
-	Arch string
+	Arch string `json:"arch,omitempty"`
 
 	// Variant is the image's architecture variant (e.g., "v8").
-	Variant string
+	Variant string `json:"variant,omitempty"`
 
 	// Digest is the resolved manifest digest.
-	Digest string
+	Digest string `json:"digest,omitempty"`
 
 	// HasHealthcheck is true if the image defines a HEALTHCHECK (CMD or CMD-SHELL).
 	// False if HEALTHCHECK is NONE or absent.
-	HasHealthcheck bool
+	HasHealthcheck bool `json:"hasHealthcheck,omitempty"`
+
+	// Platforms lists the platforms the image is published for
+	// (e.g., "linux/amd64", "linux/arm64/v8").
+	Platforms []string `json:"platforms,omitempty"`
 }
 
 // ResolveRequest is the typed input for the registry async resolver.
@@ -8,5 +8,6 @@ import (
 	// Import all rule packages to trigger their init() registration
 	_ "github.com/tinovyatkin/tally/internal/rules/buildkit"
 	_ "github.com/tinovyatkin/tally/internal/rules/hadolint"
+	_ "github.com/tinovyatkin/tally/internal/rules/shellcheck"
 	_ "github.com/tinovyatkin/tally/internal/rules/tally"
 )
@@ -18,6 +18,9 @@ type HandlerFactory func(
 // PlanExternalImageChecks builds async check requests for all external image
 // stages using the shared iteration, platform resolution, and dedup-key logic.
 // Each rule provides its own HandlerFactory to create the appropriate handler.
+//
+// Base image references are resolved with build args first. When an explicit
+// build target is set, stages that are not part of that build are skipped.
 func PlanExternalImageChecks(
 	input rules.LintInput,
 	meta rules.RuleMetadata,
@@ -33,6 +36,9 @@ func PlanExternalImageChecks(
 		if info.Stage == nil {
 			continue
 		}
+		if sem.Target() != "" && !sem.IsStageInBuild(info.Index) {
+			continue // not built for the requested --target
+		}
 
 		expectedPlatform, unresolved := semantic.ExpectedPlatform(info, sem)
 		if len(unresolved) > 0 || expectedPlatform == "" {
@@ -40,6 +46,12 @@ func PlanExternalImageChecks(
 		}
 
 		ref := info.Stage.BaseName
+		if info.BaseImage != nil {
+			if info.BaseImage.Unresolved || info.BaseImage.Resolved == "" {
+				continue // skip when the image name has unresolved ARGs
+			}
+			ref = info.BaseImage.Resolved
+		}
 		key := ref + "|" + expectedPlatform
 
 		requests = append(requests, async.CheckRequest{
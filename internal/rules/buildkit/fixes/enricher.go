@@ -3,6 +3,7 @@
 package fixes
 
 import (
+	"maps"
 	"slices"
 	"strings"
 
@@ -10,22 +11,30 @@ import (
 	"github.com/tinovyatkin/tally/internal/semantic"
 )
 
-var fixableRuleNames = []string{
-	"StageNameCasing",
-	"FromAsCasing",
-	"NoEmptyContinuation",
-	"MaintainerDeprecated",
-	"ConsistentInstructionCasing",
-	"JSONArgsRecommended",
-	"InvalidDefinitionDescription",
-	"LegacyKeyValueFormat",
-	"MultipleInstructionsDisallowed",
-	"ExposeProtoCasing",
+// fixableRules maps BuildKit rule names with auto-fixes to the safety of those fixes.
+var fixableRules = map[string]rules.FixSafety{
+	"StageNameCasing":                rules.FixSafe,
+	"FromAsCasing":                   rules.FixSafe,
+	"NoEmptyContinuation":            rules.FixSafe,
+	"MaintainerDeprecated":           rules.FixSafe,
+	"ConsistentInstructionCasing":    rules.FixSafe,
+	"JSONArgsRecommended":            rules.FixSuggestion,
+	"InvalidDefinitionDescription":   rules.FixSafe,
+	"LegacyKeyValueFormat":           rules.FixSafe,
+	"MultipleInstructionsDisallowed": rules.FixSafe,
+	"ExposeProtoCasing":              rules.FixSafe,
 }
 
 // FixableRuleNames returns the BuildKit rule names for which tally can generate auto-fixes.
 func FixableRuleNames() []string {
-	return slices.Clone(fixableRuleNames)
+	return slices.Sorted(maps.Keys(fixableRules))
+}
+
+// FixSafety returns the safety of the auto-fix generated for a BuildKit rule name.
+// The second result is false when tally has no fix for the rule.
+func FixSafety(ruleName string) (rules.FixSafety, bool) {
+	safety, ok := fixableRules[ruleName]
+	return safety, ok
 }
 
 // EnrichBuildKitFixes adds SuggestedFix to BuildKit violations where possible.
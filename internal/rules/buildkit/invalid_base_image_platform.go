@@ -39,13 +39,15 @@ func (r *InvalidBaseImagePlatformRule) PlanAsync(input rules.LintInput) []async.
 	return planExternalImageChecks(input, r.Metadata(),
 		func(meta rules.RuleMetadata, info *semantic.StageInfo, file, platform string) async.ResultHandler {
 			var loc []parser.Range
+			ref := info.Stage.BaseName
 			if info.BaseImage != nil {
 				loc = info.BaseImage.Location
+				ref = info.BaseImage.Resolved
 			}
 			return &platformCheckHandler{
 				meta:     meta,
 				file:     file,
-				ref:      info.Stage.BaseName,
+				ref:      ref,
 				expected: platform,
 				location: loc,
 				stageIdx: info.Index,
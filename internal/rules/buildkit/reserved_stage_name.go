@@ -17,6 +17,14 @@ var reservedStageNames = map[string]struct{}{
 	"scratch": {},
 }
 
+// IsReservedStageName reports whether name is one of BuildKit's reserved
+// stage names. Like BuildKit, the comparison is case-sensitive; stage names
+// are lowercased by the parser before they are checked.
+func IsReservedStageName(name string) bool {
+	_, ok := reservedStageNames[name]
+	return ok
+}
+
 // ReservedStageNameRule implements BuildKit's ReservedStageName check.
 //
 // BuildKit normally runs this during LLB conversion. tally reimplements it as a
@@ -40,7 +48,7 @@ func (r *ReservedStageNameRule) Check(input rules.LintInput) []rules.Violation {
 		if stage.Name == "" {
 			continue
 		}
-		if _, ok := reservedStageNames[stage.Name]; ok {
+		if IsReservedStageName(stage.Name) {
 			loc := rules.NewLocationFromRanges(input.File, stage.Location)
 			out = append(out, rules.NewViolation(
 				loc,
@@ -89,3 +89,17 @@ func TestReservedStageNameRule_Check_CaseSensitive(t *testing.T) {
 		t.Fatalf("expected 0 violations, got %d", len(violations))
 	}
 }
+
+func TestIsReservedStageName(t *testing.T) {
+	t.Parallel()
+	for name, want := range map[string]bool{
+		"scratch": true,
+		"context": true,
+		"Scratch": false,
+		"builder": false,
+	} {
+		if got := IsReservedStageName(name); got != want {
+			t.Errorf("IsReservedStageName(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
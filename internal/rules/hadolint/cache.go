@@ -0,0 +1,312 @@
+package hadolint
+
+import (
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/moby/buildkit/frontend/dockerfile/instructions"
+
+	"github.com/tinovyatkin/tally/internal/dockerfile"
+	"github.com/tinovyatkin/tally/internal/rules"
+	"github.com/tinovyatkin/tally/internal/runmount"
+	"github.com/tinovyatkin/tally/internal/shell"
+	"github.com/tinovyatkin/tally/internal/sourcemap"
+)
+
+// cacheSpec describes the cache a package manager leaves behind and how to
+// get rid of it. It drives the cache hygiene rules (DL3009, DL3019, DL3032,
+// DL3040, DL3042, DL3060).
+type cacheSpec struct {
+	// commands are the command names to look for.
+	commands []string
+
+	// trigger returns the subcommand word of a command that fills the cache
+	// (e.g. "install" in "yum install"). It returns false for other commands
+	// and for commands that already skip the cache (apk add --no-cache).
+	trigger func(cmd *shell.CommandInfo) (shell.Word, bool)
+
+	// cleans reports whether a command empties the cache (e.g. "yum clean all").
+	// Removing one of cacheDirs with rm is always recognized. May be nil.
+	cleans func(cmd *shell.CommandInfo) bool
+
+	// cacheDirs are the directories holding the cache. A cache or tmpfs
+	// mount on one of them (or on a parent) keeps the cache out of the image.
+	cacheDirs []string
+
+	// cacheDir returns the cache directory of the triggering command, which
+	// the suggested cache mount targets.
+	cacheDir func(cmd *shell.CommandInfo) string
+
+	// mountOptions are extra options for the suggested cache mount, e.g.
+	// "sharing=locked" for package managers that lock their cache.
+	mountOptions string
+
+	// cleanup returns the command the safe fix appends to the RUN, e.g.
+	// "yum clean all". Exactly one of cleanup and flag is set.
+	cleanup func(cmd *shell.CommandInfo) string
+
+	// flag is the option the safe fix adds after the subcommand of every
+	// triggering command instead, e.g. "--no-cache".
+	flag string
+
+	// skip reports whether a RUN needs no check, e.g. because the stage
+	// environment already disables the cache. May be nil.
+	skip func(run *instructions.RunCommand) bool
+
+	// detail explains the violation.
+	detail string
+}
+
+// staticCacheDir returns a cacheSpec.cacheDir function that always returns dir.
+func staticCacheDir(dir string) func(cmd *shell.CommandInfo) string {
+	return func(*shell.CommandInfo) string { return dir }
+}
+
+// cacheTrigger returns a cacheSpec.trigger function matching one of the
+// subcommands after the command's options.
+func cacheTrigger(valueFlags []string, subcommands ...string) func(cmd *shell.CommandInfo) (shell.Word, bool) {
+	return func(cmd *shell.CommandInfo) (shell.Word, bool) {
+		return subcommandWord(commandOperands(cmd.Words, valueFlags...), subcommands...)
+	}
+}
+
+// subcommandWord returns the leading operand when it is one of the subcommands.
+func subcommandWord(operands []shell.Word, subcommands ...string) (shell.Word, bool) {
+	if len(operands) == 0 || operands[0].Dynamic || !slices.Contains(subcommands, operands[0].Value) {
+		return shell.Word{}, false
+	}
+	return operands[0], true
+}
+
+// cleansAll returns a cacheSpec.cleans function matching "<command> clean all".
+func cleansAll(valueFlags []string) func(cmd *shell.CommandInfo) bool {
+	return func(cmd *shell.CommandInfo) bool {
+		operands := commandOperands(cmd.Words, valueFlags...)
+		return subcommandOperands(operands, []string{"clean", "all"}) != nil
+	}
+}
+
+// checkCache reports every RUN that fills a package manager cache without
+// emptying it or keeping it in a cache mount. Each violation carries a safe
+// fix that cleans up in the same RUN and a preferred suggestion that moves
+// the cache into a BuildKit cache mount instead.
+func checkCache(input rules.LintInput, meta rules.RuleMetadata, spec cacheSpec) []rules.Violation {
+	sm := input.SourceMap()
+	names := append(slices.Clone(spec.commands), "rm")
+
+	return ScanRunCommandsWithPOSIXShell(
+		input,
+		func(run *instructions.RunCommand, shellVariant shell.Variant, file string) []rules.Violation {
+			if spec.skip != nil && spec.skip(run) {
+				return nil
+			}
+			if hasCacheMount(run, spec.cacheDirs) {
+				return nil
+			}
+
+			var cmds []shell.CommandInfo
+			var runStartLine int
+
+			if run.PrependShell {
+				script, startLine := dockerfile.RunSourceScript(run, sm)
+				if script == "" {
+					return nil
+				}
+				runStartLine = startLine
+				cmds = shell.FindCommands(script, shellVariant, names...)
+			} else {
+				cmds = shell.FindCommands(dockerfile.RunCommandString(run), shellVariant, names...)
+			}
+
+			var triggers []*shell.CommandInfo
+			var words []shell.Word
+			for i := range cmds {
+				cmd := &cmds[i]
+				if removesCacheDir(cmd, spec.cacheDirs) || (spec.cleans != nil && spec.cleans(cmd)) {
+					return nil
+				}
+				if w, ok := spec.trigger(cmd); ok {
+					triggers = append(triggers, cmd)
+					words = append(words, w)
+				}
+			}
+			if len(triggers) == 0 {
+				return nil
+			}
+
+			loc := rules.NewLocationFromRanges(file, run.Location())
+			v := rules.NewViolation(loc, meta.Code, meta.Description, meta.DefaultSeverity).
+				WithDocURL(meta.DocURL).
+				WithDetail(spec.detail)
+
+			var cleanupFix *rules.SuggestedFix
+			if spec.flag != "" {
+				if run.PrependShell {
+					cleanupFix = flagFix(sm, file, runStartLine, words, spec.flag)
+				}
+			} else if run.PrependShell && len(run.Files) == 0 {
+				cleanupFix = appendCleanupFix(run, sm, file, spec.cleanup(triggers[0]))
+			}
+			if cleanupFix != nil {
+				v = v.WithSuggestedFix(cleanupFix)
+			}
+			if mountFix := cacheMountFix(run, sm, file, spec.cacheDir(triggers[0]), spec.mountOptions); mountFix != nil {
+				v = v.WithAlternativeFix(mountFix)
+			}
+
+			return []rules.Violation{v}
+		},
+	)
+}
+
+// hasCacheMount reports whether the RUN mounts a cache or tmpfs on one of
+// the directories or a parent of one.
+func hasCacheMount(run *instructions.RunCommand, dirs []string) bool {
+	return slices.ContainsFunc(runmount.GetMounts(run), func(m *instructions.Mount) bool {
+		if m.Type != instructions.MountTypeCache && m.Type != instructions.MountTypeTmpfs {
+			return false
+		}
+		return slices.ContainsFunc(dirs, func(dir string) bool {
+			return isPathWithin(dir, m.Target)
+		})
+	})
+}
+
+// removesCacheDir reports whether the command is an rm of one of the
+// directories, their contents or a parent directory.
+func removesCacheDir(cmd *shell.CommandInfo, dirs []string) bool {
+	if cmd.Name != "rm" {
+		return false
+	}
+	for _, w := range commandOperands(cmd.Words) {
+		if w.Dynamic {
+			continue
+		}
+		target := strings.TrimSuffix(w.Value, "*")
+		if rest, ok := strings.CutPrefix(target, "~/"); ok {
+			target = "/root/" + rest
+		}
+		if slices.ContainsFunc(dirs, func(dir string) bool { return isPathWithin(dir, target) }) {
+			return true
+		}
+	}
+	return false
+}
+
+// isPathWithin reports whether p is the absolute path parent or lies within it.
+func isPathWithin(p, parent string) bool {
+	if !strings.HasPrefix(parent, "/") {
+		return false
+	}
+	p, parent = path.Clean(p), path.Clean(parent)
+	return p == parent || strings.HasPrefix(p, strings.TrimSuffix(parent, "/")+"/")
+}
+
+// flagFix returns a fix adding the flag after each of the subcommand words,
+// or nil when a word's position doesn't point at it in the source.
+func flagFix(sm *sourcemap.SourceMap, file string, runStartLine int, words []shell.Word, flag string) *rules.SuggestedFix {
+	edits := make([]rules.TextEdit, 0, len(words))
+	for _, w := range words {
+		line := runStartLine + w.Line
+		lineIdx := line - 1
+		if lineIdx < 0 || lineIdx >= sm.LineCount() {
+			return nil
+		}
+		src := sm.Line(lineIdx)
+		if w.EndCol > len(src) || src[w.StartCol:w.EndCol] != w.Value {
+			return nil
+		}
+		edits = append(edits, rules.TextEdit{
+			Location: rules.NewRangeLocation(file, line, w.EndCol, line, w.EndCol),
+			NewText:  " " + flag,
+		})
+	}
+	return &rules.SuggestedFix{
+		Description: "Add " + flag,
+		Safety:      rules.FixSafe,
+		Edits:       edits,
+	}
+}
+
+// appendCleanupFix returns a fix appending the cleanup command to the end of
+// the RUN's script, or nil when the script doesn't end in a plain command
+// (a trailing comment, pipe or background job).
+func appendCleanupFix(run *instructions.RunCommand, sm *sourcemap.SourceMap, file, cleanup string) *rules.SuggestedFix {
+	runLoc := run.Location()
+	if len(runLoc) == 0 {
+		return nil
+	}
+	line := runLoc[len(runLoc)-1].End.Line
+	lineIdx := line - 1
+	if lineIdx < 0 || lineIdx >= sm.LineCount() {
+		return nil
+	}
+	src := strings.TrimRight(sm.Line(lineIdx), " \t")
+	if src == "" || strings.Contains(src, "#") || strings.HasSuffix(src, "&") ||
+		strings.HasSuffix(src, "|") || strings.HasSuffix(src, "\\") {
+		return nil
+	}
+
+	text := " && " + cleanup
+	if strings.HasSuffix(src, ";") {
+		text = " " + cleanup
+	}
+	return &rules.SuggestedFix{
+		Description: "Append `" + cleanup + "` to the RUN command",
+		Safety:      rules.FixSafe,
+		Edits: []rules.TextEdit{{
+			Location: rules.NewRangeLocation(file, line, len(src), line, len(src)),
+			NewText:  text,
+		}},
+	}
+}
+
+// cacheMountFix returns a fix adding a BuildKit cache mount on dir to the
+// RUN's flags, or nil when the RUN keyword can't be located.
+func cacheMountFix(run *instructions.RunCommand, sm *sourcemap.SourceMap, file, dir, options string) *rules.SuggestedFix {
+	line, col, ok := runFlagsPosition(run, sm)
+	if !ok {
+		return nil
+	}
+	mount := "--mount=type=cache,target=" + dir
+	if options != "" {
+		mount += "," + options
+	}
+	return &rules.SuggestedFix{
+		Description: "Use a cache mount for " + dir,
+		Safety:      rules.FixSuggestion,
+		IsPreferred: true,
+		Edits: []rules.TextEdit{{
+			Location: rules.NewRangeLocation(file, line, col, line, col),
+			NewText:  mount + " ",
+		}},
+	}
+}
+
+// runFlagsPosition returns the 1-based line and 0-based column right after
+// the RUN keyword and its whitespace, where flags can be inserted.
+func runFlagsPosition(run *instructions.RunCommand, sm *sourcemap.SourceMap) (int, int, bool) {
+	runLoc := run.Location()
+	if len(runLoc) == 0 {
+		return 0, 0, false
+	}
+	line := runLoc[0].Start.Line
+	lineIdx := line - 1
+	if lineIdx < 0 || lineIdx >= sm.LineCount() {
+		return 0, 0, false
+	}
+	src := sm.Line(lineIdx)
+	idx := strings.Index(strings.ToUpper(src), "RUN")
+	if idx < 0 {
+		return 0, 0, false
+	}
+	col := idx + len("RUN")
+	if col >= len(src) || (src[col] != ' ' && src[col] != '\t') {
+		return 0, 0, false
+	}
+	for col < len(src) && (src[col] == ' ' || src[col] == '\t') {
+		col++
+	}
+	return line, col, true
+}
@@ -0,0 +1,175 @@
+package hadolint
+
+import (
+	"testing"
+
+	"github.com/tinovyatkin/tally/internal/fix"
+	"github.com/tinovyatkin/tally/internal/rules"
+	"github.com/tinovyatkin/tally/internal/testutil"
+)
+
+// cacheCase is a cache hygiene rule test case: want is the number of RUN
+// instructions reported.
+type cacheCase struct {
+	name       string
+	dockerfile string
+	want       int
+}
+
+// runCacheCases checks a cache hygiene rule against test cases.
+func runCacheCases(t *testing.T, rule rules.Rule, tests []cacheCase) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			input := testutil.MakeLintInputWithSemantic(t, "Dockerfile", tt.dockerfile)
+			violations := rule.Check(input)
+			if len(violations) != tt.want {
+				t.Errorf("got %d violations, want %d", len(violations), tt.want)
+				for i, v := range violations {
+					t.Logf("violation %d: %s at %v", i+1, v.Message, v.Location)
+				}
+			}
+			for _, v := range violations {
+				if v.RuleCode != rule.Metadata().Code {
+					t.Errorf("rule code = %q, want %q", v.RuleCode, rule.Metadata().Code)
+				}
+				if v.Detail == "" {
+					t.Error("violation detail is empty")
+				}
+			}
+		})
+	}
+}
+
+// applyCacheFix applies the fix a fixer with the given safety threshold
+// picks for the rule's violations and returns the fixed Dockerfile.
+func applyCacheFix(t *testing.T, rule rules.Rule, dockerfile string, threshold rules.FixSafety) string {
+	t.Helper()
+	input := testutil.MakeLintInputWithSemantic(t, "Dockerfile", dockerfile)
+	violations := rule.Check(input)
+	if len(violations) == 0 {
+		t.Fatal("expected a violation")
+	}
+	fixer := &fix.Fixer{SafetyThreshold: threshold}
+	result, err := fixer.Apply(t.Context(), violations, map[string][]byte{"Dockerfile": []byte(dockerfile)})
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	return string(result.Changes["Dockerfile"].ModifiedContent)
+}
+
+func TestCache_Fixes(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		rule       rules.Rule
+		dockerfile string
+		safe       string
+		suggestion string
+	}{
+		{
+			name:       "apt-get lists",
+			rule:       NewDL3009Rule(),
+			dockerfile: "FROM debian:12\nRUN apt-get update && apt-get install -y curl\n",
+			safe:       "FROM debian:12\nRUN apt-get update && apt-get install -y curl && rm -rf /var/lib/apt/lists/*\n",
+			suggestion: "FROM debian:12\nRUN --mount=type=cache,target=/var/lib/apt/lists,sharing=locked apt-get update && apt-get install -y curl\n",
+		},
+		{
+			name:       "multi-line script ending with a semicolon",
+			rule:       NewDL3032Rule(),
+			dockerfile: "FROM centos:7\nRUN yum install -y httpd-2.4.6 ; \\\n    yum update -y;\n",
+			safe:       "FROM centos:7\nRUN yum install -y httpd-2.4.6 ; \\\n    yum update -y; yum clean all\n",
+			suggestion: "FROM centos:7\nRUN --mount=type=cache,target=/var/cache/yum,sharing=locked yum install -y httpd-2.4.6 ; \\\n    yum update -y;\n",
+		},
+		{
+			name:       "microdnf",
+			rule:       NewDL3040Rule(),
+			dockerfile: "FROM ubi9-minimal\nRUN microdnf install -y httpd\n",
+			safe:       "FROM ubi9-minimal\nRUN microdnf install -y httpd && microdnf clean all\n",
+			suggestion: "FROM ubi9-minimal\nRUN --mount=type=cache,target=/var/cache/yum,sharing=locked microdnf install -y httpd\n",
+		},
+		{
+			name:       "flag added to every install",
+			rule:       NewDL3019Rule(),
+			dockerfile: "FROM alpine:3.20\nRUN apk add curl && apk add git\n",
+			safe:       "FROM alpine:3.20\nRUN apk add --no-cache curl && apk add --no-cache git\n",
+			suggestion: "FROM alpine:3.20\nRUN --mount=type=cache,target=/var/cache/apk,sharing=locked apk add curl && apk add git\n",
+		},
+		{
+			name:       "python -m pip",
+			rule:       NewDL3042Rule(),
+			dockerfile: "FROM python:3.12\nRUN python -m pip install flask==3.0.3\n",
+			safe:       "FROM python:3.12\nRUN python -m pip install --no-cache-dir flask==3.0.3\n",
+			suggestion: "FROM python:3.12\nRUN --mount=type=cache,target=/root/.cache/pip python -m pip install flask==3.0.3\n",
+		},
+		{
+			name:       "existing flags",
+			rule:       NewDL3060Rule(),
+			dockerfile: "FROM node:22\nRUN --network=default yarn install\n",
+			safe:       "FROM node:22\nRUN --network=default yarn install && yarn cache clean\n",
+			suggestion: "FROM node:22\nRUN --mount=type=cache,target=/usr/local/share/.cache/yarn --network=default yarn install\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := applyCacheFix(t, tt.rule, tt.dockerfile, rules.FixSafe); got != tt.safe {
+				t.Errorf("safe fix:\ngot:  %q\nwant: %q", got, tt.safe)
+			}
+			if got := applyCacheFix(t, tt.rule, tt.dockerfile, rules.FixSuggestion); got != tt.suggestion {
+				t.Errorf("suggestion fix:\ngot:  %q\nwant: %q", got, tt.suggestion)
+			}
+		})
+	}
+}
+
+func TestCache_NoSafeFix(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		dockerfile string
+	}{
+		{name: "trailing comment", dockerfile: "FROM centos:7\nRUN yum install -y httpd-2.4.6 # web server\n"},
+		{name: "background job", dockerfile: "FROM centos:7\nRUN yum install -y httpd-2.4.6 &\n"},
+		{name: "exec form", dockerfile: "FROM centos:7\nRUN [\"yum\", \"install\", \"-y\", \"httpd-2.4.6\"]\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			input := testutil.MakeLintInputWithSemantic(t, "Dockerfile", tt.dockerfile)
+			violations := NewDL3032Rule().Check(input)
+			if len(violations) != 1 {
+				t.Fatalf("got %d violations, want 1", len(violations))
+			}
+			v := violations[0]
+			if v.SuggestedFix != nil {
+				t.Errorf("unexpected safe fix %+v", v.SuggestedFix)
+			}
+			// The cache mount goes in front of the script, so it is always offered.
+			if len(v.AlternativeFixes) != 1 || v.AlternativeFixes[0].Safety != rules.FixSuggestion {
+				t.Errorf("alternative fixes = %+v, want one cache mount suggestion", v.AlternativeFixes)
+			}
+		})
+	}
+}
+
+func TestIsPathWithin(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		p, parent string
+		want      bool
+	}{
+		{"/var/lib/apt/lists", "/var/lib/apt/lists", true},
+		{"/var/lib/apt/lists", "/var/lib/apt/lists/", true},
+		{"/var/lib/apt/lists", "/var/lib/apt", true},
+		{"/var/lib/apt/lists", "/var/lib/apt/lists/partial", false},
+		{"/var/lib/apt/lists", "/var/lib/ap", false},
+		{"/var/lib/apt/lists", "lists", false},
+	}
+	for _, tt := range tests {
+		if got := isPathWithin(tt.p, tt.parent); got != tt.want {
+			t.Errorf("isPathWithin(%q, %q) = %v, want %v", tt.p, tt.parent, got, tt.want)
+		}
+	}
+}
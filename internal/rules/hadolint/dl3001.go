@@ -62,6 +62,8 @@ func (r *DL3001Rule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityInfo,
 		Category:        "style",
 		IsExperimental:  false,
+		Fixable:         true,
+		FixSafety:       rules.FixSuggestion,
 	}
 }
 
@@ -28,6 +28,8 @@ func (r *DL3003Rule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityWarning,
 		Category:        "style",
 		IsExperimental:  false,
+		Fixable:         true,
+		FixSafety:       rules.FixSuggestion,
 	}
 }
 
@@ -0,0 +1,87 @@
+package hadolint
+
+import (
+	"strings"
+
+	"github.com/tinovyatkin/tally/internal/rules"
+	"github.com/tinovyatkin/tally/internal/rules/configutil"
+	"github.com/tinovyatkin/tally/internal/shell"
+)
+
+// DL3008Config is the configuration for the DL3008 rule.
+type DL3008Config PinningConfig
+
+// DL3008Rule implements the DL3008 linting rule.
+// It warns when apt-get install is given a package without a version.
+type DL3008Rule struct{}
+
+// NewDL3008Rule creates a new DL3008 rule instance.
+func NewDL3008Rule() *DL3008Rule {
+	return &DL3008Rule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *DL3008Rule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            rules.HadolintRulePrefix + "DL3008",
+		Name:            "Pin versions in apt-get install",
+		Description:     "Pin versions in apt-get install. Instead of `apt-get install <package>` use `apt-get install <package>=<version>`",
+		DocURL:          "https://github.com/hadolint/hadolint/wiki/DL3008",
+		DefaultSeverity: rules.SeverityWarning,
+		Category:        "reproducibility",
+		IsExperimental:  false,
+	}
+}
+
+// Schema returns the JSON Schema for this rule's configuration.
+func (r *DL3008Rule) Schema() map[string]any {
+	return pinningSchema()
+}
+
+// DefaultConfig returns the default configuration for this rule.
+func (r *DL3008Rule) DefaultConfig() any {
+	return DL3008Config(DefaultPinningConfig())
+}
+
+// ValidateConfig validates the configuration against the rule's JSON Schema.
+func (r *DL3008Rule) ValidateConfig(config any) error {
+	return configutil.ValidateWithSchema(config, r.Schema())
+}
+
+// aptValueFlags are the apt-get options that take a separate value.
+var aptValueFlags = []string{
+	"-o", "--option", "-c", "--config-file", "-t", "--target-release", "--default-release",
+}
+
+// aptSyntax is the apt package syntax: package=version.
+var aptSyntax = packageSyntax{
+	pinned: func(arg string) bool {
+		// Local .deb files carry their own version.
+		return strings.Contains(arg, "=") || isLocalPackage(arg, ".deb")
+	},
+	name: func(arg string) string {
+		// Drop the version and an architecture qualifier (curl:amd64).
+		return nameBefore(arg, "=:")
+	},
+	example: "%s=<version>",
+}
+
+// Check runs the DL3008 rule.
+func (r *DL3008Rule) Check(input rules.LintInput) []rules.Violation {
+	return checkPinning(input, r.Metadata(), r.resolveConfig(input.Config).Allowlist, pinningSpec{
+		commands: []string{"apt-get", "apt"},
+		packages: func(cmd *shell.CommandInfo) ([]shell.Word, packageSyntax) {
+			return subcommandOperands(commandOperands(cmd.Words, aptValueFlags...), []string{"install"}), aptSyntax
+		},
+	})
+}
+
+// resolveConfig extracts the DL3008Config from input, falling back to defaults.
+func (r *DL3008Rule) resolveConfig(config any) DL3008Config {
+	return configutil.Coerce(config, DL3008Config(DefaultPinningConfig()))
+}
+
+// init registers the rule with the default registry.
+func init() {
+	rules.Register(NewDL3008Rule())
+}
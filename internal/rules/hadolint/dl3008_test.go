@@ -0,0 +1,63 @@
+package hadolint
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func TestDL3008Rule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewDL3008Rule().Metadata())
+}
+
+func TestDL3008Rule_Check(t *testing.T) {
+	t.Parallel()
+	runPinningCases(t, NewDL3008Rule(), []pinningCase{
+		{
+			name:       "unpinned packages",
+			dockerfile: "FROM ubuntu\nRUN apt-get install -y python curl",
+			want:       []string{"python", "curl"},
+		},
+		{
+			name:       "pinned packages",
+			dockerfile: "FROM ubuntu\nRUN apt-get install -y python=3.12.3-0ubuntu1 curl=8.5.0-2ubuntu10",
+		},
+		{
+			name:       "mixed",
+			dockerfile: "FROM ubuntu\nRUN apt-get update && apt-get install -y --no-install-recommends python=3.12.3-0ubuntu1 curl",
+			want:       []string{"curl"},
+		},
+		{
+			name:       "architecture qualifier",
+			dockerfile: "FROM ubuntu\nRUN apt-get install -y libc6:amd64",
+			want:       []string{"libc6"},
+		},
+		{
+			name:       "local deb file",
+			dockerfile: "FROM ubuntu\nRUN apt-get install -y ./google-chrome-stable_current_amd64.deb",
+		},
+		{
+			name:       "option values are not packages",
+			dockerfile: "FROM debian\nRUN apt-get -o Dpkg::Options::=--force-confnew install -y -t bookworm-backports htop=3.2.2-2",
+		},
+		{
+			name:       "apt install",
+			dockerfile: "FROM ubuntu\nRUN apt install -y curl",
+			want:       []string{"curl"},
+		},
+		{
+			name:       "variable package",
+			dockerfile: "FROM ubuntu\nARG PKG\nRUN apt-get install -y \"$PKG\" curl=${CURL_VERSION}",
+		},
+		{
+			name:       "other subcommands",
+			dockerfile: "FROM ubuntu\nRUN apt-get update && apt-get remove -y curl",
+		},
+		{
+			name:       "env wrapper",
+			dockerfile: "FROM ubuntu\nRUN env DEBIAN_FRONTEND=noninteractive apt-get install -y curl",
+			want:       []string{"curl"},
+		},
+	})
+}
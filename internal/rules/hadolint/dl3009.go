@@ -0,0 +1,50 @@
+package hadolint
+
+import (
+	"github.com/tinovyatkin/tally/internal/rules"
+	"github.com/tinovyatkin/tally/internal/shell"
+)
+
+// DL3009Rule implements the DL3009 linting rule.
+// It warns when apt-get update leaves the package lists in the image.
+type DL3009Rule struct{}
+
+// NewDL3009Rule creates a new DL3009 rule instance.
+func NewDL3009Rule() *DL3009Rule {
+	return &DL3009Rule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *DL3009Rule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            rules.HadolintRulePrefix + "DL3009",
+		Name:            "Delete the apt-get lists",
+		Description:     "Delete the apt-get lists after installing something",
+		DocURL:          "https://github.com/hadolint/hadolint/wiki/DL3009",
+		DefaultSeverity: rules.SeverityInfo,
+		Category:        "performance",
+		IsExperimental:  false,
+		Fixable:         true,
+		FixSafety:       rules.FixSafe,
+	}
+}
+
+// Check runs the DL3009 rule.
+func (r *DL3009Rule) Check(input rules.LintInput) []rules.Violation {
+	return checkCache(input, r.Metadata(), cacheSpec{
+		commands:     []string{"apt-get", "apt"},
+		trigger:      cacheTrigger(aptValueFlags, "update"),
+		cacheDirs:    []string{"/var/lib/apt/lists"},
+		cacheDir:     staticCacheDir("/var/lib/apt/lists"),
+		mountOptions: "sharing=locked",
+		cleanup:      func(*shell.CommandInfo) string { return "rm -rf /var/lib/apt/lists/*" },
+		detail: "apt-get update downloads package lists to /var/lib/apt/lists, which stay in the layer " +
+			"unless removed in the same RUN. Remove them with rm -rf /var/lib/apt/lists/*, or mount a " +
+			"cache on /var/lib/apt/lists so they never reach the image.",
+	})
+}
+
+// init registers the rule with the default registry.
+func init() {
+	rules.Register(NewDL3009Rule())
+}
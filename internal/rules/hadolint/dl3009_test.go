@@ -0,0 +1,68 @@
+package hadolint
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func TestDL3009Rule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewDL3009Rule().Metadata())
+}
+
+func TestDL3009Rule_Check(t *testing.T) {
+	t.Parallel()
+	runCacheCases(t, NewDL3009Rule(), []cacheCase{
+		{
+			name:       "lists not removed",
+			dockerfile: "FROM debian:12\nRUN apt-get update && apt-get install -y python",
+			want:       1,
+		},
+		{
+			name:       "lists removed",
+			dockerfile: "FROM debian:12\nRUN apt-get update && apt-get install -y python && rm -rf /var/lib/apt/lists/*",
+		},
+		{
+			name:       "lists removed in a separate RUN",
+			dockerfile: "FROM debian:12\nRUN apt-get update && apt-get install -y python\nRUN rm -rf /var/lib/apt/lists/*",
+			want:       1,
+		},
+		{
+			name:       "apt update",
+			dockerfile: "FROM debian:12\nRUN apt update && apt install -y python",
+			want:       1,
+		},
+		{
+			name:       "install without update",
+			dockerfile: "FROM debian:12\nRUN apt-get install -y python",
+		},
+		{
+			name:       "cache mount on the lists",
+			dockerfile: "FROM debian:12\nRUN --mount=type=cache,target=/var/lib/apt/lists,sharing=locked apt-get update && apt-get install -y python",
+		},
+		{
+			name:       "cache mount on a parent directory",
+			dockerfile: "FROM debian:12\nRUN --mount=type=cache,target=/var/lib/apt apt-get update",
+		},
+		{
+			name:       "tmpfs mount on the lists",
+			dockerfile: "FROM debian:12\nRUN --mount=type=tmpfs,target=/var/lib/apt/lists apt-get update",
+		},
+		{
+			name:       "cache mount on another directory",
+			dockerfile: "FROM debian:12\nRUN --mount=type=cache,target=/var/cache/apt apt-get update && apt-get install -y python",
+			want:       1,
+		},
+		{
+			name: "mount flags on their own line",
+			dockerfile: "FROM debian:12\nRUN --mount=type=cache,target=/var/cache/apt \\\n" +
+				"    --mount=type=cache,target=/var/lib/apt/lists \\\n    apt-get update",
+		},
+		{
+			name:       "ONBUILD",
+			dockerfile: "FROM debian:12\nONBUILD RUN apt-get update",
+			want:       1,
+		},
+	})
+}
@@ -0,0 +1,127 @@
+package hadolint
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/tinovyatkin/tally/internal/rules"
+	"github.com/tinovyatkin/tally/internal/rules/configutil"
+	"github.com/tinovyatkin/tally/internal/shell"
+)
+
+// DL3013Config is the configuration for the DL3013 rule.
+type DL3013Config PinningConfig
+
+// DL3013Rule implements the DL3013 linting rule.
+// It warns when pip install is given a package without a version.
+type DL3013Rule struct{}
+
+// NewDL3013Rule creates a new DL3013 rule instance.
+func NewDL3013Rule() *DL3013Rule {
+	return &DL3013Rule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *DL3013Rule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            rules.HadolintRulePrefix + "DL3013",
+		Name:            "Pin versions in pip install",
+		Description:     "Pin versions in pip. Instead of `pip install <package>` use `pip install <package>==<version>` or `pip install --requirement <requirements file>`",
+		DocURL:          "https://github.com/hadolint/hadolint/wiki/DL3013",
+		DefaultSeverity: rules.SeverityWarning,
+		Category:        "reproducibility",
+		IsExperimental:  false,
+	}
+}
+
+// Schema returns the JSON Schema for this rule's configuration.
+func (r *DL3013Rule) Schema() map[string]any {
+	return pinningSchema()
+}
+
+// DefaultConfig returns the default configuration for this rule.
+func (r *DL3013Rule) DefaultConfig() any {
+	return DL3013Config(DefaultPinningConfig())
+}
+
+// ValidateConfig validates the configuration against the rule's JSON Schema.
+func (r *DL3013Rule) ValidateConfig(config any) error {
+	return configutil.ValidateWithSchema(config, r.Schema())
+}
+
+// pipValueFlags are the pip install options that take a separate value.
+var pipValueFlags = []string{
+	"-r", "--requirement", "-c", "--constraint", "-e", "--editable",
+	"-i", "--index-url", "--extra-index-url", "-f", "--find-links",
+	"-t", "--target", "--prefix", "--root", "--src", "--upgrade-strategy",
+	"--platform", "--python-version", "--implementation", "--abi",
+	"--no-binary", "--only-binary", "--global-option", "--install-option",
+	"-C", "--config-settings", "--trusted-host", "--cache-dir", "--log",
+	"--proxy", "--retries", "--timeout", "--exists-action", "--cert",
+	"--client-cert", "--report", "--progress-bar", "--python",
+}
+
+// pipSyntax is the pip requirement syntax: package==version or any other
+// version specifier (>=, ~=, !=, ...), or a direct reference (package @ url).
+var pipSyntax = packageSyntax{
+	pinned: func(arg string) bool {
+		// Paths, URLs (including git+https://...) and archives name a
+		// specific source rather than a package from the index.
+		return strings.ContainsAny(arg, "=<>!~@/") ||
+			isLocalPackage(arg, ".whl", ".tar.gz", ".tgz", ".zip")
+	},
+	name: func(arg string) string {
+		// Drop the version specifier and extras (package[extra]).
+		return nameBefore(arg, "=<>!~@[;")
+	},
+	example: "%s==<version>",
+}
+
+// pipCommands are the commands that run pip, directly or as "python -m pip".
+var pipCommands = []string{"pip", "pip3", "python", "python3"}
+
+// pipArgs returns the arguments given to pip by one of pipCommands, or false
+// when a python command doesn't run pip.
+func pipArgs(cmd *shell.CommandInfo) ([]shell.Word, bool) {
+	words := cmd.Words
+	if strings.HasPrefix(cmd.Name, "python") {
+		// python -m pip ...
+		i := slices.IndexFunc(words, func(w shell.Word) bool { return w.Value == "-m" })
+		if i < 0 || i+1 >= len(words) || words[i+1].Value != "pip" {
+			return nil, false
+		}
+		words = words[i+2:]
+	}
+	return words, true
+}
+
+// Check runs the DL3013 rule.
+func (r *DL3013Rule) Check(input rules.LintInput) []rules.Violation {
+	return checkPinning(input, r.Metadata(), r.resolveConfig(input.Config).Allowlist, pinningSpec{
+		commands: pipCommands,
+		packages: func(cmd *shell.CommandInfo) ([]shell.Word, packageSyntax) {
+			words, ok := pipArgs(cmd)
+			if !ok {
+				return nil, pipSyntax
+			}
+			// Hash-checking mode requires every requirement to come pinned
+			// from a requirements file, and a constraints file pins the
+			// packages named on the command line. Requirements files (-r)
+			// are not inspected.
+			if hasOption(words, "--require-hashes", "-c", "--constraint") {
+				return nil, pipSyntax
+			}
+			return subcommandOperands(commandOperands(words, pipValueFlags...), []string{"install"}), pipSyntax
+		},
+	})
+}
+
+// resolveConfig extracts the DL3013Config from input, falling back to defaults.
+func (r *DL3013Rule) resolveConfig(config any) DL3013Config {
+	return configutil.Coerce(config, DL3013Config(DefaultPinningConfig()))
+}
+
+// init registers the rule with the default registry.
+func init() {
+	rules.Register(NewDL3013Rule())
+}
@@ -0,0 +1,79 @@
+package hadolint
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func TestDL3013Rule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewDL3013Rule().Metadata())
+}
+
+func TestDL3013Rule_Check(t *testing.T) {
+	t.Parallel()
+	runPinningCases(t, NewDL3013Rule(), []pinningCase{
+		{
+			name:       "unpinned packages",
+			dockerfile: "FROM python:3.12\nRUN pip install flask requests",
+			want:       []string{"flask", "requests"},
+		},
+		{
+			name:       "version specifiers",
+			dockerfile: "FROM python:3.12\nRUN pip install flask==3.0.3 \"requests>=2.31,<3\" django~=5.0 \"numpy!=2.0.0\"",
+		},
+		{
+			name:       "extras",
+			dockerfile: "FROM python:3.12\nRUN pip install \"uvicorn[standard]\" \"fastapi[all]==0.111.0\"",
+			want:       []string{"uvicorn"},
+		},
+		{
+			name:       "python -m pip",
+			dockerfile: "FROM python:3.12\nRUN python3 -m pip install --no-cache-dir --upgrade pip",
+			want:       []string{"pip"},
+		},
+		{
+			name:       "requirements file",
+			dockerfile: "FROM python:3.12\nRUN pip install -r requirements.txt",
+		},
+		{
+			name:       "requirements file and unpinned package",
+			dockerfile: "FROM python:3.12\nRUN pip install --requirement requirements.txt gunicorn",
+			want:       []string{"gunicorn"},
+		},
+		{
+			name:       "constraints file",
+			dockerfile: "FROM python:3.12\nRUN pip install -c constraints.txt flask requests",
+		},
+		{
+			name:       "inline constraints file",
+			dockerfile: "FROM python:3.12\nRUN pip install --constraint=constraints.txt flask",
+		},
+		{
+			name:       "require hashes",
+			dockerfile: "FROM python:3.12\nRUN pip install --require-hashes -r requirements.lock",
+		},
+		{
+			name:       "local and remote sources",
+			dockerfile: "FROM python:3.12\nRUN pip install . ./pkg dist/foo-1.0-py3-none-any.whl git+https://github.com/org/repo.git foo-1.0.tar.gz",
+		},
+		{
+			name:       "editable install",
+			dockerfile: "FROM python:3.12\nRUN pip install -e ./src",
+		},
+		{
+			name:       "index options",
+			dockerfile: "FROM python:3.12\nRUN pip install --index-url https://pypi.example.com/simple --trusted-host pypi.example.com flask==3.0.3",
+		},
+		{
+			name:       "pip3",
+			dockerfile: "FROM python:3.12\nRUN pip3 install --user black",
+			want:       []string{"black"},
+		},
+		{
+			name:       "python without pip",
+			dockerfile: "FROM python:3.12\nRUN python3 -m venv /venv && python3 setup.py install",
+		},
+	})
+}
@@ -0,0 +1,112 @@
+package hadolint
+
+import (
+	"strings"
+
+	"github.com/tinovyatkin/tally/internal/rules"
+	"github.com/tinovyatkin/tally/internal/rules/configutil"
+	"github.com/tinovyatkin/tally/internal/shell"
+)
+
+// DL3016Config is the configuration for the DL3016 rule.
+type DL3016Config PinningConfig
+
+// DL3016Rule implements the DL3016 linting rule.
+// It warns when npm install is given a package without a version.
+type DL3016Rule struct{}
+
+// NewDL3016Rule creates a new DL3016 rule instance.
+func NewDL3016Rule() *DL3016Rule {
+	return &DL3016Rule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *DL3016Rule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            rules.HadolintRulePrefix + "DL3016",
+		Name:            "Pin versions in npm install",
+		Description:     "Pin versions in npm. Instead of `npm install <package>` use `npm install <package>@<version>`",
+		DocURL:          "https://github.com/hadolint/hadolint/wiki/DL3016",
+		DefaultSeverity: rules.SeverityWarning,
+		Category:        "reproducibility",
+		IsExperimental:  false,
+	}
+}
+
+// Schema returns the JSON Schema for this rule's configuration.
+func (r *DL3016Rule) Schema() map[string]any {
+	return pinningSchema()
+}
+
+// DefaultConfig returns the default configuration for this rule.
+func (r *DL3016Rule) DefaultConfig() any {
+	return DL3016Config(DefaultPinningConfig())
+}
+
+// ValidateConfig validates the configuration against the rule's JSON Schema.
+func (r *DL3016Rule) ValidateConfig(config any) error {
+	return configutil.ValidateWithSchema(config, r.Schema())
+}
+
+// npmValueFlags are the npm install options that take a separate value.
+var npmValueFlags = []string{
+	"--prefix", "--registry", "--cache", "--userconfig", "-w", "--workspace",
+	"--tag", "--omit", "--include", "--save-prefix",
+}
+
+// npmSyntax is the npm package syntax: package@version or @scope/package@version.
+var npmSyntax = packageSyntax{
+	pinned: func(arg string) bool {
+		// Protocols (git+https:, file:, github:, npm:), git refs (#ref) and
+		// local tarballs or folders name a specific source.
+		if strings.ContainsAny(arg, ":#") || isLocalPackage(arg, ".tgz", ".tar.gz", ".tar") {
+			return true
+		}
+		// GitHub shorthand (user/repo), as opposed to a scoped package.
+		if !strings.HasPrefix(arg, "@") && strings.Contains(arg, "/") {
+			return true
+		}
+		_, version := splitNpmPackage(arg)
+		return version != "" && version != "latest"
+	},
+	name: func(arg string) string {
+		name, _ := splitNpmPackage(arg)
+		return name
+	},
+	example: "%s@<version>",
+}
+
+// splitNpmPackage splits an npm package spec into name and version,
+// skipping the leading @ of a scoped package.
+func splitNpmPackage(arg string) (string, string) {
+	start := 0
+	if strings.HasPrefix(arg, "@") {
+		start = 1
+	}
+	if i := strings.Index(arg[start:], "@"); i >= 0 {
+		return arg[:start+i], arg[start+i+1:]
+	}
+	return arg, ""
+}
+
+// Check runs the DL3016 rule.
+// npm ci installs from package-lock.json, so only npm install is checked.
+func (r *DL3016Rule) Check(input rules.LintInput) []rules.Violation {
+	return checkPinning(input, r.Metadata(), r.resolveConfig(input.Config).Allowlist, pinningSpec{
+		commands: []string{"npm"},
+		packages: func(cmd *shell.CommandInfo) ([]shell.Word, packageSyntax) {
+			operands := commandOperands(cmd.Words, npmValueFlags...)
+			return subcommandOperands(operands, []string{"install"}, []string{"i"}, []string{"add"}), npmSyntax
+		},
+	})
+}
+
+// resolveConfig extracts the DL3016Config from input, falling back to defaults.
+func (r *DL3016Rule) resolveConfig(config any) DL3016Config {
+	return configutil.Coerce(config, DL3016Config(DefaultPinningConfig()))
+}
+
+// init registers the rule with the default registry.
+func init() {
+	rules.Register(NewDL3016Rule())
+}
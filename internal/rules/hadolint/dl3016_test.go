@@ -0,0 +1,49 @@
+package hadolint
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func TestDL3016Rule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewDL3016Rule().Metadata())
+}
+
+func TestDL3016Rule_Check(t *testing.T) {
+	t.Parallel()
+	runPinningCases(t, NewDL3016Rule(), []pinningCase{
+		{
+			name:       "unpinned packages",
+			dockerfile: "FROM node:22\nRUN npm install -g express typescript",
+			want:       []string{"express", "typescript"},
+		},
+		{
+			name:       "pinned packages",
+			dockerfile: "FROM node:22\nRUN npm install -g express@4.19.2 typescript@~5.4.0",
+		},
+		{
+			name:       "scoped packages",
+			dockerfile: "FROM node:22\nRUN npm i @angular/cli @types/node@20.14.2",
+			want:       []string{"@angular/cli"},
+		},
+		{
+			name:       "latest tag",
+			dockerfile: "FROM node:22\nRUN npm add pnpm@latest",
+			want:       []string{"pnpm"},
+		},
+		{
+			name:       "git, url and local sources",
+			dockerfile: "FROM node:22\nRUN npm install git+https://github.com/org/repo.git user/repo#v1.0.0 ./local-pkg ./foo-1.0.0.tgz file:../shared",
+		},
+		{
+			name:       "install from lockfile",
+			dockerfile: "FROM node:22\nRUN npm ci && npm install",
+		},
+		{
+			name:       "registry option",
+			dockerfile: "FROM node:22\nRUN npm install --registry https://registry.example.com express@4.19.2",
+		},
+	})
+}
@@ -0,0 +1,88 @@
+package hadolint
+
+import (
+	"strings"
+
+	"github.com/tinovyatkin/tally/internal/rules"
+	"github.com/tinovyatkin/tally/internal/rules/configutil"
+	"github.com/tinovyatkin/tally/internal/shell"
+)
+
+// DL3018Config is the configuration for the DL3018 rule.
+type DL3018Config PinningConfig
+
+// DL3018Rule implements the DL3018 linting rule.
+// It warns when apk add is given a package without a version.
+type DL3018Rule struct{}
+
+// NewDL3018Rule creates a new DL3018 rule instance.
+func NewDL3018Rule() *DL3018Rule {
+	return &DL3018Rule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *DL3018Rule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            rules.HadolintRulePrefix + "DL3018",
+		Name:            "Pin versions in apk add",
+		Description:     "Pin versions in apk add. Instead of `apk add <package>` use `apk add <package>=<version>`",
+		DocURL:          "https://github.com/hadolint/hadolint/wiki/DL3018",
+		DefaultSeverity: rules.SeverityWarning,
+		Category:        "reproducibility",
+		IsExperimental:  false,
+	}
+}
+
+// Schema returns the JSON Schema for this rule's configuration.
+func (r *DL3018Rule) Schema() map[string]any {
+	return pinningSchema()
+}
+
+// DefaultConfig returns the default configuration for this rule.
+func (r *DL3018Rule) DefaultConfig() any {
+	return DL3018Config(DefaultPinningConfig())
+}
+
+// ValidateConfig validates the configuration against the rule's JSON Schema.
+func (r *DL3018Rule) ValidateConfig(config any) error {
+	return configutil.ValidateWithSchema(config, r.Schema())
+}
+
+// apkValueFlags are the apk options that take a separate value.
+var apkValueFlags = []string{
+	"-t", "--virtual", "-X", "--repository", "-p", "--root", "--arch",
+	"--keys-dir", "--cache-dir", "--repositories-file",
+}
+
+// apkSyntax is the apk package syntax: package=version, package~version
+// (fuzzy match) or a version range such as package>=version.
+var apkSyntax = packageSyntax{
+	pinned: func(arg string) bool {
+		// Local .apk files carry their own version.
+		return strings.ContainsAny(arg, "=~<>") || isLocalPackage(arg, ".apk")
+	},
+	name: func(arg string) string {
+		return nameBefore(arg, "=~<>")
+	},
+	example: "%s=<version>",
+}
+
+// Check runs the DL3018 rule.
+func (r *DL3018Rule) Check(input rules.LintInput) []rules.Violation {
+	return checkPinning(input, r.Metadata(), r.resolveConfig(input.Config).Allowlist, pinningSpec{
+		commands: []string{"apk"},
+		packages: func(cmd *shell.CommandInfo) ([]shell.Word, packageSyntax) {
+			return subcommandOperands(commandOperands(cmd.Words, apkValueFlags...), []string{"add"}), apkSyntax
+		},
+	})
+}
+
+// resolveConfig extracts the DL3018Config from input, falling back to defaults.
+func (r *DL3018Rule) resolveConfig(config any) DL3018Config {
+	return configutil.Coerce(config, DL3018Config(DefaultPinningConfig()))
+}
+
+// init registers the rule with the default registry.
+func init() {
+	rules.Register(NewDL3018Rule())
+}
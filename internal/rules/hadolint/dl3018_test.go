@@ -0,0 +1,44 @@
+package hadolint
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func TestDL3018Rule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewDL3018Rule().Metadata())
+}
+
+func TestDL3018Rule_Check(t *testing.T) {
+	t.Parallel()
+	runPinningCases(t, NewDL3018Rule(), []pinningCase{
+		{
+			name:       "unpinned packages",
+			dockerfile: "FROM alpine:3.20\nRUN apk add --no-cache curl git",
+			want:       []string{"curl", "git"},
+		},
+		{
+			name:       "pinned packages",
+			dockerfile: "FROM alpine:3.20\nRUN apk add --no-cache curl=8.9.1-r2 git~2.45 \"openssl>3.3\"",
+		},
+		{
+			name:       "virtual package name is not a package",
+			dockerfile: "FROM alpine:3.20\nRUN apk add --virtual .build-deps gcc=13.2.1_git20240309-r0",
+		},
+		{
+			name:       "repository option",
+			dockerfile: "FROM alpine:3.20\nRUN apk add -X https://dl-cdn.alpinelinux.org/alpine/edge/testing foo",
+			want:       []string{"foo"},
+		},
+		{
+			name:       "local apk file",
+			dockerfile: "FROM alpine:3.20\nRUN apk add --allow-untrusted /tmp/foo-1.0-r0.apk",
+		},
+		{
+			name:       "apk update",
+			dockerfile: "FROM alpine:3.20\nRUN apk update && apk upgrade",
+		},
+	})
+}
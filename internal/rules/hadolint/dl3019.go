@@ -0,0 +1,55 @@
+package hadolint
+
+import (
+	"github.com/tinovyatkin/tally/internal/rules"
+	"github.com/tinovyatkin/tally/internal/shell"
+)
+
+// DL3019Rule implements the DL3019 linting rule.
+// It warns when apk add commands don't use the --no-cache switch.
+type DL3019Rule struct{}
+
+// NewDL3019Rule creates a new DL3019 rule instance.
+func NewDL3019Rule() *DL3019Rule {
+	return &DL3019Rule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *DL3019Rule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            rules.HadolintRulePrefix + "DL3019",
+		Name:            "Use --no-cache with apk add",
+		Description:     "Use the `--no-cache` switch to avoid the need to use `--update` and remove `/var/cache/apk/*` when done installing packages",
+		DocURL:          "https://github.com/hadolint/hadolint/wiki/DL3019",
+		DefaultSeverity: rules.SeverityInfo,
+		Category:        "performance",
+		IsExperimental:  false,
+		Fixable:         true,
+		FixSafety:       rules.FixSafe,
+	}
+}
+
+// Check runs the DL3019 rule.
+func (r *DL3019Rule) Check(input rules.LintInput) []rules.Violation {
+	trigger := cacheTrigger(apkValueFlags, "add")
+	return checkCache(input, r.Metadata(), cacheSpec{
+		commands: []string{"apk"},
+		trigger: func(cmd *shell.CommandInfo) (shell.Word, bool) {
+			if cmd.HasFlag("--no-cache") {
+				return shell.Word{}, false
+			}
+			return trigger(cmd)
+		},
+		cacheDirs:    []string{"/var/cache/apk"},
+		cacheDir:     staticCacheDir("/var/cache/apk"),
+		mountOptions: "sharing=locked",
+		flag:         "--no-cache",
+		detail: "Without --no-cache, apk add stores the package index in /var/cache/apk, which stays in " +
+			"the layer. Use --no-cache, or mount a cache on /var/cache/apk so the index never reaches the image.",
+	})
+}
+
+// init registers the rule with the default registry.
+func init() {
+	rules.Register(NewDL3019Rule())
+}
@@ -0,0 +1,53 @@
+package hadolint
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func TestDL3019Rule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewDL3019Rule().Metadata())
+}
+
+func TestDL3019Rule_Check(t *testing.T) {
+	t.Parallel()
+	runCacheCases(t, NewDL3019Rule(), []cacheCase{
+		{
+			name:       "apk add without --no-cache",
+			dockerfile: "FROM alpine:3.20\nRUN apk add curl",
+			want:       1,
+		},
+		{
+			name:       "apk add --update",
+			dockerfile: "FROM alpine:3.20\nRUN apk add --update curl",
+			want:       1,
+		},
+		{
+			name:       "apk add --no-cache",
+			dockerfile: "FROM alpine:3.20\nRUN apk add --no-cache curl",
+		},
+		{
+			name:       "apk --no-cache before the subcommand",
+			dockerfile: "FROM alpine:3.20\nRUN apk --no-cache add curl",
+		},
+		{
+			name:       "one of two installs without --no-cache",
+			dockerfile: "FROM alpine:3.20\nRUN apk add --no-cache curl && apk add git",
+			want:       1,
+		},
+		{
+			name:       "cache removed",
+			dockerfile: "FROM alpine:3.20\nRUN apk add --update curl && rm -rf /var/cache/apk/*",
+		},
+		{
+			name:       "cache mount",
+			dockerfile: "FROM alpine:3.20\nRUN --mount=type=cache,target=/var/cache/apk apk add curl",
+		},
+		{
+			name:       "apk del",
+			dockerfile: "FROM alpine:3.20\nRUN apk del curl",
+		},
+	})
+}
@@ -0,0 +1,93 @@
+package hadolint
+
+import (
+	"strings"
+
+	"github.com/tinovyatkin/tally/internal/rules"
+	"github.com/tinovyatkin/tally/internal/rules/configutil"
+	"github.com/tinovyatkin/tally/internal/shell"
+)
+
+// DL3028Config is the configuration for the DL3028 rule.
+type DL3028Config PinningConfig
+
+// DL3028Rule implements the DL3028 linting rule.
+// It warns when gem install is given a gem without a version.
+type DL3028Rule struct{}
+
+// NewDL3028Rule creates a new DL3028 rule instance.
+func NewDL3028Rule() *DL3028Rule {
+	return &DL3028Rule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *DL3028Rule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            rules.HadolintRulePrefix + "DL3028",
+		Name:            "Pin versions in gem install",
+		Description:     "Pin versions in gem install. Instead of `gem install <gem>` use `gem install <gem>:<version>`",
+		DocURL:          "https://github.com/hadolint/hadolint/wiki/DL3028",
+		DefaultSeverity: rules.SeverityWarning,
+		Category:        "reproducibility",
+		IsExperimental:  false,
+	}
+}
+
+// Schema returns the JSON Schema for this rule's configuration.
+func (r *DL3028Rule) Schema() map[string]any {
+	return pinningSchema()
+}
+
+// DefaultConfig returns the default configuration for this rule.
+func (r *DL3028Rule) DefaultConfig() any {
+	return DL3028Config(DefaultPinningConfig())
+}
+
+// ValidateConfig validates the configuration against the rule's JSON Schema.
+func (r *DL3028Rule) ValidateConfig(config any) error {
+	return configutil.ValidateWithSchema(config, r.Schema())
+}
+
+// gemValueFlags are the gem install options that take a separate value.
+var gemValueFlags = []string{
+	"-i", "--install-dir", "-n", "--bindir", "-s", "--source",
+	"-P", "--trust-policy", "--platform",
+}
+
+// gemSyntax is the gem install syntax: gem:version.
+var gemSyntax = packageSyntax{
+	pinned: func(arg string) bool {
+		// Local .gem files carry their own version.
+		return strings.Contains(arg, ":") || isLocalPackage(arg, ".gem")
+	},
+	name: func(arg string) string {
+		return nameBefore(arg, ":")
+	},
+	example: "%s:<version>",
+}
+
+// Check runs the DL3028 rule.
+func (r *DL3028Rule) Check(input rules.LintInput) []rules.Violation {
+	return checkPinning(input, r.Metadata(), r.resolveConfig(input.Config).Allowlist, pinningSpec{
+		commands: []string{"gem"},
+		packages: func(cmd *shell.CommandInfo) ([]shell.Word, packageSyntax) {
+			// --version applies to every gem on the command line, and
+			// --file installs from a Gemfile (and its Gemfile.lock).
+			if hasOption(cmd.Words, "-v", "--version", "-g", "--file") {
+				return nil, gemSyntax
+			}
+			operands := commandOperands(cmd.Words, gemValueFlags...)
+			return subcommandOperands(operands, []string{"install"}, []string{"i"}), gemSyntax
+		},
+	})
+}
+
+// resolveConfig extracts the DL3028Config from input, falling back to defaults.
+func (r *DL3028Rule) resolveConfig(config any) DL3028Config {
+	return configutil.Coerce(config, DL3028Config(DefaultPinningConfig()))
+}
+
+// init registers the rule with the default registry.
+func init() {
+	rules.Register(NewDL3028Rule())
+}
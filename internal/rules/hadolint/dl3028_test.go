@@ -0,0 +1,48 @@
+package hadolint
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func TestDL3028Rule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewDL3028Rule().Metadata())
+}
+
+func TestDL3028Rule_Check(t *testing.T) {
+	t.Parallel()
+	runPinningCases(t, NewDL3028Rule(), []pinningCase{
+		{
+			name:       "unpinned gems",
+			dockerfile: "FROM ruby:3.3\nRUN gem install bundler rake",
+			want:       []string{"bundler", "rake"},
+		},
+		{
+			name:       "pinned gems",
+			dockerfile: "FROM ruby:3.3\nRUN gem install bundler:2.5.11 rake:13.2.1",
+		},
+		{
+			name:       "version flag",
+			dockerfile: "FROM ruby:3.3\nRUN gem install bundler -v 2.5.11",
+		},
+		{
+			name:       "inline version flag",
+			dockerfile: "FROM ruby:3.3\nRUN gem install bundler --version=2.5.11",
+		},
+		{
+			name:       "gemfile",
+			dockerfile: "FROM ruby:3.3\nRUN gem install --file Gemfile",
+		},
+		{
+			name:       "local gem file",
+			dockerfile: "FROM ruby:3.3\nRUN gem install ./pkg/foo-1.0.0.gem",
+		},
+		{
+			name:       "option values are not gems",
+			dockerfile: "FROM ruby:3.3\nRUN gem install --no-document -i /usr/local/gems --source https://rubygems.org rails",
+			want:       []string{"rails"},
+		},
+	})
+}
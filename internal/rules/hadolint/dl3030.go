@@ -24,6 +24,8 @@ func (r *DL3030Rule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityWarning,
 		Category:        "best-practice",
 		IsExperimental:  false,
+		Fixable:         true,
+		FixSafety:       rules.FixSafe,
 	}
 }
 
@@ -0,0 +1,51 @@
+package hadolint
+
+import (
+	"github.com/tinovyatkin/tally/internal/rules"
+	"github.com/tinovyatkin/tally/internal/shell"
+)
+
+// DL3032Rule implements the DL3032 linting rule.
+// It warns when yum install isn't followed by yum clean all in the same RUN.
+type DL3032Rule struct{}
+
+// NewDL3032Rule creates a new DL3032 rule instance.
+func NewDL3032Rule() *DL3032Rule {
+	return &DL3032Rule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *DL3032Rule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            rules.HadolintRulePrefix + "DL3032",
+		Name:            "Run yum clean all after yum install",
+		Description:     "`yum clean all` missing after yum command",
+		DocURL:          "https://github.com/hadolint/hadolint/wiki/DL3032",
+		DefaultSeverity: rules.SeverityWarning,
+		Category:        "performance",
+		IsExperimental:  false,
+		Fixable:         true,
+		FixSafety:       rules.FixSafe,
+	}
+}
+
+// Check runs the DL3032 rule.
+func (r *DL3032Rule) Check(input rules.LintInput) []rules.Violation {
+	return checkCache(input, r.Metadata(), cacheSpec{
+		commands:     []string{"yum"},
+		trigger:      cacheTrigger(rpmValueFlags, yumInstallSubcommands...),
+		cleans:       cleansAll(rpmValueFlags),
+		cacheDirs:    []string{"/var/cache/yum"},
+		cacheDir:     staticCacheDir("/var/cache/yum"),
+		mountOptions: "sharing=locked",
+		cleanup:      func(*shell.CommandInfo) string { return "yum clean all" },
+		detail: "yum keeps repository metadata and packages in /var/cache/yum, which stay in the layer " +
+			"unless removed in the same RUN. Run yum clean all, or mount a cache on /var/cache/yum so " +
+			"they never reach the image.",
+	})
+}
+
+// init registers the rule with the default registry.
+func init() {
+	rules.Register(NewDL3032Rule())
+}
@@ -0,0 +1,49 @@
+package hadolint
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func TestDL3032Rule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewDL3032Rule().Metadata())
+}
+
+func TestDL3032Rule_Check(t *testing.T) {
+	t.Parallel()
+	runCacheCases(t, NewDL3032Rule(), []cacheCase{
+		{
+			name:       "yum install without clean",
+			dockerfile: "FROM centos:7\nRUN yum install -y httpd-2.4.24",
+			want:       1,
+		},
+		{
+			name:       "yum install with clean all",
+			dockerfile: "FROM centos:7\nRUN yum install -y httpd-2.4.24 && yum clean all",
+		},
+		{
+			name:       "yum clean without all",
+			dockerfile: "FROM centos:7\nRUN yum install -y httpd-2.4.24 && yum clean packages",
+			want:       1,
+		},
+		{
+			name:       "cache removed",
+			dockerfile: "FROM centos:7\nRUN yum install -y httpd-2.4.24 && rm -rf /var/cache/yum",
+		},
+		{
+			name:       "cache mount",
+			dockerfile: "FROM centos:7\nRUN --mount=type=cache,target=/var/cache/yum yum install -y httpd-2.4.24",
+		},
+		{
+			name:       "groupinstall",
+			dockerfile: "FROM centos:7\nRUN yum groupinstall -y 'Development Tools'",
+			want:       1,
+		},
+		{
+			name:       "yum remove",
+			dockerfile: "FROM centos:7\nRUN yum remove -y httpd",
+		},
+	})
+}
@@ -0,0 +1,117 @@
+package hadolint
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/tinovyatkin/tally/internal/rules"
+	"github.com/tinovyatkin/tally/internal/rules/configutil"
+	"github.com/tinovyatkin/tally/internal/shell"
+)
+
+// DL3033Config is the configuration for the DL3033 rule.
+type DL3033Config PinningConfig
+
+// DL3033Rule implements the DL3033 linting rule.
+// It warns when yum install is given a package without a version.
+type DL3033Rule struct{}
+
+// NewDL3033Rule creates a new DL3033 rule instance.
+func NewDL3033Rule() *DL3033Rule {
+	return &DL3033Rule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *DL3033Rule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            rules.HadolintRulePrefix + "DL3033",
+		Name:            "Pin versions in yum install",
+		Description:     "Specify version with `yum install -y <package>-<version>`",
+		DocURL:          "https://github.com/hadolint/hadolint/wiki/DL3033",
+		DefaultSeverity: rules.SeverityWarning,
+		Category:        "reproducibility",
+		IsExperimental:  false,
+	}
+}
+
+// Schema returns the JSON Schema for this rule's configuration.
+func (r *DL3033Rule) Schema() map[string]any {
+	return pinningSchema()
+}
+
+// DefaultConfig returns the default configuration for this rule.
+func (r *DL3033Rule) DefaultConfig() any {
+	return DL3033Config(DefaultPinningConfig())
+}
+
+// ValidateConfig validates the configuration against the rule's JSON Schema.
+func (r *DL3033Rule) ValidateConfig(config any) error {
+	return configutil.ValidateWithSchema(config, r.Schema())
+}
+
+// rpmValueFlags are the yum/dnf options that take a separate value.
+var rpmValueFlags = []string{
+	"-c", "--config", "--installroot", "--releasever", "--enablerepo", "--disablerepo",
+	"--repo", "--repoid", "--repofrompath", "-x", "--exclude", "--setopt", "--forcearch",
+	"-d", "--debuglevel", "-e", "--errorlevel", "--downloaddir", "--destdir",
+}
+
+// rpmVersionPattern matches the "-<version>" part of name-version[-release].
+var rpmVersionPattern = regexp.MustCompile(`-[0-9]`)
+
+// rpmSyntax is the yum/dnf package syntax: name-version[-release].
+var rpmSyntax = packageSyntax{
+	pinned: func(arg string) bool {
+		// Groups (@group) have no versions; local and remote .rpm files
+		// carry their own.
+		return rpmVersionPattern.MatchString(arg) || strings.HasPrefix(arg, "@") ||
+			isLocalPackage(arg, ".rpm") || isRemotePackage(arg)
+	},
+	name: func(arg string) string {
+		if loc := rpmVersionPattern.FindStringIndex(arg); loc != nil {
+			return arg[:loc[0]]
+		}
+		return arg
+	},
+	example: "%s-<version>",
+}
+
+// rpmModuleSyntax is the yum/dnf module syntax: name:stream[:version].
+var rpmModuleSyntax = packageSyntax{
+	pinned: func(arg string) bool {
+		return strings.Contains(arg, ":")
+	},
+	name: func(arg string) string {
+		return nameBefore(arg, ":/")
+	},
+	example: "%s:<stream>",
+}
+
+// rpmPinningSpec returns the pinning spec shared by yum (DL3033) and dnf (DL3041).
+func rpmPinningSpec(commands ...string) pinningSpec {
+	return pinningSpec{
+		commands: commands,
+		packages: func(cmd *shell.CommandInfo) ([]shell.Word, packageSyntax) {
+			operands := commandOperands(cmd.Words, rpmValueFlags...)
+			if modules := subcommandOperands(operands, []string{"module", "install"}); modules != nil {
+				return modules, rpmModuleSyntax
+			}
+			return subcommandOperands(operands, []string{"install"}), rpmSyntax
+		},
+	}
+}
+
+// Check runs the DL3033 rule.
+func (r *DL3033Rule) Check(input rules.LintInput) []rules.Violation {
+	return checkPinning(input, r.Metadata(), r.resolveConfig(input.Config).Allowlist, rpmPinningSpec("yum"))
+}
+
+// resolveConfig extracts the DL3033Config from input, falling back to defaults.
+func (r *DL3033Rule) resolveConfig(config any) DL3033Config {
+	return configutil.Coerce(config, DL3033Config(DefaultPinningConfig()))
+}
+
+// init registers the rule with the default registry.
+func init() {
+	rules.Register(NewDL3033Rule())
+}
@@ -0,0 +1,48 @@
+package hadolint
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func TestDL3033Rule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewDL3033Rule().Metadata())
+}
+
+func TestDL3033Rule_Check(t *testing.T) {
+	t.Parallel()
+	runPinningCases(t, NewDL3033Rule(), []pinningCase{
+		{
+			name:       "unpinned packages",
+			dockerfile: "FROM centos:7\nRUN yum install -y httpd python3-pip",
+			want:       []string{"httpd", "python3-pip"},
+		},
+		{
+			name:       "pinned packages",
+			dockerfile: "FROM centos:7\nRUN yum install -y httpd-2.4.6 python3-pip-9.0.3-8.el7",
+		},
+		{
+			name:       "rpm file and group",
+			dockerfile: "FROM centos:7\nRUN yum install -y https://example.com/foo.rpm /tmp/bar.rpm @development",
+		},
+		{
+			name:       "repository options",
+			dockerfile: "FROM centos:7\nRUN yum --enablerepo epel install -y jq-1.6",
+		},
+		{
+			name:       "module without stream",
+			dockerfile: "FROM centos:8\nRUN yum module install -y nodejs",
+			want:       []string{"nodejs"},
+		},
+		{
+			name:       "module with stream",
+			dockerfile: "FROM centos:8\nRUN yum module install -y nodejs:18/common",
+		},
+		{
+			name:       "dnf is not checked",
+			dockerfile: "FROM fedora\nRUN dnf install -y httpd",
+		},
+	})
+}
@@ -0,0 +1,90 @@
+package hadolint
+
+import (
+	"strings"
+
+	"github.com/tinovyatkin/tally/internal/rules"
+	"github.com/tinovyatkin/tally/internal/rules/configutil"
+	"github.com/tinovyatkin/tally/internal/shell"
+)
+
+// DL3037Config is the configuration for the DL3037 rule.
+type DL3037Config PinningConfig
+
+// DL3037Rule implements the DL3037 linting rule.
+// It warns when zypper install is given a package without a version.
+type DL3037Rule struct{}
+
+// NewDL3037Rule creates a new DL3037 rule instance.
+func NewDL3037Rule() *DL3037Rule {
+	return &DL3037Rule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *DL3037Rule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            rules.HadolintRulePrefix + "DL3037",
+		Name:            "Pin versions in zypper install",
+		Description:     "Specify version with `zypper install -y <package>[=]<version>`.",
+		DocURL:          "https://github.com/hadolint/hadolint/wiki/DL3037",
+		DefaultSeverity: rules.SeverityWarning,
+		Category:        "reproducibility",
+		IsExperimental:  false,
+	}
+}
+
+// Schema returns the JSON Schema for this rule's configuration.
+func (r *DL3037Rule) Schema() map[string]any {
+	return pinningSchema()
+}
+
+// DefaultConfig returns the default configuration for this rule.
+func (r *DL3037Rule) DefaultConfig() any {
+	return DL3037Config(DefaultPinningConfig())
+}
+
+// ValidateConfig validates the configuration against the rule's JSON Schema.
+func (r *DL3037Rule) ValidateConfig(config any) error {
+	return configutil.ValidateWithSchema(config, r.Schema())
+}
+
+// zypperValueFlags are the zypper options that take a separate value.
+var zypperValueFlags = []string{
+	"-R", "--root", "-c", "--config", "-D", "--reposd-dir", "-C", "--cache-dir",
+	"--raw-cache-dir", "--solv-cache-dir", "--pkg-cache-dir",
+	"-r", "--repo", "--from", "-t", "--type",
+}
+
+// zypperSyntax is the zypper package syntax: package=version or a version
+// range such as package>=version.
+var zypperSyntax = packageSyntax{
+	pinned: func(arg string) bool {
+		// Local and remote .rpm files carry their own version.
+		return strings.ContainsAny(arg, "=<>") || isLocalPackage(arg, ".rpm") || isRemotePackage(arg)
+	},
+	name: func(arg string) string {
+		return nameBefore(arg, "=<>")
+	},
+	example: "%s=<version>",
+}
+
+// Check runs the DL3037 rule.
+func (r *DL3037Rule) Check(input rules.LintInput) []rules.Violation {
+	return checkPinning(input, r.Metadata(), r.resolveConfig(input.Config).Allowlist, pinningSpec{
+		commands: []string{"zypper"},
+		packages: func(cmd *shell.CommandInfo) ([]shell.Word, packageSyntax) {
+			operands := commandOperands(cmd.Words, zypperValueFlags...)
+			return subcommandOperands(operands, []string{"install"}, []string{"in"}), zypperSyntax
+		},
+	})
+}
+
+// resolveConfig extracts the DL3037Config from input, falling back to defaults.
+func (r *DL3037Rule) resolveConfig(config any) DL3037Config {
+	return configutil.Coerce(config, DL3037Config(DefaultPinningConfig()))
+}
+
+// init registers the rule with the default registry.
+func init() {
+	rules.Register(NewDL3037Rule())
+}
@@ -0,0 +1,40 @@
+package hadolint
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func TestDL3037Rule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewDL3037Rule().Metadata())
+}
+
+func TestDL3037Rule_Check(t *testing.T) {
+	t.Parallel()
+	runPinningCases(t, NewDL3037Rule(), []pinningCase{
+		{
+			name:       "unpinned packages",
+			dockerfile: "FROM opensuse/leap\nRUN zypper install -y httpd",
+			want:       []string{"httpd"},
+		},
+		{
+			name:       "in alias",
+			dockerfile: "FROM opensuse/leap\nRUN zypper -n in curl",
+			want:       []string{"curl"},
+		},
+		{
+			name:       "pinned packages",
+			dockerfile: "FROM opensuse/leap\nRUN zypper install -y httpd=2.4 \"curl>=8.0\" 'git<3'",
+		},
+		{
+			name:       "repository option",
+			dockerfile: "FROM opensuse/leap\nRUN zypper --non-interactive install --from oss jq=1.7",
+		},
+		{
+			name:       "rpm file",
+			dockerfile: "FROM opensuse/leap\nRUN zypper install -y /tmp/foo.rpm",
+		},
+	})
+}
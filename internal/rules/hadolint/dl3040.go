@@ -0,0 +1,60 @@
+package hadolint
+
+import (
+	"github.com/tinovyatkin/tally/internal/rules"
+	"github.com/tinovyatkin/tally/internal/shell"
+)
+
+// DL3040Rule implements the DL3040 linting rule.
+// It warns when dnf or microdnf install isn't followed by a clean all in the same RUN.
+type DL3040Rule struct{}
+
+// NewDL3040Rule creates a new DL3040 rule instance.
+func NewDL3040Rule() *DL3040Rule {
+	return &DL3040Rule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *DL3040Rule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            rules.HadolintRulePrefix + "DL3040",
+		Name:            "Run dnf clean all after dnf install",
+		Description:     "`dnf clean all` missing after dnf command",
+		DocURL:          "https://github.com/hadolint/hadolint/wiki/DL3040",
+		DefaultSeverity: rules.SeverityWarning,
+		Category:        "performance",
+		IsExperimental:  false,
+		Fixable:         true,
+		FixSafety:       rules.FixSafe,
+	}
+}
+
+// dnfCacheDir returns the cache directory of dnf or microdnf, which keeps
+// its cache where yum did.
+func dnfCacheDir(cmd *shell.CommandInfo) string {
+	if cmd.Name == "microdnf" {
+		return "/var/cache/yum"
+	}
+	return "/var/cache/dnf"
+}
+
+// Check runs the DL3040 rule.
+func (r *DL3040Rule) Check(input rules.LintInput) []rules.Violation {
+	return checkCache(input, r.Metadata(), cacheSpec{
+		commands:     []string{"dnf", "microdnf"},
+		trigger:      cacheTrigger(rpmValueFlags, dnfInstallSubcommands...),
+		cleans:       cleansAll(rpmValueFlags),
+		cacheDirs:    []string{"/var/cache/dnf", "/var/cache/yum"},
+		cacheDir:     dnfCacheDir,
+		mountOptions: "sharing=locked",
+		cleanup:      func(cmd *shell.CommandInfo) string { return cmd.Name + " clean all" },
+		detail: "dnf keeps repository metadata and packages in /var/cache/dnf (microdnf in /var/cache/yum), " +
+			"which stay in the layer unless removed in the same RUN. Run dnf clean all, or mount a cache " +
+			"on the cache directory so they never reach the image.",
+	})
+}
+
+// init registers the rule with the default registry.
+func init() {
+	rules.Register(NewDL3040Rule())
+}
@@ -0,0 +1,48 @@
+package hadolint
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func TestDL3040Rule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewDL3040Rule().Metadata())
+}
+
+func TestDL3040Rule_Check(t *testing.T) {
+	t.Parallel()
+	runCacheCases(t, NewDL3040Rule(), []cacheCase{
+		{
+			name:       "dnf install without clean",
+			dockerfile: "FROM fedora:40\nRUN dnf install -y httpd-2.4.24",
+			want:       1,
+		},
+		{
+			name:       "dnf install with clean all",
+			dockerfile: "FROM fedora:40\nRUN dnf install -y httpd-2.4.24 && dnf clean all",
+		},
+		{
+			name:       "microdnf install without clean",
+			dockerfile: "FROM ubi9-minimal\nRUN microdnf install -y httpd",
+			want:       1,
+		},
+		{
+			name:       "microdnf install with clean all",
+			dockerfile: "FROM ubi9-minimal\nRUN microdnf install -y httpd && microdnf clean all",
+		},
+		{
+			name:       "cache removed",
+			dockerfile: "FROM fedora:40\nRUN dnf install -y httpd-2.4.24 && rm -rf /var/cache/dnf/*",
+		},
+		{
+			name:       "cache mount",
+			dockerfile: "FROM fedora:40\nRUN --mount=type=cache,target=/var/cache/dnf dnf install -y httpd-2.4.24",
+		},
+		{
+			name:       "yum is DL3032",
+			dockerfile: "FROM centos:7\nRUN yum install -y httpd-2.4.24",
+		},
+	})
+}
@@ -0,0 +1,61 @@
+package hadolint
+
+import (
+	"github.com/tinovyatkin/tally/internal/rules"
+	"github.com/tinovyatkin/tally/internal/rules/configutil"
+)
+
+// DL3041Config is the configuration for the DL3041 rule.
+type DL3041Config PinningConfig
+
+// DL3041Rule implements the DL3041 linting rule.
+// It warns when dnf or microdnf install is given a package without a version.
+type DL3041Rule struct{}
+
+// NewDL3041Rule creates a new DL3041 rule instance.
+func NewDL3041Rule() *DL3041Rule {
+	return &DL3041Rule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *DL3041Rule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            rules.HadolintRulePrefix + "DL3041",
+		Name:            "Pin versions in dnf install",
+		Description:     "Specify version with `dnf install -y <package>-<version>`",
+		DocURL:          "https://github.com/hadolint/hadolint/wiki/DL3041",
+		DefaultSeverity: rules.SeverityWarning,
+		Category:        "reproducibility",
+		IsExperimental:  false,
+	}
+}
+
+// Schema returns the JSON Schema for this rule's configuration.
+func (r *DL3041Rule) Schema() map[string]any {
+	return pinningSchema()
+}
+
+// DefaultConfig returns the default configuration for this rule.
+func (r *DL3041Rule) DefaultConfig() any {
+	return DL3041Config(DefaultPinningConfig())
+}
+
+// ValidateConfig validates the configuration against the rule's JSON Schema.
+func (r *DL3041Rule) ValidateConfig(config any) error {
+	return configutil.ValidateWithSchema(config, r.Schema())
+}
+
+// Check runs the DL3041 rule.
+func (r *DL3041Rule) Check(input rules.LintInput) []rules.Violation {
+	return checkPinning(input, r.Metadata(), r.resolveConfig(input.Config).Allowlist, rpmPinningSpec("dnf", "microdnf"))
+}
+
+// resolveConfig extracts the DL3041Config from input, falling back to defaults.
+func (r *DL3041Rule) resolveConfig(config any) DL3041Config {
+	return configutil.Coerce(config, DL3041Config(DefaultPinningConfig()))
+}
+
+// init registers the rule with the default registry.
+func init() {
+	rules.Register(NewDL3041Rule())
+}
@@ -0,0 +1,45 @@
+package hadolint
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func TestDL3041Rule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewDL3041Rule().Metadata())
+}
+
+func TestDL3041Rule_Check(t *testing.T) {
+	t.Parallel()
+	runPinningCases(t, NewDL3041Rule(), []pinningCase{
+		{
+			name:       "unpinned packages",
+			dockerfile: "FROM fedora\nRUN dnf install -y httpd",
+			want:       []string{"httpd"},
+		},
+		{
+			name:       "microdnf",
+			dockerfile: "FROM registry.access.redhat.com/ubi9/ubi-minimal\nRUN microdnf install -y tar gzip-1.12",
+			want:       []string{"tar"},
+		},
+		{
+			name:       "pinned packages",
+			dockerfile: "FROM fedora\nRUN dnf install -y httpd-2.4.62-1.fc40",
+		},
+		{
+			name:       "module without stream",
+			dockerfile: "FROM fedora\nRUN dnf module install -y postgresql",
+			want:       []string{"postgresql"},
+		},
+		{
+			name:       "module with stream",
+			dockerfile: "FROM fedora\nRUN dnf module install -y postgresql:16",
+		},
+		{
+			name:       "yum is not checked",
+			dockerfile: "FROM centos:7\nRUN yum install -y httpd",
+		},
+	})
+}
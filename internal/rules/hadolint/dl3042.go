@@ -0,0 +1,90 @@
+package hadolint
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/moby/buildkit/frontend/dockerfile/instructions"
+
+	"github.com/tinovyatkin/tally/internal/rules"
+	"github.com/tinovyatkin/tally/internal/shell"
+)
+
+// DL3042Rule implements the DL3042 linting rule.
+// It warns when pip install keeps its download cache in the image.
+type DL3042Rule struct{}
+
+// NewDL3042Rule creates a new DL3042 rule instance.
+func NewDL3042Rule() *DL3042Rule {
+	return &DL3042Rule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *DL3042Rule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            rules.HadolintRulePrefix + "DL3042",
+		Name:            "Use --no-cache-dir with pip install",
+		Description:     "Avoid use of cache directory with pip. Use `pip install --no-cache-dir <package>`",
+		DocURL:          "https://github.com/hadolint/hadolint/wiki/DL3042",
+		DefaultSeverity: rules.SeverityWarning,
+		Category:        "performance",
+		IsExperimental:  false,
+		Fixable:         true,
+		FixSafety:       rules.FixSafe,
+	}
+}
+
+// pipCacheDir is the pip cache directory of the root user.
+const pipCacheDir = "/root/.cache/pip"
+
+// Check runs the DL3042 rule.
+func (r *DL3042Rule) Check(input rules.LintInput) []rules.Violation {
+	noCacheRuns := pipNoCacheRuns(input.Stages)
+	return checkCache(input, r.Metadata(), cacheSpec{
+		commands: pipCommands,
+		trigger: func(cmd *shell.CommandInfo) (shell.Word, bool) {
+			words, ok := pipArgs(cmd)
+			if !ok || hasOption(words, "--no-cache-dir") {
+				return shell.Word{}, false
+			}
+			return subcommandWord(commandOperands(words, pipValueFlags...), "install")
+		},
+		cacheDirs: []string{pipCacheDir},
+		cacheDir:  staticCacheDir(pipCacheDir),
+		flag:      "--no-cache-dir",
+		skip: func(run *instructions.RunCommand) bool {
+			return noCacheRuns[run]
+		},
+		detail: "pip install keeps downloaded packages and built wheels in " + pipCacheDir + ", which stay " +
+			"in the layer. Use --no-cache-dir, or mount a cache on " + pipCacheDir + " so they never reach the image.",
+	})
+}
+
+// pipNoCacheRuns returns the RUN commands that follow an ENV instruction
+// disabling the pip cache (PIP_NO_CACHE_DIR) in their stage.
+func pipNoCacheRuns(stages []instructions.Stage) map[*instructions.RunCommand]bool {
+	runs := make(map[*instructions.RunCommand]bool)
+	for _, stage := range stages {
+		disabled := false
+		for _, cmd := range stage.Commands {
+			switch c := cmd.(type) {
+			case *instructions.EnvCommand:
+				for _, kv := range c.Env {
+					if kv.Key == "PIP_NO_CACHE_DIR" {
+						disabled = !slices.Contains([]string{"", "0", "false", "no", "off"}, strings.ToLower(kv.Value))
+					}
+				}
+			case *instructions.RunCommand:
+				if disabled {
+					runs[c] = true
+				}
+			}
+		}
+	}
+	return runs
+}
+
+// init registers the rule with the default registry.
+func init() {
+	rules.Register(NewDL3042Rule())
+}
@@ -0,0 +1,62 @@
+package hadolint
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func TestDL3042Rule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewDL3042Rule().Metadata())
+}
+
+func TestDL3042Rule_Check(t *testing.T) {
+	t.Parallel()
+	runCacheCases(t, NewDL3042Rule(), []cacheCase{
+		{
+			name:       "pip install without --no-cache-dir",
+			dockerfile: "FROM python:3.12\nRUN pip install MySQL_python",
+			want:       1,
+		},
+		{
+			name:       "pip install --no-cache-dir",
+			dockerfile: "FROM python:3.12\nRUN pip install --no-cache-dir MySQL_python",
+		},
+		{
+			name:       "pip3 --no-cache-dir before the subcommand",
+			dockerfile: "FROM python:3.12\nRUN pip3 --no-cache-dir install MySQL_python",
+		},
+		{
+			name:       "python -m pip install",
+			dockerfile: "FROM python:3.12\nRUN python3 -m pip install MySQL_python",
+			want:       1,
+		},
+		{
+			name:       "python without pip",
+			dockerfile: "FROM python:3.12\nRUN python3 -m venv /opt/venv",
+		},
+		{
+			name:       "PIP_NO_CACHE_DIR in the stage environment",
+			dockerfile: "FROM python:3.12\nENV PIP_NO_CACHE_DIR=1\nRUN pip install MySQL_python",
+		},
+		{
+			name:       "PIP_NO_CACHE_DIR disabled",
+			dockerfile: "FROM python:3.12\nENV PIP_NO_CACHE_DIR=false\nRUN pip install MySQL_python",
+			want:       1,
+		},
+		{
+			name:       "PIP_NO_CACHE_DIR set in another stage",
+			dockerfile: "FROM python:3.12 AS base\nENV PIP_NO_CACHE_DIR=1\nFROM python:3.12\nRUN pip install MySQL_python",
+			want:       1,
+		},
+		{
+			name:       "cache mount",
+			dockerfile: "FROM python:3.12\nRUN --mount=type=cache,target=/root/.cache pip install MySQL_python",
+		},
+		{
+			name:       "pip download",
+			dockerfile: "FROM python:3.12\nRUN pip download MySQL_python",
+		},
+	})
+}
@@ -28,6 +28,8 @@ func (r *DL3046Rule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityWarning,
 		Category:        "performance",
 		IsExperimental:  false,
+		Fixable:         true,
+		FixSafety:       rules.FixSafe,
 	}
 }
 
@@ -117,7 +119,7 @@ func (r *DL3046Rule) createAutoFix(
 	}
 
 	sm := input.SourceMap()
-	sourceScript, scriptStartLine := getRunSourceScript(run, sm)
+	sourceScript, scriptStartLine := dockerfile.RunSourceScript(run, sm)
 	if sourceScript == "" {
 		return nil
 	}
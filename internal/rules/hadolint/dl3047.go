@@ -38,6 +38,8 @@ func (r *DL3047Rule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityInfo,
 		Category:        "best-practice",
 		IsExperimental:  false,
+		Fixable:         true,
+		FixSafety:       rules.FixSafe,
 		// FixPriority 96 ensures prefer-add-unpack (priority 95) applies first.
 		// When wget|tar is replaced by ADD --unpack, the progress-bar fix becomes
 		// moot and is harmlessly skipped. For standalone wget the fix still applies.
@@ -90,7 +92,7 @@ func (r *DL3047Rule) Check(input rules.LintInput) []rules.Violation {
 
 			if run.PrependShell {
 				// Shell form: parse original source preserving column positions.
-				script, startLine := getRunSourceScript(run, sm)
+				script, startLine := dockerfile.RunSourceScript(run, sm)
 				if script == "" {
 					return nil
 				}
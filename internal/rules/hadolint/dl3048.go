@@ -0,0 +1,86 @@
+package hadolint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tinovyatkin/tally/internal/rules"
+)
+
+// DL3048Rule implements the DL3048 linting rule.
+// It reports label keys that don't follow Docker's key format guidelines.
+type DL3048Rule struct{}
+
+// NewDL3048Rule creates a new DL3048 rule instance.
+func NewDL3048Rule() *DL3048Rule {
+	return &DL3048Rule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *DL3048Rule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            rules.HadolintRulePrefix + "DL3048",
+		Name:            "Invalid label key",
+		Description:     "Invalid label key",
+		DocURL:          "https://github.com/hadolint/hadolint/wiki/DL3048",
+		DefaultSeverity: rules.SeverityStyle,
+		Category:        "style",
+		IsExperimental:  false,
+	}
+}
+
+// reservedLabelNamespaces are reserved for Docker's internal use.
+var reservedLabelNamespaces = []string{"com.docker.", "io.docker.", "org.dockerproject."}
+
+// Check runs the DL3048 rule.
+// Label keys should start with a lowercase letter, end with a lowercase
+// letter or digit, and contain only lowercase letters, digits, dots and
+// dashes, without consecutive separators. Docker's own namespaces are
+// reserved.
+func (r *DL3048Rule) Check(input rules.LintInput) []rules.Violation {
+	meta := r.Metadata()
+
+	var violations []rules.Violation
+	for _, l := range stageLabels(input) {
+		if l.key == "" || !l.keyResolved {
+			continue
+		}
+		problem := labelKeyProblem(l.key)
+		if problem == "" {
+			continue
+		}
+		violations = append(violations, newLabelViolation(input, meta, l,
+			fmt.Sprintf("invalid label key %q: %s", l.key, problem)).
+			WithDetail("Label keys should use lowercase reverse-DNS notation, e.g. org.opencontainers.image.source."))
+	}
+	return violations
+}
+
+// labelKeyProblem describes why a label key is invalid, or returns "".
+func labelKeyProblem(key string) string {
+	for _, ns := range reservedLabelNamespaces {
+		if strings.HasPrefix(key, ns) {
+			return "the " + strings.TrimSuffix(ns, ".") + " namespace is reserved"
+		}
+	}
+	for _, c := range key {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '.' && c != '-' {
+			return "only lowercase letters, digits, dots and dashes are allowed"
+		}
+	}
+	if key[0] < 'a' || key[0] > 'z' {
+		return "it must start with a lowercase letter"
+	}
+	if last := key[len(key)-1]; last == '.' || last == '-' {
+		return "it must end with a lowercase letter or digit"
+	}
+	if strings.Contains(key, "..") || strings.Contains(key, "--") {
+		return "separators must not repeat"
+	}
+	return ""
+}
+
+// init registers the rule with the default registry.
+func init() {
+	rules.Register(NewDL3048Rule())
+}
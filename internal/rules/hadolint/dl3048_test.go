@@ -0,0 +1,45 @@
+package hadolint
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func TestDL3048Rule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewDL3048Rule().Metadata())
+}
+
+func TestDL3048Rule_Check(t *testing.T) {
+	t.Parallel()
+	runLabelCases(t, NewDL3048Rule(), []labelCase{
+		{
+			name:       "valid keys",
+			dockerfile: "FROM alpine\nLABEL org.opencontainers.image.title=app maintainer=dev com.example.build-id=1",
+		},
+		{
+			name:       "uppercase key",
+			dockerfile: "FROM alpine\nLABEL Maintainer=dev",
+			want:       1,
+		},
+		{
+			name:       "reserved namespace",
+			dockerfile: "FROM alpine\nLABEL com.docker.compose.project=app io.docker.x=1 org.dockerproject.y=2",
+			want:       3,
+		},
+		{
+			name:       "invalid characters and separators",
+			dockerfile: "FROM alpine\nLABEL \"my label\"=1 org..example=2 org.example.=3 1st=4 a--b=5",
+			want:       5,
+		},
+		{
+			name:       "key from a variable",
+			dockerfile: "FROM alpine\nARG NS=org.example\nLABEL $NS.name=app",
+		},
+		{
+			name:       "key from an unknown variable",
+			dockerfile: "FROM alpine\nLABEL ${NS}.Name=app",
+		},
+	})
+}
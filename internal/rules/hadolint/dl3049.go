@@ -0,0 +1,171 @@
+package hadolint
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/tinovyatkin/tally/internal/rules"
+	"github.com/tinovyatkin/tally/internal/rules/configutil"
+	"github.com/tinovyatkin/tally/internal/semantic"
+)
+
+// Stages that must carry the labels of the label schema (DL3049Config.RequiredIn).
+const (
+	// LabelsInFinalStage requires the labels only in the stage being built.
+	LabelsInFinalStage = "final-stage"
+	// LabelsInAllStages requires the labels in every stage.
+	LabelsInAllStages = "all-stages"
+)
+
+// DL3049Config is the configuration for the DL3049 rule.
+type DL3049Config struct {
+	// RequiredIn selects the stages that must carry every label of the
+	// label schema: LabelsInFinalStage (default) or LabelsInAllStages.
+	RequiredIn string `json:"required-in,omitempty" koanf:"required-in"`
+}
+
+// DefaultDL3049Config returns the default configuration.
+func DefaultDL3049Config() DL3049Config {
+	return DL3049Config{RequiredIn: LabelsInFinalStage}
+}
+
+// DL3049Rule implements the DL3049 linting rule.
+type DL3049Rule struct{}
+
+// NewDL3049Rule creates a new DL3049 rule instance.
+func NewDL3049Rule() *DL3049Rule {
+	return &DL3049Rule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *DL3049Rule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            rules.HadolintRulePrefix + "DL3049",
+		Name:            "Label is missing",
+		Description:     "Label `<label>` is missing",
+		DocURL:          "https://github.com/hadolint/hadolint/wiki/DL3049",
+		DefaultSeverity: rules.SeverityInfo,
+		Category:        "best-practice",
+		IsExperimental:  false,
+	}
+}
+
+// Schema returns the JSON Schema for this rule's configuration.
+func (r *DL3049Rule) Schema() map[string]any {
+	return map[string]any{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type":    "object",
+		"properties": map[string]any{
+			"required-in": map[string]any{
+				"type":        "string",
+				"enum":        []any{LabelsInFinalStage, LabelsInAllStages},
+				"default":     LabelsInFinalStage,
+				"description": "Stages that must carry every label of the label schema",
+			},
+		},
+		"additionalProperties": false,
+	}
+}
+
+// DefaultConfig returns the default configuration for this rule.
+func (r *DL3049Rule) DefaultConfig() any {
+	return DefaultDL3049Config()
+}
+
+// ValidateConfig validates the configuration against the rule's JSON Schema.
+func (r *DL3049Rule) ValidateConfig(config any) error {
+	return configutil.ValidateWithSchema(config, r.Schema())
+}
+
+// Check runs the DL3049 rule.
+// It reports each label of the label schema that a required stage doesn't
+// set, at the stage's FROM. Labels set in a stage it is based on
+// (FROM <stage>) count, since images inherit them.
+func (r *DL3049Rule) Check(input rules.LintInput) []rules.Violation {
+	if len(input.LabelSchema) == 0 || len(input.Stages) == 0 {
+		return nil
+	}
+	meta := r.Metadata()
+	cfg := r.resolveConfig(input.Config)
+	sem, ok := input.Semantic.(*semantic.Model)
+	if !ok {
+		sem = nil
+	}
+
+	keys := make(map[int]map[string]bool)
+	for _, l := range stageLabels(input) {
+		if keys[l.stage] == nil {
+			keys[l.stage] = make(map[string]bool)
+		}
+		keys[l.stage][l.key] = true
+	}
+
+	var stages []int
+	if cfg.RequiredIn == LabelsInAllStages {
+		for i := range input.Stages {
+			stages = append(stages, i)
+		}
+	} else {
+		final := len(input.Stages) - 1
+		if sem != nil && sem.TargetStageIndex() >= 0 {
+			final = sem.TargetStageIndex()
+		}
+		stages = []int{final}
+	}
+
+	required := slices.Sorted(maps.Keys(input.LabelSchema))
+
+	var violations []rules.Violation
+	for _, stageIdx := range stages {
+		var missing []string
+		for _, key := range required {
+			if !hasLabel(sem, keys, len(input.Stages), stageIdx, key) {
+				missing = append(missing, key)
+			}
+		}
+		if len(missing) == 0 {
+			continue
+		}
+		loc := rules.NewLocationFromRanges(input.File, input.Stages[stageIdx].Location)
+		for _, key := range missing {
+			violations = append(violations, rules.NewViolation(
+				loc, meta.Code, fmt.Sprintf("label %q is missing", key), meta.DefaultSeverity,
+			).WithDocURL(meta.DocURL).WithDetail(
+				"The label schema requires "+strings.Join(missing, ", ")+" in this stage. "+
+					"Add them with a LABEL instruction.",
+			))
+		}
+	}
+	return violations
+}
+
+// hasLabel reports whether a stage sets a label, itself or through the
+// stages it is based on. The chain is followed at most stageCount times.
+func hasLabel(sem *semantic.Model, keys map[int]map[string]bool, stageCount, stageIdx int, key string) bool {
+	for range stageCount {
+		if keys[stageIdx][key] {
+			return true
+		}
+		if sem == nil {
+			return false
+		}
+		info := sem.StageInfo(stageIdx)
+		if info == nil || info.BaseImage == nil || !info.BaseImage.IsStageRef || info.BaseImage.StageIndex < 0 {
+			return false
+		}
+		stageIdx = info.BaseImage.StageIndex
+	}
+	return false
+}
+
+// resolveConfig extracts the DL3049Config from input, falling back to defaults.
+func (r *DL3049Rule) resolveConfig(config any) DL3049Config {
+	return configutil.Coerce(config, DefaultDL3049Config())
+}
+
+// init registers the rule with the default registry.
+func init() {
+	rules.Register(NewDL3049Rule())
+}
@@ -0,0 +1,68 @@
+package hadolint
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func TestDL3049Rule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewDL3049Rule().Metadata())
+}
+
+func TestDL3049Rule_Check(t *testing.T) {
+	t.Parallel()
+	schema := map[string]string{"version": "semver", "source": "url"}
+	runLabelCases(t, NewDL3049Rule(), []labelCase{
+		{
+			name:       "no schema",
+			dockerfile: "FROM alpine",
+		},
+		{
+			name:       "all labels present",
+			dockerfile: "FROM alpine\nLABEL version=1.0.0 source=https://example.com",
+			schema:     schema,
+		},
+		{
+			name:       "labels missing",
+			dockerfile: "FROM alpine\nLABEL version=1.0.0",
+			schema:     schema,
+			want:       1,
+		},
+		{
+			name:       "only the final stage by default",
+			dockerfile: "FROM alpine AS build\nRUN make\nFROM alpine\nLABEL version=1.0.0 source=https://example.com",
+			schema:     schema,
+		},
+		{
+			name:       "labels only in a build stage",
+			dockerfile: "FROM alpine AS build\nLABEL version=1.0.0 source=https://example.com\nFROM alpine",
+			schema:     schema,
+			want:       2,
+		},
+		{
+			name:       "labels inherited from the base stage",
+			dockerfile: "FROM alpine AS base\nLABEL version=1.0.0\nFROM base\nLABEL source=https://example.com",
+			schema:     schema,
+		},
+		{
+			name:       "all stages",
+			dockerfile: "FROM alpine AS build\nRUN make\nFROM alpine\nLABEL version=1.0.0 source=https://example.com",
+			schema:     schema,
+			config:     map[string]any{"required-in": "all-stages"},
+			want:       2,
+		},
+	})
+}
+
+func TestDL3049Rule_ValidateConfig(t *testing.T) {
+	t.Parallel()
+	r := NewDL3049Rule()
+	if err := r.ValidateConfig(map[string]any{"required-in": "all-stages"}); err != nil {
+		t.Errorf("ValidateConfig() error = %v", err)
+	}
+	if err := r.ValidateConfig(map[string]any{"required-in": "every-stage"}); err == nil {
+		t.Error("ValidateConfig() accepted an unknown required-in")
+	}
+}
@@ -0,0 +1,58 @@
+package hadolint
+
+import (
+	"fmt"
+
+	"github.com/tinovyatkin/tally/internal/rules"
+)
+
+// DL3050Rule implements the DL3050 linting rule.
+// It is hadolint's strict-labels mode and is off by default.
+type DL3050Rule struct{}
+
+// NewDL3050Rule creates a new DL3050 rule instance.
+func NewDL3050Rule() *DL3050Rule {
+	return &DL3050Rule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *DL3050Rule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            rules.HadolintRulePrefix + "DL3050",
+		Name:            "Superfluous label present",
+		Description:     "Superfluous label(s) present",
+		DocURL:          "https://github.com/hadolint/hadolint/wiki/DL3050",
+		DefaultSeverity: rules.SeverityOff, // strict-labels: enable to allow only labels of the schema
+		Category:        "best-practice",
+		IsExperimental:  false,
+	}
+}
+
+// Check runs the DL3050 rule.
+// It reports labels that are not part of the label schema.
+func (r *DL3050Rule) Check(input rules.LintInput) []rules.Violation {
+	if len(input.LabelSchema) == 0 {
+		return nil
+	}
+	meta := r.Metadata()
+
+	var violations []rules.Violation
+	for _, l := range stageLabels(input) {
+		if !l.resolved {
+			continue
+		}
+		if _, ok := input.LabelSchema[l.key]; ok {
+			continue
+		}
+		violations = append(violations, newLabelViolation(input, meta, l,
+			fmt.Sprintf("superfluous label %q is not in the label schema", l.key)).
+			WithDetail("Only the labels of the [label-schema] config are allowed. "+
+				"Add the label to the schema or remove it."))
+	}
+	return violations
+}
+
+// init registers the rule with the default registry.
+func init() {
+	rules.Register(NewDL3050Rule())
+}
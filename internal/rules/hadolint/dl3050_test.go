@@ -0,0 +1,34 @@
+package hadolint
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func TestDL3050Rule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewDL3050Rule().Metadata())
+}
+
+func TestDL3050Rule_Check(t *testing.T) {
+	t.Parallel()
+	schema := map[string]string{"version": "semver"}
+	runLabelCases(t, NewDL3050Rule(), []labelCase{
+		{
+			name:       "no schema",
+			dockerfile: "FROM alpine\nLABEL maintainer=dev",
+		},
+		{
+			name:       "only schema labels",
+			dockerfile: "FROM alpine\nLABEL version=1.0.0",
+			schema:     schema,
+		},
+		{
+			name:       "superfluous labels",
+			dockerfile: "FROM alpine\nLABEL version=1.0.0 maintainer=dev\nLABEL vendor=acme",
+			schema:     schema,
+			want:       2,
+		},
+	})
+}
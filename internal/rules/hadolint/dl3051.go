@@ -0,0 +1,53 @@
+package hadolint
+
+import (
+	"fmt"
+
+	"github.com/tinovyatkin/tally/internal/rules"
+)
+
+// DL3051Rule implements the DL3051 linting rule.
+type DL3051Rule struct{}
+
+// NewDL3051Rule creates a new DL3051 rule instance.
+func NewDL3051Rule() *DL3051Rule {
+	return &DL3051Rule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *DL3051Rule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            rules.HadolintRulePrefix + "DL3051",
+		Name:            "Label is empty",
+		Description:     "Label `<label>` is empty",
+		DocURL:          "https://github.com/hadolint/hadolint/wiki/DL3051",
+		DefaultSeverity: rules.SeverityWarning,
+		Category:        "best-practice",
+		IsExperimental:  false,
+	}
+}
+
+// Check runs the DL3051 rule.
+// It reports labels of the label schema set to an empty value, including
+// values of variables that resolve to "".
+func (r *DL3051Rule) Check(input rules.LintInput) []rules.Violation {
+	if len(input.LabelSchema) == 0 {
+		return nil
+	}
+	meta := r.Metadata()
+
+	var violations []rules.Violation
+	for _, l := range stageLabels(input) {
+		if _, ok := input.LabelSchema[l.key]; !ok || !l.resolved || l.value != "" {
+			continue
+		}
+		violations = append(violations, newLabelViolation(input, meta, l,
+			fmt.Sprintf("label %q is empty", l.key)))
+	}
+	return violations
+}
+
+// init registers the rule with the default registry.
+func init() {
+	rules.Register(NewDL3051Rule())
+}
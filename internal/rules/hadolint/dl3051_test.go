@@ -0,0 +1,46 @@
+package hadolint
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func TestDL3051Rule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewDL3051Rule().Metadata())
+}
+
+func TestDL3051Rule_Check(t *testing.T) {
+	t.Parallel()
+	schema := map[string]string{"title": "text"}
+	runLabelCases(t, NewDL3051Rule(), []labelCase{
+		{
+			name:       "value set",
+			dockerfile: "FROM alpine\nLABEL title=app",
+			schema:     schema,
+		},
+		{
+			name:       "empty value",
+			dockerfile: "FROM alpine\nLABEL title=\"\"",
+			schema:     schema,
+			want:       1,
+		},
+		{
+			name:       "variable resolves to empty",
+			dockerfile: "FROM alpine\nARG TITLE=\"\"\nLABEL title=$TITLE",
+			schema:     schema,
+			want:       1,
+		},
+		{
+			name:       "unknown variable",
+			dockerfile: "FROM alpine\nLABEL title=$TITLE",
+			schema:     schema,
+		},
+		{
+			name:       "label not in schema",
+			dockerfile: "FROM alpine\nLABEL other=\"\"",
+			schema:     schema,
+		},
+	})
+}
@@ -0,0 +1,38 @@
+package hadolint
+
+import (
+	"github.com/tinovyatkin/tally/internal/rules"
+)
+
+// DL3052Rule implements the DL3052 linting rule.
+type DL3052Rule struct{}
+
+// NewDL3052Rule creates a new DL3052 rule instance.
+func NewDL3052Rule() *DL3052Rule {
+	return &DL3052Rule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *DL3052Rule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            rules.HadolintRulePrefix + "DL3052",
+		Name:            "Label is not a valid URL",
+		Description:     "Label `<label>` is not a valid URL",
+		DocURL:          "https://github.com/hadolint/hadolint/wiki/DL3052",
+		DefaultSeverity: rules.SeverityWarning,
+		Category:        "correctness",
+		IsExperimental:  false,
+	}
+}
+
+// Check runs the DL3052 rule.
+// It reports labels of type url in the label schema whose value is not an
+// absolute URI, such as https://github.com/org/repo.
+func (r *DL3052Rule) Check(input rules.LintInput) []rules.Violation {
+	return checkLabelValues(input, r.Metadata(), rules.LabelTypeURL, validURL, "URL")
+}
+
+// init registers the rule with the default registry.
+func init() {
+	rules.Register(NewDL3052Rule())
+}
@@ -0,0 +1,50 @@
+package hadolint
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func TestDL3052Rule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewDL3052Rule().Metadata())
+}
+
+func TestDL3052Rule_Check(t *testing.T) {
+	t.Parallel()
+	schema := map[string]string{"source": "url"}
+	runLabelCases(t, NewDL3052Rule(), []labelCase{
+		{
+			name:       "valid value",
+			dockerfile: "FROM alpine\nLABEL source=https://github.com/org/repo",
+			schema:     schema,
+		},
+		{
+			name:       "invalid value",
+			dockerfile: "FROM alpine\nLABEL source=github.com/org/repo",
+			schema:     schema,
+			want:       1,
+		},
+		{
+			name:       "value from an ARG",
+			dockerfile: "ARG VALUE=github.com/org/repo\nFROM alpine\nARG VALUE\nLABEL source=$VALUE",
+			schema:     schema,
+			want:       1,
+		},
+		{
+			name:       "value from an unknown variable",
+			dockerfile: "FROM alpine\nLABEL source=$VALUE",
+			schema:     schema,
+		},
+		{
+			name:       "other type",
+			dockerfile: "FROM alpine\nLABEL source=github.com/org/repo",
+			schema:     map[string]string{"source": "text"},
+		},
+		{
+			name:       "no schema",
+			dockerfile: "FROM alpine\nLABEL source=github.com/org/repo",
+		},
+	})
+}
@@ -0,0 +1,38 @@
+package hadolint
+
+import (
+	"github.com/tinovyatkin/tally/internal/rules"
+)
+
+// DL3053Rule implements the DL3053 linting rule.
+type DL3053Rule struct{}
+
+// NewDL3053Rule creates a new DL3053 rule instance.
+func NewDL3053Rule() *DL3053Rule {
+	return &DL3053Rule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *DL3053Rule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            rules.HadolintRulePrefix + "DL3053",
+		Name:            "Label is not a valid RFC 3339 time",
+		Description:     "Label `<label>` is not a valid time format - must conform to RFC3339",
+		DocURL:          "https://github.com/hadolint/hadolint/wiki/DL3053",
+		DefaultSeverity: rules.SeverityWarning,
+		Category:        "correctness",
+		IsExperimental:  false,
+	}
+}
+
+// Check runs the DL3053 rule.
+// It reports labels of type rfc3339 in the label schema whose value is not
+// an RFC 3339 timestamp, such as 2024-05-01T12:00:00Z.
+func (r *DL3053Rule) Check(input rules.LintInput) []rules.Violation {
+	return checkLabelValues(input, r.Metadata(), rules.LabelTypeRFC3339, validRFC3339, "RFC 3339 timestamp")
+}
+
+// init registers the rule with the default registry.
+func init() {
+	rules.Register(NewDL3053Rule())
+}
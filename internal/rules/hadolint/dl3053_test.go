@@ -0,0 +1,50 @@
+package hadolint
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func TestDL3053Rule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewDL3053Rule().Metadata())
+}
+
+func TestDL3053Rule_Check(t *testing.T) {
+	t.Parallel()
+	schema := map[string]string{"created": "rfc3339"}
+	runLabelCases(t, NewDL3053Rule(), []labelCase{
+		{
+			name:       "valid value",
+			dockerfile: "FROM alpine\nLABEL created=2024-05-01T12:00:00Z",
+			schema:     schema,
+		},
+		{
+			name:       "invalid value",
+			dockerfile: "FROM alpine\nLABEL created=2024-05-01",
+			schema:     schema,
+			want:       1,
+		},
+		{
+			name:       "value from an ARG",
+			dockerfile: "ARG VALUE=2024-05-01\nFROM alpine\nARG VALUE\nLABEL created=$VALUE",
+			schema:     schema,
+			want:       1,
+		},
+		{
+			name:       "value from an unknown variable",
+			dockerfile: "FROM alpine\nLABEL created=$VALUE",
+			schema:     schema,
+		},
+		{
+			name:       "other type",
+			dockerfile: "FROM alpine\nLABEL created=2024-05-01",
+			schema:     map[string]string{"created": "text"},
+		},
+		{
+			name:       "no schema",
+			dockerfile: "FROM alpine\nLABEL created=2024-05-01",
+		},
+	})
+}
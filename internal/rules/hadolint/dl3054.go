@@ -0,0 +1,39 @@
+package hadolint
+
+import (
+	"github.com/tinovyatkin/tally/internal/rules"
+)
+
+// DL3054Rule implements the DL3054 linting rule.
+type DL3054Rule struct{}
+
+// NewDL3054Rule creates a new DL3054 rule instance.
+func NewDL3054Rule() *DL3054Rule {
+	return &DL3054Rule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *DL3054Rule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            rules.HadolintRulePrefix + "DL3054",
+		Name:            "Label is not a valid SPDX license",
+		Description:     "Label `<label>` is not a valid SPDX license identifier",
+		DocURL:          "https://github.com/hadolint/hadolint/wiki/DL3054",
+		DefaultSeverity: rules.SeverityWarning,
+		Category:        "correctness",
+		IsExperimental:  false,
+	}
+}
+
+// Check runs the DL3054 rule.
+// It reports labels of type spdx in the label schema whose value is not an
+// SPDX license expression of known license identifiers, such as MIT or
+// Apache-2.0 OR BSD-3-Clause.
+func (r *DL3054Rule) Check(input rules.LintInput) []rules.Violation {
+	return checkLabelValues(input, r.Metadata(), rules.LabelTypeSPDX, validSPDX, "SPDX license expression")
+}
+
+// init registers the rule with the default registry.
+func init() {
+	rules.Register(NewDL3054Rule())
+}
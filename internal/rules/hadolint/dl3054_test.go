@@ -0,0 +1,50 @@
+package hadolint
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func TestDL3054Rule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewDL3054Rule().Metadata())
+}
+
+func TestDL3054Rule_Check(t *testing.T) {
+	t.Parallel()
+	schema := map[string]string{"licenses": "spdx"}
+	runLabelCases(t, NewDL3054Rule(), []labelCase{
+		{
+			name:       "valid value",
+			dockerfile: "FROM alpine\nLABEL licenses=\"Apache-2.0 OR MIT\"",
+			schema:     schema,
+		},
+		{
+			name:       "invalid value",
+			dockerfile: "FROM alpine\nLABEL licenses=\"MIT License\"",
+			schema:     schema,
+			want:       1,
+		},
+		{
+			name:       "value from an ARG",
+			dockerfile: "ARG VALUE=\"MIT License\"\nFROM alpine\nARG VALUE\nLABEL licenses=$VALUE",
+			schema:     schema,
+			want:       1,
+		},
+		{
+			name:       "value from an unknown variable",
+			dockerfile: "FROM alpine\nLABEL licenses=$VALUE",
+			schema:     schema,
+		},
+		{
+			name:       "other type",
+			dockerfile: "FROM alpine\nLABEL licenses=\"MIT License\"",
+			schema:     map[string]string{"licenses": "text"},
+		},
+		{
+			name:       "no schema",
+			dockerfile: "FROM alpine\nLABEL licenses=\"MIT License\"",
+		},
+	})
+}
@@ -0,0 +1,39 @@
+package hadolint
+
+import (
+	"github.com/tinovyatkin/tally/internal/rules"
+)
+
+// DL3055Rule implements the DL3055 linting rule.
+type DL3055Rule struct{}
+
+// NewDL3055Rule creates a new DL3055 rule instance.
+func NewDL3055Rule() *DL3055Rule {
+	return &DL3055Rule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *DL3055Rule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            rules.HadolintRulePrefix + "DL3055",
+		Name:            "Label is not a valid git hash",
+		Description:     "Label `<label>` is not a valid git hash",
+		DocURL:          "https://github.com/hadolint/hadolint/wiki/DL3055",
+		DefaultSeverity: rules.SeverityWarning,
+		Category:        "correctness",
+		IsExperimental:  false,
+	}
+}
+
+// Check runs the DL3055 rule.
+// It reports labels of type hash in the label schema whose value is not a
+// git commit hash: 40 (SHA-1) or 64 (SHA-256) hex digits, or an abbreviated
+// hash of at least 7.
+func (r *DL3055Rule) Check(input rules.LintInput) []rules.Violation {
+	return checkLabelValues(input, r.Metadata(), rules.LabelTypeHash, validGitHash, "git hash")
+}
+
+// init registers the rule with the default registry.
+func init() {
+	rules.Register(NewDL3055Rule())
+}
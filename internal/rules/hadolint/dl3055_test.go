@@ -0,0 +1,50 @@
+package hadolint
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func TestDL3055Rule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewDL3055Rule().Metadata())
+}
+
+func TestDL3055Rule_Check(t *testing.T) {
+	t.Parallel()
+	schema := map[string]string{"revision": "hash"}
+	runLabelCases(t, NewDL3055Rule(), []labelCase{
+		{
+			name:       "valid value",
+			dockerfile: "FROM alpine\nLABEL revision=4b825dc642cb6eb9a060e54bf8d69288fbee4904",
+			schema:     schema,
+		},
+		{
+			name:       "invalid value",
+			dockerfile: "FROM alpine\nLABEL revision=main",
+			schema:     schema,
+			want:       1,
+		},
+		{
+			name:       "value from an ARG",
+			dockerfile: "ARG VALUE=main\nFROM alpine\nARG VALUE\nLABEL revision=$VALUE",
+			schema:     schema,
+			want:       1,
+		},
+		{
+			name:       "value from an unknown variable",
+			dockerfile: "FROM alpine\nLABEL revision=$VALUE",
+			schema:     schema,
+		},
+		{
+			name:       "other type",
+			dockerfile: "FROM alpine\nLABEL revision=main",
+			schema:     map[string]string{"revision": "text"},
+		},
+		{
+			name:       "no schema",
+			dockerfile: "FROM alpine\nLABEL revision=main",
+		},
+	})
+}
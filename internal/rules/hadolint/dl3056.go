@@ -0,0 +1,39 @@
+package hadolint
+
+import (
+	"github.com/tinovyatkin/tally/internal/rules"
+)
+
+// DL3056Rule implements the DL3056 linting rule.
+type DL3056Rule struct{}
+
+// NewDL3056Rule creates a new DL3056 rule instance.
+func NewDL3056Rule() *DL3056Rule {
+	return &DL3056Rule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *DL3056Rule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            rules.HadolintRulePrefix + "DL3056",
+		Name:            "Label does not conform to semantic versioning",
+		Description:     "Label `<label>` does not conform to semantic versioning",
+		DocURL:          "https://github.com/hadolint/hadolint/wiki/DL3056",
+		DefaultSeverity: rules.SeverityWarning,
+		Category:        "correctness",
+		IsExperimental:  false,
+	}
+}
+
+// Check runs the DL3056 rule.
+// It reports labels of type semver in the label schema whose value is not a
+// semantic version (semver.org), such as 1.4.2 or 2.0.0-rc.1. A "v" prefix
+// is not allowed.
+func (r *DL3056Rule) Check(input rules.LintInput) []rules.Violation {
+	return checkLabelValues(input, r.Metadata(), rules.LabelTypeSemVer, validSemVer, "semantic version")
+}
+
+// init registers the rule with the default registry.
+func init() {
+	rules.Register(NewDL3056Rule())
+}
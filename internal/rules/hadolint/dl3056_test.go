@@ -0,0 +1,50 @@
+package hadolint
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func TestDL3056Rule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewDL3056Rule().Metadata())
+}
+
+func TestDL3056Rule_Check(t *testing.T) {
+	t.Parallel()
+	schema := map[string]string{"version": "semver"}
+	runLabelCases(t, NewDL3056Rule(), []labelCase{
+		{
+			name:       "valid value",
+			dockerfile: "FROM alpine\nLABEL version=1.4.2",
+			schema:     schema,
+		},
+		{
+			name:       "invalid value",
+			dockerfile: "FROM alpine\nLABEL version=v1.4.2",
+			schema:     schema,
+			want:       1,
+		},
+		{
+			name:       "value from an ARG",
+			dockerfile: "ARG VALUE=v1.4.2\nFROM alpine\nARG VALUE\nLABEL version=$VALUE",
+			schema:     schema,
+			want:       1,
+		},
+		{
+			name:       "value from an unknown variable",
+			dockerfile: "FROM alpine\nLABEL version=$VALUE",
+			schema:     schema,
+		},
+		{
+			name:       "other type",
+			dockerfile: "FROM alpine\nLABEL version=v1.4.2",
+			schema:     map[string]string{"version": "text"},
+		},
+		{
+			name:       "no schema",
+			dockerfile: "FROM alpine\nLABEL version=v1.4.2",
+		},
+	})
+}
@@ -0,0 +1,38 @@
+package hadolint
+
+import (
+	"github.com/tinovyatkin/tally/internal/rules"
+)
+
+// DL3058Rule implements the DL3058 linting rule.
+type DL3058Rule struct{}
+
+// NewDL3058Rule creates a new DL3058 rule instance.
+func NewDL3058Rule() *DL3058Rule {
+	return &DL3058Rule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *DL3058Rule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            rules.HadolintRulePrefix + "DL3058",
+		Name:            "Label is not a valid email",
+		Description:     "Label `<label>` is not a valid email format - must conform to RFC5322",
+		DocURL:          "https://github.com/hadolint/hadolint/wiki/DL3058",
+		DefaultSeverity: rules.SeverityWarning,
+		Category:        "correctness",
+		IsExperimental:  false,
+	}
+}
+
+// Check runs the DL3058 rule.
+// It reports labels of type email in the label schema whose value is not an
+// RFC 5322 email address, such as dev@example.com or "Dev Team <dev@example.com>".
+func (r *DL3058Rule) Check(input rules.LintInput) []rules.Violation {
+	return checkLabelValues(input, r.Metadata(), rules.LabelTypeEmail, validEmail, "email address")
+}
+
+// init registers the rule with the default registry.
+func init() {
+	rules.Register(NewDL3058Rule())
+}
@@ -0,0 +1,50 @@
+package hadolint
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func TestDL3058Rule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewDL3058Rule().Metadata())
+}
+
+func TestDL3058Rule_Check(t *testing.T) {
+	t.Parallel()
+	schema := map[string]string{"maintainer": "email"}
+	runLabelCases(t, NewDL3058Rule(), []labelCase{
+		{
+			name:       "valid value",
+			dockerfile: "FROM alpine\nLABEL maintainer=dev@example.com",
+			schema:     schema,
+		},
+		{
+			name:       "invalid value",
+			dockerfile: "FROM alpine\nLABEL maintainer=dev-at-example.com",
+			schema:     schema,
+			want:       1,
+		},
+		{
+			name:       "value from an ARG",
+			dockerfile: "ARG VALUE=dev-at-example.com\nFROM alpine\nARG VALUE\nLABEL maintainer=$VALUE",
+			schema:     schema,
+			want:       1,
+		},
+		{
+			name:       "value from an unknown variable",
+			dockerfile: "FROM alpine\nLABEL maintainer=$VALUE",
+			schema:     schema,
+		},
+		{
+			name:       "other type",
+			dockerfile: "FROM alpine\nLABEL maintainer=dev-at-example.com",
+			schema:     map[string]string{"maintainer": "text"},
+		},
+		{
+			name:       "no schema",
+			dockerfile: "FROM alpine\nLABEL maintainer=dev-at-example.com",
+		},
+	})
+}
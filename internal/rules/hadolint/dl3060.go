@@ -0,0 +1,58 @@
+package hadolint
+
+import (
+	"github.com/tinovyatkin/tally/internal/rules"
+	"github.com/tinovyatkin/tally/internal/shell"
+)
+
+// DL3060Rule implements the DL3060 linting rule.
+// It warns when yarn install isn't followed by yarn cache clean in the same RUN.
+type DL3060Rule struct{}
+
+// NewDL3060Rule creates a new DL3060 rule instance.
+func NewDL3060Rule() *DL3060Rule {
+	return &DL3060Rule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *DL3060Rule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            rules.HadolintRulePrefix + "DL3060",
+		Name:            "Run yarn cache clean after yarn install",
+		Description:     "`yarn cache clean` missing after `yarn install` was run",
+		DocURL:          "https://github.com/hadolint/hadolint/wiki/DL3060",
+		DefaultSeverity: rules.SeverityInfo,
+		Category:        "performance",
+		IsExperimental:  false,
+		Fixable:         true,
+		FixSafety:       rules.FixSafe,
+	}
+}
+
+// yarnValueFlags are the yarn options that take a separate value.
+var yarnValueFlags = []string{"--cwd", "--cache-folder", "--modules-folder", "--network-timeout", "--mutex"}
+
+// yarnCacheDir is the yarn cache directory of the root user.
+const yarnCacheDir = "/usr/local/share/.cache/yarn"
+
+// Check runs the DL3060 rule.
+func (r *DL3060Rule) Check(input rules.LintInput) []rules.Violation {
+	return checkCache(input, r.Metadata(), cacheSpec{
+		commands: []string{"yarn"},
+		trigger:  cacheTrigger(yarnValueFlags, "install"),
+		cleans: func(cmd *shell.CommandInfo) bool {
+			return subcommandOperands(commandOperands(cmd.Words, yarnValueFlags...), []string{"cache", "clean"}) != nil
+		},
+		cacheDirs: []string{yarnCacheDir, "/root/.cache/yarn"},
+		cacheDir:  staticCacheDir(yarnCacheDir),
+		cleanup:   func(*shell.CommandInfo) string { return "yarn cache clean" },
+		detail: "yarn install keeps every downloaded package in its cache, which stays in the layer " +
+			"unless cleaned in the same RUN. Run yarn cache clean, or mount a cache on " + yarnCacheDir +
+			" so it never reaches the image.",
+	})
+}
+
+// init registers the rule with the default registry.
+func init() {
+	rules.Register(NewDL3060Rule())
+}
@@ -0,0 +1,40 @@
+package hadolint
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func TestDL3060Rule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewDL3060Rule().Metadata())
+}
+
+func TestDL3060Rule_Check(t *testing.T) {
+	t.Parallel()
+	runCacheCases(t, NewDL3060Rule(), []cacheCase{
+		{
+			name:       "yarn install without cache clean",
+			dockerfile: "FROM node:22\nRUN yarn install",
+			want:       1,
+		},
+		{
+			name:       "yarn install with cache clean",
+			dockerfile: "FROM node:22\nRUN yarn install && yarn cache clean",
+		},
+		{
+			name:       "yarn cache clean in a separate RUN",
+			dockerfile: "FROM node:22\nRUN yarn install\nRUN yarn cache clean",
+			want:       1,
+		},
+		{
+			name:       "cache mount",
+			dockerfile: "FROM node:22\nRUN --mount=type=cache,target=/usr/local/share/.cache/yarn yarn install --frozen-lockfile",
+		},
+		{
+			name:       "yarn build",
+			dockerfile: "FROM node:22\nRUN yarn build",
+		},
+	})
+}
@@ -0,0 +1,98 @@
+package hadolint
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/tinovyatkin/tally/internal/rules"
+	"github.com/tinovyatkin/tally/internal/rules/configutil"
+	"github.com/tinovyatkin/tally/internal/shell"
+)
+
+// DL3062Config is the configuration for the DL3062 rule.
+type DL3062Config PinningConfig
+
+// DL3062Rule implements the DL3062 linting rule.
+// It warns when go install is given a package without a version.
+type DL3062Rule struct{}
+
+// NewDL3062Rule creates a new DL3062 rule instance.
+func NewDL3062Rule() *DL3062Rule {
+	return &DL3062Rule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *DL3062Rule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            rules.HadolintRulePrefix + "DL3062",
+		Name:            "Pin versions in go install",
+		Description:     "Pin versions in go install. Instead of `go install <package>` use `go install <package>@<version>`",
+		DocURL:          "https://github.com/hadolint/hadolint/wiki/DL3062",
+		DefaultSeverity: rules.SeverityWarning,
+		Category:        "reproducibility",
+		IsExperimental:  false,
+	}
+}
+
+// Schema returns the JSON Schema for this rule's configuration.
+func (r *DL3062Rule) Schema() map[string]any {
+	return pinningSchema()
+}
+
+// DefaultConfig returns the default configuration for this rule.
+func (r *DL3062Rule) DefaultConfig() any {
+	return DL3062Config(DefaultPinningConfig())
+}
+
+// ValidateConfig validates the configuration against the rule's JSON Schema.
+func (r *DL3062Rule) ValidateConfig(config any) error {
+	return configutil.ValidateWithSchema(config, r.Schema())
+}
+
+// goValueFlags are the go build flags that take a separate value.
+var goValueFlags = []string{
+	"-C", "-o", "-p", "-asmflags", "-buildmode", "-compiler", "-gccgoflags", "-gcflags",
+	"-installsuffix", "-ldflags", "-mod", "-modfile", "-overlay", "-pgo", "-pkgdir",
+	"-tags", "-toolexec",
+}
+
+// goVersionQueries are module queries that resolve to a different version over time.
+var goVersionQueries = []string{"latest", "upgrade", "patch"}
+
+// goSyntax is the go install syntax: package@version.
+var goSyntax = packageSyntax{
+	pinned: func(arg string) bool {
+		// Local packages and standard library commands (no domain in the
+		// first path element) build from the source tree or toolchain.
+		first, _, _ := strings.Cut(arg, "/")
+		if isLocalPackage(arg) || !strings.Contains(first, ".") {
+			return true
+		}
+		_, version, ok := strings.Cut(arg, "@")
+		return ok && version != "" && !slices.Contains(goVersionQueries, version)
+	},
+	name: func(arg string) string {
+		return nameBefore(arg, "@")
+	},
+	example: "%s@<version>",
+}
+
+// Check runs the DL3062 rule.
+func (r *DL3062Rule) Check(input rules.LintInput) []rules.Violation {
+	return checkPinning(input, r.Metadata(), r.resolveConfig(input.Config).Allowlist, pinningSpec{
+		commands: []string{"go"},
+		packages: func(cmd *shell.CommandInfo) ([]shell.Word, packageSyntax) {
+			return subcommandOperands(commandOperands(cmd.Words, goValueFlags...), []string{"install"}), goSyntax
+		},
+	})
+}
+
+// resolveConfig extracts the DL3062Config from input, falling back to defaults.
+func (r *DL3062Rule) resolveConfig(config any) DL3062Config {
+	return configutil.Coerce(config, DL3062Config(DefaultPinningConfig()))
+}
+
+// init registers the rule with the default registry.
+func init() {
+	rules.Register(NewDL3062Rule())
+}
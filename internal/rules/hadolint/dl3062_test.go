@@ -0,0 +1,44 @@
+package hadolint
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func TestDL3062Rule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewDL3062Rule().Metadata())
+}
+
+func TestDL3062Rule_Check(t *testing.T) {
+	t.Parallel()
+	runPinningCases(t, NewDL3062Rule(), []pinningCase{
+		{
+			name:       "unpinned package",
+			dockerfile: "FROM golang:1.23\nRUN go install github.com/go-delve/delve/cmd/dlv",
+			want:       []string{"github.com/go-delve/delve/cmd/dlv"},
+		},
+		{
+			name:       "latest",
+			dockerfile: "FROM golang:1.23\nRUN go install golang.org/x/tools/gopls@latest",
+			want:       []string{"golang.org/x/tools/gopls"},
+		},
+		{
+			name:       "pinned packages",
+			dockerfile: "FROM golang:1.23\nRUN go install golang.org/x/tools/gopls@v0.16.1 honnef.co/go/tools/cmd/staticcheck@2024.1.1",
+		},
+		{
+			name:       "local packages",
+			dockerfile: "FROM golang:1.23\nRUN go install ./cmd/... && go install .",
+		},
+		{
+			name:       "build flags",
+			dockerfile: "FROM golang:1.23\nRUN go install -ldflags \"-s -w\" -tags netgo github.com/org/tool@v1.2.3",
+		},
+		{
+			name:       "go build is not checked",
+			dockerfile: "FROM golang:1.23\nRUN go build -o /app github.com/org/app",
+		},
+	})
+}
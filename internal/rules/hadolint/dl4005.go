@@ -30,6 +30,8 @@ func (r *DL4005Rule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityWarning,
 		Category:        "style",
 		IsExperimental:  false,
+		Fixable:         true,
+		FixSafety:       rules.FixSuggestion,
 	}
 }
 
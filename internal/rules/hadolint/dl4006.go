@@ -30,6 +30,8 @@ func (r *DL4006Rule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityWarning,
 		Category:        "reliability",
 		IsExperimental:  false,
+		Fixable:         true,
+		FixSafety:       rules.FixSuggestion,
 	}
 }
 
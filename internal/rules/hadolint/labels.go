@@ -0,0 +1,184 @@
+package hadolint
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/github/go-spdx/v2/spdxexp"
+	"github.com/moby/buildkit/frontend/dockerfile/instructions"
+	dfshell "github.com/moby/buildkit/frontend/dockerfile/shell"
+
+	"github.com/tinovyatkin/tally/internal/rules"
+	"github.com/tinovyatkin/tally/internal/semantic"
+)
+
+// label is a LABEL key/value pair with build variables substituted.
+type label struct {
+	key   string
+	value string
+
+	// keyResolved and resolved are false when the key or the value
+	// references a variable without a known value, so it can't be checked.
+	keyResolved bool
+	resolved    bool
+
+	// stage is the index of the stage the LABEL belongs to.
+	stage int
+
+	cmd *instructions.LabelCommand
+}
+
+// stageLabels returns the labels of every LABEL instruction, in order.
+// Variables are resolved through the semantic model (build args, ENV and
+// ARG values), so LABEL version=$VERSION is checked with the value of
+// VERSION.
+func stageLabels(input rules.LintInput) []label {
+	sem, ok := input.Semantic.(*semantic.Model)
+	if !ok {
+		sem = nil
+	}
+	escape := rune('\\')
+	if input.AST != nil && input.AST.EscapeToken != 0 {
+		escape = input.AST.EscapeToken
+	}
+	lex := dfshell.NewLex(escape)
+
+	var labels []label
+	for stageIdx, stage := range input.Stages {
+		env := labelEnv{sem: sem, stage: stageIdx, escape: escape}
+		for _, cmd := range stage.Commands {
+			lc, ok := cmd.(*instructions.LabelCommand)
+			if !ok {
+				continue
+			}
+			for _, kv := range lc.Labels {
+				key, keyOK := expandLabelWord(lex, kv.Key, env)
+				value, valueOK := expandLabelWord(lex, kv.Value, env)
+				labels = append(labels, label{
+					key:         key,
+					value:       value,
+					keyResolved: keyOK,
+					resolved:    keyOK && valueOK,
+					stage:       stageIdx,
+					cmd:         lc,
+				})
+			}
+		}
+	}
+	return labels
+}
+
+// expandLabelWord removes the quotes of a LABEL key or value and
+// substitutes its variables. It returns false if a variable has no known value.
+func expandLabelWord(lex *dfshell.Lex, word string, env dfshell.EnvGetter) (string, bool) {
+	res, err := lex.ProcessWordWithMatches(word, env)
+	if err != nil {
+		return word, false
+	}
+	return res.Result, len(res.Unmatched) == 0
+}
+
+// labelEnv resolves LABEL variables with semantic.Model.ResolveVariable.
+type labelEnv struct {
+	sem    *semantic.Model
+	stage  int
+	escape rune
+}
+
+// Get returns the value of a variable in the stage, with the quotes of its
+// ARG or ENV instruction removed. Values that still reference other
+// variables are treated as unknown.
+func (e labelEnv) Get(name string) (string, bool) {
+	if e.sem == nil {
+		return "", false
+	}
+	v, ok := e.sem.ResolveVariable(e.stage, name)
+	if !ok || strings.Contains(v, "$") {
+		return "", false
+	}
+	res, err := dfshell.NewLex(e.escape).ProcessWordWithMatches(v, labelEnv{})
+	if err != nil {
+		return v, true
+	}
+	return res.Result, true
+}
+
+// Keys is not needed for LABEL expansion.
+func (e labelEnv) Keys() []string {
+	return nil
+}
+
+// newLabelViolation creates a violation for a label, located at its LABEL instruction.
+func newLabelViolation(input rules.LintInput, meta rules.RuleMetadata, l label, msg string) rules.Violation {
+	return rules.NewViolation(
+		rules.NewLocationFromRanges(input.File, l.cmd.Location()),
+		meta.Code,
+		msg,
+		meta.DefaultSeverity,
+	).WithDocURL(meta.DocURL)
+}
+
+// checkLabelValues reports the labels whose schema type is typ and whose
+// value is not valid. Empty values are left to DL3051, and values that
+// reference unknown variables are skipped.
+func checkLabelValues(input rules.LintInput, meta rules.RuleMetadata, typ string, valid func(string) bool, what string) []rules.Violation {
+	if len(input.LabelSchema) == 0 {
+		return nil
+	}
+	var violations []rules.Violation
+	for _, l := range stageLabels(input) {
+		if !l.resolved || l.value == "" || input.LabelSchema[l.key] != typ || valid(l.value) {
+			continue
+		}
+		violations = append(violations, newLabelViolation(input, meta, l,
+			fmt.Sprintf("label %q is not a valid %s: %q", l.key, what, l.value)).
+			WithDetail(fmt.Sprintf("The label schema declares %q as %s.", l.key, typ)))
+	}
+	return violations
+}
+
+// validURL reports whether v is an absolute URI.
+func validURL(v string) bool {
+	u, err := url.Parse(v)
+	return err == nil && u.IsAbs() && (u.Host != "" || u.Opaque != "")
+}
+
+// validRFC3339 reports whether v is an RFC 3339 timestamp.
+func validRFC3339(v string) bool {
+	_, err := time.Parse(time.RFC3339, v)
+	return err == nil
+}
+
+// validSPDX reports whether v is an SPDX license expression of known
+// license identifiers, such as "MIT" or "Apache-2.0 OR GPL-2.0-only".
+func validSPDX(v string) bool {
+	ok, _ := spdxexp.ValidateLicenses([]string{v})
+	return ok
+}
+
+// gitHashPattern matches full SHA-1 (40) and SHA-256 (64) commit hashes
+// and abbreviated hashes of at least 7 hex digits.
+var gitHashPattern = regexp.MustCompile(`^(?:[0-9a-fA-F]{7,40}|[0-9a-fA-F]{64})$`)
+
+// validGitHash reports whether v is a git commit hash.
+func validGitHash(v string) bool {
+	return gitHashPattern.MatchString(v)
+}
+
+// validSemVer reports whether v is a semantic version (semver.org 2.0.0),
+// without a "v" prefix.
+func validSemVer(v string) bool {
+	_, err := semver.StrictNewVersion(v)
+	return err == nil
+}
+
+// validEmail reports whether v is an RFC 5322 email address.
+func validEmail(v string) bool {
+	_, err := mail.ParseAddress(v)
+	return err == nil
+}
@@ -0,0 +1,135 @@
+package hadolint
+
+import (
+	"testing"
+
+	"github.com/tinovyatkin/tally/internal/rules"
+	"github.com/tinovyatkin/tally/internal/testutil"
+)
+
+// labelCase is a label schema rule test case: want is the number of
+// violations reported.
+type labelCase struct {
+	name       string
+	dockerfile string
+	schema     map[string]string
+	config     any
+	want       int
+}
+
+// runLabelCases checks a label schema rule against test cases.
+func runLabelCases(t *testing.T, rule rules.Rule, tests []labelCase) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			input := testutil.MakeLintInputWithSemantic(t, "Dockerfile", tt.dockerfile)
+			input.LabelSchema = tt.schema
+			input.Config = tt.config
+			violations := rule.Check(input)
+			if len(violations) != tt.want {
+				t.Errorf("got %d violations, want %d", len(violations), tt.want)
+				for i, v := range violations {
+					t.Logf("violation %d: %s at %v", i+1, v.Message, v.Location)
+				}
+			}
+			for _, v := range violations {
+				if v.RuleCode != rule.Metadata().Code {
+					t.Errorf("rule code = %q, want %q", v.RuleCode, rule.Metadata().Code)
+				}
+			}
+		})
+	}
+}
+
+func TestStageLabels(t *testing.T) {
+	t.Parallel()
+	dockerfile := `ARG VERSION=1.2.3
+FROM alpine AS build
+ARG VERSION
+ARG REVISION
+ENV VENDOR="acme corp"
+LABEL version=$VERSION "org.example.vendor"="${VENDOR}" revision=$REVISION
+LABEL title="My app" empty=""
+`
+	input := testutil.MakeLintInputWithSemantic(t, "Dockerfile", dockerfile)
+	want := []label{
+		{key: "version", value: "1.2.3", resolved: true},
+		{key: "org.example.vendor", value: "acme corp", resolved: true},
+		{key: "revision", keyResolved: true},
+		{key: "title", value: "My app", resolved: true},
+		{key: "empty", value: "", resolved: true},
+	}
+	got := stageLabels(input)
+	if len(got) != len(want) {
+		t.Fatalf("got %d labels, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		g := got[i]
+		if g.key != w.key || g.keyResolved != (w.keyResolved || w.resolved) || g.resolved != w.resolved || (w.resolved && g.value != w.value) {
+			t.Errorf("label %d = {%q %q %v}, want {%q %q %v}", i, g.key, g.value, g.resolved, w.key, w.value, w.resolved)
+		}
+		if g.stage != 0 || g.cmd == nil {
+			t.Errorf("label %d: stage = %d, cmd = %v", i, g.stage, g.cmd)
+		}
+	}
+}
+
+func TestLabelValidators(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		valid func(string) bool
+		good  []string
+		bad   []string
+	}{
+		{
+			name:  "url",
+			valid: validURL,
+			good:  []string{"https://github.com/org/repo", "mailto:dev@example.com"},
+			bad:   []string{"github.com/org/repo", "git@github.com:org/repo.git", "/path", "https://"},
+		},
+		{
+			name:  "rfc3339",
+			valid: validRFC3339,
+			good:  []string{"2024-05-01T12:00:00Z", "2024-05-01T12:00:00.5+02:00"},
+			bad:   []string{"2024-05-01", "2024-05-01 12:00:00", "yesterday"},
+		},
+		{
+			name:  "spdx",
+			valid: validSPDX,
+			good:  []string{"MIT", "Apache-2.0 OR BSD-3-Clause", "GPL-2.0-only WITH Classpath-exception-2.0"},
+			bad:   []string{"MIT License", "Proprietary", "Apache 2"},
+		},
+		{
+			name:  "hash",
+			valid: validGitHash,
+			good:  []string{"4b825dc", "4b825dc642cb6eb9a060e54bf8d69288fbee4904"},
+			bad:   []string{"4b825d", "main", "4b825dc642cb6eb9a060e54bf8d69288fbee4904aa"},
+		},
+		{
+			name:  "semver",
+			valid: validSemVer,
+			good:  []string{"1.4.2", "2.0.0-rc.1", "1.0.0+build.5"},
+			bad:   []string{"v1.4.2", "1.4", "latest"},
+		},
+		{
+			name:  "email",
+			valid: validEmail,
+			good:  []string{"dev@example.com", "Dev Team <dev@example.com>"},
+			bad:   []string{"dev", "dev@", "@example.com"},
+		},
+	}
+	for _, tt := range tests {
+		for _, v := range tt.good {
+			if !tt.valid(v) {
+				t.Errorf("%s: %q is reported invalid", tt.name, v)
+			}
+		}
+		for _, v := range tt.bad {
+			if tt.valid(v) {
+				t.Errorf("%s: %q is reported valid", tt.name, v)
+			}
+		}
+	}
+}
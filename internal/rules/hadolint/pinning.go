@@ -0,0 +1,227 @@
+package hadolint
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/moby/buildkit/frontend/dockerfile/instructions"
+
+	"github.com/tinovyatkin/tally/internal/dockerfile"
+	"github.com/tinovyatkin/tally/internal/rules"
+	"github.com/tinovyatkin/tally/internal/shell"
+)
+
+// PinningConfig holds the options shared by the version pinning rules
+// (DL3008, DL3013, DL3016, DL3018, DL3028, DL3033, DL3037, DL3041, DL3062).
+// Each rule declares its own config type based on it.
+type PinningConfig struct {
+	// Allowlist lists packages that need no version pin. Entries are glob
+	// patterns (path.Match syntax) matched case-insensitively against the
+	// package name, e.g. "ca-certificates" or "@types/*".
+	Allowlist []string `json:"allowlist,omitempty" koanf:"allowlist"`
+}
+
+// DefaultPinningConfig returns the default configuration: every package must be pinned.
+func DefaultPinningConfig() PinningConfig {
+	return PinningConfig{}
+}
+
+// pinningSchema returns the JSON Schema for PinningConfig.
+func pinningSchema() map[string]any {
+	return map[string]any{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type":    "object",
+		"properties": map[string]any{
+			"allowlist": map[string]any{
+				"type":        "array",
+				"items":       map[string]any{"type": "string", "minLength": 1},
+				"uniqueItems": true,
+				"description": "Packages that need no version pin (glob patterns)",
+			},
+		},
+		"additionalProperties": false,
+	}
+}
+
+const pinningDetail = "Without a version, the package manager installs whatever is current at build time, " +
+	"so rebuilding the same Dockerfile can silently produce a different image. " +
+	"Pin a version, or add the package to the rule's allowlist option if any version will do."
+
+// pinningSpec describes how one package manager installs packages.
+type pinningSpec struct {
+	// commands are the command names to look for.
+	commands []string
+
+	// packages returns the package arguments of an install command and the
+	// syntax they follow. It returns no packages for other commands and for
+	// installs whose versions come from a lockfile or constraint file.
+	packages func(cmd *shell.CommandInfo) ([]shell.Word, packageSyntax)
+}
+
+// packageSyntax describes how a package argument selects a version.
+type packageSyntax struct {
+	// pinned reports whether a package argument selects a version (or a
+	// local file, URL or other source that needs no pin).
+	pinned func(arg string) bool
+
+	// name extracts the package name from an argument.
+	name func(arg string) string
+
+	// example is a format string showing the pinned form of a package,
+	// e.g. "%s=<version>".
+	example string
+}
+
+// checkPinning reports every unpinned package installed by the spec's
+// package manager, except those matching the allowlist. Each violation
+// points at the package argument.
+func checkPinning(input rules.LintInput, meta rules.RuleMetadata, allowlist []string, spec pinningSpec) []rules.Violation {
+	sm := input.SourceMap()
+
+	return ScanRunCommandsWithPOSIXShell(
+		input,
+		func(run *instructions.RunCommand, shellVariant shell.Variant, file string) []rules.Violation {
+			var cmds []shell.CommandInfo
+			var runStartLine int
+
+			if run.PrependShell {
+				script, startLine := dockerfile.RunSourceScript(run, sm)
+				if script == "" {
+					return nil
+				}
+				runStartLine = startLine
+				cmds = shell.FindCommands(script, shellVariant, spec.commands...)
+			} else {
+				cmds = shell.FindCommands(dockerfile.RunCommandString(run), shellVariant, spec.commands...)
+			}
+
+			var violations []rules.Violation
+			for _, cmd := range cmds {
+				pkgs, syntax := spec.packages(&cmd)
+				for _, pkg := range pkgs {
+					// The value of an expansion is unknown, so it may well carry a version.
+					if pkg.Dynamic || pkg.Value == "" || syntax.pinned(pkg.Value) {
+						continue
+					}
+					name := syntax.name(pkg.Value)
+					if isAllowlisted(name, allowlist) {
+						continue
+					}
+
+					loc := rules.NewLocationFromRanges(file, run.Location())
+					if run.PrependShell {
+						// Words from a nested "sh -c" script have positions
+						// relative to that script; only use positions that
+						// actually point at the argument.
+						line := runStartLine + pkg.Line
+						if lineIdx := line - 1; lineIdx >= 0 && lineIdx < sm.LineCount() {
+							src := sm.Line(lineIdx)
+							if pkg.EndCol <= len(src) && strings.Contains(src[pkg.StartCol:pkg.EndCol], pkg.Value) {
+								loc = rules.NewRangeLocation(file, line, pkg.StartCol, line, pkg.EndCol)
+							}
+						}
+					}
+
+					msg := fmt.Sprintf("pin the version of %q (e.g. %s)", name, fmt.Sprintf(syntax.example, name))
+					violations = append(violations,
+						rules.NewViolation(loc, meta.Code, msg, meta.DefaultSeverity).
+							WithDocURL(meta.DocURL).
+							WithDetail(pinningDetail))
+				}
+			}
+			return violations
+		},
+	)
+}
+
+// isAllowlisted reports whether a package name matches an allowlist pattern.
+func isAllowlisted(name string, allowlist []string) bool {
+	name = strings.ToLower(name)
+	return slices.ContainsFunc(allowlist, func(pattern string) bool {
+		ok, err := path.Match(strings.ToLower(pattern), name)
+		return err == nil && ok
+	})
+}
+
+// commandOperands returns the operands of a command: its arguments minus
+// flags and the values of flags listed in valueFlags. Values given inline
+// (--flag=value) are not consumed from the next argument. All arguments
+// after "--" are operands.
+func commandOperands(words []shell.Word, valueFlags ...string) []shell.Word {
+	var operands []shell.Word
+	for i := 0; i < len(words); i++ {
+		w := words[i]
+		switch {
+		case w.Value == "--" && !w.Dynamic:
+			return append(operands, words[i+1:]...)
+		case strings.HasPrefix(w.Value, "-") && len(w.Value) > 1:
+			if !strings.Contains(w.Value, "=") && slices.Contains(valueFlags, w.Value) {
+				i++
+			}
+		default:
+			operands = append(operands, w)
+		}
+	}
+	return operands
+}
+
+// subcommandOperands returns the operands following the subcommand path
+// (e.g. "install", or "module", "install"), or nil when the command's
+// leading operands don't match it.
+func subcommandOperands(operands []shell.Word, subcommands ...[]string) []shell.Word {
+	for _, sub := range subcommands {
+		if len(operands) < len(sub) {
+			continue
+		}
+		matched := true
+		for i, s := range sub {
+			if operands[i].Dynamic || operands[i].Value != s {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return operands[len(sub):]
+		}
+	}
+	return nil
+}
+
+// isLocalPackage reports whether an argument names a local file or
+// directory rather than a package from a repository.
+func isLocalPackage(arg string, extensions ...string) bool {
+	if strings.HasPrefix(arg, "/") || strings.HasPrefix(arg, "./") ||
+		strings.HasPrefix(arg, "../") || strings.HasPrefix(arg, "~") || arg == "." || arg == ".." {
+		return true
+	}
+	for _, ext := range extensions {
+		if strings.HasSuffix(arg, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// isRemotePackage reports whether an argument is a URL.
+func isRemotePackage(arg string) bool {
+	return strings.Contains(arg, "://")
+}
+
+// nameBefore returns arg up to the first of the given separators.
+func nameBefore(arg, separators string) string {
+	if i := strings.IndexAny(arg, separators); i > 0 {
+		return arg[:i]
+	}
+	return arg
+}
+
+// hasOption reports whether any of the named options appears among the
+// arguments, either alone or with an inline value (--name=value).
+func hasOption(words []shell.Word, names ...string) bool {
+	return slices.ContainsFunc(words, func(w shell.Word) bool {
+		name, _, _ := strings.Cut(w.Value, "=")
+		return strings.HasPrefix(name, "-") && slices.Contains(names, name)
+	})
+}
@@ -0,0 +1,172 @@
+package hadolint
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/tinovyatkin/tally/internal/rules"
+	"github.com/tinovyatkin/tally/internal/shell"
+	"github.com/tinovyatkin/tally/internal/testutil"
+)
+
+// pinningCase is a version pinning rule test case: want lists the package
+// names reported as unpinned, in order.
+type pinningCase struct {
+	name       string
+	dockerfile string
+	want       []string
+}
+
+// runPinningCases checks a version pinning rule against test cases.
+func runPinningCases(t *testing.T, rule rules.Rule, tests []pinningCase) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			input := testutil.MakeLintInputWithSemantic(t, "Dockerfile", tt.dockerfile)
+			violations := rule.Check(input)
+			if got := unpinnedNames(t, violations); !slices.Equal(got, tt.want) {
+				t.Errorf("unpinned packages = %q, want %q", got, tt.want)
+			}
+			for _, v := range violations {
+				if v.RuleCode != rule.Metadata().Code {
+					t.Errorf("rule code = %q, want %q", v.RuleCode, rule.Metadata().Code)
+				}
+				if v.DocURL != rule.Metadata().DocURL {
+					t.Errorf("doc URL = %q, want %q", v.DocURL, rule.Metadata().DocURL)
+				}
+			}
+		})
+	}
+}
+
+// unpinnedNames extracts the package names from pinning violation messages.
+func unpinnedNames(t *testing.T, violations []rules.Violation) []string {
+	t.Helper()
+	var names []string
+	for _, v := range violations {
+		rest, ok := strings.CutPrefix(v.Message, "pin the version of ")
+		if !ok {
+			t.Fatalf("unexpected message %q", v.Message)
+		}
+		quoted, err := strconv.QuotedPrefix(rest)
+		if err != nil {
+			t.Fatalf("unexpected message %q: %v", v.Message, err)
+		}
+		name, _ := strconv.Unquote(quoted)
+		names = append(names, name)
+	}
+	return names
+}
+
+func TestPinning_Location(t *testing.T) {
+	t.Parallel()
+	input := testutil.MakeLintInput(t, "Dockerfile", `FROM ubuntu:24.04
+RUN apt-get update && \
+    apt-get install -y curl=8.5.0-2ubuntu10 "git"
+`)
+	violations := NewDL3008Rule().Check(input)
+	if len(violations) != 1 {
+		t.Fatalf("got %d violations, want 1", len(violations))
+	}
+	v := violations[0]
+	if v.Message != `pin the version of "git" (e.g. git=<version>)` {
+		t.Errorf("message = %q", v.Message)
+	}
+	if v.Detail == "" {
+		t.Error("violation detail is empty")
+	}
+	// The violation points at the quoted package argument.
+	want := rules.NewRangeLocation("Dockerfile", 3, 44, 3, 49)
+	if v.Location != want {
+		t.Errorf("location = %+v, want %+v", v.Location, want)
+	}
+}
+
+func TestPinning_NestedShellLocation(t *testing.T) {
+	t.Parallel()
+	input := testutil.MakeLintInput(t, "Dockerfile", `FROM ubuntu:24.04
+RUN sh -c 'apt-get install -y curl'
+`)
+	violations := NewDL3008Rule().Check(input)
+	if len(violations) != 1 {
+		t.Fatalf("got %d violations, want 1", len(violations))
+	}
+	// Positions inside the nested script don't map to the source, so the
+	// violation falls back to the whole RUN instruction.
+	if got := violations[0].Location; got.Start.Column != 0 || got.Start.Line != 2 {
+		t.Errorf("location = %+v, want the RUN instruction", got)
+	}
+}
+
+func TestPinning_ExecForm(t *testing.T) {
+	t.Parallel()
+	input := testutil.MakeLintInput(t, "Dockerfile", `FROM alpine:3.20
+RUN ["apk", "add", "curl"]
+`)
+	violations := NewDL3018Rule().Check(input)
+	if got := unpinnedNames(t, violations); !slices.Equal(got, []string{"curl"}) {
+		t.Errorf("unpinned packages = %q, want [curl]", got)
+	}
+}
+
+func TestPinning_Allowlist(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		config any
+		want   []string
+	}{
+		{name: "no config", config: nil, want: []string{"ca-certificates", "curl", "@types/node"}},
+		{name: "exact name", config: map[string]any{"allowlist": []any{"ca-certificates"}}, want: []string{"curl", "@types/node"}},
+		{name: "case-insensitive", config: map[string]any{"allowlist": []any{"CURL"}}, want: []string{"ca-certificates", "@types/node"}},
+		{name: "glob", config: map[string]any{"allowlist": []any{"@types/*", "ca-*"}}, want: []string{"curl"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			input := testutil.MakeLintInputWithConfig(t, "Dockerfile", `FROM node:22
+RUN apt-get install -y ca-certificates curl
+RUN npm install @types/node
+`, tt.config)
+			var violations []rules.Violation
+			violations = append(violations, NewDL3008Rule().Check(input)...)
+			violations = append(violations, NewDL3016Rule().Check(input)...)
+			if got := unpinnedNames(t, violations); !slices.Equal(got, tt.want) {
+				t.Errorf("unpinned packages = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPinning_ValidateConfig(t *testing.T) {
+	t.Parallel()
+	r := NewDL3013Rule()
+	if err := r.ValidateConfig(map[string]any{"allowlist": []any{"pip", "setuptools"}}); err != nil {
+		t.Errorf("valid config rejected: %v", err)
+	}
+	if err := r.ValidateConfig(map[string]any{"allowlist": "pip"}); err == nil {
+		t.Error("expected an error for a non-array allowlist")
+	}
+	if err := r.ValidateConfig(map[string]any{"packages": []any{"pip"}}); err == nil {
+		t.Error("expected an error for an unknown option")
+	}
+}
+
+func TestCommandOperands(t *testing.T) {
+	t.Parallel()
+	words := []string{"-o", "Dpkg::Options::=--force-confold", "--target-release=bookworm", "-y", "install", "--", "-weird"}
+	var in []shell.Word
+	for _, w := range words {
+		in = append(in, shell.Word{Value: w})
+	}
+	var got []string
+	for _, w := range commandOperands(in, aptValueFlags...) {
+		got = append(got, w.Value)
+	}
+	if want := []string{"install", "-weird"}; !slices.Equal(got, want) {
+		t.Errorf("commandOperands() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,66 @@
+package rules
+
+import (
+	_ "embed"
+	"encoding/json/v2"
+	"sync"
+)
+
+//go:embed hadolint-status.json
+var hadolintStatusJSON []byte
+
+// Hadolint rule implementation statuses, as recorded in hadolint-status.json.
+const (
+	HadolintImplemented       = "implemented"
+	HadolintCoveredByBuildKit = "covered_by_buildkit"
+	HadolintCoveredByTally    = "covered_by_tally"
+	HadolintNotImplemented    = "not_implemented"
+)
+
+// HadolintRuleStatus describes how tally supports a Hadolint rule.
+type HadolintRuleStatus struct {
+	// Status is one of the Hadolint* status constants.
+	Status string `json:"status"`
+
+	// TallyRule is the tally rule code (for implemented and covered_by_tally rules).
+	TallyRule string `json:"tally_rule,omitempty"`
+
+	// BuildKitRule is the BuildKit rule name, without namespace (for covered_by_buildkit rules).
+	BuildKitRule string `json:"buildkit_rule,omitempty"`
+
+	// Fixable reports whether tally can auto-fix the rule.
+	Fixable bool `json:"fixable,omitempty"`
+}
+
+// RuleCode returns the namespaced tally rule code that reports this Hadolint
+// rule, or "" if the rule is not supported.
+func (s HadolintRuleStatus) RuleCode() string {
+	switch s.Status {
+	case HadolintImplemented, HadolintCoveredByTally:
+		return s.TallyRule
+	case HadolintCoveredByBuildKit:
+		return BuildKitRulePrefix + s.BuildKitRule
+	default:
+		return ""
+	}
+}
+
+var hadolintStatuses = sync.OnceValue(func() map[string]HadolintRuleStatus {
+	var doc struct {
+		Rules map[string]HadolintRuleStatus `json:"rules"`
+	}
+	if err := json.Unmarshal(hadolintStatusJSON, &doc, json.RejectUnknownMembers(false)); err != nil {
+		panic("rules: invalid hadolint-status.json: " + err.Error())
+	}
+	return doc.Rules
+})
+
+// HadolintStatus returns tally's support status for a Hadolint rule code
+// (e.g. "DL3006"). Rules missing from the status file are reported as
+// not implemented.
+func HadolintStatus(code string) HadolintRuleStatus {
+	if s, ok := hadolintStatuses()[code]; ok {
+		return s
+	}
+	return HadolintRuleStatus{Status: HadolintNotImplemented}
+}
@@ -0,0 +1,25 @@
+package rules
+
+// Label value types of the label-schema config, as in hadolint's label-schema.
+const (
+	// LabelTypeText accepts any value.
+	LabelTypeText = "text"
+	// LabelTypeURL requires an absolute URI.
+	LabelTypeURL = "url"
+	// LabelTypeRFC3339 requires an RFC 3339 timestamp.
+	LabelTypeRFC3339 = "rfc3339"
+	// LabelTypeSPDX requires an SPDX license expression.
+	LabelTypeSPDX = "spdx"
+	// LabelTypeHash requires a git commit hash.
+	LabelTypeHash = "hash"
+	// LabelTypeSemVer requires a semantic version.
+	LabelTypeSemVer = "semver"
+	// LabelTypeEmail requires an RFC 5322 email address.
+	LabelTypeEmail = "email"
+)
+
+// LabelTypes lists the label value types of the label-schema config.
+var LabelTypes = []string{
+	LabelTypeText, LabelTypeURL, LabelTypeRFC3339, LabelTypeSPDX,
+	LabelTypeHash, LabelTypeSemVer, LabelTypeEmail,
+}
@@ -0,0 +1,146 @@
+package rules
+
+import (
+	"slices"
+	"strings"
+)
+
+// PresetPrefix marks a built-in preset in a config file's extends list,
+// e.g. extends = ["preset:strict"].
+const PresetPrefix = "preset:"
+
+// Preset is a named, built-in configuration fragment.
+type Preset struct {
+	// Name is the preset name used after PresetPrefix.
+	Name string
+
+	// Description is a short summary of the preset.
+	Description string
+
+	// config builds the preset in the same nested shape as a .tally.toml file.
+	config func() map[string]any
+}
+
+// Config returns the preset in the same nested shape as a .tally.toml file.
+// Each call returns a fresh map that the caller may modify.
+func (p Preset) Config() map[string]any {
+	return p.config()
+}
+
+// ociLabels are the OCI image annotations (opencontainers/image-spec
+// annotations.md) required by the oci-labels preset, with their value types.
+// Annotations that build tools set themselves (ref.name, base.*) are left out.
+var ociLabels = map[string]string{
+	"org.opencontainers.image.created":     LabelTypeRFC3339,
+	"org.opencontainers.image.description": LabelTypeText,
+	"org.opencontainers.image.licenses":    LabelTypeSPDX,
+	"org.opencontainers.image.revision":    LabelTypeHash,
+	"org.opencontainers.image.source":      LabelTypeURL,
+	"org.opencontainers.image.title":       LabelTypeText,
+	"org.opencontainers.image.version":     LabelTypeSemVer,
+}
+
+// presets are the built-in presets, sorted by name.
+var presets = []Preset{
+	{
+		Name:        "hadolint-compat",
+		Description: "Only hadolint and BuildKit rules, failing at hadolint's default threshold (info)",
+		config: func() map[string]any {
+			return map[string]any{
+				"rules": map[string]any{
+					"exclude": []any{"tally/*"},
+				},
+				"output": map[string]any{
+					"fail-level": "info",
+				},
+			}
+		},
+	},
+	{
+		Name:        "oci-labels",
+		Description: "Require the OCI image annotation labels and validate their values",
+		config: func() map[string]any {
+			schema := map[string]any{}
+			for key, typ := range ociLabels {
+				schema[key] = typ
+			}
+			return map[string]any{"label-schema": schema}
+		},
+	},
+	{
+		Name:        "recommended",
+		Description: "Default rules and severities, reporting unused inline directives",
+		config: func() map[string]any {
+			return map[string]any{
+				"inline-directives": map[string]any{
+					"warn-unused": true,
+				},
+			}
+		},
+	},
+	{
+		Name:        "security",
+		Description: "Security rules enabled and raised to error",
+		config: func() map[string]any {
+			codes := []string{
+				"buildkit/SecretsUsedInArgOrEnv",
+				"hadolint/DL3002",
+				"hadolint/DL3004",
+				"tally/secrets-in-code",
+			}
+			cfg := map[string]any{}
+			include := make([]any, 0, len(codes))
+			for _, code := range codes {
+				include = append(include, code)
+				ns, name, _ := strings.Cut(code, "/")
+				nsMap, ok := cfg[ns].(map[string]any)
+				if !ok {
+					nsMap = map[string]any{}
+					cfg[ns] = nsMap
+				}
+				nsMap[name] = map[string]any{"severity": "error"}
+			}
+			cfg["include"] = include
+			return map[string]any{"rules": cfg}
+		},
+	},
+	{
+		Name:        "strict",
+		Description: "All rules enabled, inline directives must be used, valid and explained",
+		config: func() map[string]any {
+			return map[string]any{
+				"rules": map[string]any{
+					"include": []any{"*"},
+				},
+				"inline-directives": map[string]any{
+					"warn-unused":    true,
+					"validate-rules": true,
+					"require-reason": true,
+				},
+			}
+		},
+	},
+}
+
+// Presets returns the built-in presets, sorted by name.
+func Presets() []Preset {
+	return slices.Clone(presets)
+}
+
+// LookupPreset returns the built-in preset with the given name.
+func LookupPreset(name string) (Preset, bool) {
+	i := slices.IndexFunc(presets, func(p Preset) bool { return p.Name == name })
+	if i < 0 {
+		return Preset{}, false
+	}
+	return presets[i], true
+}
+
+// PresetNames returns the names of the built-in presets, sorted.
+func PresetNames() []string {
+	names := make([]string, len(presets))
+	for i, p := range presets {
+		names[i] = p.Name
+	}
+	return names
+}
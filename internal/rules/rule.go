@@ -79,6 +79,15 @@ type LintInput struct {
 	// Rules that coordinate with heredoc (like DL3003) should use this value.
 	// Zero means use the default (HeredocDefaultMinCommands).
 	HeredocMinCommands int
+
+	// LabelSchema maps label keys to the LabelType* their values must have,
+	// from the [label-schema] config. Empty when no schema is configured.
+	LabelSchema map[string]string
+
+	// BuildTarget is the configured build target ([build] target or --target),
+	// empty for the default build. When the Dockerfile has no such stage,
+	// Semantic models the default build instead.
+	BuildTarget string
 }
 
 // SourceMap creates a SourceMap for snippet extraction and line-based operations.
@@ -168,6 +177,13 @@ type RuleMetadata struct {
 	// IsExperimental marks rules that may change or be removed.
 	IsExperimental bool
 
+	// Fixable marks rules that can produce auto-fixes for tally lint --fix.
+	Fixable bool
+
+	// FixSafety is the least safe level of the rule's auto-fixes.
+	// Only meaningful when Fixable is true.
+	FixSafety FixSafety
+
 	// FixPriority determines the order in which fixes are applied.
 	// Lower values = earlier application (content fixes like DL3027: apt → apt-get).
 	// Higher values = later application (structural transforms like prefer-run-heredoc).
@@ -0,0 +1,80 @@
+package shellcheck
+
+import (
+	"slices"
+	"strings"
+
+	"mvdan.cc/sh/v3/syntax"
+
+	"github.com/tinovyatkin/tally/internal/rules"
+)
+
+// SC2035Rule implements the SC2035 linting rule.
+type SC2035Rule struct{}
+
+// NewSC2035Rule creates a new SC2035 rule instance.
+func NewSC2035Rule() *SC2035Rule {
+	return &SC2035Rule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *SC2035Rule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            rules.ShellcheckRulePrefix + "SC2035",
+		Name:            "Prefix globs with ./ so names with dashes aren't options",
+		Description:     "Use ./*glob* or -- *glob* so names with dashes won't become options",
+		DocURL:          "https://www.shellcheck.net/wiki/SC2035",
+		DefaultSeverity: rules.SeverityInfo,
+		Category:        "correctness",
+		IsExperimental:  false,
+		Fixable:         true,
+		FixSafety:       rules.FixSuggestion,
+	}
+}
+
+// globEchoCommands print their arguments rather than parsing them as options.
+var globEchoCommands = []string{"echo", "printf"}
+
+// Check runs the SC2035 rule.
+// It reports command arguments that are globs starting with *, which expand
+// to file names starting with a dash that the command takes for options.
+// Arguments after "--" are not reported.
+func (r *SC2035Rule) Check(input rules.LintInput) []rules.Violation {
+	meta := r.Metadata()
+
+	return scanScripts(input, func(s *script) []rules.Violation {
+		var violations []rules.Violation
+		s.walk(func(node syntax.Node) bool {
+			call, ok := node.(*syntax.CallExpr)
+			if !ok || len(call.Args) < 2 || isDeclCommand(call) || slices.Contains(globEchoCommands, commandName(call)) {
+				return true
+			}
+			for _, w := range call.Args[1:] {
+				if w.Lit() == "--" {
+					break
+				}
+				lit, ok := w.Parts[0].(*syntax.Lit)
+				if !ok || !strings.HasPrefix(lit.Value, "*") {
+					continue
+				}
+				v := rules.NewViolation(s.location(w.Pos(), w.End()), meta.Code, meta.Description, meta.DefaultSeverity).
+					WithDocURL(meta.DocURL).
+					WithDetail("A file named like an option (e.g. -rf) matches the glob and is parsed as one. " +
+						"Prefix the glob with ./ or end the options with --.").
+					WithSuggestedFix(&rules.SuggestedFix{
+						Description: "Prefix the glob with ./",
+						Safety:      rules.FixSuggestion,
+						Edits:       []rules.TextEdit{s.insert(w.Pos(), "./")},
+					})
+				violations = append(violations, v)
+			}
+			return true
+		})
+		return violations
+	})
+}
+
+// init registers the rule with the default registry.
+func init() {
+	rules.Register(NewSC2035Rule())
+}
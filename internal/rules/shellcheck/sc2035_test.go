@@ -0,0 +1,53 @@
+package shellcheck
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func TestSC2035Rule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewSC2035Rule().Metadata())
+}
+
+func TestSC2035Rule_Check(t *testing.T) {
+	t.Parallel()
+	runSCCases(t, NewSC2035Rule(), []scCase{
+		{
+			name:       "leading glob",
+			dockerfile: "FROM alpine\nRUN rm -f *.tar.gz",
+			want:       []string{"2:10"},
+		},
+		{
+			name:       "prefixed with ./",
+			dockerfile: "FROM alpine\nRUN rm -f ./*.tar.gz",
+		},
+		{
+			name:       "after --",
+			dockerfile: "FROM alpine\nRUN rm -f -- *.tar.gz",
+		},
+		{
+			name:       "quoted pattern",
+			dockerfile: "FROM alpine\nRUN find . -name '*.pyc' -delete",
+		},
+		{
+			name:       "echo",
+			dockerfile: "FROM alpine\nRUN echo *",
+		},
+		{
+			name:       "several globs",
+			dockerfile: "FROM alpine\nRUN chmod +x *.sh *.py",
+			want:       []string{"2:13"}, // findings on one line are merged
+		},
+	})
+}
+
+func TestSC2035Rule_Fix(t *testing.T) {
+	t.Parallel()
+	got := applyFirstFix(t, NewSC2035Rule(), "FROM alpine\nRUN rm -f *.tar.gz\n")
+	want := "FROM alpine\nRUN rm -f ./*.tar.gz\n"
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,62 @@
+package shellcheck
+
+import (
+	"mvdan.cc/sh/v3/syntax"
+
+	"github.com/tinovyatkin/tally/internal/rules"
+)
+
+// SC2046Rule implements the SC2046 linting rule.
+type SC2046Rule struct{}
+
+// NewSC2046Rule creates a new SC2046 rule instance.
+func NewSC2046Rule() *SC2046Rule {
+	return &SC2046Rule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *SC2046Rule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            rules.ShellcheckRulePrefix + "SC2046",
+		Name:            "Quote command substitutions to prevent word splitting",
+		Description:     "Quote this to prevent word splitting",
+		DocURL:          "https://www.shellcheck.net/wiki/SC2046",
+		DefaultSeverity: rules.SeverityWarning,
+		Category:        "correctness",
+		IsExperimental:  false,
+		Fixable:         true,
+		FixSafety:       rules.FixSuggestion,
+	}
+}
+
+// Check runs the SC2046 rule.
+// It reports unquoted command substitutions ($(...) and `...`) in command
+// arguments, whose output is split into words and globbed.
+func (r *SC2046Rule) Check(input rules.LintInput) []rules.Violation {
+	meta := r.Metadata()
+
+	return scanScripts(input, func(s *script) []rules.Violation {
+		var violations []rules.Violation
+		s.splitWords(func(w *syntax.Word) {
+			for _, part := range w.Parts {
+				cs, ok := part.(*syntax.CmdSubst)
+				if !ok {
+					continue
+				}
+				v := rules.NewViolation(s.location(cs.Pos(), cs.End()), meta.Code, meta.Description, meta.DefaultSeverity).
+					WithDocURL(meta.DocURL).
+					WithDetail("The output of an unquoted command substitution is split on whitespace and each word " +
+						"is expanded as a glob. Quote it to pass the output as one argument; to pass a list, " +
+						"read it into an array or use xargs.").
+					WithSuggestedFix(s.quoteFix(cs.Pos(), cs.End()))
+				violations = append(violations, v)
+			}
+		})
+		return violations
+	})
+}
+
+// init registers the rule with the default registry.
+func init() {
+	rules.Register(NewSC2046Rule())
+}
@@ -0,0 +1,54 @@
+package shellcheck
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func TestSC2046Rule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewSC2046Rule().Metadata())
+}
+
+func TestSC2046Rule_Check(t *testing.T) {
+	t.Parallel()
+	runSCCases(t, NewSC2046Rule(), []scCase{
+		{
+			name:       "unquoted command substitution",
+			dockerfile: "FROM alpine\nRUN rm $(find . -name '*.tmp')",
+			want:       []string{"2:7"},
+		},
+		{
+			name:       "backquotes",
+			dockerfile: "FROM alpine\nRUN echo `uname -m`",
+			want:       []string{"2:9"},
+		},
+		{
+			name:       "quoted command substitution",
+			dockerfile: "FROM alpine\nRUN cd \"$(dirname \"$0\")\"",
+		},
+		{
+			name:       "assignment",
+			dockerfile: "FROM alpine\nRUN arch=$(uname -m)",
+		},
+		{
+			name:       "nested in a quoted argument",
+			dockerfile: "FROM alpine\nRUN echo \"arch: $(uname -m)\"",
+		},
+		{
+			name:       "continuation line",
+			dockerfile: "FROM alpine\nRUN apk add \\\n      $(cat /packages.txt)",
+			want:       []string{"3:6"},
+		},
+	})
+}
+
+func TestSC2046Rule_Fix(t *testing.T) {
+	t.Parallel()
+	got := applyFirstFix(t, NewSC2046Rule(), "FROM alpine\nRUN mkdir -p /opt/$(uname -m)\n")
+	want := "FROM alpine\nRUN mkdir -p /opt/\"$(uname -m)\"\n"
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
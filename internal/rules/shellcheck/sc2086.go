@@ -0,0 +1,94 @@
+package shellcheck
+
+import (
+	"mvdan.cc/sh/v3/syntax"
+
+	"github.com/tinovyatkin/tally/internal/rules"
+)
+
+// SC2086Rule implements the SC2086 linting rule.
+type SC2086Rule struct{}
+
+// NewSC2086Rule creates a new SC2086 rule instance.
+func NewSC2086Rule() *SC2086Rule {
+	return &SC2086Rule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *SC2086Rule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            rules.ShellcheckRulePrefix + "SC2086",
+		Name:            "Double quote to prevent globbing and word splitting",
+		Description:     "Double quote to prevent globbing and word splitting",
+		DocURL:          "https://www.shellcheck.net/wiki/SC2086",
+		DefaultSeverity: rules.SeverityInfo,
+		Category:        "correctness",
+		IsExperimental:  false,
+		Fixable:         true,
+		FixSafety:       rules.FixSuggestion,
+	}
+}
+
+// Check runs the SC2086 rule.
+// It reports unquoted variable expansions in command arguments whose value
+// may contain whitespace or glob characters. Variables only ever assigned
+// plain literals (in the script, or by ENV/ARG) are considered safe.
+func (r *SC2086Rule) Check(input rules.LintInput) []rules.Violation {
+	meta := r.Metadata()
+
+	return scanScripts(input, func(s *script) []rules.Violation {
+		assigned := s.assignments()
+
+		var violations []rules.Violation
+		s.splitWords(func(w *syntax.Word) {
+			for _, part := range w.Parts {
+				pe, ok := part.(*syntax.ParamExp)
+				if !ok || !splitsParam(s, assigned, pe) {
+					continue
+				}
+				v := rules.NewViolation(s.location(pe.Pos(), pe.End()), meta.Code, meta.Description, meta.DefaultSeverity).
+					WithDocURL(meta.DocURL).
+					WithDetail("An unquoted expansion is split into words on whitespace and each word is expanded " +
+						"as a glob, so a value with spaces or * becomes several arguments. Quote it: \"$var\".").
+					WithSuggestedFix(s.quoteFix(pe.Pos(), pe.End()))
+				violations = append(violations, v)
+			}
+		})
+		return violations
+	})
+}
+
+// splitsParam reports whether an unquoted parameter expansion may be split
+// into several words or globbed.
+func splitsParam(s *script, assigned map[string][]*syntax.Word, pe *syntax.ParamExp) bool {
+	if pe.Param == nil || pe.Length || pe.Excl {
+		return false
+	}
+	name := pe.Param.Value
+	if isSpecialParam(name) && name != "_" && !isPositional(name) {
+		return false // $?, $#, $$, $!, $- and $@/$* (ShellCheck's SC2068)
+	}
+	if idx, ok := pe.Index.(*syntax.Word); ok && (idx.Lit() == "@" || idx.Lit() == "*") {
+		return false // ${arr[@]}, SC2068 as well
+	}
+	if pe.Exp != nil && pe.Exp.Word != nil {
+		switch pe.Exp.Op {
+		case syntax.DefaultUnset, syntax.DefaultUnsetOrNull, syntax.AssignUnset, syntax.AssignUnsetOrNull,
+			syntax.AlternateUnset, syntax.AlternateUnsetOrNull:
+			if lit, ok := literalValue(pe.Exp.Word); !ok || !safeLiteral(lit) {
+				return true
+			}
+		}
+	}
+	return isPositional(name) || !s.splitSafe(assigned, name)
+}
+
+// isPositional reports whether a parameter is a positional parameter ($1).
+func isPositional(name string) bool {
+	return name != "" && name[0] >= '0' && name[0] <= '9'
+}
+
+// init registers the rule with the default registry.
+func init() {
+	rules.Register(NewSC2086Rule())
+}
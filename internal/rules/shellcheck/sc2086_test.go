@@ -0,0 +1,106 @@
+package shellcheck
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func TestSC2086Rule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewSC2086Rule().Metadata())
+}
+
+func TestSC2086Rule_Check(t *testing.T) {
+	t.Parallel()
+	runSCCases(t, NewSC2086Rule(), []scCase{
+		{
+			name:       "unquoted variable assigned a command substitution",
+			dockerfile: "FROM alpine\nRUN dir=$(pwd); ls $dir",
+			want:       []string{"2:19"},
+		},
+		{
+			name:       "quoted variable",
+			dockerfile: "FROM alpine\nRUN dir=$(pwd); ls \"$dir\"",
+		},
+		{
+			name:       "positional parameters",
+			dockerfile: "FROM alpine\nRUN set -- a b; echo $1 ${2}",
+			want:       []string{"2:21"}, // findings on one line are merged
+		},
+		{
+			name:       "variable with a safe literal value",
+			dockerfile: "FROM alpine\nRUN dir=/app; ls $dir",
+		},
+		{
+			name:       "variable assigned a value with spaces",
+			dockerfile: "FROM alpine\nRUN opts='-l -a'; ls $opts",
+			want:       []string{"2:21"},
+		},
+		{
+			name:       "ENV with a safe value",
+			dockerfile: "FROM alpine\nENV APP_HOME=/app\nRUN ls $APP_HOME",
+		},
+		{
+			name:       "ARG without a default",
+			dockerfile: "FROM alpine\nARG TARGET\nRUN ls $TARGET",
+			want:       []string{"3:7"},
+		},
+		{
+			name:       "ENV with a variable value",
+			dockerfile: "FROM alpine\nARG BASE\nENV DIR=$BASE/app\nRUN ls $DIR",
+			want:       []string{"4:7"},
+		},
+		{
+			name:       "special parameters",
+			dockerfile: "FROM alpine\nRUN echo $? $# $$",
+		},
+		{
+			name:       "length and indexes",
+			dockerfile: "FROM alpine\nSHELL [\"/bin/bash\", \"-c\"]\nRUN a=(x y); echo ${#a} ${a[@]}",
+		},
+		{
+			name:       "assignments and arithmetic",
+			dockerfile: "FROM alpine\nRUN a=$(pwd); b=$a; echo $((a + 1))",
+		},
+		{
+			name:       "redirect target",
+			dockerfile: "FROM alpine\nRUN out=$(mktemp); echo hi > $out",
+			want:       []string{"2:29"},
+		},
+		{
+			name:       "default of an unknown variable",
+			dockerfile: "FROM alpine\nRUN echo ${dir:-/app}",
+			want:       []string{"2:9"},
+		},
+		{
+			name:       "default of a safe variable",
+			dockerfile: "FROM alpine\nENV dir=/srv\nRUN echo ${dir:-/app}",
+		},
+		{
+			name:       "heredoc body",
+			dockerfile: "FROM alpine\nRUN <<EOF\nf=$(ls)\nrm $f\nEOF",
+			want:       []string{"4:3"},
+		},
+		{
+			name:       "heredoc data is not a script",
+			dockerfile: "FROM alpine\nRUN cat <<EOF > /etc/motd\nrm $f\nEOF",
+		},
+	})
+}
+
+func TestSC2086Rule_Fix(t *testing.T) {
+	t.Parallel()
+	got := applyFirstFix(t, NewSC2086Rule(), "FROM alpine\nRUN f=$(ls); rm ${f}.bak\n")
+	want := "FROM alpine\nRUN f=$(ls); rm \"${f}\".bak\n"
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	// The merged violation of a line quotes every expansion on it.
+	got = applyFirstFix(t, NewSC2086Rule(), "FROM alpine\nRUN set -- a b; echo $1 ${2}\n")
+	want = "FROM alpine\nRUN set -- a b; echo \"$1\" \"${2}\"\n"
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,98 @@
+package shellcheck
+
+import (
+	"slices"
+	"strings"
+
+	"mvdan.cc/sh/v3/syntax"
+
+	"github.com/tinovyatkin/tally/internal/rules"
+)
+
+// SC2154Rule implements the SC2154 linting rule.
+type SC2154Rule struct{}
+
+// NewSC2154Rule creates a new SC2154 rule instance.
+func NewSC2154Rule() *SC2154Rule {
+	return &SC2154Rule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *SC2154Rule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            rules.ShellcheckRulePrefix + "SC2154",
+		Name:            "Variable is referenced but not assigned",
+		Description:     "Variable is referenced but not assigned",
+		DocURL:          "https://www.shellcheck.net/wiki/SC2154",
+		DefaultSeverity: rules.SeverityWarning,
+		Category:        "correctness",
+		IsExperimental:  false,
+	}
+}
+
+// proxyVars are the lowercase proxy variables BuildKit passes to RUN
+// without an ARG instruction.
+var proxyVars = []string{"http_proxy", "https_proxy", "ftp_proxy", "no_proxy", "all_proxy"}
+
+// Check runs the SC2154 rule.
+// It reports lowercase variables that a script references but neither
+// assigns nor gets from an ENV or ARG instruction before the RUN (or from
+// the stage it is based on). Uppercase variables are assumed to come from
+// the base image's environment, like ShellCheck does, and references with
+// a default (${var:-x}) are skipped. Each variable is reported once per
+// script.
+func (r *SC2154Rule) Check(input rules.LintInput) []rules.Violation {
+	meta := r.Metadata()
+
+	return scanScripts(input, func(s *script) []rules.Violation {
+		assigned := s.assignments()
+		reported := make(map[string]bool)
+
+		var violations []rules.Violation
+		s.walk(func(node syntax.Node) bool {
+			pe, ok := node.(*syntax.ParamExp)
+			if !ok || pe.Param == nil || pe.Excl || guardsUnset(pe) {
+				return true
+			}
+			name := pe.Param.Value
+			if reported[name] || isSpecialParam(name) || strings.ToUpper(name) == name ||
+				slices.Contains(proxyVars, name) {
+				return true
+			}
+			if _, ok := assigned[name]; ok {
+				return true
+			}
+			if _, ok := s.vars[name]; ok {
+				return true
+			}
+			reported[name] = true
+			v := rules.NewViolation(s.location(pe.Pos(), pe.End()), meta.Code,
+				name+" is referenced but not assigned", meta.DefaultSeverity).
+				WithDocURL(meta.DocURL).
+				WithDetail("No ENV or ARG instruction sets " + name + " before this RUN, and the script doesn't assign it. " +
+					"Variables assigned in a RUN don't persist to later instructions; use ENV or ARG to share them.")
+			violations = append(violations, v)
+			return true
+		})
+		return violations
+	})
+}
+
+// guardsUnset reports whether an expansion handles an unset variable itself:
+// ${var-x}, ${var:-x}, ${var+x}, ${var=x}, ${var?msg} and their : forms.
+func guardsUnset(pe *syntax.ParamExp) bool {
+	if pe.Exp == nil {
+		return false
+	}
+	switch pe.Exp.Op {
+	case syntax.DefaultUnset, syntax.DefaultUnsetOrNull, syntax.AlternateUnset, syntax.AlternateUnsetOrNull,
+		syntax.AssignUnset, syntax.AssignUnsetOrNull, syntax.ErrorUnset, syntax.ErrorUnsetOrNull:
+		return true
+	}
+	return false
+}
+
+// init registers the rule with the default registry.
+func init() {
+	rules.Register(NewSC2154Rule())
+}
@@ -0,0 +1,79 @@
+package shellcheck
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func TestSC2154Rule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewSC2154Rule().Metadata())
+}
+
+func TestSC2154Rule_Check(t *testing.T) {
+	t.Parallel()
+	runSCCases(t, NewSC2154Rule(), []scCase{
+		{
+			name:       "unassigned variable",
+			dockerfile: "FROM alpine\nRUN echo \"$version\"",
+			want:       []string{"2:10"},
+		},
+		{
+			name:       "reported once",
+			dockerfile: "FROM alpine\nRUN echo \"$version\" \"$version\"",
+			want:       []string{"2:10"},
+		},
+		{
+			name:       "assigned in the script",
+			dockerfile: "FROM alpine\nRUN version=1; echo \"$version\"",
+		},
+		{
+			name:       "read target",
+			dockerfile: "FROM alpine\nRUN read -r line < /etc/hostname; echo \"$line\"",
+		},
+		{
+			name:       "for loop variable",
+			dockerfile: "FROM alpine\nRUN for pkg in a b; do echo \"$pkg\"; done",
+		},
+		{
+			name:       "ARG before the RUN",
+			dockerfile: "FROM alpine\nARG version\nRUN echo \"$version\"",
+		},
+		{
+			name:       "ENV before the RUN",
+			dockerfile: "FROM alpine\nENV app_dir=/app\nRUN cd \"$app_dir\"",
+		},
+		{
+			name:       "ENV after the RUN",
+			dockerfile: "FROM alpine\nRUN cd \"$app_dir\"\nENV app_dir=/app",
+			want:       []string{"2:8"},
+		},
+		{
+			name:       "ENV inherited from the base stage",
+			dockerfile: "FROM alpine AS base\nENV app_dir=/app\nFROM base\nRUN cd \"$app_dir\"",
+		},
+		{
+			name:       "uppercase variable",
+			dockerfile: "FROM alpine\nRUN echo \"$JAVA_HOME\"",
+		},
+		{
+			name:       "default value",
+			dockerfile: "FROM alpine\nRUN echo \"${version:-latest}\"",
+		},
+		{
+			name:       "proxy variables",
+			dockerfile: "FROM alpine\nRUN echo \"$http_proxy\"",
+		},
+		{
+			name:       "assigned only in a previous RUN",
+			dockerfile: "FROM alpine\nRUN version=1\nRUN echo \"$version\"",
+			want:       []string{"3:10"},
+		},
+		{
+			name:       "heredoc body",
+			dockerfile: "FROM alpine\nRUN <<EOF\necho \"$version\"\nEOF",
+			want:       []string{"3:6"},
+		},
+	})
+}
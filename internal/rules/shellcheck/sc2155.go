@@ -0,0 +1,72 @@
+package shellcheck
+
+import (
+	"mvdan.cc/sh/v3/syntax"
+
+	"github.com/tinovyatkin/tally/internal/rules"
+)
+
+// SC2155Rule implements the SC2155 linting rule.
+type SC2155Rule struct{}
+
+// NewSC2155Rule creates a new SC2155 rule instance.
+func NewSC2155Rule() *SC2155Rule {
+	return &SC2155Rule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *SC2155Rule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            rules.ShellcheckRulePrefix + "SC2155",
+		Name:            "Declare and assign separately",
+		Description:     "Declare and assign separately to avoid masking return values",
+		DocURL:          "https://www.shellcheck.net/wiki/SC2155",
+		DefaultSeverity: rules.SeverityWarning,
+		Category:        "correctness",
+		IsExperimental:  false,
+	}
+}
+
+// Check runs the SC2155 rule.
+// It reports export, local, declare, readonly and typeset assignments of a
+// command substitution: the builtin's exit status replaces the command's,
+// so a failing command goes unnoticed even with set -e.
+func (r *SC2155Rule) Check(input rules.LintInput) []rules.Violation {
+	meta := r.Metadata()
+
+	return scanScripts(input, func(s *script) []rules.Violation {
+		var violations []rules.Violation
+		s.walk(func(node syntax.Node) bool {
+			for _, d := range declarations(node) {
+				if d.value == nil || !hasCmdSubst(d.value) {
+					continue
+				}
+				v := rules.NewViolation(s.location(d.pos, d.end), meta.Code, meta.Description, meta.DefaultSeverity).
+					WithDocURL(meta.DocURL).
+					WithDetail("The declaration's exit status hides the status of the command substitution. " +
+						"Assign first and declare after (" + d.name + "=$(cmd); export " + d.name + "), " +
+						"or declare first for local (local " + d.name + "; " + d.name + "=$(cmd)).")
+				violations = append(violations, v)
+			}
+			return true
+		})
+		return violations
+	})
+}
+
+// hasCmdSubst reports whether a word contains a command substitution.
+func hasCmdSubst(w *syntax.Word) bool {
+	found := false
+	syntax.Walk(w, func(node syntax.Node) bool {
+		if _, ok := node.(*syntax.CmdSubst); ok {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// init registers the rule with the default registry.
+func init() {
+	rules.Register(NewSC2155Rule())
+}
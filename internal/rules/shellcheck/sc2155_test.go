@@ -0,0 +1,41 @@
+package shellcheck
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func TestSC2155Rule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewSC2155Rule().Metadata())
+}
+
+func TestSC2155Rule_Check(t *testing.T) {
+	t.Parallel()
+	runSCCases(t, NewSC2155Rule(), []scCase{
+		{
+			name:       "export in POSIX sh",
+			dockerfile: "FROM alpine\nRUN export ARCH=$(uname -m) && make",
+			want:       []string{"2:11"},
+		},
+		{
+			name:       "local in bash",
+			dockerfile: "FROM alpine\nSHELL [\"/bin/bash\", \"-c\"]\nRUN f() { local v=\"$(date)\"; echo \"$v\"; }; f",
+			want:       []string{"3:16"},
+		},
+		{
+			name:       "readonly with several names",
+			dockerfile: "FROM alpine\nRUN readonly a=1 b=`pwd`",
+			want:       []string{"2:17"},
+		},
+		{
+			name:       "assigned separately",
+			dockerfile: "FROM alpine\nRUN ARCH=$(uname -m); export ARCH",
+		},
+		{
+			name:       "plain value",
+			dockerfile: "FROM alpine\nRUN export PATH=\"/opt/bin:$PATH\"",
+		},
+	})
+}
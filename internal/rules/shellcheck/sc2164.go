@@ -0,0 +1,134 @@
+package shellcheck
+
+import (
+	"slices"
+
+	"mvdan.cc/sh/v3/syntax"
+
+	"github.com/tinovyatkin/tally/internal/rules"
+)
+
+// SC2164Rule implements the SC2164 linting rule.
+type SC2164Rule struct{}
+
+// NewSC2164Rule creates a new SC2164 rule instance.
+func NewSC2164Rule() *SC2164Rule {
+	return &SC2164Rule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *SC2164Rule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            rules.ShellcheckRulePrefix + "SC2164",
+		Name:            "Exit if cd fails",
+		Description:     "Use 'cd ... || exit' or 'cd ... || return' in case cd fails",
+		DocURL:          "https://www.shellcheck.net/wiki/SC2164",
+		DefaultSeverity: rules.SeverityWarning,
+		Category:        "correctness",
+		IsExperimental:  false,
+		Fixable:         true,
+		FixSafety:       rules.FixSuggestion,
+	}
+}
+
+// cdCommands are the commands changing the working directory.
+var cdCommands = []string{"cd", "pushd", "popd"}
+
+// Check runs the SC2164 rule.
+// It reports cd commands whose failure goes unnoticed: not part of an && or
+// || list or a condition, and not the last command of the RUN, whose exit
+// status fails the build. Shells running with -e (SHELL ["/bin/sh", "-e",
+// "-c"], set -e) are skipped.
+func (r *SC2164Rule) Check(input rules.LintInput) []rules.Violation {
+	meta := r.Metadata()
+
+	return scanScripts(input, func(s *script) []rules.Violation {
+		if s.errexit {
+			return nil
+		}
+		var violations []rules.Violation
+		for _, stmt := range uncheckedStmts(s.prog.Stmts, true) {
+			call, ok := stmt.Cmd.(*syntax.CallExpr)
+			if !ok || !slices.Contains(cdCommands, commandName(call)) {
+				continue
+			}
+			end := call.End()
+			for _, r := range stmt.Redirs {
+				if r.End().After(end) {
+					end = r.End()
+				}
+			}
+			v := rules.NewViolation(s.location(call.Pos(), call.End()), meta.Code, meta.Description, meta.DefaultSeverity).
+				WithDocURL(meta.DocURL).
+				WithDetail("If " + commandName(call) + " fails, the following commands run in the wrong directory. " +
+					"Chain them with && or exit on failure. In Dockerfiles, WORKDIR is usually the better choice.").
+				WithSuggestedFix(&rules.SuggestedFix{
+					Description: "Append || exit",
+					Safety:      rules.FixSuggestion,
+					Edits:       []rules.TextEdit{s.insert(end, " || exit")},
+				})
+			violations = append(violations, v)
+		}
+		return violations
+	})
+}
+
+// uncheckedStmts returns the simple statements of a list whose exit status
+// is ignored. checked reports whether the status of the list's last
+// statement is checked (by a condition, an && or || list, or as the RUN's
+// own status).
+func uncheckedStmts(stmts []*syntax.Stmt, checked bool) []*syntax.Stmt {
+	var out []*syntax.Stmt
+	for i, stmt := range stmts {
+		out = append(out, uncheckedStmt(stmt, checked && i == len(stmts)-1)...)
+	}
+	return out
+}
+
+// uncheckedStmt returns the simple statements within stmt whose exit status
+// is ignored.
+func uncheckedStmt(stmt *syntax.Stmt, checked bool) []*syntax.Stmt {
+	if stmt.Negated {
+		checked = true
+	}
+	switch c := stmt.Cmd.(type) {
+	case *syntax.CallExpr:
+		if !checked && !stmt.Background {
+			return []*syntax.Stmt{stmt}
+		}
+	case *syntax.BinaryCmd:
+		if c.Op == syntax.AndStmt || c.Op == syntax.OrStmt {
+			return append(uncheckedStmt(c.X, true), uncheckedStmt(c.Y, checked)...)
+		}
+		// A directory change in a pipeline has no effect beyond it.
+	case *syntax.Block:
+		return uncheckedStmts(c.Stmts, checked)
+	case *syntax.Subshell:
+		return uncheckedStmts(c.Stmts, checked)
+	case *syntax.IfClause:
+		var out []*syntax.Stmt
+		for clause := c; clause != nil; clause = clause.Else {
+			out = append(out, uncheckedStmts(clause.Cond, true)...)
+			out = append(out, uncheckedStmts(clause.Then, checked)...)
+		}
+		return out
+	case *syntax.WhileClause:
+		return append(uncheckedStmts(c.Cond, true), uncheckedStmts(c.Do, false)...)
+	case *syntax.ForClause:
+		return uncheckedStmts(c.Do, false)
+	case *syntax.CaseClause:
+		var out []*syntax.Stmt
+		for _, item := range c.Items {
+			out = append(out, uncheckedStmts(item.Stmts, checked)...)
+		}
+		return out
+	case *syntax.FuncDecl:
+		return uncheckedStmt(c.Body, false)
+	}
+	return nil
+}
+
+// init registers the rule with the default registry.
+func init() {
+	rules.Register(NewSC2164Rule())
+}
@@ -0,0 +1,62 @@
+package shellcheck
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func TestSC2164Rule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewSC2164Rule().Metadata())
+}
+
+func TestSC2164Rule_Check(t *testing.T) {
+	t.Parallel()
+	runSCCases(t, NewSC2164Rule(), []scCase{
+		{
+			name:       "cd followed by a command",
+			dockerfile: "FROM alpine\nRUN cd /app; make",
+			want:       []string{"2:4"},
+		},
+		{
+			name:       "cd chained with &&",
+			dockerfile: "FROM alpine\nRUN cd /app && make",
+		},
+		{
+			name:       "cd with || exit",
+			dockerfile: "FROM alpine\nRUN cd /app || exit; make",
+		},
+		{
+			name:       "cd as the last command",
+			dockerfile: "FROM alpine\nRUN make; cd /app",
+		},
+		{
+			name:       "cd in a condition",
+			dockerfile: "FROM alpine\nRUN if cd /app; then make; fi",
+		},
+		{
+			name:       "pushd in a subshell",
+			dockerfile: "FROM alpine\nSHELL [\"/bin/bash\", \"-c\"]\nRUN (pushd /app; make); echo done",
+			want:       []string{"3:5"},
+		},
+		{
+			name:       "set -e earlier in the script",
+			dockerfile: "FROM alpine\nRUN set -eux; cd /app; make",
+		},
+		{
+			name:       "cd in a loop body",
+			dockerfile: "FROM alpine\nRUN for d in a b; do cd $d; make; cd ..; done",
+			want:       []string{"2:21"}, // findings on one line are merged
+		},
+	})
+}
+
+func TestSC2164Rule_Fix(t *testing.T) {
+	t.Parallel()
+	got := applyFirstFix(t, NewSC2164Rule(), "FROM alpine\nRUN cd /app 2>/dev/null; make\n")
+	want := "FROM alpine\nRUN cd /app 2>/dev/null || exit; make\n"
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
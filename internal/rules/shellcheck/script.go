@@ -0,0 +1,449 @@
+// Package shellcheck implements ShellCheck-equivalent checks on the shell
+// scripts of RUN instructions.
+//
+// Scripts are parsed with mvdan.cc/sh in the dialect of the stage's shell
+// (SHELL instruction or "# tally shell=" directive), and findings are mapped
+// back to exact Dockerfile positions. Heredoc bodies that the shell runs as
+// scripts are checked as scripts of their own.
+package shellcheck
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/moby/buildkit/frontend/dockerfile/instructions"
+	"mvdan.cc/sh/v3/syntax"
+
+	"github.com/tinovyatkin/tally/internal/dockerfile"
+	"github.com/tinovyatkin/tally/internal/rules"
+	"github.com/tinovyatkin/tally/internal/semantic"
+	"github.com/tinovyatkin/tally/internal/shell"
+	"github.com/tinovyatkin/tally/internal/sourcemap"
+)
+
+// script is a shell script run by a RUN instruction: the RUN's command line,
+// or a heredoc body that the shell executes.
+type script struct {
+	// prog is the parsed script.
+	prog *syntax.File
+
+	// file is the Dockerfile path.
+	file string
+
+	// line is the 1-based Dockerfile line of the script's first line.
+	// Script columns are Dockerfile columns on every line.
+	line int
+
+	// errexit reports whether the shell exits on the first failing command:
+	// -e in the SHELL instruction, the shell's flags or shebang, or set -e.
+	errexit bool
+
+	// vars holds the variables set by ENV and ARG instructions before the
+	// RUN, mapped to their value or nil when it is unknown.
+	vars map[string]*string
+
+	// heredocs are the heredoc bodies checked as scripts of their own,
+	// which walk skips.
+	heredocs map[*syntax.Word]bool
+}
+
+// scriptCheck reports the violations in one script.
+type scriptCheck func(s *script) []rules.Violation
+
+// scanScripts runs check on the scripts of every shell-form RUN.
+// Exec-form RUNs, ONBUILD triggers and stages with a non-POSIX shell are
+// skipped, as are scripts that fail to parse.
+func scanScripts(input rules.LintInput, check scriptCheck) []rules.Violation {
+	sem, ok := input.Semantic.(*semantic.Model)
+	if !ok {
+		sem = nil
+	}
+	sm := input.SourceMap()
+
+	var violations []rules.Violation
+	for stageIdx, stage := range input.Stages {
+		variant := shell.VariantBash
+		shellCmd := semantic.DefaultShell
+		var info *semantic.StageInfo
+		if sem != nil {
+			info = sem.StageInfo(stageIdx)
+		}
+		if info != nil {
+			variant = info.ShellSetting.Variant
+			shellCmd = info.ShellSetting.Shell
+		}
+		if variant.IsNonPOSIX() {
+			continue
+		}
+		errexit := len(shellCmd) > 1 && hasErrexit(shellCmd[1:])
+
+		vars := inheritedVars(sem, info)
+		for _, cmd := range stage.Commands {
+			switch c := cmd.(type) {
+			case *instructions.ArgCommand:
+				for _, kv := range c.Args {
+					vars[kv.Key] = kv.Value
+				}
+			case *instructions.EnvCommand:
+				for _, kv := range c.Env {
+					vars[kv.Key] = new(kv.Value)
+				}
+			case *instructions.RunCommand:
+				if !c.PrependShell {
+					continue
+				}
+				for _, s := range runScripts(c, sm, input.File, variant, errexit, vars) {
+					violations = append(violations, check(s)...)
+				}
+			}
+		}
+	}
+	return mergeLineViolations(violations)
+}
+
+// mergeLineViolations merges the findings of a rule that start on the same
+// line into the first one, which the processor chain would otherwise
+// deduplicate along with their fixes. The merged violation spans all the
+// findings and carries all their fix edits.
+func mergeLineViolations(violations []rules.Violation) []rules.Violation {
+	byLine := make(map[int]int) // start line -> index in merged
+	merged := violations[:0:0]
+	for _, v := range violations {
+		i, ok := byLine[v.Location.Start.Line]
+		if !ok {
+			byLine[v.Location.Start.Line] = len(merged)
+			merged = append(merged, v)
+			continue
+		}
+		m := &merged[i]
+		if end := v.Location.End; end.Line > m.Location.End.Line ||
+			end.Line == m.Location.End.Line && end.Column > m.Location.End.Column {
+			m.Location.End = end
+		}
+		switch {
+		case v.SuggestedFix == nil:
+		case m.SuggestedFix == nil:
+			m.SuggestedFix = v.SuggestedFix
+		default:
+			fix := *m.SuggestedFix
+			fix.Edits = slices.Concat(fix.Edits, v.SuggestedFix.Edits)
+			m.SuggestedFix = &fix
+		}
+	}
+	return merged
+}
+
+// inheritedVars returns the environment a stage inherits from the stage it
+// is based on, if any.
+func inheritedVars(sem *semantic.Model, info *semantic.StageInfo) map[string]*string {
+	vars := make(map[string]*string)
+	if info == nil || info.BaseImage == nil || !info.BaseImage.IsStageRef {
+		return vars
+	}
+	if base := sem.StageInfo(info.BaseImage.StageIndex); base != nil {
+		for k, v := range base.EffectiveEnv {
+			vars[k] = new(v)
+		}
+	}
+	return vars
+}
+
+// runScripts parses the scripts of a shell-form RUN: its command line and
+// the heredoc bodies the shell runs.
+func runScripts(
+	run *instructions.RunCommand,
+	sm *sourcemap.SourceMap,
+	file string,
+	variant shell.Variant,
+	errexit bool,
+	vars map[string]*string,
+) []*script {
+	src, startLine := dockerfile.RunSourceScript(run, sm)
+	if src == "" {
+		return nil
+	}
+	lines := strings.Split(src, "\n")
+	if len(run.Files) == 0 {
+		blankCommentLines(lines)
+	}
+	prog, err := shell.ParseScript(strings.Join(lines, "\n"), variant)
+	if err != nil {
+		return nil
+	}
+
+	outer := &script{
+		prog:     prog,
+		file:     file,
+		line:     startLine,
+		errexit:  errexit,
+		vars:     vars,
+		heredocs: make(map[*syntax.Word]bool),
+	}
+	scripts := []*script{outer}
+
+	syntax.Walk(prog, func(node syntax.Node) bool {
+		stmt, ok := node.(*syntax.Stmt)
+		if !ok {
+			return true
+		}
+		for _, r := range stmt.Redirs {
+			if (r.Op != syntax.Hdoc && r.Op != syntax.DashHdoc) || r.Hdoc == nil {
+				continue
+			}
+			if r.N != nil && r.N.Value != "0" {
+				continue
+			}
+			bodyLine := int(r.Hdoc.Pos().Line()) //nolint:gosec // G115: shell scripts won't have int-overflowing positions
+			body, ok := heredocBody(lines, bodyLine, r)
+			if !ok {
+				continue
+			}
+			bodyVariant, bodyErrexit, ok := heredocShell(stmt, body, variant, errexit)
+			if !ok {
+				continue
+			}
+			outer.heredocs[r.Hdoc] = true
+			bodyProg, err := shell.ParseScript(body, bodyVariant)
+			if err != nil {
+				continue
+			}
+			s := &script{
+				prog:    bodyProg,
+				file:    file,
+				line:    startLine + bodyLine - 1,
+				errexit: bodyErrexit,
+				vars:    vars,
+			}
+			s.errexit = s.errexit || s.setsErrexit()
+			scripts = append(scripts, s)
+		}
+		return true
+	})
+
+	outer.errexit = outer.errexit || outer.setsErrexit()
+	return scripts
+}
+
+// blankCommentLines blanks the comment lines within a RUN's line
+// continuations, which BuildKit drops before the shell sees the script.
+// Each one ends in a backslash instead, so the continuation carries on to
+// the next line while line and column numbers stay the same.
+func blankCommentLines(lines []string) {
+	for i := 1; i < len(lines); i++ {
+		if strings.HasPrefix(strings.TrimLeft(lines[i], " \t"), "#") {
+			lines[i] = strings.Repeat(" ", len(lines[i])-1) + "\\"
+		}
+	}
+}
+
+// heredocBody returns the body of a heredoc starting at the 1-based line of
+// the script lines, up to its closing delimiter.
+func heredocBody(lines []string, bodyLine int, r *syntax.Redirect) (string, bool) {
+	delim, ok := literalValue(r.Word)
+	if !ok || bodyLine < 1 {
+		return "", false
+	}
+	for i := bodyLine - 1; i < len(lines); i++ {
+		line := lines[i]
+		if r.Op == syntax.DashHdoc {
+			line = strings.TrimLeft(line, "\t")
+		}
+		if line == delim {
+			return strings.Join(lines[bodyLine-1:i], "\n"), true
+		}
+	}
+	return "", false
+}
+
+// heredocShell reports whether the heredoc body of the statement is run as a
+// shell script, and with which variant and errexit setting. That is the
+// case for a bare heredoc (RUN <<EOF), which BuildKit runs with the RUN
+// shell or the body's shebang interpreter, and for a heredoc fed to a shell
+// command without a script argument (RUN bash -e <<EOF).
+func heredocShell(stmt *syntax.Stmt, body string, variant shell.Variant, errexit bool) (shell.Variant, bool, bool) {
+	if stmt.Cmd == nil {
+		first, _, _ := strings.Cut(body, "\n")
+		interp, ok := strings.CutPrefix(first, "#!")
+		if !ok {
+			return variant, errexit, true
+		}
+		fields := strings.Fields(interp)
+		if len(fields) > 1 && shell.Basename(fields[0]) == "env" {
+			fields = fields[1:]
+		}
+		if len(fields) == 0 || !shell.IsShellName(fields[0]) {
+			return 0, false, false
+		}
+		return shell.VariantFromShell(fields[0]), hasErrexit(fields[1:]), true
+	}
+
+	call, ok := stmt.Cmd.(*syntax.CallExpr)
+	if !ok || len(call.Args) == 0 || !shell.IsShellName(call.Args[0].Lit()) {
+		return 0, false, false
+	}
+	args := make([]string, 0, len(call.Args)-1)
+	for _, w := range call.Args[1:] {
+		arg, ok := literalValue(w)
+		if !ok {
+			return 0, false, false
+		}
+		args = append(args, arg)
+	}
+	for i := 0; i < len(args); i++ {
+		switch arg := args[i]; {
+		case arg == "-o" || arg == "+o":
+			i++ // option name
+		case arg == "-s" || arg == "--":
+			// Read the script from stdin; the rest are its arguments.
+			i = len(args)
+		case strings.HasPrefix(arg, "-") || strings.HasPrefix(arg, "+"):
+			if !strings.HasPrefix(arg, "--") && strings.ContainsRune(arg, 'c') {
+				return 0, false, false
+			}
+		default:
+			// A script file argument.
+			return 0, false, false
+		}
+	}
+	return shell.VariantFromShell(call.Args[0].Lit()), hasErrexit(args), true
+}
+
+// hasErrexit reports whether shell options include -e (or -o errexit).
+func hasErrexit(args []string) bool {
+	for i, arg := range args {
+		if arg == "-o" && i+1 < len(args) && args[i+1] == "errexit" {
+			return true
+		}
+		if strings.HasPrefix(arg, "-") && !strings.HasPrefix(arg, "--") && strings.ContainsRune(arg, 'e') {
+			return true
+		}
+	}
+	return false
+}
+
+// setsErrexit reports whether the script runs set -e.
+func (s *script) setsErrexit() bool {
+	found := false
+	s.walk(func(node syntax.Node) bool {
+		call, ok := node.(*syntax.CallExpr)
+		if !ok || len(call.Args) < 2 || call.Args[0].Lit() != "set" {
+			return !found
+		}
+		args := make([]string, 0, len(call.Args)-1)
+		for _, w := range call.Args[1:] {
+			args = append(args, w.Lit())
+		}
+		found = found || hasErrexit(args)
+		return !found
+	})
+	return found
+}
+
+// walk traverses the script like syntax.Walk, skipping the heredoc bodies
+// that are checked as scripts of their own.
+func (s *script) walk(fn func(node syntax.Node) bool) {
+	syntax.Walk(s.prog, func(node syntax.Node) bool {
+		if w, ok := node.(*syntax.Word); ok && s.heredocs[w] {
+			return false
+		}
+		return fn(node)
+	})
+}
+
+// splitWords calls fn for each word that the shell splits into fields and
+// globs after expansion: the arguments of simple commands other than
+// declaration builtins, and redirection targets.
+func (s *script) splitWords(fn func(w *syntax.Word)) {
+	s.walk(func(node syntax.Node) bool {
+		switch n := node.(type) {
+		case *syntax.CallExpr:
+			if len(n.Args) > 1 && !isDeclCommand(n) {
+				for _, w := range n.Args[1:] {
+					fn(w)
+				}
+			}
+		case *syntax.Redirect:
+			if n.Word != nil && n.Op != syntax.Hdoc && n.Op != syntax.DashHdoc && n.Op != syntax.WordHdoc {
+				fn(n.Word)
+			}
+		}
+		return true
+	})
+}
+
+// quoteFix returns a fix wrapping the script range [from, to) in double quotes.
+func (s *script) quoteFix(from, to syntax.Pos) *rules.SuggestedFix {
+	return &rules.SuggestedFix{
+		Description: "Wrap in double quotes",
+		Safety:      rules.FixSuggestion,
+		Edits:       []rules.TextEdit{s.insert(from, `"`), s.insert(to, `"`)},
+	}
+}
+
+// position converts a script position to a 1-based Dockerfile line and a
+// 0-based column.
+func (s *script) position(pos syntax.Pos) (int, int) {
+	//nolint:gosec // G115: shell scripts won't have int-overflowing positions
+	return s.line + int(pos.Line()) - 1, int(pos.Col()) - 1
+}
+
+// location returns the Dockerfile location of the script range [from, to).
+func (s *script) location(from, to syntax.Pos) rules.Location {
+	startLine, startCol := s.position(from)
+	endLine, endCol := s.position(to)
+	return rules.NewRangeLocation(s.file, startLine, startCol, endLine, endCol)
+}
+
+// insert returns an edit inserting text at a script position.
+func (s *script) insert(pos syntax.Pos, text string) rules.TextEdit {
+	line, col := s.position(pos)
+	return rules.TextEdit{
+		Location: rules.NewRangeLocation(s.file, line, col, line, col),
+		NewText:  text,
+	}
+}
+
+// literalValue returns the value of a word without expansions, with its
+// quotes removed.
+func literalValue(w *syntax.Word) (string, bool) {
+	if w == nil {
+		return "", false
+	}
+	var b strings.Builder
+	for _, part := range w.Parts {
+		switch p := part.(type) {
+		case *syntax.Lit:
+			b.WriteString(strings.ReplaceAll(p.Value, `\`, ""))
+		case *syntax.SglQuoted:
+			b.WriteString(p.Value)
+		case *syntax.DblQuoted:
+			for _, inner := range p.Parts {
+				lit, ok := inner.(*syntax.Lit)
+				if !ok {
+					return "", false
+				}
+				b.WriteString(lit.Value)
+			}
+		default:
+			return "", false
+		}
+	}
+	return b.String(), true
+}
+
+// commandName returns the literal name of a simple command, or "".
+func commandName(call *syntax.CallExpr) string {
+	if len(call.Args) == 0 {
+		return ""
+	}
+	return call.Args[0].Lit()
+}
+
+// declCommands are the declaration builtins, whose assignment arguments
+// aren't split into words.
+var declCommands = []string{"declare", "export", "local", "readonly", "typeset"}
+
+// isDeclCommand reports whether the command is a declaration builtin.
+func isDeclCommand(call *syntax.CallExpr) bool {
+	return slices.Contains(declCommands, commandName(call))
+}
@@ -0,0 +1,200 @@
+package shellcheck
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/tinovyatkin/tally/internal/directive"
+	"github.com/tinovyatkin/tally/internal/fix"
+	"github.com/tinovyatkin/tally/internal/rules"
+	"github.com/tinovyatkin/tally/internal/semantic"
+	"github.com/tinovyatkin/tally/internal/sourcemap"
+	"github.com/tinovyatkin/tally/internal/testutil"
+)
+
+// scCase is a table-driven test case for the shellcheck rules.
+type scCase struct {
+	name       string
+	dockerfile string
+
+	// want are the expected violation positions as "line:column".
+	want []string
+}
+
+// makeInput creates a LintInput whose semantic model applies the shell
+// directives of the Dockerfile, like the linter does.
+func makeInput(t *testing.T, dockerfile string) rules.LintInput {
+	t.Helper()
+	input := testutil.MakeLintInputWithSemantic(t, "Dockerfile", dockerfile)
+	directives := directive.Parse(sourcemap.New([]byte(dockerfile)), nil)
+	input.Semantic = semantic.NewBuilder(testutil.ParseDockerfile(t, dockerfile), nil, "Dockerfile").
+		WithShellDirectives(directives.ShellDirectives).
+		Build()
+	return input
+}
+
+// runSCCases checks each case's violation positions.
+func runSCCases(t *testing.T, rule rules.Rule, cases []scCase) {
+	t.Helper()
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			input := makeInput(t, tc.dockerfile)
+			var got []string
+			for _, v := range rule.Check(input) {
+				got = append(got, fmt.Sprintf("%d:%d", v.Location.Start.Line, v.Location.Start.Column))
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("violations at %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+// applyFirstFix applies the suggested fix of the first violation.
+func applyFirstFix(t *testing.T, rule rules.Rule, dockerfile string) string {
+	t.Helper()
+	input := makeInput(t, dockerfile)
+	violations := rule.Check(input)
+	if len(violations) == 0 || violations[0].SuggestedFix == nil {
+		t.Fatalf("want a violation with a fix, got %v", violations)
+	}
+	f := &fix.Fixer{SafetyThreshold: rules.FixUnsafe}
+	result, err := f.Apply(t.Context(), violations[:1], map[string][]byte{"Dockerfile": []byte(dockerfile)})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	return string(result.Changes["Dockerfile"].ModifiedContent)
+}
+
+func TestScanScripts(t *testing.T) {
+	t.Parallel()
+	// SC2164 reports the position of each unchecked cd, which shows which
+	// scripts are scanned and where they map to.
+	runSCCases(t, NewSC2164Rule(), []scCase{
+		{
+			name:       "RUN flags and instruction are blanked",
+			dockerfile: "FROM alpine\nRUN --mount=type=cache,target=/root/.cache cd /app; make",
+			want:       []string{"2:43"},
+		},
+		{
+			name:       "continuation lines",
+			dockerfile: "FROM alpine\nRUN echo start; \\\n    cd /app; \\\n    make",
+			want:       []string{"3:4"},
+		},
+		{
+			name:       "comment lines in continuations",
+			dockerfile: "FROM alpine\nRUN echo start \\\n    # go to app\n    && cd /app; \\\n    make",
+			want:       []string{"4:7"},
+		},
+		{
+			name:       "bare heredoc body",
+			dockerfile: "FROM alpine\nRUN <<EOF\ncd /app\nmake\nEOF",
+			want:       []string{"3:0"},
+		},
+		{
+			name:       "heredoc fed to a shell",
+			dockerfile: "FROM alpine\nRUN bash <<'EOF'\necho start\n  cd /app\nmake\nEOF",
+			want:       []string{"4:2"},
+		},
+		{
+			name:       "heredoc with tab stripping",
+			dockerfile: "FROM alpine\nRUN <<-EOF\n\tcd /app\n\tmake\n\tEOF",
+			want:       []string{"3:1"},
+		},
+		{
+			name:       "several heredocs",
+			dockerfile: "FROM alpine\nRUN <<ONE && bash <<TWO\ncd /a\nmake\nONE\ncd /b\nmake\nTWO",
+			want:       []string{"3:0", "6:0"},
+		},
+		{
+			name:       "heredoc read as data",
+			dockerfile: "FROM alpine\nRUN cat <<EOF > /run.sh\ncd /app\nmake\nEOF",
+		},
+		{
+			name:       "heredoc passed to sh -c",
+			dockerfile: "FROM alpine\nRUN sh -c 'true' <<EOF\ncd /app\nmake\nEOF",
+		},
+		{
+			name:       "shebang of another interpreter",
+			dockerfile: "FROM alpine\nRUN <<EOF\n#!/usr/bin/env python3\ncd /app\nmake\nEOF",
+		},
+		{
+			name:       "shebang with -e",
+			dockerfile: "FROM alpine\nRUN <<EOF\n#!/bin/sh -e\ncd /app\nmake\nEOF",
+		},
+		{
+			name:       "ONBUILD RUN",
+			dockerfile: "FROM alpine\nONBUILD RUN cd /app; make",
+		},
+		{
+			name:       "exec form",
+			dockerfile: "FROM alpine\nRUN [\"sh\", \"-c\", \"cd /app; make\"]",
+		},
+		{
+			name:       "non-POSIX shell directive",
+			dockerfile: "# tally shell=powershell\nFROM mcr.microsoft.com/windows/servercore:ltsc2022\nRUN cd /app; make",
+		},
+		{
+			name:       "SHELL with -e",
+			dockerfile: "FROM alpine\nSHELL [\"/bin/sh\", \"-e\", \"-c\"]\nRUN cd /app; make",
+		},
+		{
+			name:       "SHELL with -o errexit",
+			dockerfile: "FROM alpine\nSHELL [\"/bin/bash\", \"-o\", \"errexit\", \"-c\"]\nRUN cd /app; make",
+		},
+		{
+			name:       "unparsable script",
+			dockerfile: "FROM alpine\nRUN cd /app; make (",
+		},
+	})
+}
+
+func TestScanScripts_Variant(t *testing.T) {
+	t.Parallel()
+	// Arrays only parse as Bash; the default /bin/sh is POSIX.
+	const script = "RUN a=(x); cd /app; make"
+	runSCCases(t, NewSC2164Rule(), []scCase{
+		{
+			name:       "default POSIX shell",
+			dockerfile: "FROM alpine\n" + script,
+		},
+		{
+			name:       "SHELL instruction",
+			dockerfile: "FROM alpine\nSHELL [\"/bin/bash\", \"-c\"]\n" + script,
+			want:       []string{"3:11"},
+		},
+		{
+			name:       "shell directive",
+			dockerfile: "# tally shell=bash\nFROM alpine\n" + script,
+			want:       []string{"3:11"},
+		},
+		{
+			name:       "hadolint shell directive",
+			dockerfile: "# hadolint shell=bash\nFROM alpine\n" + script,
+			want:       []string{"3:11"},
+		},
+	})
+}
+
+func TestHasErrexit(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{args: []string{"-c"}},
+		{args: []string{"-e", "-c"}, want: true},
+		{args: []string{"-eo", "pipefail", "-c"}, want: true},
+		{args: []string{"-o", "errexit", "-c"}, want: true},
+		{args: []string{"-o", "pipefail", "-c"}},
+		{args: []string{"--norc", "-c"}},
+	}
+	for _, tt := range tests {
+		if got := hasErrexit(tt.args); got != tt.want {
+			t.Errorf("hasErrexit(%q) = %v, want %v", strings.Join(tt.args, " "), got, tt.want)
+		}
+	}
+}
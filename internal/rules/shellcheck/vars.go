@@ -0,0 +1,213 @@
+package shellcheck
+
+import (
+	"strings"
+
+	"mvdan.cc/sh/v3/syntax"
+)
+
+// declaration is a variable set by a declaration builtin (export, local, ...).
+type declaration struct {
+	name string
+
+	// value is the assigned value, nil for a bare name ("export FOO").
+	value *syntax.Word
+
+	// pos and end delimit the argument declaring the variable.
+	pos, end syntax.Pos
+}
+
+// declarations returns the variables declared by a declaration builtin.
+// Bash parses these into a DeclClause; other dialects into a plain command
+// whose "name=value" arguments are split here.
+func declarations(node syntax.Node) []declaration {
+	var out []declaration
+	switch n := node.(type) {
+	case *syntax.DeclClause:
+		for _, a := range n.Args {
+			if a.Name == nil {
+				continue // an option such as -r
+			}
+			d := declaration{name: a.Name.Value, pos: a.Pos(), end: a.End()}
+			if !a.Naked {
+				d.value = a.Value
+			}
+			out = append(out, d)
+		}
+	case *syntax.CallExpr:
+		if !isDeclCommand(n) {
+			return nil
+		}
+		for _, w := range n.Args[1:] {
+			lit, ok := w.Parts[0].(*syntax.Lit)
+			if !ok {
+				continue
+			}
+			name, rest, found := strings.Cut(lit.Value, "=")
+			if !syntax.ValidName(name) || (!found && len(w.Parts) > 1) {
+				continue
+			}
+			d := declaration{name: name, pos: w.Pos(), end: w.End()}
+			if found {
+				d.value = &syntax.Word{Parts: w.Parts[1:]}
+				if rest != "" {
+					d.value.Parts = append([]syntax.WordPart{&syntax.Lit{Value: rest}}, w.Parts[1:]...)
+				}
+			}
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// assignments maps each variable the script assigns to the values assigned,
+// with a nil value where it can't be known (read, for loops, arithmetic).
+func (s *script) assignments() map[string][]*syntax.Word {
+	out := make(map[string][]*syntax.Word)
+	add := func(name string, value *syntax.Word) {
+		out[name] = append(out[name], value)
+	}
+
+	s.walk(func(node syntax.Node) bool {
+		switch n := node.(type) {
+		case *syntax.Assign:
+			// Prefix assignments and Bash declaration arguments.
+			if n.Name == nil {
+				break
+			}
+			if n.Naked || n.Append || n.Index != nil || n.Array != nil {
+				add(n.Name.Value, nil)
+			} else {
+				add(n.Name.Value, n.Value)
+			}
+		case *syntax.CallExpr:
+			for _, d := range declarations(n) {
+				add(d.name, d.value)
+			}
+			for _, name := range readTargets(n) {
+				add(name, nil)
+			}
+		case *syntax.ForClause:
+			if iter, ok := n.Loop.(*syntax.WordIter); ok {
+				add(iter.Name.Value, nil)
+			}
+		case *syntax.ParamExp:
+			if n.Param != nil && n.Exp != nil &&
+				(n.Exp.Op == syntax.AssignUnset || n.Exp.Op == syntax.AssignUnsetOrNull) {
+				add(n.Param.Value, n.Exp.Word)
+			}
+		case *syntax.BinaryArithm:
+			if isArithmAssign(n.Op) {
+				if w, ok := n.X.(*syntax.Word); ok && w.Lit() != "" {
+					add(w.Lit(), nil)
+				}
+			}
+		case *syntax.UnaryArithm:
+			if n.Op == syntax.Inc || n.Op == syntax.Dec {
+				if w, ok := n.X.(*syntax.Word); ok && w.Lit() != "" {
+					add(w.Lit(), nil)
+				}
+			}
+		}
+		return true
+	})
+	return out
+}
+
+// readTargets returns the variables a command reads input into: read,
+// mapfile/readarray, getopts and printf -v.
+func readTargets(call *syntax.CallExpr) []string {
+	args := make([]string, 0, len(call.Args))
+	for _, w := range call.Args {
+		args = append(args, w.Lit())
+	}
+	if len(args) == 0 {
+		return nil
+	}
+
+	var names []string
+	switch args[0] {
+	case "read":
+		for i := 1; i < len(args); i++ {
+			arg := args[i]
+			if !strings.HasPrefix(arg, "-") || len(arg) < 2 {
+				names = append(names, arg)
+				continue
+			}
+			// The last option letter may take the next argument.
+			switch arg[len(arg)-1] {
+			case 'a':
+				if i+1 < len(args) {
+					names = append(names, args[i+1])
+				}
+				i++
+			case 'd', 'i', 'n', 'N', 'p', 't', 'u':
+				i++
+			}
+		}
+	case "mapfile", "readarray":
+		if last := args[len(args)-1]; len(args) > 1 && !strings.HasPrefix(last, "-") {
+			names = append(names, last)
+		}
+	case "getopts":
+		if len(args) > 2 {
+			names = append(names, args[2])
+		}
+	case "printf":
+		if len(args) > 2 && args[1] == "-v" {
+			names = append(names, args[2])
+		}
+	}
+	return names
+}
+
+// isArithmAssign reports whether an arithmetic operator assigns its left
+// operand.
+func isArithmAssign(op syntax.BinAritOperator) bool {
+	switch op {
+	case syntax.Assgn, syntax.AddAssgn, syntax.SubAssgn, syntax.MulAssgn, syntax.QuoAssgn,
+		syntax.RemAssgn, syntax.AndAssgn, syntax.OrAssgn, syntax.XorAssgn, syntax.ShlAssgn, syntax.ShrAssgn:
+		return true
+	}
+	return false
+}
+
+// isSpecialParam reports whether a parameter is a special or positional
+// parameter ($?, $#, $@, $1, ...) rather than a variable.
+func isSpecialParam(name string) bool {
+	if name == "" {
+		return true
+	}
+	if strings.Trim(name, "0123456789") == "" {
+		return true
+	}
+	return len(name) == 1 && strings.Contains("@*#?-$!_", name)
+}
+
+// splitSafe reports whether expanding the variable yields a single word
+// that isn't globbed: every value the script assigns to it, or else the
+// value of the ENV or ARG setting it, is a literal without whitespace or
+// glob characters.
+func (s *script) splitSafe(assigned map[string][]*syntax.Word, name string) bool {
+	if values, ok := assigned[name]; ok {
+		for _, v := range values {
+			if v == nil {
+				return false
+			}
+			if lit, ok := literalValue(v); !ok || !safeLiteral(lit) {
+				return false
+			}
+		}
+		return true
+	}
+	if v := s.vars[name]; v != nil {
+		return !strings.Contains(*v, "$") && safeLiteral(*v)
+	}
+	return false
+}
+
+// safeLiteral reports whether a literal value survives word splitting and
+// globbing unchanged.
+func safeLiteral(v string) bool {
+	return !strings.ContainsAny(v, " \t\n*?[")
+}
@@ -37,6 +37,8 @@ func (r *ConsistentIndentationRule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityOff,
 		Category:        "style",
 		IsExperimental:  true,
+		Fixable:         true,
+		FixSafety:       rules.FixSafe,
 		FixPriority:     50, // After content fixes (casing at 0) but before structural (heredoc at 100+)
 	}
 }
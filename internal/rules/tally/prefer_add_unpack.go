@@ -49,6 +49,8 @@ func (r *PreferAddUnpackRule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityInfo,
 		Category:        "performance",
 		IsExperimental:  false,
+		Fixable:         true,
+		FixSafety:       rules.FixSuggestion,
 		FixPriority:     95,
 	}
 }
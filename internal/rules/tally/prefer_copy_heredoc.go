@@ -60,6 +60,8 @@ func (r *PreferCopyHeredocRule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityStyle,
 		Category:        "style",
 		IsExperimental:  true,
+		Fixable:         true,
+		FixSafety:       rules.FixSuggestion,
 		FixPriority:     99, // Run before prefer-run-heredoc (100)
 	}
 }
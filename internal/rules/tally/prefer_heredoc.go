@@ -58,6 +58,8 @@ func (r *PreferHeredocRule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityStyle,
 		Category:        "style",
 		IsExperimental:  true,
+		Fixable:         true,
+		FixSafety:       rules.FixSuggestion,
 		FixPriority:     100, // Structural transform: run after content fixes
 	}
 }
@@ -37,6 +37,8 @@ func (r *PreferMultiStageBuildRule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityInfo,
 		Category:        "performance",
 		IsExperimental:  true,
+		Fixable:         true,
+		FixSafety:       rules.FixUnsafe,
 		FixPriority:     150, // Whole-file rewrite should run after other structural transforms.
 	}
 }
@@ -14,6 +14,11 @@ import (
 var (
 	gitleaksOnce     sync.Once
 	gitleaksDetector *detect.Detector
+
+	// gitleaksMu serializes scans: the detector is shared process-wide and
+	// gitleaks does not document Detector as safe for concurrent use, while
+	// the CLI and LSP lint several files at once.
+	gitleaksMu sync.Mutex
 )
 
 // SecretsInCodeRule implements secret detection in Dockerfile content.
@@ -191,7 +196,9 @@ func (r *SecretsInCodeRule) scanContent(
 		return nil
 	}
 
+	gitleaksMu.Lock()
 	findings := gitleaksDetector.DetectString(content)
+	gitleaksMu.Unlock()
 	if len(findings) == 0 {
 		return nil
 	}
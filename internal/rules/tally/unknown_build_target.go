@@ -0,0 +1,62 @@
+package tally
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/moby/buildkit/frontend/dockerfile/instructions"
+
+	"github.com/tinovyatkin/tally/internal/rules"
+)
+
+// UnknownBuildTargetRule implements the unknown-build-target linting rule.
+// It reports a configured build target that is not a stage of the
+// Dockerfile, in which case the default build (last stage) is linted.
+type UnknownBuildTargetRule struct{}
+
+// NewUnknownBuildTargetRule creates a new unknown-build-target rule instance.
+func NewUnknownBuildTargetRule() *UnknownBuildTargetRule {
+	return &UnknownBuildTargetRule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *UnknownBuildTargetRule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            rules.TallyRulePrefix + "unknown-build-target",
+		Name:            "Unknown Build Target",
+		Description:     "Reports a configured build target that is not a stage of the Dockerfile",
+		DocURL:          "https://github.com/tinovyatkin/tally/blob/main/docs/rules/tally/unknown-build-target.md",
+		DefaultSeverity: rules.SeverityWarning,
+		Category:        "correctness",
+		IsExperimental:  false,
+	}
+}
+
+// Check runs the unknown-build-target rule.
+// Stage names are matched case-insensitively, like BuildKit does.
+func (r *UnknownBuildTargetRule) Check(input rules.LintInput) []rules.Violation {
+	target := input.BuildTarget
+	if target == "" || slices.ContainsFunc(input.Stages, func(s instructions.Stage) bool {
+		return s.Name != "" && strings.EqualFold(s.Name, target)
+	}) {
+		return nil
+	}
+
+	meta := r.Metadata()
+	return []rules.Violation{
+		rules.NewViolation(
+			rules.NewFileLocation(input.File),
+			meta.Code,
+			fmt.Sprintf("target stage %q could not be found; linting the default build (last stage)", target),
+			meta.DefaultSeverity,
+		).WithDocURL(meta.DocURL).
+			WithDetail("The [build] target does not name a stage of this Dockerfile. Rules that depend on the " +
+				"build target, such as tally/no-unreachable-stages, see the build of the last stage instead."),
+	}
+}
+
+// init registers the rule with the default registry.
+func init() {
+	rules.Register(NewUnknownBuildTargetRule())
+}
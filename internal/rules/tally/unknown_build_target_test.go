@@ -0,0 +1,41 @@
+package tally
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/tinovyatkin/tally/internal/testutil"
+)
+
+func TestUnknownBuildTargetRule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewUnknownBuildTargetRule().Metadata())
+}
+
+func TestUnknownBuildTargetRule_Check(t *testing.T) {
+	t.Parallel()
+	content := "FROM alpine:3.20 AS Build\nFROM alpine:3.20\n"
+	tests := []struct {
+		name   string
+		target string
+		want   int
+	}{
+		{name: "default build", target: "", want: 0},
+		{name: "existing stage, any case", target: "build", want: 0},
+		{name: "missing stage", target: "release", want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			input := testutil.MakeLintInputWithSemantic(t, "Dockerfile", content)
+			input.BuildTarget = tt.target
+			violations := NewUnknownBuildTargetRule().Check(input)
+			if len(violations) != tt.want {
+				t.Fatalf("got %d violations, want %d: %v", len(violations), tt.want, violations)
+			}
+			if tt.want > 0 && !violations[0].Location.IsFileLevel() {
+				t.Errorf("violation = %+v, want a file-level location", violations[0])
+			}
+		})
+	}
+}
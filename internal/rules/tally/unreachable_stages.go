@@ -60,6 +60,13 @@ func (r *UnreachableStagesRule) Check(input rules.LintInput) []rules.Violation {
 		return nil
 	}
 
+	// Describe what the stages are unreachable from: the final stage by default,
+	// or the requested build target.
+	from := "the final stage and does not contribute to the final image"
+	if target := sem.Target(); target != "" {
+		from = fmt.Sprintf("the build target %q and does not contribute to the built image", target)
+	}
+
 	violations := make([]rules.Violation, 0, len(unreachable))
 
 	for _, stageIdx := range unreachable {
@@ -78,7 +85,7 @@ func (r *UnreachableStagesRule) Check(input rules.LintInput) []rules.Violation {
 			stageName = fmt.Sprintf("stage %d", stageIdx)
 		}
 
-		message := stageName + " is not reachable from the final stage and does not contribute to the final image"
+		message := stageName + " is not reachable from " + from
 
 		// Get location from the FROM instruction
 		var loc rules.Location
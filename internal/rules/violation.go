@@ -1,6 +1,10 @@
 package rules
 
-import "github.com/moby/buildkit/frontend/dockerfile/parser"
+import (
+	"slices"
+
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+)
 
 // FixSafety categorizes how reliable a fix is.
 type FixSafety int
@@ -119,6 +123,11 @@ type Violation struct {
 	// Supports "auto-fix suggestion" without auto-applying.
 	SuggestedFix *SuggestedFix `json:"suggestedFix,omitempty"`
 
+	// AlternativeFixes lists other ways to fix the violation (optional).
+	// The fixer applies the preferred fix allowed by its safety threshold;
+	// editors offer every fix as a separate code action.
+	AlternativeFixes []*SuggestedFix `json:"alternativeFixes,omitempty"`
+
 	// StageIndex tracks which Dockerfile stage this violation belongs to.
 	// Used internally for merging async results; not serialized.
 	StageIndex int `json:"-"`
@@ -143,6 +152,9 @@ const TallyRulePrefix = "tally/"
 // HadolintRulePrefix is the namespace prefix for Hadolint-compatible rules.
 const HadolintRulePrefix = "hadolint/"
 
+// ShellcheckRulePrefix is the namespace prefix for ShellCheck-equivalent rules.
+const ShellcheckRulePrefix = "shellcheck/"
+
 // NewViolationFromBuildKitWarning converts BuildKit linter callback parameters
 // to our Violation type. This bridges BuildKit's linter.LintWarnFunc with our
 // output schema.
@@ -199,6 +211,21 @@ func (v Violation) WithSuggestedFix(fix *SuggestedFix) Violation {
 	return v
 }
 
+// WithAlternativeFix adds another fix suggestion to the violation.
+func (v Violation) WithAlternativeFix(fix *SuggestedFix) Violation {
+	v.AlternativeFixes = append(slices.Clip(v.AlternativeFixes), fix)
+	return v
+}
+
+// Fixes returns the suggested fix followed by the alternative fixes.
+func (v Violation) Fixes() []*SuggestedFix {
+	var fixes []*SuggestedFix
+	if v.SuggestedFix != nil {
+		fixes = append(fixes, v.SuggestedFix)
+	}
+	return append(fixes, v.AlternativeFixes...)
+}
+
 // File returns the file path from the location.
 func (v Violation) File() string {
 	return v.Location.File
@@ -22,6 +22,7 @@ type Builder struct {
 	parseResult     *dockerfile.ParseResult
 	buildArgs       map[string]string
 	file            string
+	target          string
 	shellDirectives []directive.ShellDirective
 
 	// Accumulated during build
@@ -47,6 +48,13 @@ func (b *Builder) WithShellDirectives(directives []directive.ShellDirective) *Bu
 	return b
 }
 
+// WithTarget sets the build target stage (docker build --target).
+// An empty target selects the last stage, matching BuildKit.
+func (b *Builder) WithTarget(target string) *Builder {
+	b.target = target
+	return b
+}
+
 // Build constructs the semantic model.
 // This performs single-pass analysis of the Dockerfile, detecting
 // construction-time violations (e.g., instruction order issues).
@@ -55,6 +63,7 @@ func (b *Builder) Build() *Model {
 		return &Model{
 			stagesByName: make(map[string]int),
 			graph:        newStageGraph(0),
+			targetIndex:  -1,
 		}
 	}
 
@@ -72,6 +81,10 @@ func (b *Builder) Build() *Model {
 	stageCount := len(stages)
 	stageInfo := make([]*StageInfo, stageCount)
 	graph := newStageGraph(stageCount)
+	targetIndex := targetStageIndex(stages, b.target)
+	if targetIndex >= 0 {
+		graph.target = targetIndex
+	}
 
 	for i := range stages {
 		stage := &stages[i]
@@ -85,7 +98,7 @@ func (b *Builder) Build() *Model {
 		b.processStageNaming(stage, i)
 
 		// Process base image
-		info.BaseImage = b.processBaseImage(stage, i, graph)
+		info.BaseImage = b.processBaseImage(stage, i, graph, fromEval)
 
 		// FROM ARG analysis (UndefinedArgInFrom, InvalidDefaultArgInFrom).
 		b.applyFromArgAnalysis(info, stage, fromEval)
@@ -123,6 +136,8 @@ func (b *Builder) Build() *Model {
 		stageInfo:    stageInfo,
 		graph:        graph,
 		buildArgs:    b.buildArgs,
+		target:       b.target,
+		targetIndex:  targetIndex,
 		file:         b.file,
 		issues:       b.issues,
 	}
@@ -150,7 +165,7 @@ func (b *Builder) initFromArgEval(stages []instructions.Stage, metaArgs []instru
 	// Match BuildKit behavior by seeding:
 	// - defaultsEnv with the automatic args without --build-arg overrides
 	// - effectiveEnv and the semantic global scope with override-aware values
-	targetStage := targetStageName(stages)
+	targetStage := targetStageName(stages, b.target)
 	autoArgsNoOverrides := defaultFromArgs(targetStage, nil)
 	autoArgsWithOverrides := defaultFromArgs(targetStage, b.buildArgs)
 	b.addAutoArgsToGlobalScope(autoArgsWithOverrides)
@@ -175,7 +190,12 @@ func (b *Builder) initFromArgEval(stages []instructions.Stage, metaArgs []instru
 	}
 }
 
-func targetStageName(stages []instructions.Stage) string {
+// targetStageName returns the TARGETSTAGE value BuildKit exposes to FROM:
+// the requested target, else the last stage's name, else "default".
+func targetStageName(stages []instructions.Stage, target string) string {
+	if target != "" {
+		return target
+	}
 	targetStage := defaultTargetStageName
 	if len(stages) > 0 && stages[len(stages)-1].Name != "" {
 		targetStage = stages[len(stages)-1].Name
@@ -183,6 +203,22 @@ func targetStageName(stages []instructions.Stage) string {
 	return targetStage
 }
 
+// targetStageIndex resolves the build target to a stage index.
+// An empty target selects the last stage. Returns -1 if the named
+// target does not exist (or there are no stages).
+func targetStageIndex(stages []instructions.Stage, target string) int {
+	if target == "" {
+		return len(stages) - 1
+	}
+	normalized := normalizeStageRef(target)
+	for i := range stages {
+		if stages[i].Name != "" && normalizeStageRef(stages[i].Name) == normalized {
+			return i
+		}
+	}
+	return -1
+}
+
 func (b *Builder) addAutoArgsToGlobalScope(autoArgs map[string]string) {
 	// Add automatic args to the global scope in deterministic order.
 	autoKeys := make([]string, 0, len(autoArgs))
@@ -342,15 +378,25 @@ func (b *Builder) processStageNaming(stage *instructions.Stage, index int) {
 }
 
 // processBaseImage analyzes the FROM instruction's base image.
-func (b *Builder) processBaseImage(stage *instructions.Stage, stageIndex int, graph *StageGraph) *BaseImageRef {
+// The base name is expanded with global ARGs (including build args) first,
+// so that e.g. FROM ${BASE} resolves to the stage or image BuildKit would use.
+func (b *Builder) processBaseImage(stage *instructions.Stage, stageIndex int, graph *StageGraph, eval fromArgEval) *BaseImageRef {
 	ref := &BaseImageRef{
 		Raw:      stage.BaseName,
+		Resolved: stage.BaseName,
 		Platform: stage.Platform,
 		Location: stage.Location,
 	}
+	if eval.effectiveOK && strings.Contains(stage.BaseName, "$") {
+		res, err := eval.shlex.ProcessWordWithMatches(stage.BaseName, eval.effectiveEnv)
+		if err == nil {
+			ref.Resolved = res.Result
+			ref.Unresolved = len(res.Unmatched) > 0
+		}
+	}
 
 	// Check if base name references another stage
-	normalized := normalizeStageRef(stage.BaseName)
+	normalized := normalizeStageRef(ref.Resolved)
 	if idx, found := b.stagesByName[normalized]; found {
 		ref.IsStageRef = true
 		ref.StageIndex = idx
@@ -410,10 +456,22 @@ func (b *Builder) processStageCommands(stage *instructions.Stage, info *StageInf
 		switch c := cmd.(type) {
 		case *instructions.ArgCommand:
 			info.Variables.AddArgCommand(c)
+			for _, kv := range c.Args {
+				if v, ok := env.Get(kv.Key); ok {
+					info.Variables.setArgExpanded(kv.Key, v)
+				}
+			}
+			info.recordVariables(c)
 
 		case *instructions.EnvCommand:
 			applyEnvCommandToEnv(c, shlex, env)
 			info.Variables.AddEnvCommand(c)
+			for _, kv := range c.Env {
+				if v, ok := env.Get(kv.Key); ok {
+					info.Variables.setEnvExpanded(kv.Key, v)
+				}
+			}
+			info.recordVariables(c)
 
 		case *instructions.CmdCommand:
 			lastCmdLoc = b.checkDuplicateInstruction(lastCmdLoc, c)
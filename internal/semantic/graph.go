@@ -15,6 +15,10 @@ type StageGraph struct {
 
 	// stageCount is the total number of stages.
 	stageCount int
+
+	// target is the index of the stage being built (the last stage unless
+	// a --target was requested).
+	target int
 }
 
 // DependsOn returns true if stageA depends on stageB (directly or transitively).
@@ -83,18 +87,18 @@ func (g *StageGraph) IsReachable(stageIndex, finalStageIndex int) bool {
 	return false
 }
 
-// UnreachableStages returns indices of stages that are not reachable from the final stage.
-// These are stages that don't contribute to the final image.
+// UnreachableStages returns indices of stages that are not reachable from the
+// target stage (the final stage unless a build target was requested).
+// These are stages that don't contribute to the built image.
 func (g *StageGraph) UnreachableStages() []int {
 	if g.stageCount == 0 {
 		return nil
 	}
 
-	finalStage := g.stageCount - 1
 	var unreachable []int
 
 	for i := range g.stageCount {
-		if !g.IsReachable(i, finalStage) {
+		if !g.IsReachable(i, g.target) {
 			unreachable = append(unreachable, i)
 		}
 	}
@@ -118,6 +122,11 @@ func (g *StageGraph) ExternalRefs(stageIndex int) []string {
 	return g.externalRefs[stageIndex]
 }
 
+// TargetStage returns the index of the stage being built.
+func (g *StageGraph) TargetStage() int {
+	return g.target
+}
+
 // StageCount returns the total number of stages.
 func (g *StageGraph) StageCount() int {
 	return g.stageCount
@@ -130,6 +139,7 @@ func newStageGraph(stageCount int) *StageGraph {
 		reverseEdges: make(map[int][]int),
 		externalRefs: make(map[int][]string),
 		stageCount:   stageCount,
+		target:       stageCount - 1,
 	}
 }
 
@@ -42,7 +42,7 @@ func resolvePlatformExpr(expr string, model *Model) (string, []string) {
 	}
 
 	// Build an environment from meta ARGs + build args + automatic platform args.
-	env := newFromEnv(defaultFromArgs(targetStageName(model.stages), model.buildArgs))
+	env := newFromEnv(defaultFromArgs(targetStageName(model.stages, model.target), model.buildArgs))
 
 	// Add meta ARGs.
 	for _, ma := range model.metaArgs {
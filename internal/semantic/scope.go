@@ -1,6 +1,9 @@
 package semantic
 
 import (
+	"maps"
+	"slices"
+
 	"github.com/moby/buildkit/frontend/dockerfile/instructions"
 	"github.com/moby/buildkit/frontend/dockerfile/parser"
 )
@@ -11,6 +14,9 @@ type ArgEntry struct {
 	Name string
 	// Value is the default value (nil means no default).
 	Value *string
+	// Expanded is Value with its variable references expanded as at the
+	// declaration, or nil if the builder did not evaluate it.
+	Expanded *string
 	// Location is where the ARG was declared.
 	Location []parser.Range
 }
@@ -21,6 +27,9 @@ type EnvEntry struct {
 	Name string
 	// Value is the environment variable value.
 	Value string
+	// Expanded is Value with its variable references expanded as at the
+	// declaration, or nil if the builder did not evaluate it.
+	Expanded *string
 	// Location is where the ENV was declared.
 	Location []parser.Range
 }
@@ -65,12 +74,15 @@ func NewStageScope(parent *VariableScope) *VariableScope {
 func (s *VariableScope) AddArg(name string, value *string, location []parser.Range) {
 	// Docker/BuildKit semantics: redeclaring ARG without a default does not
 	// clear a previously set default/build-arg value. It only (re)declares
-	// the name and updates its location.
+	// the name and updates its location. The entry is replaced rather than
+	// modified, as clones of the scope may share it.
 	if existing, exists := s.args[name]; exists {
-		existing.Location = location
+		entry := *existing
+		entry.Location = location
 		if value != nil {
-			existing.Value = value
+			entry.Value, entry.Expanded = value, nil
 		}
+		s.args[name] = &entry
 		return
 	}
 
@@ -110,6 +122,82 @@ func (s *VariableScope) AddEnvCommand(cmd *instructions.EnvCommand) {
 	}
 }
 
+// setArgExpanded records the expanded default of the latest ARG
+// declaration of name, just after it was added.
+func (s *VariableScope) setArgExpanded(name, value string) {
+	if entry, ok := s.args[name]; ok && entry.Value != nil {
+		entry.Expanded = &value
+	}
+}
+
+// setEnvExpanded records the expanded value of the latest ENV declaration
+// of name, just after it was added.
+func (s *VariableScope) setEnvExpanded(name, value string) {
+	if entry, ok := s.envs[name]; ok {
+		entry.Expanded = &value
+	}
+}
+
+// clone returns a copy of the scope that later declarations in s do not
+// affect. Entries are shared: declarations replace them instead of
+// modifying them.
+func (s *VariableScope) clone() *VariableScope {
+	return &VariableScope{
+		parent:   s.parent,
+		args:     maps.Clone(s.args),
+		envs:     maps.Clone(s.envs),
+		argOrder: slices.Clone(s.argOrder),
+		envOrder: slices.Clone(s.envOrder),
+	}
+}
+
+// VariableSource tells which declaration provides a variable's value.
+type VariableSource int
+
+const (
+	// VariableSourceEnv is an ENV instruction of the stage.
+	VariableSourceEnv VariableSource = iota
+	// VariableSourceArg is a stage ARG instruction with a default value.
+	VariableSourceArg
+	// VariableSourceGlobalArg is a global ARG: either resolved in the global
+	// scope, or providing the default of a stage ARG declared without one.
+	VariableSourceGlobalArg
+	// VariableSourceBuildArg is a build arg overriding a declared ARG.
+	VariableSourceBuildArg
+)
+
+// String returns the instruction kind, e.g. "global ARG".
+func (v VariableSource) String() string {
+	switch v {
+	case VariableSourceEnv:
+		return "ENV"
+	case VariableSourceArg:
+		return "ARG"
+	case VariableSourceGlobalArg:
+		return "global ARG"
+	case VariableSourceBuildArg:
+		return "build arg"
+	default:
+		return "unknown"
+	}
+}
+
+// VariableDefinition is a resolved variable and where its value comes from.
+type VariableDefinition struct {
+	// Name is the variable name.
+	Name string
+	// Value is the resolved value.
+	Value string
+	// Expanded is Value with its variable references expanded as at the
+	// declaration providing it. It equals Value when no expansion applies.
+	Expanded string
+	// Source is the kind of declaration providing Value.
+	Source VariableSource
+	// Location is the instruction providing Value. For build args it is the
+	// ARG instruction declaring the variable.
+	Location []parser.Range
+}
+
 // Resolve looks up a variable by name using Docker's precedence rules.
 // Precedence (highest first):
 //  1. Stage ENV (environment variables always take precedence)
@@ -122,9 +210,19 @@ func (s *VariableScope) AddEnvCommand(cmd *instructions.EnvCommand) {
 //
 // Returns the value and true if found, or empty string and false if not.
 func (s *VariableScope) Resolve(name string, buildArgs map[string]string) (string, bool) {
+	def, ok := s.Lookup(name, buildArgs)
+	return def.Value, ok
+}
+
+// Lookup resolves a variable like Resolve and also reports which declaration
+// provides its value.
+func (s *VariableScope) Lookup(name string, buildArgs map[string]string) (VariableDefinition, bool) {
 	// 1. Check stage ENV first (highest priority in Docker)
 	if env, found := s.envs[name]; found {
-		return env.Value, true
+		return VariableDefinition{
+			Name: name, Value: env.Value, Expanded: expandedOr(env.Expanded, env.Value),
+			Source: VariableSourceEnv, Location: env.Location,
+		}, true
 	}
 
 	// 2. Check stage ARG (with build-arg override support)
@@ -132,31 +230,43 @@ func (s *VariableScope) Resolve(name string, buildArgs map[string]string) (strin
 		// Build arg override applies to declared ARGs
 		if buildArgs != nil {
 			if val, found := buildArgs[name]; found {
-				return val, true
+				return VariableDefinition{
+					Name: name, Value: val, Expanded: val, Source: VariableSourceBuildArg, Location: arg.Location,
+				}, true
 			}
 		}
 		if arg.Value != nil {
-			return *arg.Value, true
+			return VariableDefinition{
+				Name: name, Value: *arg.Value, Expanded: expandedOr(arg.Expanded, *arg.Value),
+				Source: VariableSourceArg, Location: arg.Location,
+			}, true
 		}
 		// ARG declared but no default - check parent for inherited default
 		// This handles: ARG VERSION (in stage) inheriting from ARG VERSION=1.0 (global)
 		if s.parent != nil {
 			if parentArg := s.parent.GetArg(name); parentArg != nil && parentArg.Value != nil {
-				return *parentArg.Value, true
+				return VariableDefinition{
+					Name: name, Value: *parentArg.Value, Expanded: expandedOr(parentArg.Expanded, *parentArg.Value),
+					Source: VariableSourceGlobalArg, Location: parentArg.Location,
+				}, true
 			}
 		}
 		// ARG declared but no default anywhere - not set
-		return "", false
+		return VariableDefinition{}, false
 	}
 
 	// 3. For stage scopes (has parent), do NOT fall through to parent
 	// Global ARGs are only visible in stages that explicitly declare them
-	if s.parent != nil {
-		return "", false
-	}
-
 	// 4. For global scope (no parent), we're done
-	return "", false
+	return VariableDefinition{}, false
+}
+
+// expandedOr returns *expanded, or value if expanded is nil.
+func expandedOr(expanded *string, value string) string {
+	if expanded != nil {
+		return *expanded
+	}
+	return value
 }
 
 // HasArg returns true if the variable is declared as an ARG anywhere in the
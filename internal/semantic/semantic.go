@@ -40,6 +40,13 @@ type Model struct {
 	// buildArgs are CLI --build-arg values.
 	buildArgs map[string]string
 
+	// target is the requested build target (--target); empty means the last stage.
+	target string
+
+	// targetIndex is the index of the target stage, or -1 if target names
+	// a stage that does not exist.
+	targetIndex int
+
 	// file is the path to the Dockerfile (for violation locations).
 	file string
 
@@ -116,6 +123,91 @@ func (m *Model) ResolveVariable(stageIndex int, name string) (string, bool) {
 	return info.Variables.Resolve(name, m.buildArgs)
 }
 
+// LookupVariable resolves a variable like ResolveVariable and reports which
+// declaration provides its value. A negative stageIndex resolves in the
+// global scope, as for the FROM instructions and global ARG defaults.
+func (m *Model) LookupVariable(stageIndex int, name string) (VariableDefinition, bool) {
+	scope := m.Scope(stageIndex)
+	if scope == nil {
+		return VariableDefinition{}, false
+	}
+	def, ok := scope.Lookup(name, m.buildArgs)
+	if ok && stageIndex < 0 && def.Source == VariableSourceArg {
+		def.Source = VariableSourceGlobalArg
+	}
+	return def, ok
+}
+
+// LookupVariableAt is LookupVariable for a reference at a 1-based line:
+// in a stage, only the ARG and ENV instructions before the line are
+// visible (see StageInfo.VariablesAt).
+func (m *Model) LookupVariableAt(stageIndex, line int, name string) (VariableDefinition, bool) {
+	if stageIndex < 0 {
+		return m.LookupVariable(stageIndex, name)
+	}
+	scope := m.ScopeAt(stageIndex, line)
+	if scope == nil {
+		return VariableDefinition{}, false
+	}
+	return scope.Lookup(name, m.buildArgs)
+}
+
+// ScopeAt returns the variable scope visible at a 1-based line of a stage,
+// or the global scope for a negative stageIndex. Returns nil if the stage
+// doesn't exist.
+func (m *Model) ScopeAt(stageIndex, line int) *VariableScope {
+	if stageIndex < 0 {
+		return m.globalScope()
+	}
+	info := m.StageInfo(stageIndex)
+	if info == nil {
+		return nil
+	}
+	return info.VariablesAt(line)
+}
+
+// Scope returns the variable scope of a stage, or the global scope (the ARGs
+// before the first FROM) for a negative stageIndex. Returns nil if the stage
+// doesn't exist.
+func (m *Model) Scope(stageIndex int) *VariableScope {
+	if stageIndex < 0 {
+		return m.globalScope()
+	}
+	info := m.StageInfo(stageIndex)
+	if info == nil {
+		return nil
+	}
+	return info.Variables
+}
+
+// BuildArgs returns the build arg values the model was built with.
+// The returned map must not be modified.
+func (m *Model) BuildArgs() map[string]string {
+	return m.buildArgs
+}
+
+// Target returns the requested build target stage name.
+// Empty means no explicit target was requested (the last stage is built).
+func (m *Model) Target() string {
+	return m.target
+}
+
+// TargetStageIndex returns the index of the stage being built: the stage
+// named by the requested target, or the last stage when no target is set.
+// Returns -1 if the requested target does not exist.
+func (m *Model) TargetStageIndex() int {
+	return m.targetIndex
+}
+
+// IsStageInBuild reports whether the stage at stageIndex is part of the
+// build for the selected target (the target itself or one of its dependencies).
+func (m *Model) IsStageInBuild(stageIndex int) bool {
+	if m.targetIndex < 0 || m.graph == nil {
+		return false
+	}
+	return m.graph.IsReachable(stageIndex, m.targetIndex)
+}
+
 // Graph returns the stage dependency graph.
 func (m *Model) Graph() *StageGraph {
 	return m.graph
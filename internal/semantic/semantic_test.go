@@ -357,6 +357,138 @@ RUN echo $MYVAR
 	}
 }
 
+func TestLookupVariable(t *testing.T) {
+	t.Parallel()
+	content := `ARG BASE=alpine:3.18
+ARG VERSION=1.0
+ARG TAG
+FROM $BASE
+ARG VERSION
+ARG REVISION=abc
+ARG TAG
+ENV HOME=/app
+`
+	pr := parseDockerfile(t, content)
+	model := NewModel(pr, map[string]string{"REVISION": "def"}, "Dockerfile")
+
+	tests := []struct {
+		stage     int
+		name      string
+		want      string
+		source    VariableSource
+		line      int
+		wantFound bool
+	}{
+		{stage: 0, name: "HOME", want: "/app", source: VariableSourceEnv, line: 8, wantFound: true},
+		{stage: 0, name: "VERSION", want: "1.0", source: VariableSourceGlobalArg, line: 2, wantFound: true},
+		{stage: 0, name: "REVISION", want: "def", source: VariableSourceBuildArg, line: 6, wantFound: true},
+		{stage: 0, name: "BASE"},
+		{stage: 0, name: "TAG"},
+		{stage: -1, name: "BASE", want: "alpine:3.18", source: VariableSourceGlobalArg, line: 1, wantFound: true},
+		{stage: -1, name: "HOME"},
+	}
+	for _, tt := range tests {
+		def, found := model.LookupVariable(tt.stage, tt.name)
+		if found != tt.wantFound {
+			t.Errorf("LookupVariable(%d, %s) found = %v, want %v", tt.stage, tt.name, found, tt.wantFound)
+			continue
+		}
+		if !found {
+			continue
+		}
+		if def.Value != tt.want || def.Source != tt.source || def.Location[0].Start.Line != tt.line {
+			t.Errorf("LookupVariable(%d, %s) = %q from %s at line %d, want %q from %s at line %d",
+				tt.stage, tt.name, def.Value, def.Source, def.Location[0].Start.Line, tt.want, tt.source, tt.line)
+		}
+		if val, ok := model.ResolveVariable(max(tt.stage, 0), tt.name); tt.stage >= 0 && (!ok || val != def.Value) {
+			t.Errorf("ResolveVariable(%d, %s) = %q, want %q", tt.stage, tt.name, val, def.Value)
+		}
+	}
+}
+
+func TestLookupVariableAt(t *testing.T) {
+	t.Parallel()
+	content := `ARG B=global
+FROM alpine
+ENV V=one
+RUN echo $V $B
+ENV V=two \
+    W=$V
+ARG B=late
+RUN echo $V $B
+`
+	pr := parseDockerfile(t, content)
+	model := NewModel(pr, nil, "Dockerfile")
+
+	tests := []struct {
+		line      int
+		name      string
+		want      string
+		defLine   int
+		wantFound bool
+	}{
+		{line: 3, name: "V"},
+		{line: 4, name: "V", want: "one", defLine: 3, wantFound: true},
+		{line: 4, name: "B"},
+		{line: 6, name: "V", want: "one", defLine: 3, wantFound: true},
+		{line: 8, name: "V", want: "two", defLine: 5, wantFound: true},
+		{line: 8, name: "B", want: "late", defLine: 7, wantFound: true},
+	}
+	for _, tt := range tests {
+		def, found := model.LookupVariableAt(0, tt.line, tt.name)
+		if found != tt.wantFound {
+			t.Errorf("LookupVariableAt(0, %d, %s) found = %v, want %v", tt.line, tt.name, found, tt.wantFound)
+			continue
+		}
+		if found && (def.Value != tt.want || def.Location[0].Start.Line != tt.defLine) {
+			t.Errorf("LookupVariableAt(0, %d, %s) = %q at line %d, want %q at line %d",
+				tt.line, tt.name, def.Value, def.Location[0].Start.Line, tt.want, tt.defLine)
+		}
+	}
+
+	// Values are expanded as at their declaration.
+	if def, _ := model.LookupVariableAt(0, 8, "W"); def.Value != "$V" || def.Expanded != "one" {
+		t.Errorf("LookupVariableAt(0, 8, W) = %q expanded to %q, want $V expanded to one", def.Value, def.Expanded)
+	}
+
+	// The stage scope still sees every declaration.
+	if val, _ := model.ResolveVariable(0, "V"); val != "two" {
+		t.Errorf("ResolveVariable(0, V) = %q, want two", val)
+	}
+}
+
+func TestScope(t *testing.T) {
+	t.Parallel()
+	content := `ARG BASE=alpine:3.18
+ARG VERSION=1.0
+FROM $BASE
+ARG VERSION
+ENV HOME=/app
+`
+	pr := parseDockerfile(t, content)
+	model := NewModel(pr, nil, "Dockerfile")
+
+	global := model.Scope(-1)
+	if global == nil || global.Parent() != nil {
+		t.Fatalf("Scope(-1) = %v, want the global scope", global)
+	}
+	if args := global.Args(); len(args) != 2 || args[0].Name != "BASE" || args[1].Name != "VERSION" {
+		t.Errorf("global Args() = %v, want BASE, VERSION", args)
+	}
+
+	stage := model.Scope(0)
+	if stage == nil || stage.Parent() == nil {
+		t.Fatalf("Scope(0) = %v, want a stage scope", stage)
+	}
+	if args, envs := stage.Args(), stage.Envs(); len(args) != 1 || args[0].Name != "VERSION" || len(envs) != 1 || envs[0].Name != "HOME" {
+		t.Errorf("stage Args() = %v, Envs() = %v, want VERSION and HOME", args, envs)
+	}
+
+	if scope := model.Scope(1); scope != nil {
+		t.Errorf("Scope(1) = %v, want nil", scope)
+	}
+}
+
 func TestCopyFromNamedStage(t *testing.T) {
 	t.Parallel()
 	content := `FROM golang:1.21 AS builder
@@ -624,6 +756,93 @@ RUN echo "final"
 	}
 }
 
+func TestBuildTargetReachability(t *testing.T) {
+	t.Parallel()
+	content := `FROM golang:1.21 AS builder
+RUN go build -o /app
+
+FROM alpine:3.18 AS debug
+COPY --from=builder /app /app
+
+FROM alpine:3.18 AS production
+RUN echo "no app"
+`
+	pr := parseDockerfile(t, content)
+	model := NewBuilder(pr, nil, "Dockerfile").WithTarget("DEBUG").Build()
+
+	if got := model.TargetStageIndex(); got != 1 {
+		t.Fatalf("TargetStageIndex() = %d, want 1", got)
+	}
+	if model.Target() != "DEBUG" {
+		t.Errorf("Target() = %q, want %q", model.Target(), "DEBUG")
+	}
+	if !model.IsStageInBuild(0) || !model.IsStageInBuild(1) {
+		t.Error("builder and debug stages should be part of the debug build")
+	}
+	if model.IsStageInBuild(2) {
+		t.Error("production stage should not be part of the debug build")
+	}
+
+	unreachable := model.Graph().UnreachableStages()
+	if !slices.Equal(unreachable, []int{2}) {
+		t.Errorf("UnreachableStages() = %v, want [2]", unreachable)
+	}
+}
+
+func TestBuildTargetNotFound(t *testing.T) {
+	t.Parallel()
+	pr := parseDockerfile(t, "FROM alpine:3.18 AS base\n")
+	model := NewBuilder(pr, nil, "Dockerfile").WithTarget("missing").Build()
+
+	if got := model.TargetStageIndex(); got != -1 {
+		t.Errorf("TargetStageIndex() = %d, want -1", got)
+	}
+	if model.IsStageInBuild(0) {
+		t.Error("no stage should be in the build for an unknown target")
+	}
+}
+
+func TestBaseImageResolvedWithBuildArgs(t *testing.T) {
+	t.Parallel()
+	content := `ARG BASE_IMAGE=alpine:3.18
+FROM golang:1.21 AS builder
+FROM ${BASE_IMAGE}
+`
+	pr := parseDockerfile(t, content)
+
+	defaults := NewModel(pr, nil, "Dockerfile")
+	info := defaults.StageInfo(1)
+	if info.BaseImage.Resolved != "alpine:3.18" {
+		t.Errorf("Resolved = %q, want %q", info.BaseImage.Resolved, "alpine:3.18")
+	}
+	if info.BaseImage.IsStageRef {
+		t.Error("default base image should be external")
+	}
+
+	overridden := NewModel(pr, map[string]string{"BASE_IMAGE": "builder"}, "Dockerfile")
+	info = overridden.StageInfo(1)
+	if info.BaseImage.Raw != "${BASE_IMAGE}" {
+		t.Errorf("Raw = %q, want %q", info.BaseImage.Raw, "${BASE_IMAGE}")
+	}
+	if !info.BaseImage.IsStageRef || info.BaseImage.StageIndex != 0 {
+		t.Errorf("build arg should resolve base image to stage 0, got IsStageRef=%v StageIndex=%d",
+			info.BaseImage.IsStageRef, info.BaseImage.StageIndex)
+	}
+	if !overridden.Graph().IsReachable(0, 1) {
+		t.Error("builder stage should be reachable via the resolved FROM")
+	}
+}
+
+func TestBaseImageUnresolved(t *testing.T) {
+	t.Parallel()
+	pr := parseDockerfile(t, "ARG BASE_IMAGE\nFROM ${BASE_IMAGE}\n")
+	model := NewModel(pr, nil, "Dockerfile")
+
+	if !model.StageInfo(0).BaseImage.Unresolved {
+		t.Error("base image without ARG value should be marked unresolved")
+	}
+}
+
 func TestOnbuildCopyFrom(t *testing.T) {
 	t.Parallel()
 	content := `FROM golang:1.21 AS builder
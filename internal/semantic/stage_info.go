@@ -86,6 +86,10 @@ type StageInfo struct {
 	// Variables contains the variable scope for this stage.
 	Variables *VariableScope
 
+	// variableHistory holds a copy of Variables after each ARG and ENV
+	// instruction of the stage, in order (see VariablesAt).
+	variableHistory []variableSnapshot
+
 	// EffectiveEnv is the approximate effective environment for this stage after
 	// evaluating ARG and ENV instructions (matching BuildKit's word expansion
 	// environment semantics for linting).
@@ -117,6 +121,41 @@ type StageInfo struct {
 	IsLastStage bool
 }
 
+// variableSnapshot is the variable scope of a stage after an ARG or ENV
+// instruction.
+type variableSnapshot struct {
+	// endLine is the last 1-based line of the instruction.
+	endLine int
+	scope   *VariableScope
+}
+
+// recordVariables snapshots Variables after the ARG or ENV instruction cmd.
+func (s *StageInfo) recordVariables(cmd instructions.Command) {
+	ranges := cmd.Location()
+	if len(ranges) == 0 {
+		return
+	}
+	s.variableHistory = append(s.variableHistory, variableSnapshot{
+		endLine: ranges[len(ranges)-1].End.Line,
+		scope:   s.Variables.clone(),
+	})
+}
+
+// VariablesAt returns the variable scope visible at a 1-based line of the
+// stage: the variables declared by the ARG and ENV instructions that end
+// before it. Unlike Variables, it doesn't see declarations after the line,
+// nor the declaration of the instruction containing it.
+func (s *StageInfo) VariablesAt(line int) *VariableScope {
+	scope := NewStageScope(s.Variables.Parent())
+	for _, snap := range s.variableHistory {
+		if snap.endLine >= line {
+			break
+		}
+		scope = snap.scope
+	}
+	return scope
+}
+
 // HasPackage checks if a package was installed in this stage.
 func (s *StageInfo) HasPackage(pkg string) bool {
 	for _, install := range s.InstalledPackages {
@@ -135,7 +174,7 @@ func (s *StageInfo) IsExternalImage() bool {
 		return false
 	}
 	// scratch is a special "no base" image
-	if s.Stage.BaseName == "scratch" {
+	if s.Stage.BaseName == "scratch" || (s.BaseImage != nil && s.BaseImage.Resolved == "scratch") {
 		return false
 	}
 	// Check if it references another stage
@@ -163,6 +202,14 @@ type BaseImageRef struct {
 	// Raw is the original base image string (e.g., "ubuntu:22.04", "builder").
 	Raw string
 
+	// Resolved is Raw after expanding global ARGs with their build-arg or
+	// default values (e.g., "${BASE}" -> "python:3.12-slim").
+	// Equals Raw when the base name contains no variables.
+	Resolved string
+
+	// Unresolved is true when Resolved still depends on ARGs with no value.
+	Unresolved bool
+
 	// IsStageRef is true if this references another stage in the Dockerfile.
 	IsStageRef bool
 
@@ -20,6 +20,10 @@ type CommandInfo struct {
 	// Args contains all arguments including flags.
 	Args []string
 
+	// Words contains all arguments with their positions. Unlike Args, it
+	// keeps arguments that have no literal content (e.g. "$PKG").
+	Words []Word
+
 	// Position information for the command name.
 	Line     int // 0-based line within the script
 	StartCol int // 0-based column where command starts
@@ -32,6 +36,52 @@ type CommandInfo struct {
 	SubcommandEndCol   int // 0-based column where subcommand ends
 }
 
+// Word is a command argument with its position in the script.
+type Word struct {
+	// Value is the argument with quotes removed. Expansions are dropped, so
+	// for Dynamic words it holds only the literal parts.
+	Value string
+
+	// Dynamic reports whether the argument contains parameter expansions,
+	// command substitutions or arithmetic, whose value is only known at runtime.
+	Dynamic bool
+
+	Line     int // 0-based line within the script
+	StartCol int // 0-based column where the argument starts
+	EndCol   int // 0-based column where the argument ends
+}
+
+// newWord builds a Word from a parsed shell word.
+func newWord(w *syntax.Word) Word {
+	pos := w.Pos()
+	endPos := w.End()
+	return Word{
+		Value:    extractQuotedContent(w),
+		Dynamic:  isDynamicWord(w),
+		Line:     int(pos.Line()) - 1,   //nolint:gosec // shell positions won't overflow
+		StartCol: int(pos.Col()) - 1,    //nolint:gosec
+		EndCol:   int(endPos.Col()) - 1, //nolint:gosec
+	}
+}
+
+// isDynamicWord reports whether a word contains anything but literal text.
+func isDynamicWord(w *syntax.Word) bool {
+	for _, part := range w.Parts {
+		switch p := part.(type) {
+		case *syntax.Lit, *syntax.SglQuoted:
+		case *syntax.DblQuoted:
+			for _, dpart := range p.Parts {
+				if _, ok := dpart.(*syntax.Lit); !ok {
+					return true
+				}
+			}
+		default:
+			return true
+		}
+	}
+	return false
+}
+
 // HasFlag checks if the command has a specific flag.
 // Handles both short flags (-y) and long flags (--yes).
 // For short flags, also checks combined flags (e.g., -yq contains -y).
@@ -193,6 +243,7 @@ func FindCommands(script string, variant Variant, names ...string) []CommandInfo
 
 		// Extract all arguments and find subcommand with position
 		for _, arg := range call.Args[1:] {
+			info.Words = append(info.Words, newWord(arg))
 			lit := extractQuotedContent(arg)
 			if lit == "" {
 				continue
@@ -235,6 +286,7 @@ func findWrappedCommands(args []*syntax.Word, variant Variant, wrapperName strin
 
 			// Extract remaining args and find subcommand with position
 			for _, ra := range wa.RemainingArgs {
+				info.Words = append(info.Words, newWord(ra))
 				raLit := extractQuotedContent(ra)
 				if raLit == "" {
 					continue
@@ -405,6 +405,34 @@ func TestFindCommands_SubcommandPosition(t *testing.T) {
 	}
 }
 
+func TestFindCommands_Words(t *testing.T) {
+	t.Parallel()
+	cmds := FindCommands(`pip install "flask==$V" "$PKG" 'a b'`, VariantBash, "pip")
+	if len(cmds) != 1 {
+		t.Fatalf("expected 1 command, got %d", len(cmds))
+	}
+
+	want := []Word{
+		{Value: "install", Line: 0, StartCol: 4, EndCol: 11},
+		{Value: "flask==", Dynamic: true, Line: 0, StartCol: 12, EndCol: 23},
+		{Value: "", Dynamic: true, Line: 0, StartCol: 24, EndCol: 30},
+		{Value: "a b", Line: 0, StartCol: 31, EndCol: 36},
+	}
+	got := cmds[0].Words
+	if len(got) != len(want) {
+		t.Fatalf("got %d words, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Words[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	// Args omits arguments without literal content.
+	if len(cmds[0].Args) != 3 {
+		t.Errorf("Args = %q, want 3 entries", cmds[0].Args)
+	}
+}
+
 func TestAptGetYesDetection(t *testing.T) {
 	t.Parallel()
 	// Test all the ways to specify "yes" for apt-get per DL3014
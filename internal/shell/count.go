@@ -207,7 +207,7 @@ func IsHeredocCandidate(script string, variant Variant, minCommands int) bool {
 	}
 
 	// Parse once and reuse for all checks
-	prog, err := parseScript(script, variant)
+	prog, err := ParseScript(script, variant)
 	if err != nil {
 		return false
 	}
@@ -231,8 +231,8 @@ func IsHeredocCandidate(script string, variant Variant, minCommands int) bool {
 	return true
 }
 
-// parseScript parses a shell script into an AST.
-func parseScript(script string, variant Variant) (*syntax.File, error) {
+// ParseScript parses a shell script into an AST using the variant's dialect.
+func ParseScript(script string, variant Variant) (*syntax.File, error) {
 	parser := syntax.NewParser(
 		syntax.Variant(variant.toLangVariant()),
 		syntax.KeepComments(false),
@@ -281,7 +281,7 @@ func HasPipes(script string, variant Variant) bool {
 		return false
 	}
 
-	prog, err := parseScript(script, variant)
+	prog, err := ParseScript(script, variant)
 	if err != nil {
 		return false
 	}
@@ -311,7 +311,7 @@ func HasExitCommand(script string, variant Variant) bool {
 		return false
 	}
 
-	prog, err := parseScript(script, variant)
+	prog, err := ParseScript(script, variant)
 	if err != nil {
 		return false
 	}
@@ -74,7 +74,7 @@ func DetectFileCreation(script string, variant Variant, knownVars func(name stri
 		return nil
 	}
 
-	prog, err := parseScript(script, variant)
+	prog, err := ParseScript(script, variant)
 	if err != nil {
 		return nil
 	}
@@ -104,7 +104,7 @@ func DetectStandaloneChmod(script string, variant Variant) *ChmodInfo {
 		return nil
 	}
 
-	prog, err := parseScript(script, variant)
+	prog, err := ParseScript(script, variant)
 	if err != nil {
 		return nil
 	}
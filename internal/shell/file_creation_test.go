@@ -810,7 +810,7 @@ func TestParseUmask(t *testing.T) {
 			t.Parallel()
 			// Build a minimal CallExpr for testing
 			script := strings.Join(tt.args, " ")
-			prog, err := parseScript(script, VariantBash)
+			prog, err := ParseScript(script, VariantBash)
 			if err != nil {
 				t.Fatalf("parseScript failed: %v", err)
 			}
@@ -128,6 +128,12 @@ var shellWrappers = map[string]bool{
 	"ksh":  true,
 }
 
+// IsShellName reports whether name (a command name or path) is a POSIX-family
+// shell that runs the script it is given, e.g. "bash" or "/bin/sh".
+func IsShellName(name string) bool {
+	return shellWrappers[path.Base(name)]
+}
+
 // CommandNamesWithVariant extracts all command names from a shell script
 // using the specified shell variant for parsing.
 //
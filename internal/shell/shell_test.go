@@ -325,6 +325,28 @@ func TestVariantFromShell(t *testing.T) {
 	}
 }
 
+func TestIsShellName(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"sh", true},
+		{"/bin/sh", true},
+		{"/usr/bin/bash", true},
+		{"dash", true},
+		{"python3", false},
+		{"/usr/bin/env", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsShellName(tt.name); got != tt.want {
+			t.Errorf("IsShellName(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
 func TestVariantFromShellCmd(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -8,6 +8,7 @@ import (
 )
 
 func main() {
+	cmd.SetConfigSchema(configSchema)
 	if err := cmd.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
@@ -0,0 +1,8 @@
+package main
+
+import _ "embed"
+
+// configSchema is the JSON schema for .tally.toml, used by tally config validate.
+//
+//go:embed schema.json
+var configSchema []byte
@@ -159,6 +159,7 @@ func run(targets targets) error {
 	}
 
 	tallyCount := countRegisteredPrefix(rules.TallyRulePrefix)
+	shellcheckCount := countRegisteredPrefix(rules.ShellcheckRulePrefix)
 	buildkitSupported := len(implementedRows) + len(capturedRows)
 	buildkitTotal := len(defs)
 	if got := len(bkregistry.All()); got != buildkitTotal {
@@ -170,7 +171,7 @@ func run(targets targets) error {
 	}
 
 	if targets.readme {
-		readmeBlock := renderReadmeRulesTable(buildkitSupported, buildkitTotal, tallyCount, hadolintSupported)
+		readmeBlock := renderReadmeRulesTable(buildkitSupported, buildkitTotal, tallyCount, hadolintSupported, shellcheckCount)
 		if err := applyOrCheck(
 			targets.mode,
 			readmePath,
@@ -192,6 +193,7 @@ func run(targets targets) error {
 			hadolintImplemented,
 			hadolintCovered,
 			hadolintTotal,
+			shellcheckCount,
 		)
 		if err := applyOrCheck(
 			targets.mode,
@@ -620,7 +622,7 @@ func hadolintTotalCount(path string) (int, error) {
 	return len(arr), nil
 }
 
-func renderReadmeRulesTable(buildkitSupported, buildkitTotal, tallyCount, hadolintSupported int) string {
+func renderReadmeRulesTable(buildkitSupported, buildkitTotal, tallyCount, hadolintSupported, shellcheckCount int) string {
 	var b strings.Builder
 	b.WriteString("| Source | Rules | Description |\n")
 	b.WriteString("|--------|-------|-------------|\n")
@@ -637,6 +639,10 @@ func renderReadmeRulesTable(buildkitSupported, buildkitTotal, tallyCount, hadoli
 	fmt.Fprintf(&b, "%d rules | ", hadolintSupported)
 	b.WriteString("Hadolint-compatible Dockerfile rules (expanding) |\n")
 
+	b.WriteString("| **[ShellCheck](https://www.shellcheck.net/)** | ")
+	fmt.Fprintf(&b, "%d rules | ", shellcheckCount)
+	b.WriteString("ShellCheck-equivalent checks of RUN scripts, run natively |\n")
+
 	return b.String()
 }
 
@@ -648,6 +654,7 @@ func renderRulesSummaryTable(
 	hadolintImplemented int,
 	hadolintCovered int,
 	hadolintTotal int,
+	shellcheckCount int,
 ) string {
 	return fmt.Sprintf(
 		""+
@@ -655,7 +662,8 @@ func renderRulesSummaryTable(
 			"|-----------|-------------|---------------------|-------|\n"+
 			"| tally | %d | - | %d |\n"+
 			"| buildkit | %d + %d captured | - | %d |\n"+
-			"| hadolint | %d | %d | %d |\n",
+			"| hadolint | %d | %d | %d |\n"+
+			"| shellcheck | %d | - | %d |\n",
 		tallyCount,
 		tallyCount,
 		buildkitImplemented,
@@ -664,6 +672,8 @@ func renderRulesSummaryTable(
 		hadolintImplemented,
 		hadolintCovered,
 		hadolintTotal,
+		shellcheckCount,
+		shellcheckCount,
 	)
 }
 